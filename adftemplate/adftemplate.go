@@ -0,0 +1,355 @@
+// Package adftemplate fills ADF documents used as templates, such as issue templates.
+//
+// Variables replace placeholder nodes, which are named by their prompt text, and {{name}}
+// tokens inside text nodes. A variable holds either plain text or an ADF fragment: inline
+// nodes are spliced into the surrounding text, and block nodes replace a paragraph that
+// holds nothing but the placeholder or token. Placeholders and tokens without a variable
+// are left in place and reported in Result.Missing.
+package adftemplate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// tokenRe matches a {{name}} token; the name is trimmed of surrounding spaces.
+var tokenRe = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+// inlineTypes lists the ADF nodes that are valid inside a paragraph.
+var inlineTypes = map[string]bool{
+	"text":            true,
+	"hardBreak":       true,
+	"mention":         true,
+	"emoji":           true,
+	"date":            true,
+	"status":          true,
+	"inlineCard":      true,
+	"inlineExtension": true,
+	"placeholder":     true,
+	"mediaInline":     true,
+}
+
+// Result holds a filled document.
+type Result struct {
+	Doc converter.Doc `json:"doc"`
+	// Missing lists the variables the template refers to but vars does not define, in
+	// document order and without duplicates.
+	Missing []string `json:"missing,omitempty"`
+}
+
+// Fill returns a copy of doc with its placeholders and {{name}} tokens replaced by vars.
+//
+// A variable is a string, a converter.Node, a []converter.Node or a converter.Doc, whose
+// content is used; any other value is formatted with fmt.Sprint. Fill returns an error
+// when block content would have to go inline, or anything but text into a code block.
+func Fill(doc converter.Doc, vars map[string]any) (Result, error) {
+	f := &filler{vars: vars, seen: make(map[string]bool)}
+	content, err := f.fillBlocks(doc.Content)
+	if err != nil {
+		return Result{}, err
+	}
+	doc.Content = content
+	return Result{Doc: doc, Missing: f.missing}, nil
+}
+
+// filler holds the state of a single Fill call.
+type filler struct {
+	vars    map[string]any
+	missing []string
+	seen    map[string]bool
+}
+
+// lookup returns the fragment of the named variable, recording it as missing when it is
+// not defined.
+func (f *filler) lookup(name string) ([]converter.Node, bool) {
+	value, ok := f.vars[name]
+	if !ok {
+		if !f.seen[name] {
+			f.seen[name] = true
+			f.missing = append(f.missing, name)
+		}
+		return nil, false
+	}
+	return fragment(value), true
+}
+
+// fillBlocks fills the children of a node whose content is not inline.
+func (f *filler) fillBlocks(content []converter.Node) ([]converter.Node, error) {
+	if content == nil {
+		return nil, nil
+	}
+	filled := make([]converter.Node, 0, len(content))
+	for _, node := range content {
+		if name, ok := soleVariable(node); ok {
+			if value, found := f.lookup(name); found && !isInline(value) {
+				filled = append(filled, wrapInline(value)...)
+				continue
+			}
+		}
+
+		if len(node.Content) > 0 {
+			var err error
+			if hasInlineContent(node) {
+				node.Content, err = f.fillInline(node.Type, node.Content)
+			} else {
+				node.Content, err = f.fillBlocks(node.Content)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		filled = append(filled, node)
+	}
+	return filled, nil
+}
+
+// fillInline fills the inline content of a paragraph, heading, code block or similar node.
+func (f *filler) fillInline(parentType string, content []converter.Node) ([]converter.Node, error) {
+	filled := make([]converter.Node, 0, len(content))
+	for _, node := range content {
+		switch node.Type {
+		case "placeholder":
+			name := placeholderName(node)
+			if name == "" {
+				filled = append(filled, node)
+				continue
+			}
+			value, ok := f.lookup(name)
+			if !ok {
+				filled = append(filled, node)
+				continue
+			}
+			nodes, err := inlineValue(parentType, name, value, nil)
+			if err != nil {
+				return nil, err
+			}
+			filled = append(filled, nodes...)
+		case "text":
+			nodes, err := f.fillText(parentType, node)
+			if err != nil {
+				return nil, err
+			}
+			filled = append(filled, nodes...)
+		default:
+			filled = append(filled, node)
+		}
+	}
+	return mergeText(filled), nil
+}
+
+// fillText replaces the tokens of a text node. Text values keep the node's marks, and
+// inline fragments split the node around them.
+func (f *filler) fillText(parentType string, node converter.Node) ([]converter.Node, error) {
+	matches := tokenRe.FindAllStringSubmatchIndex(node.Text, -1)
+	if len(matches) == 0 {
+		return []converter.Node{node}, nil
+	}
+
+	var nodes []converter.Node
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, textNode(text.String(), node.Marks))
+			text.Reset()
+		}
+	}
+
+	last := 0
+	for _, match := range matches {
+		text.WriteString(node.Text[last:match[0]])
+		last = match[1]
+
+		name := node.Text[match[2]:match[3]]
+		if name == "" {
+			text.WriteString(node.Text[match[0]:match[1]])
+			continue
+		}
+		value, ok := f.lookup(name)
+		if !ok {
+			text.WriteString(node.Text[match[0]:match[1]])
+			continue
+		}
+		if plain, ok := plainText(value); ok {
+			text.WriteString(plain)
+			continue
+		}
+
+		inline, err := inlineValue(parentType, name, value, node.Marks)
+		if err != nil {
+			return nil, err
+		}
+		flush()
+		nodes = append(nodes, inline...)
+	}
+	text.WriteString(node.Text[last:])
+	flush()
+	return nodes, nil
+}
+
+// inlineValue checks that value can stand in an inline position of parentType. Text nodes
+// of the fragment without marks take marks.
+func inlineValue(parentType, name string, value []converter.Node, marks []converter.Mark) ([]converter.Node, error) {
+	if !isInline(value) {
+		return nil, fmt.Errorf("variable %q holds block content but is used inside a %s", name, parentType)
+	}
+	if parentType == "codeBlock" {
+		if _, ok := plainText(value); !ok {
+			return nil, fmt.Errorf("variable %q holds rich content but is used inside a codeBlock", name)
+		}
+	}
+
+	nodes := make([]converter.Node, len(value))
+	for i, node := range value {
+		if node.Type == "text" && len(node.Marks) == 0 {
+			node.Marks = marks
+		}
+		nodes[i] = node
+	}
+	return nodes, nil
+}
+
+// soleVariable returns the variable name when node is a paragraph that holds nothing but
+// one placeholder or one {{name}} token.
+func soleVariable(node converter.Node) (string, bool) {
+	if node.Type != "paragraph" {
+		return "", false
+	}
+
+	name := ""
+	for _, child := range node.Content {
+		switch {
+		case child.Type == "text" && strings.TrimSpace(child.Text) == "":
+			continue
+		case name != "":
+			return "", false
+		case child.Type == "placeholder":
+			name = placeholderName(child)
+		case child.Type == "text":
+			trimmed := strings.TrimSpace(child.Text)
+			match := tokenRe.FindStringSubmatchIndex(trimmed)
+			if match == nil || match[0] != 0 || match[1] != len(trimmed) {
+				return "", false
+			}
+			name = trimmed[match[2]:match[3]]
+		default:
+			return "", false
+		}
+	}
+	return name, name != ""
+}
+
+// placeholderName returns the variable that fills a placeholder: its prompt text, or the
+// name inside the text when the prompt is itself a {{name}} token.
+func placeholderName(node converter.Node) string {
+	text := strings.TrimSpace(node.GetStringAttr("text", ""))
+	if match := tokenRe.FindStringSubmatch(text); match != nil && match[0] == text {
+		return match[1]
+	}
+	return text
+}
+
+// fragment converts a variable value to ADF nodes.
+func fragment(value any) []converter.Node {
+	switch v := value.(type) {
+	case string:
+		return []converter.Node{textNode(v, nil)}
+	case converter.Node:
+		return []converter.Node{v}
+	case []converter.Node:
+		return v
+	case converter.Doc:
+		return v.Content
+	default:
+		return []converter.Node{textNode(fmt.Sprint(v), nil)}
+	}
+}
+
+// plainText returns the text of a fragment made of unmarked text nodes only.
+func plainText(nodes []converter.Node) (string, bool) {
+	var sb strings.Builder
+	for _, node := range nodes {
+		if node.Type != "text" || len(node.Marks) > 0 {
+			return "", false
+		}
+		sb.WriteString(node.Text)
+	}
+	return sb.String(), true
+}
+
+func isInline(nodes []converter.Node) bool {
+	for _, node := range nodes {
+		if !inlineTypes[node.Type] {
+			return false
+		}
+	}
+	return true
+}
+
+// hasInlineContent reports whether node holds inline content rather than blocks.
+func hasInlineContent(node converter.Node) bool {
+	if node.Type == "paragraph" || node.Type == "heading" || node.Type == "codeBlock" || node.Type == "taskItem" ||
+		node.Type == "decisionItem" {
+		return true
+	}
+	return isInline(node.Content)
+}
+
+// wrapInline puts runs of inline nodes in a block fragment into paragraphs.
+func wrapInline(nodes []converter.Node) []converter.Node {
+	var blocks []converter.Node
+	var run []converter.Node
+	flush := func() {
+		if len(run) > 0 {
+			blocks = append(blocks, converter.Node{Type: "paragraph", Content: run})
+			run = nil
+		}
+	}
+	for _, node := range nodes {
+		if inlineTypes[node.Type] {
+			run = append(run, node)
+			continue
+		}
+		flush()
+		blocks = append(blocks, node)
+	}
+	flush()
+	return blocks
+}
+
+// mergeText joins adjacent text nodes with the same marks and drops empty ones, which
+// ADF does not allow.
+func mergeText(nodes []converter.Node) []converter.Node {
+	merged := make([]converter.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if node.Type == "text" {
+			if node.Text == "" {
+				continue
+			}
+			if last := len(merged) - 1; last >= 0 && merged[last].Type == "text" && sameMarks(merged[last].Marks, node.Marks) {
+				merged[last].Text += node.Text
+				continue
+			}
+		}
+		merged = append(merged, node)
+	}
+	return merged
+}
+
+func sameMarks(a, b []converter.Mark) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Type != b[i].Type || fmt.Sprint(a[i].Attrs) != fmt.Sprint(b[i].Attrs) {
+			return false
+		}
+	}
+	return true
+}
+
+func textNode(text string, marks []converter.Mark) converter.Node {
+	return converter.Node{Type: "text", Text: text, Marks: marks}
+}
@@ -0,0 +1,155 @@
+package adftemplate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseDoc(t *testing.T, input string) converter.Doc {
+	t.Helper()
+	var doc converter.Doc
+	require.NoError(t, json.Unmarshal([]byte(input), &doc))
+	return doc
+}
+
+func assertDoc(t *testing.T, expected string, doc converter.Doc) {
+	t.Helper()
+	actual, err := json.Marshal(doc)
+	require.NoError(t, err)
+	assert.JSONEq(t, expected, string(actual))
+}
+
+func TestFillTextTokens(t *testing.T) {
+	doc := parseDoc(t, `{"version":1,"type":"doc","content":[
+		{"type":"heading","attrs":{"level":1},"content":[{"type":"text","text":"Release {{ version }}"}]},
+		{"type":"paragraph","content":[
+			{"type":"text","text":"Owner: {{owner}}, due {{due}}.","marks":[{"type":"em"}]}
+		]},
+		{"type":"codeBlock","content":[{"type":"text","text":"git tag {{version}}"}]}
+	]}`)
+
+	result, err := Fill(doc, map[string]any{"version": "1.4.0", "owner": "Ann"})
+	require.NoError(t, err)
+	assertDoc(t, `{"version":1,"type":"doc","content":[
+		{"type":"heading","attrs":{"level":1},"content":[{"type":"text","text":"Release 1.4.0"}]},
+		{"type":"paragraph","content":[
+			{"type":"text","text":"Owner: Ann, due {{due}}.","marks":[{"type":"em"}]}
+		]},
+		{"type":"codeBlock","content":[{"type":"text","text":"git tag 1.4.0"}]}
+	]}`, result.Doc)
+	assert.Equal(t, []string{"due"}, result.Missing)
+}
+
+func TestFillPlaceholders(t *testing.T) {
+	doc := parseDoc(t, `{"version":1,"type":"doc","content":[
+		{"type":"paragraph","content":[
+			{"type":"text","text":"Reporter: "},
+			{"type":"placeholder","attrs":{"text":"Reporter"}},
+			{"type":"text","text":" / "},
+			{"type":"placeholder","attrs":{"text":"{{team}}"}},
+			{"type":"text","text":" / "},
+			{"type":"placeholder","attrs":{"text":"Component"}}
+		]}
+	]}`)
+
+	result, err := Fill(doc, map[string]any{
+		"Reporter": converter.Node{Type: "mention", Attrs: map[string]any{"id": "42", "text": "@Ann"}},
+		"team":     "Platform",
+	})
+	require.NoError(t, err)
+	assertDoc(t, `{"version":1,"type":"doc","content":[
+		{"type":"paragraph","content":[
+			{"type":"text","text":"Reporter: "},
+			{"type":"mention","attrs":{"id":"42","text":"@Ann"}},
+			{"type":"text","text":" / Platform / "},
+			{"type":"placeholder","attrs":{"text":"Component"}}
+		]}
+	]}`, result.Doc)
+	assert.Equal(t, []string{"Component"}, result.Missing)
+}
+
+func TestFillRichInlineFragmentSplitsText(t *testing.T) {
+	doc := parseDoc(t, `{"version":1,"type":"doc","content":[
+		{"type":"paragraph","content":[{"type":"text","text":"See {{link}} now","marks":[{"type":"strong"}]}]}
+	]}`)
+
+	result, err := Fill(doc, map[string]any{"link": []converter.Node{
+		{Type: "text", Text: "docs", Marks: []converter.Mark{{Type: "link", Attrs: map[string]any{"href": "https://example.com"}}}},
+		{Type: "text", Text: "!"},
+	}})
+	require.NoError(t, err)
+	assertDoc(t, `{"version":1,"type":"doc","content":[
+		{"type":"paragraph","content":[
+			{"type":"text","text":"See ","marks":[{"type":"strong"}]},
+			{"type":"text","text":"docs","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]},
+			{"type":"text","text":"! now","marks":[{"type":"strong"}]}
+		]}
+	]}`, result.Doc)
+	assert.Empty(t, result.Missing)
+}
+
+func TestFillBlockFragmentReplacesParagraph(t *testing.T) {
+	doc := parseDoc(t, `{"version":1,"type":"doc","content":[
+		{"type":"paragraph","content":[{"type":"text","text":"Steps:"}]},
+		{"type":"paragraph","content":[{"type":"text","text":" {{steps}} "}]},
+		{"type":"bulletList","content":[{"type":"listItem","content":[
+			{"type":"paragraph","content":[{"type":"placeholder","attrs":{"text":"Notes"}}]}
+		]}]}
+	]}`)
+
+	steps := converter.Doc{Type: "doc", Content: []converter.Node{{Type: "orderedList", Content: []converter.Node{
+		{Type: "listItem", Content: []converter.Node{{Type: "paragraph", Content: []converter.Node{{Type: "text", Text: "Build"}}}}},
+	}}}}
+	notes := []converter.Node{
+		{Type: "text", Text: "Intro"},
+		{Type: "rule"},
+	}
+
+	result, err := Fill(doc, map[string]any{"steps": steps, "Notes": notes})
+	require.NoError(t, err)
+	assertDoc(t, `{"version":1,"type":"doc","content":[
+		{"type":"paragraph","content":[{"type":"text","text":"Steps:"}]},
+		{"type":"orderedList","content":[{"type":"listItem","content":[
+			{"type":"paragraph","content":[{"type":"text","text":"Build"}]}
+		]}]},
+		{"type":"bulletList","content":[{"type":"listItem","content":[
+			{"type":"paragraph","content":[{"type":"text","text":"Intro"}]},
+			{"type":"rule"}
+		]}]}
+	]}`, result.Doc)
+}
+
+func TestFillDoesNotModifyInput(t *testing.T) {
+	input := `{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"Hi {{name}}"}]}]}`
+	doc := parseDoc(t, input)
+
+	result, err := Fill(doc, map[string]any{"name": "Ann", "unused": 1})
+	require.NoError(t, err)
+	assert.Equal(t, "Hi Ann", result.Doc.Content[0].Content[0].Text)
+	assertDoc(t, input, doc)
+}
+
+func TestFillFormatsOtherValues(t *testing.T) {
+	doc := parseDoc(t, `{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"Points: {{points}}{{}}"}]}]}`)
+
+	result, err := Fill(doc, map[string]any{"points": 5})
+	require.NoError(t, err)
+	assert.Equal(t, "Points: 5{{}}", result.Doc.Content[0].Content[0].Text)
+	assert.Empty(t, result.Missing)
+}
+
+func TestFillRejectsMisplacedFragments(t *testing.T) {
+	doc := parseDoc(t, `{"version":1,"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"Steps: {{steps}}"}]}]}`)
+	_, err := Fill(doc, map[string]any{"steps": converter.Node{Type: "bulletList"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `variable "steps" holds block content but is used inside a paragraph`)
+
+	doc = parseDoc(t, `{"version":1,"type":"doc","content":[{"type":"codeBlock","content":[{"type":"text","text":"{{who}}"}]}]}`)
+	_, err = Fill(doc, map[string]any{"who": converter.Node{Type: "mention", Attrs: map[string]any{"id": "1"}}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "inside a codeBlock")
+}
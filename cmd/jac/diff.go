@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+type diffLine struct {
+	kind byte
+	text string
+}
+
+// unifiedDiff returns a unified diff between two texts, or an empty string when they are
+// equal.
+func unifiedDiff(oldName, newName, before, after string) string {
+	lines := diffLines(splitLines(before), splitLines(after))
+
+	// oldAt and newAt hold the line numbers reached before each diff line.
+	oldAt := make([]int, len(lines)+1)
+	newAt := make([]int, len(lines)+1)
+	for i, line := range lines {
+		oldAt[i+1], newAt[i+1] = oldAt[i], newAt[i]
+		if line.kind != '+' {
+			oldAt[i+1]++
+		}
+		if line.kind != '-' {
+			newAt[i+1]++
+		}
+	}
+
+	var sb strings.Builder
+	for start := 0; start < len(lines); {
+		for start < len(lines) && lines[start].kind == ' ' {
+			start++
+		}
+		if start == len(lines) {
+			break
+		}
+
+		// Extend the hunk while the next change is close enough to share context.
+		end := start + 1
+		for i := end; i < len(lines) && i-end < 2*diffContext; i++ {
+			if lines[i].kind != ' ' {
+				end = i + 1
+			}
+		}
+		from := max(0, start-diffContext)
+		to := min(len(lines), end+diffContext)
+
+		if sb.Len() == 0 {
+			sb.WriteString("--- " + oldName + "\n+++ " + newName + "\n")
+		}
+		sb.WriteString(fmt.Sprintf("@@ -%s +%s @@\n",
+			hunkRange(oldAt[from], oldAt[to]-oldAt[from]), hunkRange(newAt[from], newAt[to]-newAt[from])))
+		for _, line := range lines[from:to] {
+			sb.WriteString(string(line.kind) + line.text + "\n")
+		}
+		start = to
+	}
+	return sb.String()
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines aligns two line slices on their longest common subsequence.
+func diffLines(before, after []string) []diffLine {
+	common := make([][]int, len(before)+1)
+	for i := range common {
+		common[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(before) && j < len(after) {
+		switch {
+		case before[i] == after[j]:
+			lines = append(lines, diffLine{' ', before[i]})
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			lines = append(lines, diffLine{'-', before[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', after[j]})
+			j++
+		}
+	}
+	for ; i < len(before); i++ {
+		lines = append(lines, diffLine{'-', before[i]})
+	}
+	for ; j < len(after); j++ {
+		lines = append(lines, diffLine{'+', after[j]})
+	}
+	return lines
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/rgonek/jira-adf-converter/mdconverter"
+)
+
+// markdownFormatter re-renders Markdown through ADF with matching reverse and forward
+// configs.
+type markdownFormatter struct {
+	reverse *mdconverter.Converter
+	forward *converter.Converter
+}
+
+// formatResult is the outcome of formatting one Markdown document.
+type formatResult struct {
+	Formatted string
+	// Changes lists the constructs that the round trip would change or drop. Formatting is
+	// only safe when it is empty.
+	Changes []string
+}
+
+func runFmt(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	preset := fs.String("preset", presetBalanced, "Preset: balanced|strict|readable|lossy|pandoc|obsidian|gitlab|commonmark")
+	allowHTML := fs.Bool("allow-html", false, "Enable HTML output and detection")
+	wrap := fs.Int("wrap", 0, "Wrap paragraph text at this column (0 disables wrapping)")
+	check := fs.Bool("check", false, "List files that are not formatted and exit with status 1")
+	diff := fs.Bool("diff", false, "Print a diff of the changes instead of the formatted Markdown")
+	write := fs.Bool("w", false, "Write the formatted Markdown back to the files")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: jac fmt [options] <input-file|->...\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 1
+	}
+	if *check && *write {
+		fmt.Fprintf(stderr, "-check and -w cannot be combined\n")
+		return 2
+	}
+
+	formatter, err := newMarkdownFormatter(*preset, *allowHTML, *wrap)
+	if err != nil {
+		fmt.Fprintf(stderr, "Invalid config: %v\n", err)
+		return 1
+	}
+
+	status := 0
+	for _, path := range fs.Args() {
+		var data []byte
+		if path == "-" {
+			if *write {
+				fmt.Fprintf(stderr, "-: cannot write standard input in place\n")
+				status = 1
+				continue
+			}
+			data, err = io.ReadAll(stdin)
+		} else {
+			data, err = os.ReadFile(path)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "Error reading file: %v\n", err)
+			status = 1
+			continue
+		}
+
+		result, err := formatter.format(string(data))
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", path, err)
+			status = 1
+			continue
+		}
+		if len(result.Changes) > 0 {
+			fmt.Fprintf(stderr, "%s: not formatted, the round trip would change:\n", path)
+			for _, change := range result.Changes {
+				fmt.Fprintf(stderr, "  %s\n", change)
+			}
+			status = 1
+			continue
+		}
+
+		changed := result.Formatted != string(data)
+		if *check && changed {
+			fmt.Fprintln(stdout, path)
+			status = 1
+		}
+		if *diff && changed {
+			fmt.Fprint(stdout, unifiedDiff(path+".orig", path, string(data), result.Formatted))
+		}
+		if *write && changed {
+			info, err := os.Stat(path)
+			if err == nil {
+				err = os.WriteFile(path, []byte(result.Formatted), info.Mode().Perm())
+			}
+			if err != nil {
+				fmt.Fprintf(stderr, "Error writing file: %v\n", err)
+				status = 1
+			}
+		}
+		if !*check && !*diff && !*write {
+			fmt.Fprint(stdout, result.Formatted)
+		}
+	}
+	return status
+}
+
+func newMarkdownFormatter(preset string, allowHTML bool, wrap int) (markdownFormatter, error) {
+	reverseCfg, err := resolveReverseConfig(preset, allowHTML, false)
+	if err != nil {
+		return markdownFormatter{}, err
+	}
+	reverse, err := mdconverter.New(reverseCfg)
+	if err != nil {
+		return markdownFormatter{}, err
+	}
+
+	forwardCfg, err := resolveConfig(preset, allowHTML, false)
+	if err != nil {
+		return markdownFormatter{}, err
+	}
+	forwardCfg.WrapWidth = wrap
+	forward, err := converter.New(forwardCfg)
+	if err != nil {
+		return markdownFormatter{}, err
+	}
+
+	return markdownFormatter{reverse: reverse, forward: forward}, nil
+}
+
+// format parses markdown, renders the ADF back to Markdown and parses the result again.
+// The formatted Markdown is only lossless when both parses produce the same ADF and no
+// step reported a warning.
+func (f markdownFormatter) format(markdown string) (formatResult, error) {
+	parsed, err := f.reverse.Convert(markdown)
+	if err != nil {
+		return formatResult{}, err
+	}
+	rendered, err := f.forward.ConvertWithContext(context.Background(), parsed.ADF, converter.ConvertOptions{Metadata: parsed.Metadata})
+	if err != nil {
+		return formatResult{}, err
+	}
+	reparsed, err := f.reverse.Convert(rendered.Markdown)
+	if err != nil {
+		return formatResult{}, err
+	}
+
+	var changes []string
+	for _, warning := range append(parsed.Warnings, rendered.Warnings...) {
+		changes = append(changes, warning.Message)
+	}
+
+	var before, after converter.Doc
+	if err := json.Unmarshal(parsed.ADF, &before); err != nil {
+		return formatResult{}, err
+	}
+	if err := json.Unmarshal(reparsed.ADF, &after); err != nil {
+		return formatResult{}, err
+	}
+	if !jsonEqual(parsed.Metadata, reparsed.Metadata) {
+		changes = append(changes, "front matter would change")
+	}
+	changes = append(changes, diffNodes("", "doc", before.Content, after.Content)...)
+
+	return formatResult{Formatted: rendered.Markdown, Changes: changes}, nil
+}
+
+// diffNodes describes how the children of the node at path differ, using JSON Pointer
+// paths into the ADF document.
+func diffNodes(path, nodeType string, before, after []converter.Node) []string {
+	if len(before) != len(after) {
+		return []string{fmt.Sprintf("%s: %s content would change from %d to %d nodes", pointer(path), nodeType, len(before), len(after))}
+	}
+
+	var changes []string
+	for i := range before {
+		childPath := path + "/content/" + strconv.Itoa(i)
+		changes = append(changes, diffNode(childPath, before[i], after[i])...)
+	}
+	return changes
+}
+
+func diffNode(path string, before, after converter.Node) []string {
+	switch {
+	case before.Type != after.Type:
+		return []string{fmt.Sprintf("%s: %s would become %s", path, before.Type, after.Type)}
+	case before.Text != after.Text:
+		return []string{fmt.Sprintf("%s: text %q would become %q", path, before.Text, after.Text)}
+	case before.Level != after.Level || !jsonEqual(before.Attrs, after.Attrs):
+		return []string{fmt.Sprintf("%s: %s attributes would change", path, before.Type)}
+	case !jsonEqual(before.Marks, after.Marks):
+		return []string{fmt.Sprintf("%s: %s marks would change", path, before.Type)}
+	}
+	return diffNodes(path, before.Type, before.Content, after.Content)
+}
+
+func pointer(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// jsonEqual compares values by their JSON encoding, so that nil and empty maps or slices
+// are equal.
+func jsonEqual(a, b any) bool {
+	return jsonValue(a) == jsonValue(b)
+}
+
+func jsonValue(v any) string {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	switch string(encoded) {
+	case "null", "{}", "[]":
+		return ""
+	}
+	return string(encoded)
+}
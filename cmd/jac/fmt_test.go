@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const unformattedMarkdown = "# Title\n\nSome *text* here\nwith a soft break.\n\n* one\n* two\n"
+
+const formattedMarkdown = "# Title\n\nSome *text* here with a soft break.\n\n- one\n- two\n"
+
+func writeMarkdown(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "page.md")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestRunFmt(t *testing.T) {
+	path := writeMarkdown(t, unformattedMarkdown)
+
+	var stdout, stderr bytes.Buffer
+	code := runFmt([]string{path}, nil, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	assert.Equal(t, formattedMarkdown, stdout.String())
+}
+
+func TestRunFmtStdinAndWrap(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runFmt([]string{"-wrap", "20", "-"}, strings.NewReader("A paragraph that is long enough to wrap.\n"), &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	assert.Equal(t, "A paragraph that is\nlong enough to wrap.\n", stdout.String())
+}
+
+func TestRunFmtCheck(t *testing.T) {
+	path := writeMarkdown(t, unformattedMarkdown)
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 1, runFmt([]string{"-check", path}, nil, &stdout, &stderr))
+	assert.Equal(t, path+"\n", stdout.String())
+
+	formatted := writeMarkdown(t, formattedMarkdown)
+	stdout.Reset()
+	assert.Equal(t, 0, runFmt([]string{"-check", formatted}, nil, &stdout, &stderr))
+	assert.Empty(t, stdout.String())
+}
+
+func TestRunFmtDiff(t *testing.T) {
+	path := writeMarkdown(t, unformattedMarkdown)
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, 0, runFmt([]string{"-diff", path}, nil, &stdout, &stderr), stderr.String())
+	assert.Equal(t, "--- "+path+".orig\n+++ "+path+"\n"+
+		"@@ -1,7 +1,6 @@\n"+
+		" # Title\n \n"+
+		"-Some *text* here\n-with a soft break.\n"+
+		"+Some *text* here with a soft break.\n \n"+
+		"-* one\n-* two\n"+
+		"+- one\n+- two\n", stdout.String())
+}
+
+func TestRunFmtWrite(t *testing.T) {
+	path := writeMarkdown(t, unformattedMarkdown)
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, 0, runFmt([]string{"-w", path}, nil, &stdout, &stderr), stderr.String())
+	assert.Empty(t, stdout.String())
+
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, formattedMarkdown, string(written))
+}
+
+func TestRunFmtReportsLossyRoundTrip(t *testing.T) {
+	input := "Intro\n\n<div class=\"note\">raw</div>\n"
+	path := writeMarkdown(t, input)
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 1, runFmt([]string{"-w", path}, nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), path+": not formatted, the round trip would change:")
+	assert.Contains(t, stderr.String(), "unsupported html block")
+
+	unchanged, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, input, string(unchanged))
+}
+
+func TestRunFmtErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 1, runFmt(nil, nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "Usage: jac fmt")
+
+	stderr.Reset()
+	assert.Equal(t, 2, runFmt([]string{"-check", "-w", "page.md"}, nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "cannot be combined")
+
+	stderr.Reset()
+	assert.Equal(t, 1, runFmt([]string{"-preset", "unknown", "page.md"}, nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "Invalid config")
+}
+
+func TestDiffNodes(t *testing.T) {
+	changes := diffNodes("", "doc", []converter.Node{
+		{Type: "panel", Attrs: map[string]any{"panelType": "info"}},
+		{Type: "paragraph", Content: []converter.Node{{Type: "text", Text: "a"}}},
+	}, []converter.Node{
+		{Type: "blockquote"},
+		{Type: "paragraph", Content: []converter.Node{{Type: "text", Text: "b"}}},
+	})
+	assert.Equal(t, []string{
+		"/content/0: panel would become blockquote",
+		`/content/1/content/0: text "a" would become "b"`,
+	}, changes)
+
+	assert.Equal(t, []string{"/: doc content would change from 1 to 0 nodes"},
+		diffNodes("", "doc", []converter.Node{{Type: "rule"}}, nil))
+	assert.Empty(t, diffNodes("", "doc", []converter.Node{{Type: "rule", Attrs: map[string]any{}}}, []converter.Node{{Type: "rule"}}))
+}
+
+func TestUnifiedDiffSplitsDistantChanges(t *testing.T) {
+	before := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	after := "A\nb\nc\nd\ne\nf\ng\nh\ni\nJ\nk\n"
+	assert.Equal(t, "--- old\n+++ new\n"+
+		"@@ -1,4 +1,4 @@\n-a\n+A\n b\n c\n d\n"+
+		"@@ -7,4 +7,5 @@\n g\n h\n i\n-j\n+J\n+k\n", unifiedDiff("old", "new", before, after))
+	assert.Empty(t, unifiedDiff("old", "new", before, before))
+}
+
+func TestRunFmtKeepsFrontMatter(t *testing.T) {
+	input := "---\ntitle:   Design\nlabels: [a, b]\n---\n\n* one\n"
+
+	var stdout, stderr bytes.Buffer
+	code := runFmt([]string{"-"}, strings.NewReader(input), &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	assert.Equal(t, "---\nlabels:\n  - a\n  - b\ntitle: Design\n---\n\n- one\n", stdout.String())
+}
+
+func TestRunFmtKeepsCommentOnlyFrontMatterAsMarkdown(t *testing.T) {
+	input := "---\n# Title\n---\n\nBody\n"
+
+	var stdout, stderr bytes.Buffer
+	code := runFmt([]string{"-"}, strings.NewReader(input), &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	assert.Contains(t, stdout.String(), "# Title\n")
+	assert.Contains(t, stdout.String(), "Body\n")
+}
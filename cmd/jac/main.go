@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -12,11 +13,14 @@ import (
 )
 
 const (
-	presetBalanced = "balanced"
-	presetStrict   = "strict"
-	presetReadable = "readable"
-	presetLossy    = "lossy"
-	presetPandoc   = "pandoc"
+	presetBalanced   = "balanced"
+	presetStrict     = "strict"
+	presetReadable   = "readable"
+	presetLossy      = "lossy"
+	presetPandoc     = "pandoc"
+	presetObsidian   = "obsidian"
+	presetGitLab     = "gitlab"
+	presetCommonMark = "commonmark"
 )
 
 func presetConfig(preset string) (converter.Config, error) {
@@ -68,8 +72,23 @@ func presetConfig(preset string) (converter.Config, error) {
 			LayoutSectionStyle:   converter.LayoutSectionPandoc,
 			TableMode:            converter.TableAutoPandoc,
 		}, nil
+	case presetObsidian:
+		return converter.Config{
+			BackgroundColorStyle: converter.ColorObsidian,
+			PanelStyle:           converter.PanelObsidian,
+			ExpandStyle:          converter.ExpandObsidian,
+			WikiLinks:            true,
+		}, nil
+	case presetGitLab:
+		return converter.Config{
+			PanelStyle:  converter.PanelGitLab,
+			StatusStyle: converter.StatusGitLab,
+			TOCStyle:    converter.TOCGitLab,
+		}, nil
+	case presetCommonMark:
+		return converter.Config{CommonMark: true}, nil
 	default:
-		return converter.Config{}, fmt.Errorf("unknown preset %q (allowed: balanced, strict, readable, lossy, pandoc)", preset)
+		return converter.Config{}, fmt.Errorf("unknown preset %q (allowed: balanced, strict, readable, lossy, pandoc, obsidian, gitlab, commonmark)", preset)
 	}
 }
 
@@ -152,9 +171,47 @@ func reversePresetConfig(preset string) (mdconverter.ReverseConfig, error) {
 			LayoutSectionDetection: mdconverter.LayoutSectionDetectPandoc,
 				TableGridDetection:  true,
 		}, nil
+	case presetObsidian:
+		return mdconverter.ReverseConfig{
+			ColorDetection:    mdconverter.ColorDetectObsidian,
+			PanelDetection:    mdconverter.PanelDetectObsidian,
+			ExpandDetection:   mdconverter.ExpandDetectObsidian,
+			WikiLinkDetection: true,
+			CommentDetection:  true,
+		}, nil
+	case presetGitLab:
+		return mdconverter.ReverseConfig{
+			PanelDetection:          mdconverter.PanelDetectGitLab,
+			StatusDetection:         mdconverter.StatusDetectGitLab,
+			MultilineQuoteDetection: true,
+			TOCDetection:            true,
+			MathBlockDetection:      true,
+		}, nil
+	case presetCommonMark:
+		return mdconverter.ReverseConfig{
+			ExpandDetection: mdconverter.ExpandDetectBlockquote,
+		}, nil
 	default:
-		return mdconverter.ReverseConfig{}, fmt.Errorf("unknown preset %q (allowed: balanced, strict, readable, lossy, pandoc)", preset)
+		return mdconverter.ReverseConfig{}, fmt.Errorf("unknown preset %q (allowed: balanced, strict, readable, lossy, pandoc, obsidian, gitlab, commonmark)", preset)
+	}
+}
+
+// applyConfigFile decodes the JSON file at path into cfg, so that the fields it sets
+// replace those of the preset. An empty path leaves cfg unchanged.
+func applyConfigFile(path string, cfg any) error {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
 	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(cfg); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
 }
 
 func resolveReverseConfig(preset string, allowHTML, strict bool) (mdconverter.ReverseConfig, error) {
@@ -191,12 +248,23 @@ func resolveReverseConfig(preset string, allowHTML, strict bool) (mdconverter.Re
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "stats" {
+		os.Exit(runStats(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "pandoc" {
+		os.Exit(runPandoc(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "fmt" {
+		os.Exit(runFmt(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+	}
+
 	reverse := flag.Bool("reverse", false, "Convert Markdown to ADF JSON")
 	allowHTML := flag.Bool("allow-html", false, "Enable HTML output")
 	strict := flag.Bool("strict", false, "Return error on unknown nodes")
-	preset := flag.String("preset", presetBalanced, "Preset: balanced|strict|readable|lossy|pandoc")
+	preset := flag.String("preset", presetBalanced, "Preset: balanced|strict|readable|lossy|pandoc|obsidian|gitlab|commonmark")
+	configFile := flag.String("config", "", "JSON file of config fields applied over the preset and flags")
 	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: jac [options] <input-file>\n")
+		fmt.Fprintf(os.Stderr, "Usage: jac [options] <input-file>\n       jac stats [options] <input-file>\n       jac pandoc [options] <input-file|->\n       jac fmt [options] <input-file|->...\n")
 		flag.PrintDefaults()
 	}
 	flag.Parse()
@@ -220,6 +288,10 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Invalid preset: %v\n", err)
 			os.Exit(1)
 		}
+		if err := applyConfigFile(*configFile, &cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid config file: %v\n", err)
+			os.Exit(1)
+		}
 
 		conv, err := mdconverter.New(cfg)
 		if err != nil {
@@ -252,6 +324,10 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Invalid preset: %v\n", err)
 		os.Exit(1)
 	}
+	if err := applyConfigFile(*configFile, &cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid config file: %v\n", err)
+		os.Exit(1)
+	}
 
 	conv, err := converter.New(cfg)
 	if err != nil {
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/rgonek/jira-adf-converter/pandocjson"
+)
+
+// runPandoc implements `jac pandoc`, converting an ADF file to the Pandoc JSON AST, or a
+// Pandoc JSON file back to ADF with -reverse. An input of "-" reads stdin, so that
+// `jac pandoc page.json | pandoc -f json -o page.docx` and
+// `pandoc -t json page.docx | jac pandoc -reverse -` both work.
+func runPandoc(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("pandoc", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	reverse := fs.Bool("reverse", false, "Convert Pandoc JSON to ADF JSON")
+	strict := fs.Bool("strict", false, "Return error on unknown nodes")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: jac pandoc [options] <input-file|->\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 1
+	}
+
+	var data []byte
+	var err error
+	if fs.Arg(0) == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(fs.Arg(0))
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Error reading file: %v\n", err)
+		return 1
+	}
+
+	cfg := pandocjson.Config{}
+	if *strict {
+		cfg.UnknownNodes = converter.UnknownError
+		cfg.UnknownMarks = converter.UnknownError
+	}
+	conv, err := pandocjson.New(cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "Invalid config: %v\n", err)
+		return 1
+	}
+
+	if !*reverse {
+		result, err := conv.Render(data)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error converting file: %v\n", err)
+			return 1
+		}
+		fmt.Fprintln(stdout, string(result.JSON))
+		return 0
+	}
+
+	result, err := conv.Parse(data)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error converting file: %v\n", err)
+		return 1
+	}
+	pretty, err := json.MarshalIndent(result.Doc, "", "  ")
+	if err != nil {
+		fmt.Fprintf(stderr, "Error formatting ADF JSON: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(stdout, string(pretty))
+	return 0
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunPandocRoundTrip(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "page.adf.json")
+	require.NoError(t, os.WriteFile(input, []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[{"type":"status","attrs":{"text":"Done","color":"green"}}]}
+	]}`), 0o644))
+
+	var pandoc, stderr bytes.Buffer
+	code := runPandoc([]string{input}, nil, &pandoc, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	assert.Contains(t, pandoc.String(), `"pandoc-api-version":[1,23,1]`)
+	assert.Contains(t, pandoc.String(), `["",["status"],[["color","green"]]]`)
+
+	var adf bytes.Buffer
+	code = runPandoc([]string{"-reverse", "-"}, &pandoc, &adf, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	assert.JSONEq(t, `{"version":1,"type":"doc","content":[
+		{"type":"paragraph","content":[{"type":"status","attrs":{"text":"Done","color":"green"}}]}
+	]}`, adf.String())
+}
+
+func TestRunPandocErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 1, runPandoc(nil, nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "Usage: jac pandoc")
+
+	stderr.Reset()
+	assert.Equal(t, 1, runPandoc([]string{"-reverse", "-"}, strings.NewReader(`{"pandoc-api-version":[1,17],"blocks":[]}`), &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "unsupported pandoc-api-version")
+
+	stderr.Reset()
+	assert.Equal(t, 1, runPandoc([]string{"-strict", "-"}, strings.NewReader(`{"type":"doc","content":[{"type":"mystery"}]}`), &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "unknown node type: mystery")
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/rgonek/jira-adf-converter/converter"
@@ -65,12 +67,35 @@ func TestPresetConfig(t *testing.T) {
 		assert.Equal(t, converter.InlineCardPandoc, cfg.InlineCardStyle)
 		assert.Equal(t, converter.TableAutoPandoc, cfg.TableMode)
 	})
+
+	t.Run("obsidian", func(t *testing.T) {
+		cfg, err := presetConfig(presetObsidian)
+		require.NoError(t, err)
+		assert.Equal(t, converter.ColorObsidian, cfg.BackgroundColorStyle)
+		assert.Equal(t, converter.PanelObsidian, cfg.PanelStyle)
+		assert.Equal(t, converter.ExpandObsidian, cfg.ExpandStyle)
+		assert.True(t, cfg.WikiLinks)
+	})
+
+	t.Run("gitlab", func(t *testing.T) {
+		cfg, err := presetConfig(presetGitLab)
+		require.NoError(t, err)
+		assert.Equal(t, converter.PanelGitLab, cfg.PanelStyle)
+		assert.Equal(t, converter.StatusGitLab, cfg.StatusStyle)
+		assert.Equal(t, converter.TOCGitLab, cfg.TOCStyle)
+	})
+
+	t.Run("commonmark", func(t *testing.T) {
+		cfg, err := presetConfig(presetCommonMark)
+		require.NoError(t, err)
+		assert.True(t, cfg.CommonMark)
+	})
 }
 
 func TestPresetConfigInvalid(t *testing.T) {
 	_, err := presetConfig("unknown")
 	require.Error(t, err)
-	assert.Equal(t, `unknown preset "unknown" (allowed: balanced, strict, readable, lossy, pandoc)`, err.Error())
+	assert.Equal(t, `unknown preset "unknown" (allowed: balanced, strict, readable, lossy, pandoc, obsidian, gitlab, commonmark)`, err.Error())
 }
 
 func TestResolveConfigPresetPrecedence(t *testing.T) {
@@ -140,12 +165,38 @@ func TestReversePresetConfig(t *testing.T) {
 		assert.Equal(t, mdconverter.InlineCardDetectPandoc, cfg.InlineCardDetection)
 		assert.True(t, cfg.TableGridDetection)
 	})
+
+	t.Run("obsidian", func(t *testing.T) {
+		cfg, err := reversePresetConfig(presetObsidian)
+		require.NoError(t, err)
+		assert.Equal(t, mdconverter.ColorDetectObsidian, cfg.ColorDetection)
+		assert.Equal(t, mdconverter.PanelDetectObsidian, cfg.PanelDetection)
+		assert.Equal(t, mdconverter.ExpandDetectObsidian, cfg.ExpandDetection)
+		assert.True(t, cfg.WikiLinkDetection)
+		assert.True(t, cfg.CommentDetection)
+	})
+
+	t.Run("gitlab", func(t *testing.T) {
+		cfg, err := reversePresetConfig(presetGitLab)
+		require.NoError(t, err)
+		assert.Equal(t, mdconverter.PanelDetectGitLab, cfg.PanelDetection)
+		assert.Equal(t, mdconverter.StatusDetectGitLab, cfg.StatusDetection)
+		assert.True(t, cfg.MultilineQuoteDetection)
+		assert.True(t, cfg.TOCDetection)
+		assert.True(t, cfg.MathBlockDetection)
+	})
+
+	t.Run("commonmark", func(t *testing.T) {
+		cfg, err := reversePresetConfig(presetCommonMark)
+		require.NoError(t, err)
+		assert.Equal(t, mdconverter.ExpandDetectBlockquote, cfg.ExpandDetection)
+	})
 }
 
 func TestReversePresetConfigInvalid(t *testing.T) {
 	_, err := reversePresetConfig("unknown")
 	require.Error(t, err)
-	assert.Equal(t, `unknown preset "unknown" (allowed: balanced, strict, readable, lossy, pandoc)`, err.Error())
+	assert.Equal(t, `unknown preset "unknown" (allowed: balanced, strict, readable, lossy, pandoc, obsidian, gitlab, commonmark)`, err.Error())
 }
 
 func TestResolveReverseConfigPresetPrecedence(t *testing.T) {
@@ -165,3 +216,21 @@ func TestResolveReverseConfigPresetPrecedence(t *testing.T) {
 	assert.Equal(t, mdconverter.InlineCardDetectLink, cfg.InlineCardDetection)
 	assert.Equal(t, mdconverter.DecisionDetectEmoji, cfg.DecisionDetection)
 }
+
+func TestApplyConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"statusStyle":"text","templates":{"status":"`+"`{{.Text}}`"+`"}}`), 0o644))
+
+	cfg, err := resolveConfig(presetReadable, false, false)
+	require.NoError(t, err)
+	require.NoError(t, applyConfigFile(path, &cfg))
+
+	assert.Equal(t, converter.StatusText, cfg.StatusStyle)
+	assert.Equal(t, converter.MentionText, cfg.MentionStyle)
+	assert.Equal(t, map[string]string{"status": "`{{.Text}}`"}, cfg.Templates)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"statusStyles":"text"}`), 0o644))
+	err = applyConfigFile(path, &cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown field "statusStyles"`)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// runStats implements `jac stats`, printing the outline and statistics of an ADF file as JSON.
+func runStats(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	headingOffset := fs.Int("heading-offset", 0, "Shift outline heading levels (same as Config.HeadingOffset)")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: jac stats [options] <input-file>\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 1
+	}
+
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "Error reading file: %v\n", err)
+		return 1
+	}
+
+	var doc converter.Doc
+	if err := json.Unmarshal(data, &doc); err != nil {
+		fmt.Fprintf(stderr, "Error parsing ADF JSON: %v\n", err)
+		return 1
+	}
+
+	conv, err := converter.New(converter.Config{HeadingOffset: *headingOffset})
+	if err != nil {
+		fmt.Fprintf(stderr, "Invalid config: %v\n", err)
+		return 1
+	}
+
+	pretty, err := json.MarshalIndent(conv.Analyze(doc), "", "  ")
+	if err != nil {
+		fmt.Fprintf(stderr, "Error formatting stats JSON: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(stdout, string(pretty))
+	return 0
+}
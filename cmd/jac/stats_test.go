@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunStats(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "page.adf.json")
+	require.NoError(t, os.WriteFile(input, []byte(`{"type":"doc","content":[
+		{"type":"heading","attrs":{"level":1},"content":[{"type":"text","text":"Title"}]},
+		{"type":"codeBlock","attrs":{"language":"sql"},"content":[{"type":"text","text":"select 1"}]}
+	]}`), 0o644))
+
+	var stdout, stderr bytes.Buffer
+	code := runStats([]string{"-heading-offset", "1", input}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+
+	var analysis converter.Analysis
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &analysis))
+	require.Len(t, analysis.Outline, 1)
+	assert.Equal(t, 2, analysis.Outline[0].Level)
+	assert.Equal(t, "Title", analysis.Outline[0].Text)
+	assert.Equal(t, map[string]int{"sql": 1}, analysis.Stats.CodeBlocksByLanguage)
+	assert.Equal(t, 3, analysis.Stats.Words)
+}
+
+func TestRunStatsErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 1, runStats(nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "Usage: jac stats")
+
+	stderr.Reset()
+	input := filepath.Join(t.TempDir(), "bad.json")
+	require.NoError(t, os.WriteFile(input, []byte(`not json`), 0o644))
+	assert.Equal(t, 1, runStats([]string{input}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "Error parsing ADF JSON")
+
+	stderr.Reset()
+	require.NoError(t, os.WriteFile(input, []byte(`{"type":"doc"}`), 0o644))
+	assert.Equal(t, 1, runStats([]string{"-heading-offset", "9", input}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "Invalid config")
+}
@@ -0,0 +1,94 @@
+package converter
+
+import (
+	"strings"
+)
+
+// admonitionKeywords maps panel types to the admonition types of MkDocs Material and the
+// Hugo admonition shortcode, which share a vocabulary. Docusaurus has no success type and
+// uses tip instead.
+var admonitionKeywords = map[string]string{
+	"info":    "info",
+	"note":    "note",
+	"success": "success",
+	"warning": "warning",
+	"error":   "danger",
+}
+
+// admonitionKeyword returns the admonition type for a panel type in the given style.
+// Panels without a known type become notes.
+func admonitionKeyword(style PanelStyle, panelType string) string {
+	keyword, ok := admonitionKeywords[panelType]
+	if !ok {
+		return "note"
+	}
+	if style == PanelDocusaurus && keyword == "success" {
+		return "tip"
+	}
+	return keyword
+}
+
+// renderMkDocsAdmonition renders an MkDocs Material admonition. The marker is "!!!" for
+// panels and "???" for collapsed blocks; the content is indented four spaces.
+func renderMkDocsAdmonition(marker, keyword, title, content string) string {
+	var sb strings.Builder
+	sb.WriteString(marker + " " + keyword)
+	if title != "" {
+		sb.WriteString(` "` + title + `"`)
+	}
+	sb.WriteString("\n")
+
+	content = strings.TrimRight(content, "\n")
+	if content != "" {
+		for _, line := range strings.Split(content, "\n") {
+			if line != "" {
+				sb.WriteString("    " + line)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// renderDocusaurusAdmonition renders a Docusaurus admonition. Its fence is one colon
+// longer than any fence in the content, so nested admonitions close correctly.
+func renderDocusaurusAdmonition(keyword, title, content string) string {
+	longest := 0
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+		run := len(trimmed) - len(strings.TrimLeft(trimmed, ":"))
+		longest = max(longest, run)
+	}
+	fence := strings.Repeat(":", max(3, longest+1))
+
+	opening := fence + keyword
+	if title != "" {
+		opening += " " + title
+	}
+	content = strings.TrimRight(content, "\n")
+	if content == "" {
+		return opening + "\n" + fence + "\n\n"
+	}
+	return opening + "\n\n" + content + "\n\n" + fence + "\n\n"
+}
+
+// renderHugoShortcode renders a paired Hugo shortcode: the admonition shortcode for
+// panels and Hugo's built-in details shortcode for expands.
+func renderHugoShortcode(name, params, content string) string {
+	var sb strings.Builder
+	sb.WriteString("{{< " + name + params + " >}}\n")
+	if content = strings.TrimRight(content, "\n"); content != "" {
+		sb.WriteString(content + "\n")
+	}
+	sb.WriteString("{{< /" + name + " >}}\n\n")
+	return sb.String()
+}
+
+// hugoParam renders a named shortcode parameter, or nothing for an empty value.
+func hugoParam(name, value string) string {
+	if value == "" {
+		return ""
+	}
+	return " " + name + `="` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
@@ -0,0 +1,52 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var admonitionTestDoc = []byte(`{"type":"doc","content":[
+	{"type":"panel","attrs":{"panelType":"success","title":"Done"},"content":[
+		{"type":"paragraph","content":[{"type":"text","text":"Shipped"}]},
+		{"type":"bulletList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"item"}]}]}]}
+	]},
+	{"type":"panel","attrs":{"panelType":"error"},"content":[{"type":"paragraph","content":[{"type":"text","text":"Broken"}]}]},
+	{"type":"expand","attrs":{"title":"More \"info\""},"content":[{"type":"paragraph","content":[{"type":"text","text":"hidden"}]}]}
+]}`)
+
+func TestConvertPanelsAndExpandsAsMkDocsAdmonitions(t *testing.T) {
+	result, err := newTestConverter(t, Config{PanelStyle: PanelMkDocs, ExpandStyle: ExpandMkDocs}).Convert(admonitionTestDoc)
+	require.NoError(t, err)
+	assert.Equal(t, "!!! success \"Done\"\n    Shipped\n\n    - item\n\n"+
+		"!!! danger\n    Broken\n\n"+
+		"??? note \"More \"info\"\"\n    hidden\n", result.Markdown)
+}
+
+func TestConvertPanelsAndExpandsAsDocusaurusAdmonitions(t *testing.T) {
+	result, err := newTestConverter(t, Config{PanelStyle: PanelDocusaurus, ExpandStyle: ExpandDocusaurus}).Convert(admonitionTestDoc)
+	require.NoError(t, err)
+	assert.Equal(t, ":::tip Done\n\nShipped\n\n- item\n\n:::\n\n"+
+		":::danger\n\nBroken\n\n:::\n\n"+
+		"<details><summary>More &#34;info&#34;</summary>\n\nhidden\n\n</details>\n", result.Markdown)
+}
+
+func TestConvertPanelsAndExpandsAsHugoShortcodes(t *testing.T) {
+	result, err := newTestConverter(t, Config{PanelStyle: PanelHugo, ExpandStyle: ExpandHugo}).Convert(admonitionTestDoc)
+	require.NoError(t, err)
+	assert.Equal(t, "{{< admonition type=\"success\" title=\"Done\" >}}\nShipped\n\n- item\n{{< /admonition >}}\n\n"+
+		"{{< admonition type=\"danger\" >}}\nBroken\n{{< /admonition >}}\n\n"+
+		"{{< details summary=\"More \\\"info\\\"\" >}}\nhidden\n{{< /details >}}\n", result.Markdown)
+}
+
+func TestConvertNestedDocusaurusAdmonitionsLengthenOuterFence(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"panel","attrs":{"panelType":"warning"},"content":[
+			{"type":"panel","attrs":{"panelType":"info","title":"Inner"},"content":[{"type":"paragraph","content":[{"type":"text","text":"deep"}]}]}
+		]}
+	]}`)
+	result, err := newTestConverter(t, Config{PanelStyle: PanelDocusaurus}).Convert(input)
+	require.NoError(t, err)
+	assert.Equal(t, "::::warning\n\n:::info Inner\n\ndeep\n\n:::\n\n::::\n", result.Markdown)
+}
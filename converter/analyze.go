@@ -0,0 +1,178 @@
+package converter
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	unspecifiedLanguage  = "unspecified"
+	unspecifiedPanelType = "unspecified"
+)
+
+// Analysis is a structured summary of an ADF document.
+type Analysis struct {
+	Outline []OutlineHeading `json:"outline,omitempty"`
+	Stats   DocumentStats    `json:"stats"`
+}
+
+// OutlineHeading is a heading in the document outline. Level is the Markdown heading
+// level after Config.HeadingOffset is applied; Children holds the deeper headings that
+// follow it.
+type OutlineHeading struct {
+	Level    int              `json:"level"`
+	Text     string           `json:"text"`
+	Path     string           `json:"path"`
+	Children []OutlineHeading `json:"children,omitempty"`
+}
+
+// DocumentStats counts content in an ADF document. Code blocks without a language and
+// panels without a panelType are counted under "unspecified".
+type DocumentStats struct {
+	Headings             int            `json:"headings"`
+	Tables               int            `json:"tables"`
+	CodeBlocks           int            `json:"codeBlocks"`
+	CodeBlocksByLanguage map[string]int `json:"codeBlocksByLanguage,omitempty"`
+	TasksDone            int            `json:"tasksDone"`
+	TasksOpen            int            `json:"tasksOpen"`
+	Panels               int            `json:"panels"`
+	PanelsByType         map[string]int `json:"panelsByType,omitempty"`
+	Words                int            `json:"words"`
+	Characters           int            `json:"characters"`
+}
+
+// analyzer accumulates an Analysis over a single walk of the document.
+type analyzer struct {
+	headingOffset int
+	stats         DocumentStats
+	headings      []OutlineHeading
+	text          strings.Builder
+}
+
+// Analyze returns the heading outline and content statistics of doc.
+// Heading levels follow the converter's HeadingOffset, clamped to 1-6 as in Markdown output.
+func (c *Converter) Analyze(doc Doc) Analysis {
+	a := &analyzer{headingOffset: c.config.HeadingOffset}
+	a.walk(doc.Content, "")
+
+	a.stats.Words = len(strings.Fields(a.text.String()))
+
+	return Analysis{
+		Outline: buildOutline(a.headings),
+		Stats:   a.stats,
+	}
+}
+
+func (a *analyzer) walk(content []Node, parentPath string) {
+	for index, node := range content {
+		nodePath := parentPath + "/content/" + strconv.Itoa(index)
+		a.visit(node, nodePath)
+	}
+}
+
+func (a *analyzer) visit(node Node, nodePath string) {
+	switch node.Type {
+	case "heading":
+		a.stats.Headings++
+		a.headings = append(a.headings, OutlineHeading{
+			Level: a.outlineLevel(node),
+			Text:  strings.TrimSpace(plainText(node.Content)),
+			Path:  nodePath,
+		})
+	case "table":
+		a.stats.Tables++
+	case "codeBlock":
+		a.stats.CodeBlocks++
+		language := node.GetStringAttr("language", "")
+		if language == "" {
+			language = unspecifiedLanguage
+		}
+		a.stats.CodeBlocksByLanguage = incrementCount(a.stats.CodeBlocksByLanguage, language)
+	case "taskItem":
+		if strings.EqualFold(node.GetStringAttr("state", "TODO"), "DONE") {
+			a.stats.TasksDone++
+		} else {
+			a.stats.TasksOpen++
+		}
+	case "panel":
+		a.stats.Panels++
+		panelType := strings.ToLower(node.GetStringAttr("panelType", ""))
+		if panelType == "" {
+			panelType = unspecifiedPanelType
+		}
+		a.stats.PanelsByType = incrementCount(a.stats.PanelsByType, panelType)
+	}
+
+	if text := inlineNodeText(node); text != "" {
+		a.stats.Characters += utf8.RuneCountInString(text)
+		a.text.WriteString(text)
+	}
+
+	a.walk(node.Content, nodePath)
+
+	// Container and hardBreak boundaries separate words; adjacent text nodes (e.g.
+	// differently marked runs of one word) do not.
+	if len(node.Content) > 0 || node.Type == "hardBreak" {
+		a.text.WriteByte('\n')
+	}
+}
+
+func (a *analyzer) outlineLevel(node Node) int {
+	level := headingLevel(node) + a.headingOffset
+	if level < 1 {
+		level = 1
+	}
+	if level > 6 {
+		level = 6
+	}
+	return level
+}
+
+// buildOutline nests a flat, document-ordered heading list by level.
+func buildOutline(headings []OutlineHeading) []OutlineHeading {
+	var build func(start, parentLevel int) ([]OutlineHeading, int)
+	build = func(start, parentLevel int) ([]OutlineHeading, int) {
+		var result []OutlineHeading
+		index := start
+		for index < len(headings) && headings[index].Level > parentLevel {
+			heading := headings[index]
+			heading.Children, index = build(index+1, heading.Level)
+			result = append(result, heading)
+		}
+		return result, index
+	}
+
+	outline, _ := build(0, 0)
+	return outline
+}
+
+// plainText returns the concatenated text of inline content.
+func plainText(content []Node) string {
+	var sb strings.Builder
+	for _, node := range content {
+		sb.WriteString(inlineNodeText(node))
+		sb.WriteString(plainText(node.Content))
+	}
+	return sb.String()
+}
+
+// inlineNodeText returns the visible text carried by a single node.
+func inlineNodeText(node Node) string {
+	switch node.Type {
+	case "text":
+		return node.Text
+	case "mention", "status":
+		return node.GetStringAttr("text", "")
+	default:
+		return ""
+	}
+}
+
+func incrementCount(counts map[string]int, key string) map[string]int {
+	if counts == nil {
+		counts = make(map[string]int)
+	}
+	counts[key]++
+	return counts
+}
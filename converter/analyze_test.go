@@ -0,0 +1,79 @@
+package converter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeBuildsOutlineAndStats(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"heading","attrs":{"level":1},"content":[{"type":"text","text":"Overview"}]},
+		{"type":"paragraph","content":[{"type":"text","text":"Hello "},{"type":"text","text":"wor","marks":[{"type":"strong"}]},{"type":"text","text":"ld, "},{"type":"mention","attrs":{"id":"u1","text":"@Ada"}}]},
+		{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Setup "},{"type":"text","text":"steps","marks":[{"type":"em"}]}]},
+		{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"fmt.Println()"}]},
+		{"type":"codeBlock","content":[{"type":"text","text":"echo hi"}]},
+		{"type":"heading","attrs":{"level":3},"content":[{"type":"text","text":"Details"}]},
+		{"type":"taskList","content":[
+			{"type":"taskItem","attrs":{"state":"DONE"},"content":[{"type":"text","text":"one"}]},
+			{"type":"taskItem","attrs":{"state":"TODO"},"content":[{"type":"text","text":"two"}]},
+			{"type":"taskItem","content":[{"type":"text","text":"three"}]}
+		]},
+		{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Reference"}]},
+		{"type":"panel","attrs":{"panelType":"warning"},"content":[{"type":"paragraph","content":[{"type":"text","text":"careful"}]}]},
+		{"type":"panel","content":[{"type":"paragraph","content":[{"type":"text","text":"note"}]}]},
+		{"type":"table","content":[{"type":"tableRow","content":[{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"cell"}]}]}]}]}
+	]}`)
+
+	var doc Doc
+	require.NoError(t, json.Unmarshal(input, &doc))
+
+	analysis := newTestConverter(t, Config{HeadingOffset: 1}).Analyze(doc)
+
+	assert.Equal(t, []OutlineHeading{
+		{
+			Level: 2, Text: "Overview", Path: "/content/0",
+			Children: []OutlineHeading{
+				{
+					Level: 3, Text: "Setup steps", Path: "/content/2",
+					Children: []OutlineHeading{{Level: 4, Text: "Details", Path: "/content/5"}},
+				},
+				{Level: 3, Text: "Reference", Path: "/content/7"},
+			},
+		},
+	}, analysis.Outline)
+
+	stats := analysis.Stats
+	assert.Equal(t, 4, stats.Headings)
+	assert.Equal(t, 1, stats.Tables)
+	assert.Equal(t, 2, stats.CodeBlocks)
+	assert.Equal(t, map[string]int{"go": 1, "unspecified": 1}, stats.CodeBlocksByLanguage)
+	assert.Equal(t, 1, stats.TasksDone)
+	assert.Equal(t, 2, stats.TasksOpen)
+	assert.Equal(t, 2, stats.Panels)
+	assert.Equal(t, map[string]int{"warning": 1, "unspecified": 1}, stats.PanelsByType)
+
+	// "wor" + "ld," is one word; text from every block counts.
+	words := []string{"Overview", "Hello", "world,", "@Ada", "Setup", "steps", "fmt.Println()", "echo", "hi",
+		"Details", "one", "two", "three", "Reference", "careful", "note", "cell"}
+	assert.Equal(t, len(words), stats.Words)
+	assert.Equal(t, len("OverviewHello world, @AdaSetup stepsfmt.Println()echo hiDetailsonetwothreeReferencecarefulnotecell"), stats.Characters)
+}
+
+func TestAnalyzeClampsOutlineLevels(t *testing.T) {
+	doc := Doc{Type: "doc", Content: []Node{
+		{Type: "heading", Attrs: map[string]interface{}{"level": float64(3)}},
+		{Type: "heading", Attrs: map[string]interface{}{"level": float64(1)}},
+		{Type: "heading", Attrs: map[string]interface{}{"level": float64(6)}},
+	}}
+
+	analysis := newTestConverter(t, Config{HeadingOffset: 2}).Analyze(doc)
+
+	require.Len(t, analysis.Outline, 2)
+	assert.Equal(t, 5, analysis.Outline[0].Level)
+	assert.Equal(t, 3, analysis.Outline[1].Level)
+	require.Len(t, analysis.Outline[1].Children, 1)
+	assert.Equal(t, 6, analysis.Outline[1].Children[0].Level)
+}
@@ -0,0 +1,988 @@
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// asciidocBlockStartRe matches line starts that AsciiDoc would parse as block syntax:
+	// section titles, list markers, delimiters, attribute entries, block attributes,
+	// admonition labels and literal (indented) lines.
+	asciidocBlockStartRe = regexp.MustCompile(`^(?:[=*.\-/'<:+_>#|\[` + "`" + `]|\s|\d+\.\s|[a-zA-Z]\.\s|(?:NOTE|TIP|IMPORTANT|WARNING|CAUTION):)`)
+	asciidocAttrRefRe    = regexp.MustCompile(`^\{[A-Za-z0-9_][A-Za-z0-9_-]*\}`)
+)
+
+// asciidocAdmonitions maps panel types to AsciiDoc admonition labels.
+var asciidocAdmonitions = map[string]string{
+	"info":    "NOTE",
+	"note":    "NOTE",
+	"success": "TIP",
+	"warning": "WARNING",
+	"error":   "CAUTION",
+}
+
+// asciidocColorRoles are the color names with built-in Asciidoctor roles; each also has
+// a "-background" role.
+var asciidocColorRoles = map[string]bool{
+	"aqua": true, "black": true, "blue": true, "fuchsia": true, "gray": true, "green": true,
+	"lime": true, "maroon": true, "navy": true, "olive": true, "purple": true, "red": true,
+	"silver": true, "teal": true, "white": true, "yellow": true,
+}
+
+// asciidocStatusRoles maps status colors to the color role of the rendered status.
+var asciidocStatusRoles = map[string]string{
+	"purple": "purple",
+	"blue":   "blue",
+	"red":    "red",
+	"yellow": "olive",
+	"green":  "green",
+}
+
+// AsciiDocResult holds the output of an AsciiDoc conversion.
+type AsciiDocResult struct {
+	AsciiDoc string    `json:"asciidoc"`
+	Warnings []Warning `json:"warnings,omitempty"`
+	// CacheStats reports HookCache hits and misses; it is zero when no cache is configured.
+	CacheStats HookCacheStats `json:"cacheStats,omitzero"`
+	// References lists links, media, mentions, inlineCards, emoji and extensions of the input.
+	References References `json:"references,omitzero"`
+}
+
+// asciidocFormat renders AsciiDoc.
+type asciidocFormat struct {
+	*state
+	// depth is the nesting depth of delimited blocks; nested blocks use longer delimiters.
+	depth int
+	// listDepth is the nesting depth of lists; markers repeat once per level.
+	listDepth int
+	// inTable is set while rendering table cells, where "|" starts a new cell.
+	inTable bool
+}
+
+// ConvertAsciiDoc takes an ADF JSON document and returns AsciiDoc.
+func (c *Converter) ConvertAsciiDoc(input []byte) (AsciiDocResult, error) {
+	return c.ConvertAsciiDocWithContext(context.Background(), input, ConvertOptions{})
+}
+
+// ConvertAsciiDocWithContext takes an ADF JSON document and returns AsciiDoc as read by
+// Asciidoctor and Antora. It uses the same node dispatch, link and media hooks, batch
+// resolution and hook cache as Markdown output. Panels become admonitions, expands
+// collapsible blocks and tables keep their spans and column widths. Extension handlers
+// are not invoked because they produce Markdown. Content that AsciiDoc cannot express
+// is approximated and reported as a warning.
+func (c *Converter) ConvertAsciiDocWithContext(ctx context.Context, input []byte, opts ConvertOptions) (AsciiDocResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := ctx.Err(); err != nil {
+		return AsciiDocResult{}, err
+	}
+
+	var doc Doc
+	if err := json.Unmarshal(input, &doc); err != nil {
+		return AsciiDocResult{}, fmt.Errorf("failed to parse ADF JSON: %w", err)
+	}
+
+	s := &state{
+		config:     c.config,
+		ctx:        ctx,
+		options:    opts,
+		cacheStats: &hookCacheCounter{},
+	}
+	s.format = &asciidocFormat{state: s}
+	if err := s.resolveBatch(doc.Content); err != nil {
+		return AsciiDocResult{}, err
+	}
+
+	s.references.addContent(doc.Content, "")
+	output, err := s.convertNode(Node{Type: "doc", Content: doc.Content})
+	if err != nil {
+		return AsciiDocResult{}, err
+	}
+	if err := s.checkContext(); err != nil {
+		return AsciiDocResult{}, err
+	}
+
+	return AsciiDocResult{
+		AsciiDoc:   output,
+		Warnings:   s.warnings,
+		CacheStats: s.cacheStats.stats(),
+		References: s.references.references(),
+	}, nil
+}
+
+// renderNode renders node types whose AsciiDoc form differs from Markdown.
+// It reports false for types that share the Markdown rendering path.
+func (a *asciidocFormat) renderNode(node Node) (string, bool, error) {
+	switch node.Type {
+	case "text":
+		return a.escapeAsciiDocText(node.Text), true, nil
+
+	case "paragraph":
+		content, err := a.convertInlineContent(node.Content)
+		if err != nil || strings.TrimSpace(content) == "" {
+			return "", true, err
+		}
+		return a.asciidocAlignment(node) + guardAsciiDocLines(content, true) + "\n\n", true, nil
+
+	case "heading":
+		level := min(max(headingLevel(node)+a.config.HeadingOffset, 1), 5)
+		content, err := a.convertInlineContent(node.Content)
+		if err != nil || strings.TrimSpace(content) == "" {
+			return "", true, err
+		}
+		return a.asciidocAlignment(node) + strings.Repeat("=", level+1) + " " + strings.ReplaceAll(content, " +\n", " ") + "\n\n", true, nil
+
+	case "blockquote":
+		content, err := a.convertAsciiDocDelimited("", "_", node.Content)
+		return content, true, err
+
+	case "rule":
+		return "'''\n\n", true, nil
+
+	case "hardBreak":
+		return " +\n", true, nil
+
+	case "codeBlock":
+		return a.convertAsciiDocCodeBlock(node), true, nil
+
+	case "bulletList", "orderedList", "taskList", "decisionList":
+		content, err := a.convertAsciiDocList(node)
+		return content, true, err
+
+	case "listItem", "taskItem", "decisionItem":
+		content, err := a.convertAsciiDocListItem(node, "*")
+		return content, true, err
+
+	case "table":
+		content, err := a.convertAsciiDocTable(node)
+		return content, true, err
+
+	case "panel":
+		content, err := a.convertAsciiDocPanel(node)
+		return content, true, err
+
+	case "expand", "nestedExpand":
+		prefix := "[%collapsible]\n"
+		if title := node.GetStringAttr("title", ""); title != "" {
+			prefix = "." + a.escapeAsciiDocText(title) + "\n" + prefix
+		}
+		content, err := a.convertAsciiDocDelimited(prefix, "=", node.Content)
+		return content, true, err
+
+	case "layoutSection":
+		a.addWarning(WarningDroppedFeature, node.Type, "layout columns rendered sequentially; AsciiDoc has no column layout")
+		content, err := a.convertChildren(node.Content)
+		return content, true, err
+
+	case "layoutColumn":
+		content, err := a.convertChildren(node.Content)
+		return content, true, err
+
+	case "mediaSingle":
+		content, err := a.convertAsciiDocMediaSingle(node)
+		return content, true, err
+
+	case "mediaGroup":
+		items := make([]string, 0, len(node.Content))
+		for _, child := range node.Content {
+			item, err := a.convertNode(child)
+			if err != nil {
+				return "", true, err
+			}
+			if item != "" {
+				items = append(items, item)
+			}
+		}
+		if len(items) == 0 {
+			return "", true, nil
+		}
+		return guardAsciiDocLines(strings.Join(items, " "), true) + "\n\n", true, nil
+
+	case "media":
+		content, err := a.convertAsciiDocMedia(node, false)
+		return content, true, err
+
+	case "mention":
+		return a.convertAsciiDocMention(node), true, nil
+
+	case "status":
+		text := a.escapeAsciiDocText(node.GetStringAttr("text", "Unknown"))
+		if a.config.StatusStyle == StatusText {
+			return text, true, nil
+		}
+		if role, ok := asciidocStatusRoles[strings.ToLower(node.GetStringAttr("color", ""))]; ok {
+			return "[." + role + "]##**" + text + "**##", true, nil
+		}
+		return "**" + text + "**", true, nil
+
+	case "emoji":
+		content, err := a.convertEmoji(node)
+		return a.escapeAsciiDocText(content), true, err
+
+	case "date":
+		content, err := a.convertDate(node)
+		return a.escapeAsciiDocText(content), true, err
+
+	case "inlineCard":
+		content, err := a.convertAsciiDocInlineCard(node)
+		return content, true, err
+
+	case "extension", "inlineExtension", "bodiedExtension":
+		content, err := a.convertAsciiDocExtension(node)
+		return content, true, err
+	}
+
+	return "", false, nil
+}
+
+// asciidocAlignment returns the role line that aligns a paragraph or heading.
+func (a *asciidocFormat) asciidocAlignment(node Node) string {
+	switch a.getNodeAlignment(node) {
+	case "center":
+		return "[.text-center]\n"
+	case "right":
+		return "[.text-right]\n"
+	default:
+		return ""
+	}
+}
+
+// convertAsciiDocDelimited renders content inside a delimited block such as "____" or
+// "====". Nested blocks use longer delimiters so they cannot close their parent.
+func (a *asciidocFormat) convertAsciiDocDelimited(prefix, char string, content []Node) (string, error) {
+	delimiter := strings.Repeat(char, 4+a.depth)
+	a.depth++
+	body, err := a.convertChildren(content)
+	a.depth--
+	if err != nil || strings.TrimSpace(body) == "" {
+		return "", err
+	}
+	return prefix + delimiter + "\n" + strings.TrimRight(body, "\n") + "\n" + delimiter + "\n\n", nil
+}
+
+func (a *asciidocFormat) convertAsciiDocCodeBlock(node Node) string {
+	var sb strings.Builder
+	for _, child := range node.Content {
+		sb.WriteString(child.Text)
+	}
+	code := strings.TrimRight(sb.String(), "\n")
+
+	language := node.GetStringAttr("language", "")
+	if mapped, ok := a.config.LanguageMap[language]; ok {
+		language = mapped
+	}
+	language = asciidocAttrValue(language)
+
+	// The delimiter must be longer than any line of dashes in the code.
+	delimiter := "----"
+	for _, line := range strings.Split(code, "\n") {
+		if strings.Trim(line, "-") == "" && len(line) >= len(delimiter) {
+			delimiter = strings.Repeat("-", len(line)+1)
+		}
+	}
+
+	opening := "[source]\n"
+	if language != "" {
+		opening = "[source," + language + "]\n"
+	}
+	return opening + delimiter + "\n" + code + "\n" + delimiter + "\n\n"
+}
+
+// convertAsciiDocList renders bullet, ordered, task and decision lists. Nested lists
+// repeat the marker once per level; task lists become checklists.
+func (a *asciidocFormat) convertAsciiDocList(node Node) (string, error) {
+	a.listDepth++
+	defer func() { a.listDepth-- }()
+
+	marker := strings.Repeat("*", a.listDepth)
+	prefix := ""
+	if node.Type == "orderedList" {
+		marker = strings.Repeat(".", a.listDepth)
+		if order := node.GetIntAttr("order", 1); order != 1 {
+			prefix = "[start=" + strconv.Itoa(order) + "]\n"
+		}
+	}
+
+	var sb strings.Builder
+	for _, item := range node.Content {
+		if err := a.checkContext(); err != nil {
+			return "", err
+		}
+		if item.Type == "taskList" {
+			nested, err := a.convertAsciiDocList(item)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(nested)
+			continue
+		}
+		content, err := a.convertAsciiDocListItem(item, marker)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(content)
+	}
+	if sb.Len() == 0 {
+		return "", nil
+	}
+
+	content := prefix + sb.String()
+	if a.listDepth == 1 {
+		content += "\n"
+	}
+	return content, nil
+}
+
+// convertAsciiDocListItem renders a list item. The first paragraph goes on the marker
+// line; later blocks are attached with list continuations and nested lists follow.
+func (a *asciidocFormat) convertAsciiDocListItem(item Node, marker string) (string, error) {
+	switch item.Type {
+	case "taskItem":
+		content, err := a.convertInlineContent(item.Content)
+		if err != nil {
+			return "", err
+		}
+		box := "[ ]"
+		if item.GetStringAttr("state", "") == "DONE" {
+			box = "[x]"
+		}
+		return marker + " " + box + " " + guardAsciiDocLines(content, false) + "\n", nil
+
+	case "decisionItem":
+		content, err := a.convertInlineContent(item.Content)
+		if err != nil {
+			return "", err
+		}
+		return marker + " " + a.decisionPrefix(item.GetStringAttr("state", "")) + guardAsciiDocLines(content, false) + "\n", nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(marker + " ")
+	started := false
+	for _, child := range item.Content {
+		switch child.Type {
+		case "bulletList", "orderedList", "taskList", "decisionList":
+			if !started {
+				sb.WriteString("{empty}\n")
+				started = true
+			}
+			content, err := a.convertAsciiDocList(child)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(content)
+			continue
+		case "paragraph":
+			if !started {
+				content, err := a.convertInlineContent(child.Content)
+				if err != nil {
+					return "", err
+				}
+				sb.WriteString(guardAsciiDocLines(content, false) + "\n")
+				started = true
+				continue
+			}
+		}
+
+		content, err := a.convertNode(child)
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+		if !started {
+			sb.WriteString("{empty}\n")
+			started = true
+		}
+		sb.WriteString("+\n" + strings.TrimRight(content, "\n") + "\n")
+	}
+	if !started {
+		sb.WriteString("{empty}\n")
+	}
+	return sb.String(), nil
+}
+
+// convertAsciiDocTable renders a table with one cell per line. Column widths come from
+// the first row's colwidth attrs, a leading row of header cells becomes the header row
+// and other header cells use the "h" style. Cells holding more than paragraphs use the
+// "a" style so lists and code blocks keep their formatting.
+func (a *asciidocFormat) convertAsciiDocTable(node Node) (string, error) {
+	var rows []Node
+	for _, row := range node.Content {
+		if row.Type == "tableRow" && len(row.Content) > 0 {
+			rows = append(rows, row)
+		}
+	}
+	if len(rows) == 0 {
+		return "", nil
+	}
+
+	var widths []string
+	knownWidths := true
+	for _, cell := range rows[0].Content {
+		colspan := max(cell.GetIntAttr("colspan", 1), 1)
+		colwidth, _ := cell.Attrs["colwidth"].([]interface{})
+		for i := 0; i < colspan; i++ {
+			width := 0.0
+			if i < len(colwidth) {
+				width, _ = colwidth[i].(float64)
+			}
+			if width <= 0 {
+				knownWidths = false
+			}
+			widths = append(widths, strconv.Itoa(max(int(width), 1)))
+		}
+	}
+	cols := strconv.Itoa(len(widths)) + "*"
+	if knownWidths {
+		cols = strings.Join(widths, ",")
+	}
+
+	headerRow := true
+	for _, cell := range rows[0].Content {
+		if cell.Type != "tableHeader" {
+			headerRow = false
+		}
+	}
+
+	a.inTable = true
+	defer func() { a.inTable = false }()
+
+	var sb strings.Builder
+	options := ""
+	if headerRow {
+		options = `,options="header"`
+	}
+	sb.WriteString(`[cols="` + cols + `"` + options + "]\n|===\n")
+	for i, row := range rows {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		for _, cell := range row.Content {
+			if err := a.checkContext(); err != nil {
+				return "", err
+			}
+			spec, err := a.asciidocCellSpec(cell, i == 0 && headerRow)
+			if err != nil {
+				return "", err
+			}
+			content, err := a.convertChildren(cell.Content)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(spec + "|" + strings.TrimRight(content, "\n") + "\n")
+		}
+	}
+	sb.WriteString("|===\n\n")
+	return sb.String(), nil
+}
+
+// asciidocCellSpec returns the span and style specifier written before a cell's "|".
+func (a *asciidocFormat) asciidocCellSpec(cell Node, inHeaderRow bool) (string, error) {
+	var spec strings.Builder
+	colspan := cell.GetIntAttr("colspan", 1)
+	rowspan := cell.GetIntAttr("rowspan", 1)
+	if colspan > 1 {
+		spec.WriteString(strconv.Itoa(colspan))
+	}
+	if rowspan > 1 {
+		spec.WriteString("." + strconv.Itoa(rowspan))
+	}
+	if spec.Len() > 0 {
+		spec.WriteString("+")
+	}
+	if cell.GetStringAttr("background", "") != "" {
+		a.addWarning(WarningDroppedFeature, cell.Type, "table cell background dropped")
+	}
+
+	for _, child := range cell.Content {
+		if child.Type != "paragraph" {
+			spec.WriteString("a")
+			return spec.String(), nil
+		}
+	}
+	if cell.Type == "tableHeader" && !inHeaderRow {
+		spec.WriteString("h")
+	}
+	return spec.String(), nil
+}
+
+// convertAsciiDocPanel renders a panel as an admonition block titled by the panel
+// title. With PanelStyle none the panel becomes an unlabeled example block.
+func (a *asciidocFormat) convertAsciiDocPanel(node Node) (string, error) {
+	prefix := ""
+	if title := node.GetStringAttr("title", ""); title != "" {
+		prefix = "." + a.escapeAsciiDocText(title) + "\n"
+	}
+	if node.GetStringAttr("panelColor", "") != "" {
+		a.addWarning(WarningDroppedFeature, node.Type, "panel color dropped; AsciiDoc admonitions have fixed styles")
+	}
+
+	if a.config.PanelStyle != PanelNone {
+		panelType := strings.ToLower(node.GetStringAttr("panelType", ""))
+		label, ok := asciidocAdmonitions[panelType]
+		if !ok {
+			label = "NOTE"
+			if panelType != "" {
+				a.addWarning(WarningDroppedFeature, node.Type, fmt.Sprintf("panel type %q rendered as NOTE", panelType))
+			}
+		}
+		prefix += "[" + label + "]\n"
+	}
+	return a.convertAsciiDocDelimited(prefix, "=", node.Content)
+}
+
+// convertAsciiDocMediaSingle renders a media node as a block image, aligned by the
+// mediaSingle layout and titled by its caption.
+func (a *asciidocFormat) convertAsciiDocMediaSingle(node Node) (string, error) {
+	var media, caption string
+	for _, child := range node.Content {
+		switch child.Type {
+		case "media":
+			content, err := a.convertAsciiDocMedia(child, true)
+			if err != nil {
+				return "", err
+			}
+			media = content
+		case "caption":
+			content, err := a.convertInlineContent(child.Content)
+			if err != nil {
+				return "", err
+			}
+			caption = strings.TrimSpace(content)
+		default:
+			content, err := a.convertNode(child)
+			if err != nil {
+				return "", err
+			}
+			media += content
+		}
+	}
+	if strings.TrimSpace(media) == "" {
+		return "", nil
+	}
+	if !strings.HasPrefix(media, "image::") {
+		return guardAsciiDocLines(media, true) + "\n\n", nil
+	}
+
+	switch node.GetStringAttr("layout", "") {
+	case "align-start", "wrap-left":
+		media = strings.TrimSuffix(media, "]") + ",align=left]"
+	case "align-end", "wrap-right":
+		media = strings.TrimSuffix(media, "]") + ",align=right]"
+	}
+	if caption != "" {
+		media = "." + caption + "\n" + media
+	}
+	return media + "\n\n", nil
+}
+
+// convertAsciiDocMedia renders media as image macros or file links; block renders
+// images as block images. Media hook Markdown image and link output is translated;
+// other output is inserted verbatim.
+func (a *asciidocFormat) convertAsciiDocMedia(node Node, block bool) (string, error) {
+	imageMacro := "image:"
+	if block {
+		imageMacro = "image::"
+	}
+
+	hookOutput, handled, err := a.applyMediaRenderHook(node.Type, a.mediaRenderInput(node))
+	if err != nil {
+		return "", err
+	}
+	if handled {
+		markdown := strings.TrimSpace(hookOutput.Markdown)
+		if match := wikiMarkdownImageRe.FindStringSubmatch(markdown); match != nil {
+			return imageMacro + asciidocTarget(match[2]) + "[" + asciidocAttrValue(match[1]) + "]", nil
+		}
+		if match := wikiMarkdownLinkRe.FindStringSubmatch(markdown); match != nil {
+			return "link:" + asciidocTarget(match[2]) + "[" + a.escapeAsciiDocText(match[1]) + "]", nil
+		}
+		a.addWarning(WarningDroppedFeature, node.Type, "media hook markdown inserted verbatim into AsciiDoc")
+		return markdown, nil
+	}
+
+	mediaType := node.GetStringAttr("type", "")
+	id := node.GetStringAttr("id", "")
+	alt := node.GetStringAttr("alt", "")
+	url := node.GetStringAttr("url", "")
+	if url == "" && id != "" && a.config.MediaBaseURL != "" {
+		base := a.config.MediaBaseURL
+		if !strings.HasSuffix(base, "/") {
+			base += "/"
+		}
+		url = base + id
+	}
+
+	// Attachments are referenced by file name, relative to the document.
+	if url == "" {
+		url = firstNonEmptyTrimmed(mediaMetadataFromAttrs(node.Attrs, id, url).Filename, alt)
+	}
+	if url != "" {
+		if mediaType == "file" {
+			return "link:" + asciidocTarget(url) + "[" + a.escapeAsciiDocText(firstNonEmptyTrimmed(alt, id, url)) + "]", nil
+		}
+		attrs := asciidocAttrValue(alt)
+		for _, dimension := range []string{"width", "height"} {
+			if size := node.GetIntAttr(dimension, 0); size > 0 {
+				attrs += "," + dimension + "=" + strconv.Itoa(size)
+			}
+		}
+		return imageMacro + asciidocTarget(url) + "[" + attrs + "]", nil
+	}
+
+	if id == "" {
+		if a.config.UnknownNodes == UnknownError {
+			return "", fmt.Errorf("media node missing id")
+		}
+		a.addWarning(WarningMissingAttribute, node.Type, "media node missing id")
+		return a.escapeAsciiDocText("[Media: (no id)]"), nil
+	}
+	a.addWarning(WarningMissingAttribute, node.Type, fmt.Sprintf("media %q has no url or file name; rendered as placeholder", id))
+	label := "Media"
+	switch mediaType {
+	case "image":
+		label = "Image"
+	case "file":
+		label = "File"
+	}
+	return a.escapeAsciiDocText(fmt.Sprintf("[%s: %s]", label, id)), nil
+}
+
+// convertAsciiDocMention renders a mention as a mention: link, or as text with
+// MentionText and the styles that have no AsciiDoc form.
+func (a *asciidocFormat) convertAsciiDocMention(node Node) string {
+	id := node.GetStringAttr("id", "")
+	text := node.GetStringAttr("text", "")
+	if text == "" {
+		text = "Unknown User"
+	} else if !strings.HasPrefix(text, "@") {
+		text = "@" + text
+	}
+
+	if a.config.MentionStyle != MentionLink {
+		return a.escapeAsciiDocText(text)
+	}
+	if id == "" {
+		a.addWarning(WarningMissingAttribute, node.Type, "mention node missing id")
+		return a.escapeAsciiDocText(text)
+	}
+	return "link:mention:" + asciidocTarget(id) + "[" + a.escapeAsciiDocText(text) + "]"
+}
+
+func (a *asciidocFormat) convertAsciiDocInlineCard(node Node) (string, error) {
+	title, url := a.getInlineCardLinkData(node)
+
+	hookOutput, handled, err := a.applyLinkRenderHook(node.Type, a.inlineCardRenderInput(node))
+	if err != nil {
+		return "", err
+	}
+	if handled {
+		if hookOutput.TextOnly {
+			return a.escapeAsciiDocText(firstNonEmptyTrimmed(hookOutput.Title, title, url)), nil
+		}
+		title = hookOutput.Title
+		url = hookOutput.Href
+	}
+
+	if url == "" {
+		if title != "" {
+			return a.escapeAsciiDocText(title), nil
+		}
+		if a.config.UnknownNodes == UnknownError {
+			return "", fmt.Errorf("inlineCard missing url and valid data")
+		}
+		a.addWarning(WarningMissingAttribute, node.Type, "inlineCard missing url and valid data")
+		return a.escapeAsciiDocText("[Smart Link]"), nil
+	}
+	if a.config.InlineCardStyle == InlineCardURL || title == "" || title == url {
+		return "link:" + asciidocTarget(url) + "[]", nil
+	}
+	return "link:" + asciidocTarget(url) + "[" + a.escapeAsciiDocText(title) + "]", nil
+}
+
+// convertAsciiDocExtension renders extensions following Config.Extensions. Bodied
+// extensions keep their body. Extension handlers are not invoked because they produce
+// Markdown.
+func (a *asciidocFormat) convertAsciiDocExtension(node Node) (string, error) {
+	extensionKey := node.GetStringAttr("extensionKey", "")
+	if _, ok := a.config.ExtensionHandlers[extensionKey]; ok && extensionKey != "" {
+		a.addWarning(WarningExtensionFallback, node.Type, fmt.Sprintf("extension handler %q not used for AsciiDoc", extensionKey))
+	}
+
+	if node.Type == "bodiedExtension" {
+		a.addWarning(WarningDroppedFeature, node.Type, fmt.Sprintf("bodied extension %q rendered as its body", extensionKey))
+		return a.convertChildren(node.Content)
+	}
+
+	extensionType := firstNonEmptyTrimmed(node.GetStringAttr("extensionType", ""), extensionKey, node.Type)
+	switch strategy := a.config.Extensions.ModeFor(extensionType); strategy {
+	case ExtensionStrip:
+		a.addWarning(WarningDroppedFeature, node.Type, fmt.Sprintf("extension %q stripped", extensionType))
+		return "", nil
+	case ExtensionText:
+		text := a.escapeAsciiDocText(node.GetStringAttr("text", ""))
+		if len(node.Content) > 0 {
+			children, err := a.convertChildren(node.Content)
+			if err != nil {
+				return "", err
+			}
+			if trimmed := strings.TrimSpace(children); trimmed != "" {
+				text = trimmed
+			}
+		}
+		if text == "" {
+			a.addWarning(WarningExtensionFallback, node.Type, fmt.Sprintf("extension %q has no fallback text", extensionType))
+		}
+		if node.Type == "extension" && text != "" {
+			return text + "\n\n", nil
+		}
+		return text, nil
+	case ExtensionJSON:
+		if node.Type == "inlineExtension" {
+			compact, err := json.Marshal(extensionJSONNode{Type: node.Type, Attrs: node.Attrs, Content: node.Content})
+			if err != nil {
+				return "", fmt.Errorf("failed to marshal extension node: %w", err)
+			}
+			return "`+" + string(compact) + "+`", nil
+		}
+		data, err := json.MarshalIndent(extensionJSONNode{Type: node.Type, Attrs: node.Attrs, Content: node.Content}, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal extension node: %w", err)
+		}
+		return "[source,json]\n----\n" + string(data) + "\n----\n\n", nil
+	default:
+		return "", fmt.Errorf("unknown extension strategy: %s", strategy)
+	}
+}
+
+// markOpening returns the opening AsciiDoc markup for a mark and reports marks
+// that AsciiDoc cannot express. It is called once per opened mark. Formatting uses the
+// unconstrained (doubled) forms, which also work inside words.
+func (a *asciidocFormat) markOpening(mark Mark, _ bool) (string, error) {
+	switch mark.Type {
+	case "strong":
+		return "**", nil
+	case "em":
+		return "__", nil
+	case "code":
+		return "`+", nil
+	case "strike":
+		return "[.line-through]##", nil
+	case "underline":
+		if a.config.UnderlineStyle == UnderlineIgnore {
+			return "", nil
+		}
+		return "[.underline]##", nil
+	case "subsup":
+		switch mark.GetStringAttr("type", "") {
+		case "sub":
+			return "~", nil
+		case "sup":
+			return "^", nil
+		}
+		return "", nil
+	case "textColor", "backgroundColor":
+		if role, ok := asciidocColorRole(mark); ok {
+			return "[." + role + "]##", nil
+		}
+		if raw := mark.GetStringAttr("color", ""); raw != "" {
+			a.addWarning(WarningDroppedFeature, mark.Type, fmt.Sprintf("color %q dropped; AsciiDoc only has roles for the 16 basic color names", raw))
+		}
+		return "", nil
+	case "link":
+		href, _, ok, err := a.resolveLinkMark(mark)
+		if err != nil || !ok {
+			return "", err
+		}
+		return "link:" + asciidocTarget(href) + "[", nil
+	default:
+		return "", nil
+	}
+}
+
+// markClosing returns the closing AsciiDoc markup for a mark.
+func (a *asciidocFormat) markClosing(mark Mark, _ bool) (string, error) {
+	switch mark.Type {
+	case "code":
+		return "+`", nil
+	case "strike":
+		return "##", nil
+	case "underline":
+		if a.config.UnderlineStyle == UnderlineIgnore {
+			return "", nil
+		}
+		return "##", nil
+	case "textColor", "backgroundColor":
+		if _, ok := asciidocColorRole(mark); ok {
+			return "##", nil
+		}
+		return "", nil
+	case "link":
+		_, _, ok, err := a.resolveLinkMark(mark)
+		if err != nil || !ok {
+			return "", err
+		}
+		return "]", nil
+	default:
+		return a.markOpening(mark, false)
+	}
+}
+
+// asciidocColorRole returns the built-in role of a color mark, such as "red" or
+// "yellow-background".
+func asciidocColorRole(mark Mark) (string, bool) {
+	color, ok := SanitizeCSSColor(mark.GetStringAttr("color", ""))
+	color = strings.ToLower(color)
+	if !ok || !asciidocColorRoles[color] {
+		return "", false
+	}
+	if mark.Type == "backgroundColor" {
+		return color + "-background", true
+	}
+	return color, true
+}
+
+// asciidocTarget returns a macro target, wrapping targets with spaces or brackets in
+// a passthrough.
+func asciidocTarget(target string) string {
+	if strings.ContainsAny(target, " []") {
+		return "++" + target + "++"
+	}
+	return target
+}
+
+// asciidocAttrValue returns a value for a macro or block attribute list, quoted when
+// it contains list syntax.
+func asciidocAttrValue(value string) string {
+	value = strings.TrimSpace(strings.NewReplacer("\n", " ", "\r", "").Replace(value))
+	if !strings.ContainsAny(value, `,="]`) {
+		return value
+	}
+	return `"` + strings.NewReplacer(`"`, `\"`, "]", `\]`).Replace(value) + `"`
+}
+
+// guardAsciiDocLines prefixes lines that AsciiDoc would parse as block syntax with
+// {empty}. The first line is left alone when it follows a list marker.
+func guardAsciiDocLines(content string, first bool) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if (i > 0 || first) && asciidocBlockStartRe.MatchString(line) && !isAsciiDocInlineMarkup(line) {
+			lines[i] = "{empty}" + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isAsciiDocInlineMarkup reports whether a line starts with formatting this renderer
+// emits, which AsciiDoc does not confuse with block syntax.
+func isAsciiDocInlineMarkup(line string) bool {
+	for _, prefix := range []string{"**", "__", "`+", "[.", "link:", "image:"} {
+		if strings.HasPrefix(line, prefix) && !strings.HasPrefix(line, "** ") && !strings.HasPrefix(line, "__ ") {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeAsciiDocText replaces characters that would start AsciiDoc markup. Brackets
+// and carets always become attribute references; formatting characters only where they
+// could open or close a span, so ordinary punctuation stays readable. "_" and "#" have
+// no attribute reference and use an inline passthrough.
+func (a *asciidocFormat) escapeAsciiDocText(text string) string {
+	if !strings.ContainsAny(text, "[]{}|*_`#^~+<") {
+		return text
+	}
+
+	runes := []rune(text)
+	var sb strings.Builder
+	for i, r := range runes {
+		prev, next := ' ', ' '
+		if i > 0 {
+			prev = runes[i-1]
+		}
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch r {
+		case '[':
+			sb.WriteString("{startsb}")
+			continue
+		case ']':
+			sb.WriteString("{endsb}")
+			continue
+		case '^':
+			sb.WriteString("{caret}")
+			continue
+		case '~':
+			sb.WriteString("{tilde}")
+			continue
+		case '{':
+			if asciidocAttrRefRe.MatchString(string(runes[i:])) {
+				sb.WriteRune('\\')
+			}
+		case '|':
+			if a.inTable {
+				sb.WriteString("{vbar}")
+				continue
+			}
+		case '<':
+			if next == '<' {
+				sb.WriteString("{lt}")
+				continue
+			}
+		case '*', '_', '`', '#', '+':
+			opens := !isWikiWordRune(prev) && !unicode.IsSpace(next)
+			closes := !unicode.IsSpace(prev) && !isWikiWordRune(next)
+			if opens || closes || prev == r || next == r {
+				sb.WriteString(asciidocCharReplacements[r])
+				continue
+			}
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// asciidocCharReplacements spell formatting characters without starting markup.
+var asciidocCharReplacements = map[rune]string{
+	'*': "{asterisk}",
+	'`': "{backtick}",
+	'+': "{plus}",
+	'_': "pass:[_]",
+	'#': "pass:[#]",
+}
+
+func hasMarkType(marks []Mark, markType string) bool {
+	for _, mark := range marks {
+		if mark.Type == markType {
+			return true
+		}
+	}
+	return false
+}
+
+func (*asciidocFormat) keepMark(Mark) bool {
+	return true
+}
+
+func (a *asciidocFormat) renderText(placeholder, text string, marks []Mark) string {
+	// Code spans are passthroughs, so their text is written as is.
+	if hasMarkType(marks, "code") {
+		return a.escapeAsciiDocText(placeholder) + text
+	}
+	return a.escapeAsciiDocText(placeholder + text)
+}
+
+func (*asciidocFormat) finishInline(content string) string {
+	return content
+}
@@ -0,0 +1,220 @@
+package converter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertAsciiDocRendersBlocks(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"heading","attrs":{"level":1},"content":[{"type":"text","text":"Release notes"}]},
+		{"type":"bulletList","content":[
+			{"type":"listItem","content":[
+				{"type":"paragraph","content":[{"type":"text","text":"one"}]},
+				{"type":"orderedList","attrs":{"order":3},"content":[
+					{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"nested"}]}]}
+				]}
+			]},
+			{"type":"listItem","content":[
+				{"type":"paragraph","content":[{"type":"text","text":"two"}]},
+				{"type":"codeBlock","content":[{"type":"text","text":"x"}]}
+			]}
+		]},
+		{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"x := 1\n----"}]},
+		{"type":"blockquote","content":[{"type":"paragraph","content":[{"type":"text","text":"quoted"}]}]},
+		{"type":"paragraph","attrs":{"layout":"center"},"content":[{"type":"text","text":"centered"}]},
+		{"type":"rule"}
+	]}`)
+
+	result, err := newTestConverter(t, Config{}).ConvertAsciiDoc(input)
+	require.NoError(t, err)
+
+	expected := "== Release notes\n\n" +
+		"* one\n[start=3]\n.. nested\n* two\n+\n[source]\n----\nx\n----\n\n" +
+		"[source,go]\n-----\nx := 1\n----\n-----\n\n" +
+		"____\nquoted\n____\n\n" +
+		"[.text-center]\ncentered\n\n" +
+		"'''\n"
+	assert.Equal(t, expected, result.AsciiDoc)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestConvertAsciiDocRendersPanelsAndExpands(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"panel","attrs":{"panelType":"warning","title":"Careful"},"content":[
+			{"type":"paragraph","content":[{"type":"text","text":"Heads up"}]},
+			{"type":"expand","attrs":{"title":"Details"},"content":[
+				{"type":"nestedExpand","attrs":{"title":"More"},"content":[{"type":"paragraph","content":[{"type":"text","text":"deep"}]}]}
+			]}
+		]},
+		{"type":"panel","attrs":{"panelType":"success"},"content":[{"type":"paragraph","content":[{"type":"text","text":"Done"}]}]}
+	]}`)
+
+	result, err := newTestConverter(t, Config{}).ConvertAsciiDoc(input)
+	require.NoError(t, err)
+
+	expected := ".Careful\n[WARNING]\n====\nHeads up\n\n" +
+		".Details\n[%collapsible]\n=====\n" +
+		".More\n[%collapsible]\n======\ndeep\n======\n" +
+		"=====\n" +
+		"====\n\n" +
+		"[TIP]\n====\nDone\n====\n"
+	assert.Equal(t, expected, result.AsciiDoc)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestConvertAsciiDocRendersTablesWithSpansAndWidths(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[{"type":"table","content":[
+		{"type":"tableRow","content":[
+			{"type":"tableHeader","attrs":{"colwidth":[100]},"content":[{"type":"paragraph","content":[{"type":"text","text":"Key"}]}]},
+			{"type":"tableHeader","attrs":{"colwidth":[200]},"content":[{"type":"paragraph","content":[{"type":"text","text":"a | b"}]}]}
+		]},
+		{"type":"tableRow","content":[
+			{"type":"tableHeader","attrs":{"rowspan":2},"content":[{"type":"paragraph","content":[{"type":"text","text":"row"}]}]},
+			{"type":"tableCell","content":[{"type":"bulletList","content":[
+				{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"item"}]}]}
+			]}]}
+		]},
+		{"type":"tableRow","content":[
+			{"type":"tableCell","attrs":{"background":"#eeeeee"},"content":[]}
+		]},
+		{"type":"tableRow","content":[
+			{"type":"tableCell","attrs":{"colspan":2},"content":[{"type":"paragraph","content":[{"type":"text","text":"wide"}]}]}
+		]}
+	]}]}`)
+
+	result, err := newTestConverter(t, Config{}).ConvertAsciiDoc(input)
+	require.NoError(t, err)
+
+	expected := "[cols=\"100,200\",options=\"header\"]\n|===\n" +
+		"|Key\n|a {vbar} b\n\n" +
+		".2+h|row\na|* item\n\n" +
+		"|\n\n" +
+		"2+|wide\n" +
+		"|===\n"
+	assert.Equal(t, expected, result.AsciiDoc)
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, WarningDroppedFeature, result.Warnings[0].Type)
+	assert.Equal(t, "tableCell", result.Warnings[0].NodeType)
+}
+
+func TestConvertAsciiDocRendersInlineContent(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[
+			{"type":"text","text":"Bold","marks":[{"type":"strong"}]},
+			{"type":"text","text":", "},
+			{"type":"text","text":"docs","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]},
+			{"type":"text","text":", "},
+			{"type":"text","text":"a*b","marks":[{"type":"code"}]},
+			{"type":"text","text":", "},
+			{"type":"text","text":"red","marks":[{"type":"textColor","attrs":{"color":"red"}}]},
+			{"type":"text","text":", H"},
+			{"type":"text","text":"2","marks":[{"type":"subsup","attrs":{"type":"sub"}}]},
+			{"type":"text","text":"O "},
+			{"type":"mention","attrs":{"id":"u1","text":"Ada"}},
+			{"type":"text","text":" "},
+			{"type":"status","attrs":{"text":"Done","color":"green"}}
+		]},
+		{"type":"taskList","content":[
+			{"type":"taskItem","attrs":{"state":"DONE"},"content":[{"type":"text","text":"shipped"}]},
+			{"type":"taskList","content":[
+				{"type":"taskItem","attrs":{"state":"TODO"},"content":[{"type":"text","text":"follow up"}]}
+			]}
+		]},
+		{"type":"decisionList","content":[
+			{"type":"decisionItem","attrs":{"state":"DECIDED"},"content":[{"type":"text","text":"Ship"}]}
+		]}
+	]}`)
+
+	result, err := newTestConverter(t, Config{}).ConvertAsciiDoc(input)
+	require.NoError(t, err)
+
+	expected := "**Bold**, link:https://example.com[docs], `+a*b+`, [.red]##red##, H~2~O link:mention:u1[@Ada] [.green]##**Done**##\n\n" +
+		"* [x] shipped\n** [ ] follow up\n\n" +
+		"* **✓ Decision**: Ship\n"
+	assert.Equal(t, expected, result.AsciiDoc)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestConvertAsciiDocEscapesText(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[{"type":"text","text":"Use [brackets], {nbsp} attrs, *stars*, snake_case, 2 * 3 and x^2"}]},
+		{"type":"paragraph","content":[{"type":"text","text":"= not a title"}]},
+		{"type":"paragraph","content":[{"type":"text","text":"NOTE: not an admonition"}]}
+	]}`)
+
+	result, err := newTestConverter(t, Config{}).ConvertAsciiDoc(input)
+	require.NoError(t, err)
+
+	expected := `Use {startsb}brackets{endsb}, \{nbsp} attrs, {asterisk}stars{asterisk}, snake_case, 2 * 3 and x{caret}2` + "\n\n" +
+		"{empty}= not a title\n\n" +
+		"{empty}NOTE: not an admonition\n"
+	assert.Equal(t, expected, result.AsciiDoc)
+}
+
+func TestConvertAsciiDocWarnsOnUnsupportedFeatures(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[
+			{"type":"text","text":"tinted","marks":[{"type":"textColor","attrs":{"color":"#123456"}}]}
+		]},
+		{"type":"panel","attrs":{"panelType":"custom","panelColor":"#ffeedd"},"content":[{"type":"paragraph","content":[{"type":"text","text":"custom"}]}]},
+		{"type":"layoutSection","content":[
+			{"type":"layoutColumn","attrs":{"width":50},"content":[{"type":"paragraph","content":[{"type":"text","text":"left"}]}]},
+			{"type":"layoutColumn","attrs":{"width":50},"content":[{"type":"paragraph","content":[{"type":"text","text":"right"}]}]}
+		]}
+	]}`)
+
+	result, err := newTestConverter(t, Config{}).ConvertAsciiDoc(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "tinted\n\n[NOTE]\n====\ncustom\n====\n\nleft\n\nright\n", result.AsciiDoc)
+	require.Len(t, result.Warnings, 4)
+	for _, warning := range result.Warnings {
+		assert.Equal(t, WarningDroppedFeature, warning.Type)
+	}
+	assert.Equal(t, "textColor", result.Warnings[0].NodeType)
+	assert.Equal(t, "panel", result.Warnings[1].NodeType)
+	assert.Equal(t, "panel", result.Warnings[2].NodeType)
+	assert.Equal(t, "layoutSection", result.Warnings[3].NodeType)
+}
+
+func TestConvertAsciiDocUsesHooks(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[
+			{"type":"text","text":"page","marks":[{"type":"link","attrs":{"href":"https://wiki.example.com/page"}}]}
+		]},
+		{"type":"mediaSingle","attrs":{"layout":"align-end"},"content":[
+			{"type":"media","attrs":{"type":"file","id":"m1","alt":"diagram"}},
+			{"type":"caption","content":[{"type":"text","text":"Architecture"}]}
+		]}
+	]}`)
+
+	converter := newTestConverter(t, Config{
+		LinkHook: func(_ context.Context, in LinkRenderInput) (LinkRenderOutput, error) {
+			return LinkRenderOutput{Href: "https://local/page", Title: in.Title, Handled: true}, nil
+		},
+		MediaHook: func(_ context.Context, in MediaRenderInput) (MediaRenderOutput, error) {
+			return MediaRenderOutput{Markdown: "![" + in.Alt + "](https://cdn.example.com/" + in.ID + ")", Handled: true}, nil
+		},
+	})
+
+	result, err := converter.ConvertAsciiDoc(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "link:https://local/page[page]\n\n.Architecture\nimage::https://cdn.example.com/m1[diagram,align=right]\n", result.AsciiDoc)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestConvertAsciiDocUnknownNodePolicy(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[{"type":"mystery"}]}`)
+
+	result, err := newTestConverter(t, Config{}).ConvertAsciiDoc(input)
+	require.NoError(t, err)
+	assert.Equal(t, "{startsb}Unknown node: mystery{endsb}\n", result.AsciiDoc)
+
+	_, err = newTestConverter(t, Config{UnknownNodes: UnknownError}).ConvertAsciiDoc(input)
+	require.Error(t, err)
+}
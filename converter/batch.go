@@ -0,0 +1,191 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+)
+
+// BatchResolver resolves every link, inlineCard and media reference of a document in a
+// single call before rendering starts. It is an alternative to calling LinkHook and
+// MediaHook once per reference, for resolvers backed by remote APIs.
+type BatchResolver interface {
+	ResolveBatch(ctx context.Context, req BatchRequest) (BatchResponse, error)
+}
+
+// BatchResolverFunc adapts a plain function to the BatchResolver interface.
+type BatchResolverFunc func(ctx context.Context, req BatchRequest) (BatchResponse, error)
+
+// ResolveBatch calls f(ctx, req).
+func (f BatchResolverFunc) ResolveBatch(ctx context.Context, req BatchRequest) (BatchResponse, error) {
+	return f(ctx, req)
+}
+
+// BatchRequest lists the unique references collected from a document.
+// Inputs are identical to what LinkHook and MediaHook would receive.
+type BatchRequest struct {
+	SourcePath       string
+	DocumentMetadata map[string]any `json:"-"`
+	Links            []LinkRenderInput
+	Media            []MediaRenderInput
+}
+
+// BatchResponse holds resolutions aligned by index with BatchRequest.Links and
+// BatchRequest.Media. References without a resolution (shorter slices) fall back to
+// the per-reference hooks, if configured.
+type BatchResponse struct {
+	Links []LinkResolution
+	Media []MediaResolution
+}
+
+// LinkResolution is the batch equivalent of a LinkHook return value.
+// Err follows the same ErrUnresolved/ResolutionMode semantics as LinkHook.
+type LinkResolution struct {
+	Output LinkRenderOutput
+	Err    error
+}
+
+// MediaResolution is the batch equivalent of a MediaHook return value.
+// Err follows the same ErrUnresolved/ResolutionMode semantics as MediaHook.
+type MediaResolution struct {
+	Output MediaRenderOutput
+	Err    error
+}
+
+type linkBatchKey struct {
+	source string
+	href   string
+	title  string
+	text   string
+	meta   LinkMetadata
+}
+
+type mediaBatchKey struct {
+	mediaType string
+	id        string
+	url       string
+	alt       string
+	meta      MediaMetadata
+}
+
+// batchResolutions holds resolved references for a single conversion.
+// It is read-only once built, so parallel block states may share it.
+type batchResolutions struct {
+	links map[linkBatchKey]LinkResolution
+	media map[mediaBatchKey]MediaResolution
+}
+
+func newLinkBatchKey(in LinkRenderInput) linkBatchKey {
+	return linkBatchKey{source: in.Source, href: in.Href, title: in.Title, text: in.Text, meta: in.Meta}
+}
+
+func newMediaBatchKey(in MediaRenderInput) mediaBatchKey {
+	return mediaBatchKey{mediaType: in.MediaType, id: in.ID, url: in.URL, alt: in.Alt, meta: in.Meta}
+}
+
+func (b *batchResolutions) lookupLink(in LinkRenderInput) (LinkResolution, bool) {
+	if b == nil {
+		return LinkResolution{}, false
+	}
+	resolution, ok := b.links[newLinkBatchKey(in)]
+	return resolution, ok
+}
+
+func (b *batchResolutions) lookupMedia(in MediaRenderInput) (MediaResolution, bool) {
+	if b == nil {
+		return MediaResolution{}, false
+	}
+	resolution, ok := b.media[newMediaBatchKey(in)]
+	return resolution, ok
+}
+
+// resolveBatch collects references from the document and resolves them in one call.
+// References already present in HookCache are taken from the cache and not requested.
+func (s *state) resolveBatch(content []Node) error {
+	if s.config.BatchResolver == nil {
+		return nil
+	}
+
+	collected := BatchRequest{}
+	seenLinks := make(map[linkBatchKey]bool)
+	seenMedia := make(map[mediaBatchKey]bool)
+	s.collectBatchReferences(content, &collected, seenLinks, seenMedia)
+
+	batch := &batchResolutions{
+		links: make(map[linkBatchKey]LinkResolution),
+		media: make(map[mediaBatchKey]MediaResolution),
+	}
+	req := BatchRequest{SourcePath: s.options.SourcePath, DocumentMetadata: s.options.Metadata}
+	for _, input := range collected.Links {
+		if output, err, ok := LookupHookCache[LinkRenderInput, LinkRenderOutput](s.config.HookCache, linkRenderCacheKind, input); ok {
+			batch.links[newLinkBatchKey(input)] = LinkResolution{Output: output, Err: err}
+			continue
+		}
+		req.Links = append(req.Links, input)
+	}
+	for _, input := range collected.Media {
+		if output, err, ok := LookupHookCache[MediaRenderInput, MediaRenderOutput](s.config.HookCache, mediaRenderCacheKind, input); ok {
+			batch.media[newMediaBatchKey(input)] = MediaResolution{Output: output, Err: err}
+			continue
+		}
+		req.Media = append(req.Media, input)
+	}
+	s.batch = batch
+	if len(req.Links) == 0 && len(req.Media) == 0 {
+		return nil
+	}
+
+	if err := s.checkContext(); err != nil {
+		return err
+	}
+	resp, err := s.config.BatchResolver.ResolveBatch(s.ctx, req)
+	if err != nil {
+		return fmt.Errorf("batch resolver failed: %w", err)
+	}
+	if len(resp.Links) > len(req.Links) || len(resp.Media) > len(req.Media) {
+		return fmt.Errorf("batch resolver returned more resolutions than requested references")
+	}
+
+	for index, resolution := range resp.Links {
+		batch.links[newLinkBatchKey(req.Links[index])] = resolution
+	}
+	for index, resolution := range resp.Media {
+		batch.media[newMediaBatchKey(req.Media[index])] = resolution
+	}
+
+	return nil
+}
+
+func (s *state) collectBatchReferences(content []Node, req *BatchRequest, seenLinks map[linkBatchKey]bool, seenMedia map[mediaBatchKey]bool) {
+	for _, node := range content {
+		switch node.Type {
+		case "text":
+			for _, mark := range node.Marks {
+				if mark.Type != "link" {
+					continue
+				}
+				input, ok := s.linkMarkRenderInput(mark)
+				if !ok {
+					continue
+				}
+				if key := newLinkBatchKey(input); !seenLinks[key] {
+					seenLinks[key] = true
+					req.Links = append(req.Links, input)
+				}
+			}
+		case "inlineCard":
+			input := s.inlineCardRenderInput(node)
+			if key := newLinkBatchKey(input); !seenLinks[key] {
+				seenLinks[key] = true
+				req.Links = append(req.Links, input)
+			}
+		case "media":
+			input := s.mediaRenderInput(node)
+			if key := newMediaBatchKey(input); !seenMedia[key] {
+				seenMedia[key] = true
+				req.Media = append(req.Media, input)
+			}
+		}
+
+		s.collectBatchReferences(node.Content, req, seenLinks, seenMedia)
+	}
+}
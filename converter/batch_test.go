@@ -0,0 +1,140 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchResolverResolvesAllReferencesInOneCall(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[
+			{"type":"text","text":"A","marks":[{"type":"link","attrs":{"href":"https://confluence.example/a"}}]},
+			{"type":"text","text":" and "},
+			{"type":"text","text":"again","marks":[{"type":"link","attrs":{"href":"https://confluence.example/a"}}]},
+			{"type":"text","text":" "},
+			{"type":"inlineCard","attrs":{"url":"https://confluence.example/card"}}
+		]},
+		{"type":"mediaSingle","content":[{"type":"media","attrs":{"type":"file","id":"att-1"}}]}
+	]}`)
+
+	calls := 0
+	conv := newTestConverter(t, Config{
+		LinkHook: func(context.Context, LinkRenderInput) (LinkRenderOutput, error) {
+			t.Fatal("per-link hook must not be called for batch-resolved references")
+			return LinkRenderOutput{}, nil
+		},
+		BatchResolver: BatchResolverFunc(func(_ context.Context, req BatchRequest) (BatchResponse, error) {
+			calls++
+			assert.Equal(t, "docs/page.adf.json", req.SourcePath)
+			require.Len(t, req.Links, 2)
+			assert.Equal(t, "mark", req.Links[0].Source)
+			assert.Equal(t, "https://confluence.example/a", req.Links[0].Href)
+			assert.Equal(t, "inlineCard", req.Links[1].Source)
+			require.Len(t, req.Media, 1)
+			assert.Equal(t, "att-1", req.Media[0].ID)
+
+			return BatchResponse{
+				Links: []LinkResolution{
+					{Output: LinkRenderOutput{Href: "./a.md", Handled: true}},
+					{Output: LinkRenderOutput{Href: "./card.md", Title: "Card", Handled: true}},
+				},
+				Media: []MediaResolution{
+					{Output: MediaRenderOutput{Markdown: "[att-1](./att-1.bin)", Handled: true}},
+				},
+			}, nil
+		}),
+	})
+
+	result, err := conv.ConvertWithContext(context.Background(), input, ConvertOptions{SourcePath: "docs/page.adf.json"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "[A](./a.md) and [again](./a.md) [Card](./card.md)\n\n[att-1](./att-1.bin)\n", result.Markdown)
+}
+
+func TestBatchResolverUnresolvedFollowsResolutionMode(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"A","marks":[{"type":"link","attrs":{"href":"https://example.com/a"}}]}]}]}`)
+	resolver := BatchResolverFunc(func(context.Context, BatchRequest) (BatchResponse, error) {
+		return BatchResponse{Links: []LinkResolution{{Err: ErrUnresolved}}}, nil
+	})
+
+	conv := newTestConverter(t, Config{BatchResolver: resolver})
+	result, err := conv.Convert(input)
+	require.NoError(t, err)
+	assert.Equal(t, "[A](https://example.com/a)\n", result.Markdown)
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, WarningUnresolvedReference, result.Warnings[0].Type)
+
+	conv = newTestConverter(t, Config{BatchResolver: resolver, ResolutionMode: ResolutionStrict})
+	_, err = conv.Convert(input)
+	require.ErrorIs(t, err, ErrUnresolved)
+}
+
+func TestBatchResolverFallsBackToHooksForMissingResolutions(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[{"type":"paragraph","content":[
+		{"type":"text","text":"A","marks":[{"type":"link","attrs":{"href":"https://example.com/a"}}]},
+		{"type":"text","text":" "},
+		{"type":"text","text":"B","marks":[{"type":"link","attrs":{"href":"https://example.com/b"}}]}
+	]}]}`)
+
+	conv := newTestConverter(t, Config{
+		LinkHook: func(_ context.Context, in LinkRenderInput) (LinkRenderOutput, error) {
+			assert.Equal(t, "https://example.com/b", in.Href)
+			return LinkRenderOutput{Href: "./b.md", Handled: true}, nil
+		},
+		BatchResolver: BatchResolverFunc(func(context.Context, BatchRequest) (BatchResponse, error) {
+			return BatchResponse{Links: []LinkResolution{{Output: LinkRenderOutput{Href: "./a.md", Handled: true}}}}, nil
+		}),
+	})
+
+	result, err := conv.Convert(input)
+	require.NoError(t, err)
+	assert.Equal(t, "[A](./a.md) [B](./b.md)\n", result.Markdown)
+}
+
+func TestBatchResolverErrors(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"A","marks":[{"type":"link","attrs":{"href":"https://example.com/a"}}]}]}]}`)
+
+	conv := newTestConverter(t, Config{
+		BatchResolver: BatchResolverFunc(func(context.Context, BatchRequest) (BatchResponse, error) {
+			return BatchResponse{}, errors.New("api down")
+		}),
+	})
+	_, err := conv.Convert(input)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "batch resolver failed: api down")
+
+	conv = newTestConverter(t, Config{
+		BatchResolver: BatchResolverFunc(func(context.Context, BatchRequest) (BatchResponse, error) {
+			return BatchResponse{Links: []LinkResolution{{Output: LinkRenderOutput{Handled: true}}}}, nil
+		}),
+	})
+	_, err = conv.Convert(input)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid link hook output")
+}
+
+func TestBatchResolverWithParallelism(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[{"type":"text","text":"A","marks":[{"type":"link","attrs":{"href":"https://example.com/a"}}]}]},
+		{"type":"paragraph","content":[{"type":"text","text":"B","marks":[{"type":"link","attrs":{"href":"https://example.com/b"}}]}]}
+	]}`)
+
+	conv := newTestConverter(t, Config{
+		Parallelism: 2,
+		BatchResolver: BatchResolverFunc(func(_ context.Context, req BatchRequest) (BatchResponse, error) {
+			resp := BatchResponse{}
+			for _, link := range req.Links {
+				resp.Links = append(resp.Links, LinkResolution{Output: LinkRenderOutput{Href: link.Href + ".md", Handled: true}})
+			}
+			return resp, nil
+		}),
+	})
+
+	result, err := conv.Convert(input)
+	require.NoError(t, err)
+	assert.Equal(t, "[A](https://example.com/a.md)\n\n[B](https://example.com/b.md)\n", result.Markdown)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"html"
 	"strings"
+	"unicode/utf8"
 )
 
 // convertParagraph converts a paragraph node to markdown
@@ -16,6 +17,7 @@ func (s *state) convertParagraph(node Node) (string, error) {
 	if content == "" {
 		return "", nil
 	}
+	content = s.wrapText(content)
 
 	if alignment := s.getNodeAlignment(node); alignment != "" {
 		trimmed := strings.TrimSuffix(content, "\n\n")
@@ -51,9 +53,8 @@ func (s *state) convertParagraphContent(content []Node) (string, error) {
 	return res + "\n\n", nil
 }
 
-// convertHeading converts a heading node to markdown
-func (s *state) convertHeading(node Node) (string, error) {
-	// Extract level from attributes (default to 1 if missing/invalid).
+// headingLevel extracts the ADF heading level from attributes (default to 1 if missing/invalid).
+func headingLevel(node Node) int {
 	level := node.GetIntAttr("level", 0)
 	if level <= 0 {
 		level = node.Level
@@ -61,8 +62,12 @@ func (s *state) convertHeading(node Node) (string, error) {
 	if level <= 0 {
 		level = 1
 	}
+	return level
+}
 
-	level += s.config.HeadingOffset
+// convertHeading converts a heading node to markdown
+func (s *state) convertHeading(node Node) (string, error) {
+	level := headingLevel(node) + s.config.HeadingOffset
 
 	// Clamp level to valid range (1-6)
 	if level < 1 {
@@ -110,7 +115,9 @@ func (s *state) convertBlockquote(node Node) (string, error) {
 	}
 
 	// Process child content recursively
-	sbStr, err := s.convertChildren(node.Content)
+	sbStr, err := s.withWrapIndent(2, 0, func() (string, error) {
+		return s.convertChildren(node.Content)
+	})
 	if err != nil {
 		return "", err
 	}
@@ -254,8 +261,16 @@ func (s *state) convertPanel(node Node) (string, error) {
 		return "", nil
 	}
 
+	panelType := strings.ToLower(node.GetStringAttr("panelType", ""))
+	hasPanelType := panelType != ""
+	panelTitle := node.GetStringAttr("title", "")
+	panelUpper, panelTitleCase := panelTypeLabels(panelType)
+
 	// Check if panel has actual content or just whitespace
-	fullContent, err := s.convertChildren(node.Content)
+	indent, lead := s.panelWrapPrefix(panelTitleCase, hasPanelType)
+	fullContent, err := s.withWrapIndent(indent, lead, func() (string, error) {
+		return s.convertChildren(node.Content)
+	})
 	if err != nil {
 		return "", err
 	}
@@ -264,12 +279,17 @@ func (s *state) convertPanel(node Node) (string, error) {
 		return "", nil
 	}
 
-	panelType := strings.ToLower(node.GetStringAttr("panelType", ""))
-	hasPanelType := panelType != ""
-	panelTitle := node.GetStringAttr("title", "")
-	panelUpper, panelTitleCase := panelTypeLabels(panelType)
-
 	switch s.config.PanelStyle {
+	case PanelMkDocs:
+		return renderMkDocsAdmonition("!!!", admonitionKeyword(PanelMkDocs, panelType), panelTitle, fullContent), nil
+	case PanelDocusaurus:
+		return renderDocusaurusAdmonition(admonitionKeyword(PanelDocusaurus, panelType), panelTitle, fullContent), nil
+	case PanelHugo:
+		return renderHugoShortcode("admonition", hugoParam("type", admonitionKeyword(PanelHugo, panelType))+hugoParam("title", panelTitle), fullContent), nil
+	case PanelObsidian:
+		return s.renderCallout(admonitionKeyword(PanelObsidian, panelType), "", panelTitle, fullContent), nil
+	case PanelGitLab:
+		return s.renderCallout(gitLabAlertType(panelType), "", panelTitle, fullContent), nil
 	case PanelNone:
 		quoted := s.blockquoteContent(fullContent, "")
 		if quoted == "" {
@@ -279,7 +299,7 @@ func (s *state) convertPanel(node Node) (string, error) {
 	case PanelBold:
 		prefix := ""
 		if hasPanelType {
-			prefix = fmt.Sprintf("**%s**: ", panelTitleCase)
+			prefix = panelBoldPrefix(panelTitleCase)
 		}
 		quoted := s.blockquoteContent(fullContent, prefix)
 		if quoted == "" {
@@ -320,6 +340,26 @@ func (s *state) convertPanel(node Node) (string, error) {
 	}
 }
 
+// panelWrapPrefix returns the width of the prefix that the panel style adds to every
+// line of panel content, and of the label that bold panels add to its first line.
+func (s *state) panelWrapPrefix(panelTitleCase string, hasPanelType bool) (int, int) {
+	switch s.config.PanelStyle {
+	case PanelMkDocs:
+		return 4, 0
+	case PanelDocusaurus, PanelHugo:
+		return 0, 0
+	case PanelBold:
+		if hasPanelType {
+			return 2, utf8.RuneCountInString(panelBoldPrefix(panelTitleCase))
+		}
+	}
+	return 2, 0
+}
+
+func panelBoldPrefix(panelTitleCase string) string {
+	return fmt.Sprintf("**%s**: ", panelTitleCase)
+}
+
 // convertDecisionList converts a decision list to a single continuous blockquote
 func (s *state) convertDecisionList(node Node) (string, error) {
 	if len(node.Content) == 0 {
@@ -355,40 +395,39 @@ func (s *state) convertDecisionItem(node Node) (string, error) {
 	return s.convertDecisionItemContent(node)
 }
 
-// convertDecisionItemContent processes a decision item's content
-func (s *state) convertDecisionItemContent(node Node) (string, error) {
-	if len(node.Content) == 0 {
-		return "", nil
-	}
-
-	// Get decision state
-	state := node.GetStringAttr("state", "")
-
-	// Map state to prefix
-	prefix := ""
+// decisionPrefix returns the bold label that starts a decision item in the given state.
+func (s *state) decisionPrefix(state string) string {
 	switch state {
 	case "DECIDED":
 		if s.config.DecisionStyle == DecisionText {
-			prefix = "**DECIDED**: "
-		} else {
-			prefix = "**✓ Decision**: "
+			return "**DECIDED**: "
 		}
+		return "**✓ Decision**: "
 	case "UNDECIDED":
 		if s.config.DecisionStyle == DecisionText {
-			prefix = "**UNDECIDED**: "
-		} else {
-			prefix = "**? Decision**: "
+			return "**UNDECIDED**: "
 		}
+		return "**? Decision**: "
 	default:
 		if s.config.DecisionStyle == DecisionText {
-			prefix = "**DECISION**: "
-		} else {
-			prefix = "**Decision**: "
+			return "**DECISION**: "
 		}
+		return "**Decision**: "
+	}
+}
+
+// convertDecisionItemContent processes a decision item's content
+func (s *state) convertDecisionItemContent(node Node) (string, error) {
+	if len(node.Content) == 0 {
+		return "", nil
 	}
 
+	prefix := s.decisionPrefix(node.GetStringAttr("state", ""))
+
 	// Process content
-	sbStr, err := s.convertChildren(node.Content)
+	sbStr, err := s.withWrapIndent(2, utf8.RuneCountInString(prefix), func() (string, error) {
+		return s.convertChildren(node.Content)
+	})
 	if err != nil {
 		return "", err
 	}
@@ -434,12 +473,22 @@ func (s *state) convertExpand(node Node) (string, error) {
 	title := node.GetStringAttr("title", "")
 
 	// Process content
-	content, err := s.convertChildren(node.Content)
+	content, err := s.withWrapIndent(s.expandWrapIndent(), 0, func() (string, error) {
+		return s.convertChildren(node.Content)
+	})
 	if err != nil {
 		return "", err
 	}
 
-	if s.config.ExpandStyle == ExpandHTML {
+	switch s.config.ExpandStyle {
+	case ExpandMkDocs:
+		return renderMkDocsAdmonition("???", "note", title, content), nil
+	case ExpandHugo:
+		return renderHugoShortcode("details", hugoParam("summary", title), content), nil
+	case ExpandObsidian:
+		return s.renderCallout("note", "-", title, content), nil
+	}
+	if s.config.ExpandStyle == ExpandHTML || s.config.ExpandStyle == ExpandDocusaurus {
 		var htmlBuilder strings.Builder
 		htmlBuilder.WriteString("<details><summary>")
 		htmlBuilder.WriteString(html.EscapeString(title))
@@ -480,6 +529,19 @@ func (s *state) convertExpand(node Node) (string, error) {
 	return text.String() + "\n\n", nil
 }
 
+// expandWrapIndent returns the width of the prefix that the expand style adds to every
+// line of expand content.
+func (s *state) expandWrapIndent() int {
+	switch s.config.ExpandStyle {
+	case ExpandBlockquote, ExpandObsidian:
+		return 2
+	case ExpandMkDocs:
+		return 4
+	default:
+		return 0
+	}
+}
+
 // convertLayoutSection converts a layout section node
 func (s *state) convertLayoutSection(node Node) (string, error) {
 	if len(node.Content) == 0 {
@@ -0,0 +1,462 @@
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// TokenCounter returns the number of tokens in text for a ChunkOptions.MaxTokens budget.
+type TokenCounter func(text string) int
+
+// ChunkOptions controls how a document is split into chunks.
+// At least one of MaxChars and MaxTokens must be set; when both are set, chunks respect both.
+type ChunkOptions struct {
+	// MaxChars is the character (rune) budget for each chunk's Markdown.
+	MaxChars int `json:"maxChars,omitempty"`
+	// MaxTokens is the token budget for each chunk's Markdown, measured with CountTokens.
+	MaxTokens int `json:"maxTokens,omitempty"`
+	// CountTokens counts tokens. It defaults to EstimateTokens.
+	CountTokens TokenCounter `json:"-"`
+}
+
+// Chunk is a self-contained Markdown fragment of a converted document.
+type Chunk struct {
+	Markdown string `json:"markdown"`
+	// Breadcrumb holds the text of the enclosing headings, outermost first, including the
+	// heading that opens the chunk.
+	Breadcrumb []string `json:"breadcrumb,omitempty"`
+	// StartPath and EndPath are JSON Pointer paths of the first and last ADF node the chunk
+	// was rendered from. For split tables and lists they point at rows or items.
+	StartPath  string `json:"startPath"`
+	EndPath    string `json:"endPath"`
+	Characters int    `json:"characters"`
+	Tokens     int    `json:"tokens"`
+	// Oversized marks a chunk holding a single block that exceeds the budget but cannot be
+	// split (a code block, a table row, a list item, ...).
+	Oversized bool `json:"oversized,omitempty"`
+}
+
+// ChunkResult holds the chunks of a document and the warnings raised while converting it.
+type ChunkResult struct {
+	Chunks   []Chunk   `json:"chunks"`
+	Warnings []Warning `json:"warnings,omitempty"`
+}
+
+// EstimateTokens approximates the token count of text as one token per four characters.
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// Validate checks that the options describe a usable budget.
+func (o ChunkOptions) Validate() error {
+	if o.MaxChars < 0 {
+		return fmt.Errorf("maxChars must be non-negative, got %d", o.MaxChars)
+	}
+	if o.MaxTokens < 0 {
+		return fmt.Errorf("maxTokens must be non-negative, got %d", o.MaxTokens)
+	}
+	if o.MaxChars == 0 && o.MaxTokens == 0 {
+		return errors.New("chunk budget requires maxChars or maxTokens")
+	}
+	return nil
+}
+
+// Chunk converts an ADF JSON document and splits the Markdown into chunks.
+func (c *Converter) Chunk(input []byte, opts ChunkOptions) (ChunkResult, error) {
+	return c.ChunkWithContext(context.Background(), input, ConvertOptions{}, opts)
+}
+
+// ChunkWithContext converts an ADF JSON document and splits the Markdown into chunks that
+// fit the budget. Chunks break before every heading; other top-level blocks are packed
+// until the budget is reached. Code blocks, table rows and list items are never split;
+// oversized tables are split by rows with the header row repeated, and oversized lists,
+// panels and blockquotes are split between their children. Every chunk is valid Markdown
+// on its own.
+//
+// Oversized blocks are rendered again to find split points: every child once on its own
+// and every part once more, so link and media hooks may be invoked repeatedly for the same
+// reference; configure HookCache when hooks are expensive.
+func (c *Converter) ChunkWithContext(ctx context.Context, input []byte, convertOpts ConvertOptions, opts ChunkOptions) (ChunkResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := opts.Validate(); err != nil {
+		return ChunkResult{}, err
+	}
+	if opts.CountTokens == nil {
+		opts.CountTokens = EstimateTokens
+	}
+	if err := ctx.Err(); err != nil {
+		return ChunkResult{}, err
+	}
+
+	var doc Doc
+	if err := json.Unmarshal(input, &doc); err != nil {
+		return ChunkResult{}, fmt.Errorf("failed to parse ADF JSON: %w", err)
+	}
+
+	s := &state{
+		config:     c.config,
+		ctx:        ctx,
+		options:    convertOpts,
+		cacheStats: &hookCacheCounter{},
+	}
+	s.format = markdownFormat{s}
+	// Chunks are read on their own, so links stay inline.
+	s.config.LinkStyle = LinkInline
+	if err := s.resolveBatch(doc.Content); err != nil {
+		return ChunkResult{}, err
+	}
+
+	ch := &chunker{state: s, opts: opts}
+	if err := ch.chunkDocument(doc.Content); err != nil {
+		return ChunkResult{}, err
+	}
+	if err := s.checkContext(); err != nil {
+		return ChunkResult{}, err
+	}
+
+	return ChunkResult{Chunks: ch.chunks, Warnings: s.warnings}, nil
+}
+
+// chunkUnit is a rendered block, or a re-wrapped part of one, that is packed as a whole.
+type chunkUnit struct {
+	node      Node
+	markdown  string
+	startPath string
+	endPath   string
+	oversized bool
+}
+
+type chunker struct {
+	state      *state
+	opts       ChunkOptions
+	chunks     []Chunk
+	headings   []chunkHeading
+	pending    []chunkUnit
+	breadcrumb []string
+}
+
+type chunkHeading struct {
+	level int
+	text  string
+}
+
+func (ch *chunker) chunkDocument(content []Node) error {
+	for index, node := range content {
+		nodePath := "/content/" + strconv.Itoa(index)
+
+		// Render with the main state first so warnings and errors match Convert.
+		markdown, err := ch.state.convertNode(node)
+		if err != nil {
+			return err
+		}
+
+		if node.Type == "heading" {
+			ch.flush()
+			ch.pushHeading(node)
+		}
+
+		unit := chunkUnit{node: node, markdown: markdown, startPath: nodePath, endPath: nodePath}
+		if ch.fits(unit.markdown) {
+			ch.add(unit)
+			continue
+		}
+
+		units, err := ch.split(unit)
+		if err != nil {
+			return err
+		}
+		for _, part := range units {
+			ch.add(part)
+		}
+	}
+	ch.flush()
+	return nil
+}
+
+func (ch *chunker) pushHeading(node Node) {
+	level := headingLevel(node)
+	for len(ch.headings) > 0 && ch.headings[len(ch.headings)-1].level >= level {
+		ch.headings = ch.headings[:len(ch.headings)-1]
+	}
+	ch.headings = append(ch.headings, chunkHeading{level: level, text: strings.TrimSpace(plainText(node.Content))})
+
+	ch.breadcrumb = make([]string, len(ch.headings))
+	for index, heading := range ch.headings {
+		ch.breadcrumb[index] = heading.text
+	}
+}
+
+// add appends unit to the pending chunk, flushing first when it would exceed the budget.
+func (ch *chunker) add(unit chunkUnit) {
+	if strings.TrimSpace(unit.markdown) == "" {
+		return
+	}
+	if unit.oversized {
+		ch.flush()
+		ch.pending = append(ch.pending, unit)
+		ch.flush()
+		return
+	}
+	if len(ch.pending) > 0 && !ch.fits(joinChunkUnits(append(ch.pending[:len(ch.pending):len(ch.pending)], unit))) {
+		ch.flush()
+	}
+	ch.pending = append(ch.pending, unit)
+}
+
+func (ch *chunker) flush() {
+	if len(ch.pending) == 0 {
+		return
+	}
+
+	markdown := joinChunkUnits(ch.pending)
+	oversized := false
+	for _, unit := range ch.pending {
+		oversized = oversized || unit.oversized
+	}
+	ch.chunks = append(ch.chunks, Chunk{
+		Markdown:   markdown,
+		Breadcrumb: ch.breadcrumb,
+		StartPath:  ch.pending[0].startPath,
+		EndPath:    ch.pending[len(ch.pending)-1].endPath,
+		Characters: utf8.RuneCountInString(markdown),
+		Tokens:     ch.opts.CountTokens(markdown),
+		Oversized:  oversized,
+	})
+	ch.pending = nil
+}
+
+func (ch *chunker) fits(markdown string) bool {
+	return ch.within(ch.measure(markdown))
+}
+
+// chunkSize is the size of rendered Markdown in the units of the chunk budget.
+type chunkSize struct {
+	chars  int
+	tokens int
+}
+
+func (ch *chunker) measure(markdown string) chunkSize {
+	markdown = finishDocument(markdown)
+	size := chunkSize{chars: utf8.RuneCountInString(markdown)}
+	if ch.opts.MaxTokens > 0 {
+		size.tokens = ch.opts.CountTokens(markdown)
+	}
+	return size
+}
+
+func (ch *chunker) within(size chunkSize) bool {
+	if ch.opts.MaxChars > 0 && size.chars > ch.opts.MaxChars {
+		return false
+	}
+	if ch.opts.MaxTokens > 0 && size.tokens > ch.opts.MaxTokens {
+		return false
+	}
+	return true
+}
+
+// split breaks an oversized unit into units that fit the budget, re-wrapping groups of
+// children in a copy of the block. Blocks that cannot be split stay a single oversized unit.
+func (ch *chunker) split(unit chunkUnit) ([]chunkUnit, error) {
+	node := unit.node
+	unit.oversized = true
+
+	var header []Node
+	children := node.Content
+	offset := 0
+	nestedSplit := false
+	switch node.Type {
+	case "table":
+		if len(children) > 0 && isTableHeaderRow(children[0]) {
+			header = children[:1]
+			children = children[1:]
+			offset = 1
+		}
+	case "bulletList", "orderedList", "taskList", "decisionList":
+	case "panel", "blockquote":
+		nestedSplit = true
+	default:
+		return []chunkUnit{unit}, nil
+	}
+	if len(children) == 0 {
+		return []chunkUnit{unit}, nil
+	}
+
+	// Every child is rendered once on its own. A group's size is estimated by adding the
+	// children's sizes minus the container around them, and the group is rendered when it
+	// is flushed.
+	empty, err := ch.renderScratch(wrapChunkChildren(node, header, nil, 0))
+	if err != nil {
+		return nil, err
+	}
+	base := ch.measure(empty)
+
+	var units []chunkUnit
+	var group []Node
+	var groupSize chunkSize
+	groupStart := 0
+
+	// render wraps children[start:start+len(nodes)] in the container and renders it.
+	render := func(nodes []Node, start int) (chunkUnit, error) {
+		wrapped := wrapChunkChildren(node, header, nodes, start)
+		markdown, err := ch.renderScratch(wrapped)
+		if err != nil {
+			return chunkUnit{}, err
+		}
+		return chunkUnit{
+			node:      wrapped,
+			markdown:  markdown,
+			startPath: childPath(unit.startPath, offset+start),
+			endPath:   childPath(unit.startPath, offset+start+len(nodes)-1),
+		}, nil
+	}
+	flushGroup := func() error {
+		for len(group) > 0 {
+			part, err := render(group, groupStart)
+			if err != nil {
+				return err
+			}
+			// The estimate can fall short, for example by the blank lines between
+			// children; leave the last children for the next part until it fits.
+			count := len(group)
+			for count > 1 && !ch.fits(part.markdown) {
+				count--
+				if part, err = render(group[:count], groupStart); err != nil {
+					return err
+				}
+			}
+			units = append(units, part)
+			group = group[count:]
+			groupStart += count
+		}
+		groupSize = chunkSize{}
+		return nil
+	}
+
+	for index, child := range children {
+		if err := ch.state.checkContext(); err != nil {
+			return nil, err
+		}
+
+		single, err := render([]Node{child}, index)
+		if err != nil {
+			return nil, err
+		}
+		size := ch.measure(single.markdown)
+		if ch.within(size) {
+			added := chunkSize{chars: size.chars - base.chars, tokens: size.tokens - base.tokens}
+			if len(group) > 0 && !ch.within(chunkSize{chars: groupSize.chars + added.chars, tokens: groupSize.tokens + added.tokens}) {
+				if err := flushGroup(); err != nil {
+					return nil, err
+				}
+			}
+			if len(group) == 0 {
+				groupStart = index
+				groupSize = size
+			} else {
+				groupSize.chars += added.chars
+				groupSize.tokens += added.tokens
+			}
+			group = append(group, child)
+			continue
+		}
+		if err := flushGroup(); err != nil {
+			return nil, err
+		}
+		if !nestedSplit {
+			single.oversized = true
+			units = append(units, single)
+			continue
+		}
+
+		// Split the child on its own, then wrap every part back into the container so it
+		// keeps the panel or quote rendering.
+		childMarkdown, err := ch.renderScratch(child)
+		if err != nil {
+			return nil, err
+		}
+		childPathValue := childPath(unit.startPath, offset+index)
+		parts, err := ch.split(chunkUnit{node: child, markdown: childMarkdown, startPath: childPathValue, endPath: childPathValue})
+		if err != nil {
+			return nil, err
+		}
+		for _, part := range parts {
+			wrapped := wrapChunkChildren(node, nil, []Node{part.node}, 0)
+			markdown, err := ch.renderScratch(wrapped)
+			if err != nil {
+				return nil, err
+			}
+			units = append(units, chunkUnit{
+				node:      wrapped,
+				markdown:  markdown,
+				startPath: part.startPath,
+				endPath:   part.endPath,
+				oversized: part.oversized || !ch.fits(markdown),
+			})
+		}
+	}
+	if err := flushGroup(); err != nil {
+		return nil, err
+	}
+
+	return units, nil
+}
+
+// renderScratch renders node with a throwaway state so that trial renders do not repeat warnings.
+func (ch *chunker) renderScratch(node Node) (string, error) {
+	scratch := &state{
+		config:     ch.state.config,
+		ctx:        ch.state.ctx,
+		options:    ch.state.options,
+		batch:      ch.state.batch,
+		cacheStats: ch.state.cacheStats,
+	}
+	scratch.format = markdownFormat{scratch}
+	return scratch.convertNode(node)
+}
+
+// wrapChunkChildren copies container with its children replaced by header followed by
+// children. Ordered lists keep their numbering by shifting the order attribute.
+func wrapChunkChildren(container Node, header, children []Node, start int) Node {
+	wrapped := container
+	wrapped.Content = append(append([]Node{}, header...), children...)
+	if container.Type == "orderedList" && start > 0 {
+		attrs := cloneAnyMap(container.Attrs)
+		if attrs == nil {
+			attrs = make(map[string]interface{})
+		}
+		attrs["order"] = float64(container.GetIntAttr("order", 1) + start)
+		wrapped.Attrs = attrs
+	}
+	return wrapped
+}
+
+func isTableHeaderRow(row Node) bool {
+	if row.Type != "tableRow" || len(row.Content) == 0 {
+		return false
+	}
+	for _, cell := range row.Content {
+		if cell.Type != "tableHeader" {
+			return false
+		}
+	}
+	return true
+}
+
+func childPath(parentPath string, index int) string {
+	return parentPath + "/content/" + strconv.Itoa(index)
+}
+
+func joinChunkUnits(units []chunkUnit) string {
+	var sb strings.Builder
+	for _, unit := range units {
+		sb.WriteString(unit.markdown)
+	}
+	return finishDocument(sb.String())
+}
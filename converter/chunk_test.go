@@ -0,0 +1,209 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func paragraphJSON(text string) string {
+	return fmt.Sprintf(`{"type":"paragraph","content":[{"type":"text","text":%q}]}`, text)
+}
+
+func headingJSON(level int, text string) string {
+	return fmt.Sprintf(`{"type":"heading","attrs":{"level":%d},"content":[{"type":"text","text":%q}]}`, level, text)
+}
+
+func docJSON(blocks ...string) []byte {
+	return []byte(`{"type":"doc","content":[` + strings.Join(blocks, ",") + `]}`)
+}
+
+func TestChunkBreaksAtHeadingsWithBreadcrumbs(t *testing.T) {
+	input := docJSON(
+		paragraphJSON("Intro"),
+		headingJSON(1, "Guide"),
+		paragraphJSON("Guide text"),
+		headingJSON(2, "Install"),
+		paragraphJSON("Install text"),
+		headingJSON(2, "Usage"),
+		paragraphJSON("Usage text"),
+	)
+
+	result, err := newTestConverter(t, Config{}).Chunk(input, ChunkOptions{MaxChars: 1000})
+	require.NoError(t, err)
+
+	require.Len(t, result.Chunks, 4)
+	assert.Equal(t, "Intro\n", result.Chunks[0].Markdown)
+	assert.Empty(t, result.Chunks[0].Breadcrumb)
+	assert.Equal(t, "# Guide\n\nGuide text\n", result.Chunks[1].Markdown)
+	assert.Equal(t, []string{"Guide"}, result.Chunks[1].Breadcrumb)
+	assert.Equal(t, []string{"Guide", "Install"}, result.Chunks[2].Breadcrumb)
+	assert.Equal(t, []string{"Guide", "Usage"}, result.Chunks[3].Breadcrumb)
+	assert.Equal(t, "/content/5", result.Chunks[3].StartPath)
+	assert.Equal(t, "/content/6", result.Chunks[3].EndPath)
+}
+
+func TestChunkPacksBlocksWithinBudget(t *testing.T) {
+	input := docJSON(
+		paragraphJSON(strings.Repeat("a", 30)),
+		paragraphJSON(strings.Repeat("b", 30)),
+		paragraphJSON(strings.Repeat("c", 30)),
+	)
+
+	result, err := newTestConverter(t, Config{}).Chunk(input, ChunkOptions{MaxChars: 70})
+	require.NoError(t, err)
+
+	require.Len(t, result.Chunks, 2)
+	assert.Equal(t, strings.Repeat("a", 30)+"\n\n"+strings.Repeat("b", 30)+"\n", result.Chunks[0].Markdown)
+	assert.Equal(t, "/content/0", result.Chunks[0].StartPath)
+	assert.Equal(t, "/content/1", result.Chunks[0].EndPath)
+	assert.Equal(t, strings.Repeat("c", 30)+"\n", result.Chunks[1].Markdown)
+	for _, chunk := range result.Chunks {
+		assert.LessOrEqual(t, chunk.Characters, 70)
+		assert.False(t, chunk.Oversized)
+	}
+}
+
+func TestChunkSplitsOversizedTableByRowsRepeatingHeader(t *testing.T) {
+	cell := func(cellType, text string) string {
+		return fmt.Sprintf(`{"type":%q,"content":[%s]}`, cellType, paragraphJSON(text))
+	}
+	rows := []string{fmt.Sprintf(`{"type":"tableRow","content":[%s,%s]}`, cell("tableHeader", "Key"), cell("tableHeader", "Value"))}
+	for i := 0; i < 6; i++ {
+		rows = append(rows, fmt.Sprintf(`{"type":"tableRow","content":[%s,%s]}`, cell("tableCell", fmt.Sprintf("k%d", i)), cell("tableCell", fmt.Sprintf("v%d", i))))
+	}
+	input := docJSON(`{"type":"table","content":[` + strings.Join(rows, ",") + `]}`)
+
+	full, err := newTestConverter(t, Config{}).Convert(input)
+	require.NoError(t, err)
+
+	result, err := newTestConverter(t, Config{}).Chunk(input, ChunkOptions{MaxChars: 80})
+	require.NoError(t, err)
+
+	require.Greater(t, len(result.Chunks), 1)
+	assert.Greater(t, len(full.Markdown), 80)
+	var rowCount int
+	for _, chunk := range result.Chunks {
+		assert.True(t, strings.HasPrefix(chunk.Markdown, "| Key | Value |\n| --- | --- |\n"), chunk.Markdown)
+		assert.LessOrEqual(t, chunk.Characters, 80)
+		rowCount += strings.Count(chunk.Markdown, "\n") - 2
+	}
+	assert.Equal(t, 6, rowCount)
+	assert.Equal(t, "/content/0/content/1", result.Chunks[0].StartPath)
+	assert.Equal(t, "/content/0/content/6", result.Chunks[len(result.Chunks)-1].EndPath)
+}
+
+func TestChunkSplitsListsBetweenItemsAndKeepsNumbering(t *testing.T) {
+	var items []string
+	for i := 0; i < 4; i++ {
+		items = append(items, fmt.Sprintf(`{"type":"listItem","content":[%s]}`, paragraphJSON(fmt.Sprintf("item %d %s", i+1, strings.Repeat("x", 20)))))
+	}
+	input := docJSON(`{"type":"orderedList","attrs":{"order":3},"content":[` + strings.Join(items, ",") + `]}`)
+
+	result, err := newTestConverter(t, Config{}).Chunk(input, ChunkOptions{MaxChars: 70})
+	require.NoError(t, err)
+
+	require.Len(t, result.Chunks, 2)
+	assert.Equal(t, "3. item 1 xxxxxxxxxxxxxxxxxxxx\n4. item 2 xxxxxxxxxxxxxxxxxxxx\n", result.Chunks[0].Markdown)
+	assert.Equal(t, "5. item 3 xxxxxxxxxxxxxxxxxxxx\n6. item 4 xxxxxxxxxxxxxxxxxxxx\n", result.Chunks[1].Markdown)
+	assert.Equal(t, "/content/0/content/2", result.Chunks[1].StartPath)
+	assert.Equal(t, "/content/0/content/3", result.Chunks[1].EndPath)
+}
+
+func TestChunkSplitMeasuresEachItemOnce(t *testing.T) {
+	var items []string
+	for i := 0; i < 200; i++ {
+		items = append(items, fmt.Sprintf(`{"type":"listItem","content":[%s]}`, paragraphJSON(fmt.Sprintf("item %03d", i))))
+	}
+	input := docJSON(`{"type":"bulletList","content":[` + strings.Join(items, ",") + `]}`)
+
+	measured := 0
+	words := func(text string) int {
+		measured += len(text)
+		return len(strings.Fields(text))
+	}
+	result, err := newTestConverter(t, Config{}).Chunk(input, ChunkOptions{MaxTokens: 60, CountTokens: words})
+	require.NoError(t, err)
+
+	var markdown strings.Builder
+	for _, chunk := range result.Chunks {
+		assert.False(t, chunk.Oversized)
+		assert.LessOrEqual(t, chunk.Tokens, 60)
+		markdown.WriteString(chunk.Markdown)
+	}
+	assert.Equal(t, 200, strings.Count(markdown.String(), "- item "))
+
+	// Re-rendering every growing group measured this list about fifteen times over.
+	assert.Less(t, measured, 8*markdown.Len())
+}
+
+func TestChunkNeverSplitsCodeBlocks(t *testing.T) {
+	code := strings.Repeat("line\n", 40)
+	input := docJSON(
+		paragraphJSON("before"),
+		fmt.Sprintf(`{"type":"codeBlock","attrs":{"language":"text"},"content":[{"type":"text","text":%q}]}`, code),
+		paragraphJSON("after"),
+	)
+
+	result, err := newTestConverter(t, Config{}).Chunk(input, ChunkOptions{MaxChars: 50})
+	require.NoError(t, err)
+
+	require.Len(t, result.Chunks, 3)
+	assert.True(t, result.Chunks[1].Oversized)
+	assert.Equal(t, "```text\n"+code+"```\n", result.Chunks[1].Markdown)
+	assert.False(t, result.Chunks[0].Oversized)
+	assert.False(t, result.Chunks[2].Oversized)
+}
+
+func TestChunkSplitsOversizedPanelKeepingPanelRendering(t *testing.T) {
+	input := docJSON(`{"type":"panel","attrs":{"panelType":"info"},"content":[` +
+		paragraphJSON(strings.Repeat("a", 40)) + "," + paragraphJSON(strings.Repeat("b", 40)) + `]}`)
+
+	conv := newTestConverter(t, Config{PanelStyle: PanelGitHub})
+	result, err := conv.Chunk(input, ChunkOptions{MaxChars: 70})
+	require.NoError(t, err)
+
+	require.Len(t, result.Chunks, 2)
+	for _, chunk := range result.Chunks {
+		assert.True(t, strings.HasPrefix(chunk.Markdown, "> [!INFO]\n"), chunk.Markdown)
+	}
+	assert.Equal(t, "/content/0/content/1", result.Chunks[1].StartPath)
+}
+
+func TestChunkTokenBudget(t *testing.T) {
+	input := docJSON(paragraphJSON("one two three"), paragraphJSON("four five six"))
+
+	words := func(text string) int { return len(strings.Fields(text)) }
+	result, err := newTestConverter(t, Config{}).Chunk(input, ChunkOptions{MaxTokens: 4, CountTokens: words})
+	require.NoError(t, err)
+
+	require.Len(t, result.Chunks, 2)
+	assert.Equal(t, 3, result.Chunks[0].Tokens)
+}
+
+func TestChunkReportsWarningsOnceAndValidatesOptions(t *testing.T) {
+	var items []string
+	for i := 0; i < 3; i++ {
+		items = append(items, fmt.Sprintf(`{"type":"listItem","content":[%s,{"type":"mystery%d"}]}`, paragraphJSON(strings.Repeat("x", 30)), i))
+	}
+	input := docJSON(`{"type":"bulletList","content":[` + strings.Join(items, ",") + `]}`)
+
+	conv := newTestConverter(t, Config{UnknownNodes: UnknownPlaceholder})
+	result, err := conv.Chunk(input, ChunkOptions{MaxChars: 80})
+	require.NoError(t, err)
+	assert.Len(t, result.Warnings, 3)
+
+	_, err = conv.Chunk(input, ChunkOptions{})
+	require.Error(t, err)
+	_, err = conv.Chunk(input, ChunkOptions{MaxChars: -1})
+	require.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = conv.ChunkWithContext(ctx, input, ConvertOptions{}, ChunkOptions{MaxChars: 10})
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,107 @@
+package converter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestConvertCommonMarkProfile(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"table","content":[
+			{"type":"tableRow","content":[
+				{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"Name"}]}]},
+				{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"Role"}]}]},
+				{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"Notes"}]}]}
+			]},
+			{"type":"tableRow","content":[
+				{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"Alice"}]}]},
+				{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"Admin","marks":[{"type":"strong"}]}]}]},
+				{"type":"tableCell","content":[{"type":"bulletList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"one"}]}]}]}]}
+			]},
+			{"type":"tableRow","content":[
+				{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"Bob"}]}]},
+				{"type":"tableCell","content":[]},
+				{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"Away"}]}]}
+			]}
+		]},
+		{"type":"taskList","content":[
+			{"type":"taskItem","attrs":{"state":"DONE"},"content":[{"type":"text","text":"shipped"}]},
+			{"type":"taskItem","attrs":{"state":"TODO"},"content":[{"type":"text","text":"later"}]}
+		]},
+		{"type":"paragraph","content":[
+			{"type":"text","text":"old","marks":[{"type":"strike"}]},
+			{"type":"text","text":" <b>bold</b> H"},
+			{"type":"text","text":"2","marks":[{"type":"subsup","attrs":{"type":"sub"}}]},
+			{"type":"text","text":"O "},
+			{"type":"text","text":"under","marks":[{"type":"underline"}]},
+			{"type":"text","text":" "},
+			{"type":"text","text":"<tag>","marks":[{"type":"code"}]}
+		]},
+		{"type":"expand","attrs":{"title":"More"},"content":[{"type":"paragraph","content":[{"type":"text","text":"hidden"}]}]},
+		{"type":"panel","attrs":{"panelType":"info"},"content":[{"type":"paragraph","content":[{"type":"text","text":"Note"}]}]}
+	]}`)
+
+	result, err := newTestConverter(t, Config{CommonMark: true}).Convert(input)
+	require.NoError(t, err)
+	assert.Equal(t, "- Name: Alice\n"+
+		"  - Role: **Admin**\n"+
+		"  - Notes:\n\n"+
+		"    - one\n\n"+
+		"- Name: Bob\n"+
+		"  - Role:\n"+
+		"  - Notes: Away\n\n"+
+		"- \\[x\\] shipped\n- \\[ \\] later\n\n"+
+		"old \\<b>bold\\</b> H$_{2}$O **under** `<tag>`\n\n"+
+		"> **More**\n> \n> hidden\n\n"+
+		"> **Info**: Note\n", result.Markdown)
+
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, WarningDroppedFeature, result.Warnings[0].Type)
+	assert.Equal(t, "strike", result.Warnings[0].NodeType)
+
+	source := []byte(result.Markdown)
+	doc := goldmark.New().Parser().Parse(text.NewReader(source))
+	err = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering && (n.Kind() == ast.KindRawHTML || n.Kind() == ast.KindHTMLBlock) {
+			t.Errorf("unexpected HTML node %s in CommonMark output", n.Kind())
+		}
+		return ast.WalkContinue, nil
+	})
+	require.NoError(t, err)
+
+	// GFM must read the output the same way plain CommonMark does.
+	var plain, gfm bytes.Buffer
+	require.NoError(t, goldmark.New().Convert(source, &plain))
+	require.NoError(t, goldmark.New(goldmark.WithExtensions(extension.GFM)).Convert(source, &gfm))
+	assert.Equal(t, plain.String(), gfm.String())
+}
+
+func TestConvertCommonMarkSimpleTableAsList(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[{"type":"table","content":[
+		{"type":"tableRow","content":[
+			{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"a"}]}]},
+			{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"b"}]}]}
+		]}
+	]}]}`)
+
+	result, err := newTestConverter(t, Config{CommonMark: true}).Convert(input)
+	require.NoError(t, err)
+	assert.Equal(t, "- a\n  - b\n", result.Markdown)
+}
+
+func TestCommonMarkRejectsHTMLStyles(t *testing.T) {
+	_, err := New(Config{CommonMark: true, ExpandStyle: ExpandHTML})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not allowed with commonMark output")
+
+	_, err = New(Config{CommonMark: true, TableMode: TableAutoPandoc})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tableMode")
+}
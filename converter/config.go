@@ -33,6 +33,9 @@ const (
 	ColorIgnore ColorStyle = "ignore"
 	ColorHTML   ColorStyle = "html"
 	ColorPandoc ColorStyle = "pandoc"
+	// ColorObsidian renders background colors as Obsidian ==highlights==, dropping the
+	// color itself. It is only valid for BackgroundColorStyle.
+	ColorObsidian ColorStyle = "obsidian"
 )
 
 // MentionStyle controls how user mentions are rendered.
@@ -61,6 +64,16 @@ const (
 	PanelBold   PanelStyle = "bold"
 	PanelGitHub PanelStyle = "github"
 	PanelTitle  PanelStyle = "title"
+	// PanelMkDocs renders MkDocs Material admonitions (!!! note "Title").
+	PanelMkDocs PanelStyle = "mkdocs"
+	// PanelDocusaurus renders Docusaurus admonitions (:::tip Title).
+	PanelDocusaurus PanelStyle = "docusaurus"
+	// PanelHugo renders Hugo admonition shortcodes ({{< admonition type="note" >}}).
+	PanelHugo PanelStyle = "hugo"
+	// PanelObsidian renders Obsidian callouts (> [!tip] Title).
+	PanelObsidian PanelStyle = "obsidian"
+	// PanelGitLab renders GitLab alerts (> [!note] Title).
+	PanelGitLab PanelStyle = "gitlab"
 )
 
 // AlignmentStyle controls how block alignment is rendered.
@@ -87,6 +100,14 @@ const (
 	ExpandBlockquote ExpandStyle = "blockquote"
 	ExpandHTML       ExpandStyle = "html"
 	ExpandPandoc     ExpandStyle = "pandoc"
+	// ExpandMkDocs renders collapsible MkDocs Material admonitions (??? note "Title").
+	ExpandMkDocs ExpandStyle = "mkdocs"
+	// ExpandDocusaurus renders <details> blocks, which Docusaurus styles as collapsibles.
+	ExpandDocusaurus ExpandStyle = "docusaurus"
+	// ExpandHugo renders Hugo details shortcodes ({{< details summary="Title" >}}).
+	ExpandHugo ExpandStyle = "hugo"
+	// ExpandObsidian renders folded Obsidian callouts (> [!note]- Title).
+	ExpandObsidian ExpandStyle = "obsidian"
 )
 
 // StatusStyle controls how status badges are rendered.
@@ -95,6 +116,53 @@ type StatusStyle string
 const (
 	StatusBracket StatusStyle = "bracket"
 	StatusText    StatusStyle = "text"
+	// StatusGitLab renders a GitLab colour chip followed by the bold status text.
+	StatusGitLab StatusStyle = "gitlab"
+)
+
+// TOCStyle controls how table of contents extensions (extensionKey "toc") are rendered.
+type TOCStyle string
+
+const (
+	// TOCExtension renders tables of contents like any other extension.
+	TOCExtension TOCStyle = "extension"
+	// TOCGitLab renders the GitLab [[_TOC_]] marker.
+	TOCGitLab TOCStyle = "gitlab"
+)
+
+// PlaceholderStyle controls how placeholder nodes (template prompts) are rendered.
+type PlaceholderStyle string
+
+const (
+	// PlaceholderDrop leaves placeholders out of the output.
+	PlaceholderDrop PlaceholderStyle = "drop"
+	// PlaceholderBraces renders the prompt text as {{text}}, the token that the adftemplate
+	// package fills.
+	PlaceholderBraces PlaceholderStyle = "braces"
+)
+
+// LinkStyle controls how link marks are rendered.
+type LinkStyle string
+
+const (
+	// LinkInline renders [text](href "title").
+	LinkInline LinkStyle = "inline"
+	// LinkNumbered renders numbered reference links ([text][1]), one per link.
+	LinkNumbered LinkStyle = "numbered"
+	// LinkNamed renders named reference links ([text][design-doc]) that links to the same
+	// target share.
+	LinkNamed LinkStyle = "named"
+)
+
+// LinkDefinitions controls where reference link definitions are written.
+type LinkDefinitions string
+
+const (
+	// LinkDefinitionsDocument writes all definitions at the end of the document.
+	LinkDefinitionsDocument LinkDefinitions = "document"
+	// LinkDefinitionsSection writes definitions before each top-level heading and at
+	// the end of the document.
+	LinkDefinitionsSection LinkDefinitions = "section"
 )
 
 // InlineCardStyle controls how smart links / inline cards are rendered.
@@ -151,6 +219,8 @@ const (
 	TableHTML       TableMode = "html"
 	TablePandoc     TableMode = "pandoc"
 	TableAutoPandoc TableMode = "autopandoc"
+	// TableList renders each row as a list item, with the other cells nested below the first.
+	TableList TableMode = "list"
 )
 
 // ExtensionMode controls how extension nodes are handled.
@@ -205,19 +275,33 @@ type Config struct {
 	LayoutSectionStyle   LayoutSectionStyle          `json:"layoutSectionStyle,omitempty"`
 	BodiedExtensionStyle BodiedExtensionStyle        `json:"bodiedExtensionStyle,omitempty"`
 	DecisionStyle        DecisionStyle               `json:"decisionStyle,omitempty"`
+	TOCStyle             TOCStyle                    `json:"tocStyle,omitempty"`
+	PlaceholderStyle     PlaceholderStyle            `json:"placeholderStyle,omitempty"`
+	LinkStyle            LinkStyle                   `json:"linkStyle,omitempty"`
+	LinkDefinitions      LinkDefinitions             `json:"linkDefinitions,omitempty"`
 	DateFormat           string                      `json:"dateFormat,omitempty"`
 	TableMode            TableMode                   `json:"tableMode,omitempty"`
 	BulletMarker         rune                        `json:"bulletMarker,omitempty"`
 	OrderedListStyle     OrderedListStyle            `json:"orderedListStyle,omitempty"`
 	Extensions           ExtensionRules              `json:"extensions,omitempty"`
 	MediaBaseURL         string                      `json:"mediaBaseURL,omitempty"`
+	WikiLinks            bool                        `json:"wikiLinks,omitempty"`
+	CommonMark           bool                        `json:"commonMark,omitempty"`
+	WrapWidth            int                         `json:"wrapWidth,omitempty"`
 	ResolutionMode       ResolutionMode              `json:"resolutionMode,omitempty"`
 	LanguageMap          map[string]string           `json:"languageMap,omitempty"`
+	Templates            map[string]string           `json:"templates,omitempty"`
 	UnknownNodes         UnknownPolicy               `json:"unknownNodes,omitempty"`
 	UnknownMarks         UnknownPolicy               `json:"unknownMarks,omitempty"`
+	Parallelism          int                         `json:"parallelism,omitempty"`
 	LinkHook             LinkRenderHook              `json:"-"`
 	MediaHook            MediaRenderHook             `json:"-"`
+	BatchResolver        BatchResolver               `json:"-"`
+	HookCache            HookCache                   `json:"-"`
 	ExtensionHandlers    map[string]ExtensionHandler `json:"-"`
+
+	// templates holds Templates once New has parsed them.
+	templates nodeTemplates
 }
 
 func (c Config) applyDefaults() Config {
@@ -226,6 +310,9 @@ func (c Config) applyDefaults() Config {
 	}
 	if c.SubSupStyle == "" {
 		c.SubSupStyle = SubSupHTML
+		if c.CommonMark {
+			c.SubSupStyle = SubSupLaTeX
+		}
 	}
 	if c.TextColorStyle == "" {
 		c.TextColorStyle = ColorIgnore
@@ -241,6 +328,9 @@ func (c Config) applyDefaults() Config {
 	}
 	if c.PanelStyle == "" {
 		c.PanelStyle = PanelGitHub
+		if c.CommonMark {
+			c.PanelStyle = PanelBold
+		}
 	}
 	if c.HardBreakStyle == "" {
 		c.HardBreakStyle = HardBreakBackslash
@@ -250,6 +340,9 @@ func (c Config) applyDefaults() Config {
 	}
 	if c.ExpandStyle == "" {
 		c.ExpandStyle = ExpandHTML
+		if c.CommonMark {
+			c.ExpandStyle = ExpandBlockquote
+		}
 	}
 	if c.StatusStyle == "" {
 		c.StatusStyle = StatusBracket
@@ -262,15 +355,33 @@ func (c Config) applyDefaults() Config {
 	}
 	if c.BodiedExtensionStyle == "" {
 		c.BodiedExtensionStyle = BodiedExtensionPandoc
+		if c.CommonMark {
+			c.BodiedExtensionStyle = BodiedExtensionStandard
+		}
 	}
 	if c.DecisionStyle == "" {
 		c.DecisionStyle = DecisionEmoji
 	}
+	if c.TOCStyle == "" {
+		c.TOCStyle = TOCExtension
+	}
+	if c.PlaceholderStyle == "" {
+		c.PlaceholderStyle = PlaceholderDrop
+	}
+	if c.LinkStyle == "" {
+		c.LinkStyle = LinkInline
+	}
+	if c.LinkDefinitions == "" {
+		c.LinkDefinitions = LinkDefinitionsDocument
+	}
 	if c.DateFormat == "" {
 		c.DateFormat = "2006-01-02"
 	}
 	if c.TableMode == "" {
 		c.TableMode = TableAuto
+		if c.CommonMark {
+			c.TableMode = TableList
+		}
 	}
 	if c.BulletMarker == 0 {
 		c.BulletMarker = '-'
@@ -299,8 +410,11 @@ func (c Config) clone() Config {
 	cloned := c
 	cloned.Extensions.ByType = cloneExtensionModeMap(c.Extensions.ByType)
 	cloned.LanguageMap = cloneStringMap(c.LanguageMap)
+	cloned.Templates = cloneStringMap(c.Templates)
 	cloned.LinkHook = c.LinkHook
 	cloned.MediaHook = c.MediaHook
+	cloned.BatchResolver = c.BatchResolver
+	cloned.HookCache = c.HookCache
 	cloned.ExtensionHandlers = cloneExtensionHandlerMap(c.ExtensionHandlers)
 	return cloned
 }
@@ -316,7 +430,8 @@ func (c Config) Validate() error {
 	if c.TextColorStyle != ColorIgnore && c.TextColorStyle != ColorHTML && c.TextColorStyle != ColorPandoc {
 		return fmt.Errorf("invalid textColorStyle %q", c.TextColorStyle)
 	}
-	if c.BackgroundColorStyle != ColorIgnore && c.BackgroundColorStyle != ColorHTML && c.BackgroundColorStyle != ColorPandoc {
+	if c.BackgroundColorStyle != ColorIgnore && c.BackgroundColorStyle != ColorHTML && c.BackgroundColorStyle != ColorPandoc &&
+		c.BackgroundColorStyle != ColorObsidian {
 		return fmt.Errorf("invalid backgroundColorStyle %q", c.BackgroundColorStyle)
 	}
 	if c.MentionStyle != MentionText && c.MentionStyle != MentionLink && c.MentionStyle != MentionHTML && c.MentionStyle != MentionPandoc {
@@ -325,7 +440,9 @@ func (c Config) Validate() error {
 	if c.EmojiStyle != EmojiShortcode && c.EmojiStyle != EmojiUnicode {
 		return fmt.Errorf("invalid emojiStyle %q", c.EmojiStyle)
 	}
-	if c.PanelStyle != PanelNone && c.PanelStyle != PanelBold && c.PanelStyle != PanelGitHub && c.PanelStyle != PanelTitle {
+	if c.PanelStyle != PanelNone && c.PanelStyle != PanelBold && c.PanelStyle != PanelGitHub && c.PanelStyle != PanelTitle &&
+		c.PanelStyle != PanelMkDocs && c.PanelStyle != PanelDocusaurus && c.PanelStyle != PanelHugo && c.PanelStyle != PanelObsidian &&
+		c.PanelStyle != PanelGitLab {
 		return fmt.Errorf("invalid panelStyle %q", c.PanelStyle)
 	}
 	if c.HeadingOffset < 0 || c.HeadingOffset > 5 {
@@ -337,10 +454,12 @@ func (c Config) Validate() error {
 	if c.AlignmentStyle != AlignIgnore && c.AlignmentStyle != AlignHTML && c.AlignmentStyle != AlignPandoc {
 		return fmt.Errorf("invalid alignmentStyle %q", c.AlignmentStyle)
 	}
-	if c.ExpandStyle != ExpandBlockquote && c.ExpandStyle != ExpandHTML && c.ExpandStyle != ExpandPandoc {
+	if c.ExpandStyle != ExpandBlockquote && c.ExpandStyle != ExpandHTML && c.ExpandStyle != ExpandPandoc &&
+		c.ExpandStyle != ExpandMkDocs && c.ExpandStyle != ExpandDocusaurus && c.ExpandStyle != ExpandHugo &&
+		c.ExpandStyle != ExpandObsidian {
 		return fmt.Errorf("invalid expandStyle %q", c.ExpandStyle)
 	}
-	if c.StatusStyle != StatusBracket && c.StatusStyle != StatusText {
+	if c.StatusStyle != StatusBracket && c.StatusStyle != StatusText && c.StatusStyle != StatusGitLab {
 		return fmt.Errorf("invalid statusStyle %q", c.StatusStyle)
 	}
 	if c.InlineCardStyle != InlineCardLink && c.InlineCardStyle != InlineCardURL && c.InlineCardStyle != InlineCardEmbed && c.InlineCardStyle != InlineCardPandoc {
@@ -355,10 +474,23 @@ func (c Config) Validate() error {
 	if c.DecisionStyle != DecisionEmoji && c.DecisionStyle != DecisionText {
 		return fmt.Errorf("invalid decisionStyle %q", c.DecisionStyle)
 	}
+	if c.TOCStyle != TOCExtension && c.TOCStyle != TOCGitLab {
+		return fmt.Errorf("invalid tocStyle %q", c.TOCStyle)
+	}
+	if c.PlaceholderStyle != PlaceholderDrop && c.PlaceholderStyle != PlaceholderBraces {
+		return fmt.Errorf("invalid placeholderStyle %q", c.PlaceholderStyle)
+	}
+	if c.LinkStyle != LinkInline && c.LinkStyle != LinkNumbered && c.LinkStyle != LinkNamed {
+		return fmt.Errorf("invalid linkStyle %q", c.LinkStyle)
+	}
+	if c.LinkDefinitions != LinkDefinitionsDocument && c.LinkDefinitions != LinkDefinitionsSection {
+		return fmt.Errorf("invalid linkDefinitions %q", c.LinkDefinitions)
+	}
 	if c.DateFormat == "" || !hasDateReferenceTokens(c.DateFormat) {
 		return fmt.Errorf("invalid dateFormat %q: must contain Go reference date components", c.DateFormat)
 	}
-	if c.TableMode != TableAuto && c.TableMode != TablePipe && c.TableMode != TableHTML && c.TableMode != TablePandoc && c.TableMode != TableAutoPandoc {
+	if c.TableMode != TableAuto && c.TableMode != TablePipe && c.TableMode != TableHTML && c.TableMode != TablePandoc && c.TableMode != TableAutoPandoc &&
+		c.TableMode != TableList {
 		return fmt.Errorf("invalid tableMode %q", c.TableMode)
 	}
 	if c.BulletMarker != '-' && c.BulletMarker != '*' && c.BulletMarker != '+' {
@@ -383,6 +515,13 @@ func (c Config) Validate() error {
 			return fmt.Errorf("languageMap keys and values must be non-empty")
 		}
 	}
+	// New parses the templates before validating and keeps the result, so they are only
+	// parsed here for a config that has not been through New.
+	if c.templates == nil {
+		if _, err := c.parseTemplates(); err != nil {
+			return err
+		}
+	}
 	if c.UnknownNodes != UnknownError && c.UnknownNodes != UnknownSkip && c.UnknownNodes != UnknownPlaceholder {
 		return fmt.Errorf("invalid unknownNodes policy %q", c.UnknownNodes)
 	}
@@ -392,10 +531,51 @@ func (c Config) Validate() error {
 	if c.ResolutionMode != ResolutionBestEffort && c.ResolutionMode != ResolutionStrict {
 		return fmt.Errorf("invalid resolutionMode %q", c.ResolutionMode)
 	}
+	if c.Parallelism < 0 {
+		return fmt.Errorf("parallelism must be non-negative, got %d", c.Parallelism)
+	}
+	if c.WrapWidth < 0 {
+		return fmt.Errorf("wrapWidth must be non-negative, got %d", c.WrapWidth)
+	}
+	if c.CommonMark {
+		if field, value := c.nonCommonMarkStyle(); field != "" {
+			return fmt.Errorf("%s %q is not allowed with commonMark output", field, value)
+		}
+	}
 
 	return nil
 }
 
+// nonCommonMarkStyle returns the first style that emits HTML or a GFM table, which
+// CommonMark output rules out.
+func (c Config) nonCommonMarkStyle() (string, string) {
+	switch {
+	case c.UnderlineStyle == UnderlineHTML:
+		return "underlineStyle", string(c.UnderlineStyle)
+	case c.SubSupStyle == SubSupHTML:
+		return "subSupStyle", string(c.SubSupStyle)
+	case c.TextColorStyle == ColorHTML:
+		return "textColorStyle", string(c.TextColorStyle)
+	case c.BackgroundColorStyle == ColorHTML:
+		return "backgroundColorStyle", string(c.BackgroundColorStyle)
+	case c.MentionStyle == MentionHTML:
+		return "mentionStyle", string(c.MentionStyle)
+	case c.HardBreakStyle == HardBreakHTML:
+		return "hardBreakStyle", string(c.HardBreakStyle)
+	case c.AlignmentStyle == AlignHTML:
+		return "alignmentStyle", string(c.AlignmentStyle)
+	case c.ExpandStyle == ExpandHTML || c.ExpandStyle == ExpandDocusaurus:
+		return "expandStyle", string(c.ExpandStyle)
+	case c.LayoutSectionStyle == LayoutSectionHTML:
+		return "layoutSectionStyle", string(c.LayoutSectionStyle)
+	case c.BodiedExtensionStyle == BodiedExtensionHTML:
+		return "bodiedExtensionStyle", string(c.BodiedExtensionStyle)
+	case c.TableMode != TableList:
+		return "tableMode", string(c.TableMode)
+	}
+	return "", ""
+}
+
 func hasDateReferenceTokens(format string) bool {
 	format = strings.TrimSpace(format)
 	if format == "" {
@@ -55,6 +55,10 @@ func TestValidateValid(t *testing.T) {
 		InlineCardStyle:      InlineCardEmbed,
 		BodiedExtensionStyle: BodiedExtensionStandard,
 		DecisionStyle:        DecisionText,
+		TOCStyle:             TOCGitLab,
+		PlaceholderStyle:     PlaceholderBraces,
+		LinkStyle:            LinkNamed,
+		LinkDefinitions:      LinkDefinitionsSection,
 		DateFormat:           "2006-01-02",
 		TableMode:            TablePipe,
 		LayoutSectionStyle:   LayoutSectionStandard,
@@ -239,3 +243,12 @@ func TestConfigClonePreservesExtensionHandlers(t *testing.T) {
 	cloned.ExtensionHandlers["new"] = handler
 	assert.NotContains(t, cfg.ExtensionHandlers, "new")
 }
+
+func TestValidateRejectsNegativeParallelism(t *testing.T) {
+	cfg := (Config{}).applyDefaults()
+	cfg.Parallelism = -1
+	require.Error(t, cfg.Validate())
+
+	cfg.Parallelism = 8
+	require.NoError(t, cfg.Validate())
+}
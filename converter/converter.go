@@ -19,11 +19,31 @@ type state struct {
 	ctx      context.Context
 	options  ConvertOptions
 	warnings []Warning
+	batch    *batchResolutions
+	// cacheStats is shared with parallel block states.
+	cacheStats *hookCacheCounter
+	// format renders the node types and marks that differ from Markdown.
+	format outputFormat
+	// references records the references of the rendered nodes.
+	references referenceRecorder
+	// links collects definitions for reference-style links.
+	links *linkReferences
+	// wrapIndent is the width of the prefixes that enclosing blocks add to each line, and
+	// wrapLead the width of an extra prefix on the next line only.
+	wrapIndent int
+	wrapLead   int
+	// noWrap is set while rendering table cells, which must stay on one line.
+	noWrap bool
 }
 
 // New creates a new Converter with the given config
 func New(config Config) (*Converter, error) {
 	cfg := config.applyDefaults().clone()
+	templates, err := cfg.parseTemplates()
+	if err != nil {
+		return nil, err
+	}
+	cfg.templates = templates
 	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
@@ -57,20 +77,42 @@ func (c *Converter) ConvertWithContext(ctx context.Context, input []byte, opts C
 	}
 
 	s := &state{
-		config:  c.config,
-		ctx:     ctx,
-		options: opts,
+		config:     c.config,
+		ctx:        ctx,
+		options:    opts,
+		cacheStats: &hookCacheCounter{},
+	}
+	s.format = markdownFormat{s}
+
+	if err := s.resolveBatch(doc.Content); err != nil {
+		return Result{}, err
 	}
 
-	markdown, err := s.convertNode(Node{Type: doc.Type, Content: doc.Content})
+	var markdown string
+	var err error
+	if s.shouldConvertInParallel(doc) {
+		markdown, err = s.convertDocParallel(doc.Content)
+	} else {
+		s.references.addContent(doc.Content, "")
+		markdown, err = s.convertNode(Node{Type: doc.Type, Content: doc.Content})
+	}
 	if err != nil {
 		return Result{}, err
 	}
 	if err := s.checkContext(); err != nil {
 		return Result{}, err
 	}
+	markdown, err = withFrontMatter(opts.Metadata, markdown)
+	if err != nil {
+		return Result{}, err
+	}
 
-	return Result{Markdown: markdown, Warnings: s.warnings}, nil
+	return Result{
+		Markdown:   markdown,
+		Warnings:   s.warnings,
+		CacheStats: s.cacheStats.stats(),
+		References: s.references.references(),
+	}, nil
 }
 
 func (s *state) convertNode(node Node) (string, error) {
@@ -78,6 +120,12 @@ func (s *state) convertNode(node Node) (string, error) {
 		return "", err
 	}
 
+	s.references.enter(node.Content)
+
+	if result, handled, err := s.format.renderNode(node); handled {
+		return result, err
+	}
+
 	switch node.Type {
 	case "doc":
 		return s.convertDoc(node)
@@ -174,7 +222,7 @@ func (s *state) convertNode(node Node) (string, error) {
 		return s.convertDecisionItem(node)
 
 	case "placeholder":
-		return "", nil
+		return s.convertPlaceholder(node), nil
 
 	default:
 		if s.isExtensionNode(node.Type) {
@@ -189,7 +237,7 @@ func (s *state) convertNode(node Node) (string, error) {
 			return "", nil
 		default:
 			s.addWarning(WarningUnknownNode, node.Type, fmt.Sprintf("unknown node rendered as placeholder: %s", node.Type))
-			return fmt.Sprintf("[Unknown node: %s]", node.Type), nil
+			return s.format.renderText("", fmt.Sprintf("[Unknown node: %s]", node.Type), nil), nil
 		}
 	}
 }
@@ -242,16 +290,26 @@ func (s *state) convertChildren(content []Node) (string, error) {
 
 // convertDoc converts the root document node
 func (s *state) convertDoc(node Node) (string, error) {
-	res, err := s.convertChildren(node.Content)
+	var res string
+	var err error
+	if s.config.LinkStyle != LinkInline && s.config.LinkDefinitions == LinkDefinitionsSection {
+		res, err = s.convertSections(node.Content)
+	} else {
+		res, err = s.convertChildren(node.Content)
+	}
 	if err != nil {
 		return "", err
 	}
-	// Trim right to avoid excessive newlines at the end of file, then ensure exactly one.
+	return finishDocument(s.appendLinkDefinitions(res)), nil
+}
+
+// finishDocument trims excessive newlines at the end of file, then ensures exactly one.
+func finishDocument(res string) string {
 	result := strings.TrimRight(res, "\n")
 	if result == "" {
-		return "", nil
+		return ""
 	}
-	return result + "\n", nil
+	return result + "\n"
 }
 
 // convertInlineContent processes a slice of nodes (typically text with marks)
@@ -263,6 +321,19 @@ func (s *state) convertInlineContent(content []Node) (string, error) {
 	// Check if any text node has both strong and em anywhere in the paragraph
 	useUnderscoreForEm := s.hasStrongAndEm(content)
 
+	// wikiLinkStart is where an open [[wiki link]] starts in sb, or -1.
+	wikiLinkStart := -1
+	closeMarks := func(marks []Mark) error {
+		if err := s.closeMarks(marks, useUnderscoreForEm, &sb); err != nil {
+			return err
+		}
+		if wikiLinkStart >= 0 && hasMarkType(marks, "link") {
+			collapseWikiLink(&sb, wikiLinkStart)
+			wikiLinkStart = -1
+		}
+		return nil
+	}
+
 	for _, node := range content {
 		if err := s.checkContext(); err != nil {
 			return "", err
@@ -270,7 +341,7 @@ func (s *state) convertInlineContent(content []Node) (string, error) {
 
 		if node.Type != "text" {
 			// For non-text nodes, close all active marks, process node, reset marks
-			if err := s.closeMarks(activeMarks, useUnderscoreForEm, &sb); err != nil {
+			if err := closeMarks(activeMarks); err != nil {
 				return "", err
 			}
 
@@ -290,6 +361,9 @@ func (s *state) convertInlineContent(content []Node) (string, error) {
 		currentMarks := make([]Mark, 0, len(node.Marks))
 		var unknownPlaceholder strings.Builder
 		for _, mark := range node.Marks {
+			if !s.format.keepMark(mark) {
+				continue
+			}
 			if s.isKnownMark(mark.Type) {
 				currentMarks = append(currentMarks, mark)
 				continue
@@ -320,7 +394,7 @@ func (s *state) convertInlineContent(content []Node) (string, error) {
 		marksToOpen := s.getMarksToOpenFull(activeMarks, effectiveMarks)
 
 		// Close marks
-		if err := s.closeMarks(marksToClose, useUnderscoreForEm, &sb); err != nil {
+		if err := closeMarks(marksToClose); err != nil {
 			return "", err
 		}
 
@@ -336,29 +410,29 @@ func (s *state) convertInlineContent(content []Node) (string, error) {
 		}
 
 		for _, mark := range marksToOpen {
-			opening, err := s.getOpeningDelimiterForMark(mark, useUnderscoreForEm)
+			opening, err := s.format.markOpening(mark, useUnderscoreForEm)
 			if err != nil {
 				return "", err
 			}
+			if mark.Type == "link" && strings.HasPrefix(opening, "[[") {
+				wikiLinkStart = sb.Len()
+			}
 			sb.WriteString(opening)
 		}
 
 		// Write text content (including placeholders for unknown marks).
-		if unknownPlaceholder.Len() > 0 {
-			sb.WriteString(unknownPlaceholder.String())
-		}
-		sb.WriteString(textValue)
+		sb.WriteString(s.format.renderText(unknownPlaceholder.String(), textValue, effectiveMarks))
 
 		// Update active marks
 		activeMarks = effectiveMarks
 	}
 
 	// Close any remaining marks at end of content
-	if err := s.closeMarks(activeMarks, useUnderscoreForEm, &sb); err != nil {
+	if err := closeMarks(activeMarks); err != nil {
 		return "", err
 	}
 
-	return sb.String(), nil
+	return s.format.finishInline(sb.String()), nil
 }
 
 func startsWithFence(value string) bool {
@@ -385,7 +459,7 @@ func (s *state) closeMarks(marks []Mark, useUnderscoreForEm bool, sb *strings.Bu
 
 	var closings strings.Builder
 	for i := len(marks) - 1; i >= 0; i-- {
-		closing, err := s.getClosingDelimiterForMark(marks[i], useUnderscoreForEm)
+		closing, err := s.format.markClosing(marks[i], useUnderscoreForEm)
 		if err != nil {
 			return err
 		}
@@ -868,3 +868,22 @@ func BenchmarkConvertLargeDocument(b *testing.B) {
 		}
 	}
 }
+
+func TestConvertPlaceholderStyle(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[{"type":"paragraph","content":[
+		{"type":"text","text":"Summary: "},
+		{"type":"placeholder","attrs":{"text":"Describe the change"}}
+	]}]}`)
+
+	result, err := newTestConverter(t, Config{}).Convert(input)
+	require.NoError(t, err)
+	assert.Equal(t, "Summary: \n", result.Markdown)
+
+	result, err = newTestConverter(t, Config{PlaceholderStyle: PlaceholderBraces}).Convert(input)
+	require.NoError(t, err)
+	assert.Equal(t, "Summary: {{Describe the change}}\n", result.Markdown)
+
+	_, err = New(Config{PlaceholderStyle: "visible"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid placeholderStyle")
+}
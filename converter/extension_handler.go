@@ -5,6 +5,8 @@ import "context"
 type ExtensionRenderInput struct {
 	SourcePath string
 	Node       Node
+	// DocumentMetadata is the metadata of the document being converted.
+	DocumentMetadata map[string]any `json:"-"`
 }
 
 type ExtensionRenderOutput struct {
@@ -18,6 +20,8 @@ type ExtensionParseInput struct {
 	ExtensionKey string
 	Body         string            // raw markdown content inside the .adf-extension div
 	Metadata     map[string]string // div attrs (minus key and .adf-extension class)
+	// DocumentMetadata is the metadata of the document being converted.
+	DocumentMetadata map[string]any `json:"-"`
 }
 
 type ExtensionParseOutput struct {
@@ -13,8 +13,9 @@ func (s *state) convertExtension(node Node) (string, error) {
 	if extensionKey != "" && s.config.ExtensionHandlers != nil {
 		if handler, ok := s.config.ExtensionHandlers[extensionKey]; ok {
 			input := ExtensionRenderInput{
-				SourcePath: s.options.SourcePath,
-				Node:       node,
+				SourcePath:       s.options.SourcePath,
+				Node:             node,
+				DocumentMetadata: s.options.Metadata,
 			}
 			output, err := handler.ToMarkdown(s.ctx, input)
 			if err != nil {
@@ -44,6 +45,10 @@ func (s *state) convertExtension(node Node) (string, error) {
 		}
 	}
 
+	if node.Type == "extension" && extensionKey == "toc" && s.config.TOCStyle == TOCGitLab {
+		return "[[_TOC_]]\n\n", nil
+	}
+
 	if node.Type == "bodiedExtension" && s.config.BodiedExtensionStyle != BodiedExtensionJSON {
 		return s.convertBodiedExtension(node)
 	}
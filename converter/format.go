@@ -0,0 +1,54 @@
+package converter
+
+import "strings"
+
+// outputFormat renders the parts of a conversion that differ between output formats.
+// convertNode and convertInlineContent hold the shared dispatch and rendering of
+// Markdown; a format replaces the node types, marks and text it writes differently.
+type outputFormat interface {
+	// renderNode renders node, or reports false for types that use the Markdown rendering.
+	renderNode(node Node) (string, bool, error)
+	// keepMark reports whether mark is rendered. A dropped mark is reported as a warning.
+	keepMark(mark Mark) bool
+	markOpening(mark Mark, useUnderscoreForEm bool) (string, error)
+	markClosing(mark Mark, useUnderscoreForEm bool) (string, error)
+	// renderText escapes text written under marks, preceded by placeholder.
+	renderText(placeholder, text string, marks []Mark) string
+	// finishInline completes the rendered inline content of a block.
+	finishInline(content string) string
+}
+
+// markdownFormat renders Markdown, applying node templates and the CommonMark
+// restrictions.
+type markdownFormat struct {
+	*state
+}
+
+func (m markdownFormat) renderNode(node Node) (string, bool, error) {
+	tmpl, ok := m.config.templates[node.Type]
+	if !ok {
+		return "", false, nil
+	}
+	result, err := m.convertTemplateNode(tmpl, node)
+	return result, true, err
+}
+
+func (m markdownFormat) keepMark(mark Mark) bool {
+	if mark.Type == "strike" && m.config.CommonMark {
+		m.addWarning(WarningDroppedFeature, mark.Type, "strikethrough is not part of CommonMark; rendered as plain text")
+		return false
+	}
+	return true
+}
+
+func (m markdownFormat) renderText(placeholder, text string, marks []Mark) string {
+	// Escaping < keeps text such as "<b>" from reading as raw HTML.
+	if m.config.CommonMark && !hasMarkType(marks, "code") {
+		text = strings.ReplaceAll(text, "<", `\<`)
+	}
+	return placeholder + text
+}
+
+func (markdownFormat) finishInline(content string) string {
+	return content
+}
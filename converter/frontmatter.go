@@ -0,0 +1,39 @@
+package converter
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// renderFrontMatter returns metadata as a YAML front matter block with sorted keys, or ""
+// when there is no metadata.
+func renderFrontMatter(metadata map[string]any) (string, error) {
+	if len(metadata) == 0 {
+		return "", nil
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(metadata); err != nil {
+		return "", fmt.Errorf("failed to encode front matter: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode front matter: %w", err)
+	}
+	return "---\n" + buf.String() + "---\n", nil
+}
+
+// withFrontMatter prepends the front matter block to markdown, separated by a blank line.
+func withFrontMatter(metadata map[string]any, markdown string) (string, error) {
+	frontMatter, err := renderFrontMatter(metadata)
+	if err != nil || frontMatter == "" {
+		return markdown, err
+	}
+	if markdown == "" {
+		return frontMatter, nil
+	}
+	return frontMatter + "\n" + markdown, nil
+}
@@ -0,0 +1,101 @@
+package converter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertWithFrontMatter(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"Body"}]}]}`)
+	metadata := map[string]any{
+		"title":    "Design: v2",
+		"pageId":   "12345",
+		"spaceKey": "ENG",
+		"labels":   []any{"api", "draft"},
+		"version":  float64(7),
+	}
+
+	result, err := newTestConverter(t, Config{}).ConvertWithContext(context.Background(), input, ConvertOptions{Metadata: metadata})
+	require.NoError(t, err)
+	assert.Equal(t, "---\n"+
+		"labels:\n  - api\n  - draft\n"+
+		"pageId: \"12345\"\n"+
+		"spaceKey: ENG\n"+
+		"title: 'Design: v2'\n"+
+		"version: 7\n"+
+		"---\n\nBody\n", result.Markdown)
+}
+
+func TestConvertWithFrontMatterOnly(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[]}`)
+
+	result, err := newTestConverter(t, Config{}).ConvertWithContext(context.Background(), input, ConvertOptions{Metadata: map[string]any{"title": "Empty"}})
+	require.NoError(t, err)
+	assert.Equal(t, "---\ntitle: Empty\n---\n", result.Markdown)
+
+	result, err = newTestConverter(t, Config{}).Convert(input)
+	require.NoError(t, err)
+	assert.Equal(t, "", result.Markdown)
+}
+
+func TestHooksReceiveDocumentMetadata(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[{"type":"text","text":"page","marks":[{"type":"link","attrs":{"href":"https://example.com/page"}}]}]},
+		{"type":"mediaSingle","content":[{"type":"media","attrs":{"type":"file","id":"m1"}}]}
+	]}`)
+	metadata := map[string]any{"spaceKey": "ENG"}
+
+	var linkMetadata, mediaMetadata map[string]any
+	conv := newTestConverter(t, Config{
+		LinkHook: func(ctx context.Context, in LinkRenderInput) (LinkRenderOutput, error) {
+			linkMetadata = in.DocumentMetadata
+			return LinkRenderOutput{}, nil
+		},
+		MediaHook: func(ctx context.Context, in MediaRenderInput) (MediaRenderOutput, error) {
+			mediaMetadata = in.DocumentMetadata
+			return MediaRenderOutput{}, nil
+		},
+	})
+	_, err := conv.ConvertWithContext(context.Background(), input, ConvertOptions{Metadata: metadata})
+	require.NoError(t, err)
+	assert.Equal(t, metadata, linkMetadata)
+	assert.Equal(t, metadata, mediaMetadata)
+
+	var request BatchRequest
+	conv = newTestConverter(t, Config{BatchResolver: BatchResolverFunc(func(ctx context.Context, req BatchRequest) (BatchResponse, error) {
+		request = req
+		return BatchResponse{}, nil
+	})})
+	_, err = conv.ConvertWithContext(context.Background(), input, ConvertOptions{Metadata: metadata})
+	require.NoError(t, err)
+	assert.Equal(t, metadata, request.DocumentMetadata)
+	require.Len(t, request.Links, 1)
+	assert.Equal(t, metadata, request.Links[0].DocumentMetadata)
+}
+
+func TestHookCacheIgnoresDocumentMetadata(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[{"type":"text","text":"page","marks":[{"type":"link","attrs":{"href":"https://example.com/page"}}]}]}
+	]}`)
+
+	calls := 0
+	conv := newTestConverter(t, Config{
+		HookCache: NewLRUHookCache(0, HookCacheOptions{}),
+		LinkHook: func(ctx context.Context, in LinkRenderInput) (LinkRenderOutput, error) {
+			calls++
+			return LinkRenderOutput{Href: "./page.md", Handled: true}, nil
+		},
+	})
+
+	first, err := conv.ConvertWithContext(context.Background(), input, ConvertOptions{Metadata: map[string]any{"title": "One"}})
+	require.NoError(t, err)
+	assert.Equal(t, HookCacheStats{Misses: 1}, first.CacheStats)
+
+	second, err := conv.ConvertWithContext(context.Background(), input, ConvertOptions{Metadata: map[string]any{"title": "Two"}})
+	require.NoError(t, err)
+	assert.Equal(t, HookCacheStats{Hits: 1}, second.CacheStats)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,46 @@
+package converter
+
+import "strings"
+
+// gitLabAlertTypes maps panel types to GitLab alert types by colour: the purple note
+// panel becomes important and the red error panel becomes caution.
+var gitLabAlertTypes = map[string]string{
+	"info":    "note",
+	"note":    "important",
+	"success": "tip",
+	"warning": "warning",
+	"error":   "caution",
+}
+
+// gitLabStatusColors maps status colors to the hex colour chips GitLab renders for
+// inline code.
+var gitLabStatusColors = map[string]string{
+	"neutral": "#42526e",
+	"purple":  "#403294",
+	"blue":    "#0747a6",
+	"red":     "#bf2600",
+	"yellow":  "#ff8b00",
+	"green":   "#006644",
+}
+
+// gitLabAlertType returns the GitLab alert type for a panel type. Panels without a
+// known type become notes.
+func gitLabAlertType(panelType string) string {
+	if alertType, ok := gitLabAlertTypes[panelType]; ok {
+		return alertType
+	}
+	return "note"
+}
+
+// renderGitLabStatus renders a status as a colour chip followed by its bold text. Text
+// that would break the bold run falls back to the bracket form.
+func renderGitLabStatus(text, color string) string {
+	if strings.ContainsAny(text, "*`\n") {
+		return "[Status: " + text + "]"
+	}
+	chip, ok := gitLabStatusColors[strings.ToLower(color)]
+	if !ok {
+		chip = gitLabStatusColors["neutral"]
+	}
+	return "`" + chip + "` **" + text + "**"
+}
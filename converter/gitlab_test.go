@@ -0,0 +1,42 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertGitLabProfile(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"extension","attrs":{"extensionType":"com.atlassian.confluence.macro.core","extensionKey":"toc"}},
+		{"type":"panel","attrs":{"panelType":"note","title":"Heads up"},"content":[{"type":"paragraph","content":[{"type":"text","text":"Read this"}]}]},
+		{"type":"panel","attrs":{"panelType":"error"},"content":[{"type":"paragraph","content":[{"type":"text","text":"Broken"}]}]},
+		{"type":"paragraph","content":[
+			{"type":"status","attrs":{"text":"IN PROGRESS","color":"blue"}},
+			{"type":"text","text":" "},
+			{"type":"status","attrs":{"text":"NEW"}},
+			{"type":"text","text":" "},
+			{"type":"status","attrs":{"text":"a*b","color":"red"}}
+		]}
+	]}`)
+
+	result, err := newTestConverter(t, Config{
+		PanelStyle:  PanelGitLab,
+		StatusStyle: StatusGitLab,
+		TOCStyle:    TOCGitLab,
+	}).Convert(input)
+	require.NoError(t, err)
+	assert.Equal(t, "[[_TOC_]]\n\n"+
+		"> [!important] Heads up\n> Read this\n\n"+
+		"> [!caution]\n> Broken\n\n"+
+		"`#0747a6` **IN PROGRESS** `#42526e` **NEW** [Status: a*b]\n", result.Markdown)
+}
+
+func TestConvertTOCAsExtensionByDefault(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[{"type":"extension","attrs":{"extensionKey":"toc"}}]}`)
+
+	result, err := newTestConverter(t, Config{}).Convert(input)
+	require.NoError(t, err)
+	assert.Contains(t, result.Markdown, "```adf:extension")
+}
@@ -0,0 +1,320 @@
+package converter
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const defaultLRUHookCacheCapacity = 1024
+
+// HookCache stores link and media hook results across conversions, keyed by the hook
+// input. Implementations must be safe for concurrent use.
+type HookCache interface {
+	Get(key string) (HookCacheEntry, bool)
+	Set(key string, entry HookCacheEntry)
+}
+
+// HookCacheEntry is a cached hook result. Output holds the JSON-encoded hook output.
+// Unresolved records a hook that returned ErrUnresolved (negative caching).
+type HookCacheEntry struct {
+	Output     json.RawMessage `json:"output,omitempty"`
+	Unresolved bool            `json:"unresolved,omitempty"`
+}
+
+// HookCacheStats reports hook cache usage for a single conversion.
+type HookCacheStats struct {
+	Hits   int `json:"hits"`
+	Misses int `json:"misses"`
+}
+
+// HookCacheOptions controls entry expiry for the built-in HookCache implementations.
+type HookCacheOptions struct {
+	// TTL bounds the lifetime of resolved entries. Zero means entries never expire.
+	TTL time.Duration
+	// NegativeTTL bounds the lifetime of ErrUnresolved entries. Zero uses TTL;
+	// a negative value disables negative caching.
+	NegativeTTL time.Duration
+}
+
+// expiry returns the expiry time for entry, and false if the entry must not be stored.
+func (o HookCacheOptions) expiry(entry HookCacheEntry, now time.Time) (time.Time, bool) {
+	ttl := o.TTL
+	if entry.Unresolved && o.NegativeTTL != 0 {
+		ttl = o.NegativeTTL
+	}
+	if ttl < 0 {
+		return time.Time{}, false
+	}
+	if ttl == 0 {
+		return time.Time{}, true
+	}
+	return now.Add(ttl), true
+}
+
+// LRUHookCache is a bounded in-memory HookCache that evicts the least recently used entry.
+type LRUHookCache struct {
+	mu       sync.Mutex
+	capacity int
+	options  HookCacheOptions
+	order    *list.List
+	items    map[string]*list.Element
+	now      func() time.Time
+}
+
+type lruHookCacheItem struct {
+	key     string
+	entry   HookCacheEntry
+	expires time.Time
+}
+
+// NewLRUHookCache creates an in-memory cache holding at most capacity entries.
+// A non-positive capacity uses a default of 1024.
+func NewLRUHookCache(capacity int, opts HookCacheOptions) *LRUHookCache {
+	if capacity <= 0 {
+		capacity = defaultLRUHookCacheCapacity
+	}
+	return &LRUHookCache{
+		capacity: capacity,
+		options:  opts,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+		now:      time.Now,
+	}
+}
+
+// Get returns the entry for key if present and not expired.
+func (c *LRUHookCache) Get(key string) (HookCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return HookCacheEntry{}, false
+	}
+	item := element.Value.(*lruHookCacheItem)
+	if !item.expires.IsZero() && !c.now().Before(item.expires) {
+		c.order.Remove(element)
+		delete(c.items, key)
+		return HookCacheEntry{}, false
+	}
+
+	c.order.MoveToFront(element)
+	return item.entry, true
+}
+
+// Set stores entry under key, evicting the least recently used entry when full.
+func (c *LRUHookCache) Set(key string, entry HookCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires, ok := c.options.expiry(entry, c.now())
+	if !ok {
+		return
+	}
+
+	if element, exists := c.items[key]; exists {
+		item := element.Value.(*lruHookCacheItem)
+		item.entry = entry
+		item.expires = expires
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruHookCacheItem{key: key, entry: entry, expires: expires})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruHookCacheItem).key)
+	}
+}
+
+// Len returns the number of entries currently held, including expired ones not yet evicted.
+func (c *LRUHookCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// FileHookCache is a HookCache that persists one JSON file per entry in a directory,
+// so results survive across processes. Read and write failures are treated as misses.
+type FileHookCache struct {
+	dir     string
+	options HookCacheOptions
+	now     func() time.Time
+}
+
+type fileHookCacheRecord struct {
+	Entry   HookCacheEntry `json:"entry"`
+	Expires time.Time      `json:"expires,omitzero"`
+}
+
+// NewFileHookCache creates a file-backed cache rooted at dir, creating it if needed.
+func NewFileHookCache(dir string, opts HookCacheOptions) (*FileHookCache, error) {
+	if dir == "" {
+		return nil, errors.New("hook cache directory is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileHookCache{dir: dir, options: opts, now: time.Now}, nil
+}
+
+// Get returns the entry for key if present and not expired.
+func (c *FileHookCache) Get(key string) (HookCacheEntry, bool) {
+	path := c.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return HookCacheEntry{}, false
+	}
+
+	var record fileHookCacheRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return HookCacheEntry{}, false
+	}
+	if !record.Expires.IsZero() && !c.now().Before(record.Expires) {
+		_ = os.Remove(path)
+		return HookCacheEntry{}, false
+	}
+
+	return record.Entry, true
+}
+
+// Set stores entry under key. The file is written atomically.
+func (c *FileHookCache) Set(key string, entry HookCacheEntry) {
+	expires, ok := c.options.expiry(entry, c.now())
+	if !ok {
+		return
+	}
+
+	data, err := json.Marshal(fileHookCacheRecord{Entry: entry, Expires: expires})
+	if err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+func (c *FileHookCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// hookCacheCounter accumulates hits and misses; it is shared by parallel block states.
+type hookCacheCounter struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+func (c *hookCacheCounter) stats() HookCacheStats {
+	if c == nil {
+		return HookCacheStats{}
+	}
+	return HookCacheStats{Hits: int(c.hits.Load()), Misses: int(c.misses.Load())}
+}
+
+// count records a cache hit or miss.
+func (c *hookCacheCounter) count(hit bool) {
+	if hit {
+		c.hits.Add(1)
+		return
+	}
+	c.misses.Add(1)
+}
+
+// hookCacheKey derives a cache key from a hook kind and its JSON-encoded input.
+// It returns an empty key when the input cannot be encoded.
+func hookCacheKey(kind string, input any) string {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return kind + ":" + hex.EncodeToString(sum[:])
+}
+
+// LookupHookCache returns the cached result of the kind hook for input without calling
+// the hook. The returned error is ErrUnresolved for negative entries. A nil cache, an
+// input that cannot be encoded and an entry that cannot be decoded are all misses. The
+// converter and mdconverter share it, so both use the same key format.
+func LookupHookCache[In, Out any](cache HookCache, kind string, input In) (Out, error, bool) {
+	var zero Out
+	if cache == nil {
+		return zero, nil, false
+	}
+	key := hookCacheKey(kind, input)
+	if key == "" {
+		return zero, nil, false
+	}
+	return decodeHookCacheEntry[Out](cache, key)
+}
+
+// decodeHookCacheEntry loads key from cache. The returned error is ErrUnresolved for
+// negative entries. Entries that cannot be decoded are reported as missing.
+func decodeHookCacheEntry[Out any](cache HookCache, key string) (Out, error, bool) {
+	var output Out
+	entry, ok := cache.Get(key)
+	if !ok {
+		return output, nil, false
+	}
+	if entry.Unresolved {
+		return output, ErrUnresolved, true
+	}
+	if err := json.Unmarshal(entry.Output, &output); err != nil {
+		return output, nil, false
+	}
+	return output, nil, true
+}
+
+// ResolveWithHookCache returns the cached result of the kind hook for input, or calls
+// resolve and caches its result. Only successful results and ErrUnresolved are cached.
+// count, when set, is told whether each cache lookup hit; it is not called without a
+// cache.
+func ResolveWithHookCache[In, Out any](cache HookCache, kind string, input In, resolve func() (Out, error), count func(hit bool)) (Out, error) {
+	if cache == nil {
+		return resolve()
+	}
+	key := hookCacheKey(kind, input)
+	if key == "" {
+		return resolve()
+	}
+
+	output, err, ok := decodeHookCacheEntry[Out](cache, key)
+	if count != nil {
+		count(ok)
+	}
+	if ok {
+		return output, err
+	}
+
+	output, err = resolve()
+	switch {
+	case err == nil:
+		if data, marshalErr := json.Marshal(output); marshalErr == nil {
+			cache.Set(key, HookCacheEntry{Output: data})
+		}
+	case errors.Is(err, ErrUnresolved):
+		cache.Set(key, HookCacheEntry{Unresolved: true})
+	}
+
+	return output, err
+}
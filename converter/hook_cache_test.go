@@ -0,0 +1,192 @@
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUHookCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewLRUHookCache(2, HookCacheOptions{})
+	cache.Set("a", HookCacheEntry{Output: json.RawMessage(`"a"`)})
+	cache.Set("b", HookCacheEntry{Output: json.RawMessage(`"b"`)})
+
+	_, ok := cache.Get("a")
+	require.True(t, ok)
+
+	cache.Set("c", HookCacheEntry{Output: json.RawMessage(`"c"`)})
+	assert.Equal(t, 2, cache.Len())
+
+	_, ok = cache.Get("b")
+	assert.False(t, ok, "b was least recently used and should be evicted")
+	_, ok = cache.Get("a")
+	assert.True(t, ok)
+	_, ok = cache.Get("c")
+	assert.True(t, ok)
+}
+
+func TestLRUHookCacheExpiresEntries(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewLRUHookCache(10, HookCacheOptions{TTL: time.Minute, NegativeTTL: time.Second})
+	cache.now = func() time.Time { return now }
+
+	cache.Set("resolved", HookCacheEntry{Output: json.RawMessage(`{}`)})
+	cache.Set("unresolved", HookCacheEntry{Unresolved: true})
+
+	now = now.Add(2 * time.Second)
+	_, ok := cache.Get("resolved")
+	assert.True(t, ok)
+	_, ok = cache.Get("unresolved")
+	assert.False(t, ok)
+
+	now = now.Add(time.Minute)
+	_, ok = cache.Get("resolved")
+	assert.False(t, ok)
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestLRUHookCacheNegativeCachingCanBeDisabled(t *testing.T) {
+	cache := NewLRUHookCache(10, HookCacheOptions{NegativeTTL: -1})
+	cache.Set("unresolved", HookCacheEntry{Unresolved: true})
+
+	_, ok := cache.Get("unresolved")
+	assert.False(t, ok)
+}
+
+func TestFileHookCachePersistsAcrossInstances(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	first, err := NewFileHookCache(dir, HookCacheOptions{TTL: time.Hour})
+	require.NoError(t, err)
+	first.now = func() time.Time { return now }
+	first.Set("link_render:abc", HookCacheEntry{Output: json.RawMessage(`{"Href":"./a.md"}`)})
+
+	second, err := NewFileHookCache(dir, HookCacheOptions{TTL: time.Hour})
+	require.NoError(t, err)
+	second.now = func() time.Time { return now.Add(time.Minute) }
+	entry, ok := second.Get("link_render:abc")
+	require.True(t, ok)
+	assert.JSONEq(t, `{"Href":"./a.md"}`, string(entry.Output))
+
+	second.now = func() time.Time { return now.Add(2 * time.Hour) }
+	_, ok = second.Get("link_render:abc")
+	assert.False(t, ok)
+
+	_, err = NewFileHookCache("", HookCacheOptions{})
+	require.Error(t, err)
+}
+
+func TestHookCacheSharesResultsAcrossConversions(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[{"type":"paragraph","content":[
+		{"type":"text","text":"A","marks":[{"type":"link","attrs":{"href":"https://example.com/a"}}]},
+		{"type":"text","text":" "},
+		{"type":"text","text":"Missing","marks":[{"type":"link","attrs":{"href":"https://example.com/missing"}}]}
+	]},{"type":"mediaSingle","content":[{"type":"media","attrs":{"type":"file","id":"att-1"}}]}]}`)
+
+	linkCalls, mediaCalls := 0, 0
+	conv := newTestConverter(t, Config{
+		HookCache: NewLRUHookCache(0, HookCacheOptions{}),
+		LinkHook: func(_ context.Context, in LinkRenderInput) (LinkRenderOutput, error) {
+			linkCalls++
+			if in.Href == "https://example.com/missing" {
+				return LinkRenderOutput{}, ErrUnresolved
+			}
+			return LinkRenderOutput{Href: "./a.md", Handled: true}, nil
+		},
+		MediaHook: func(context.Context, MediaRenderInput) (MediaRenderOutput, error) {
+			mediaCalls++
+			return MediaRenderOutput{Markdown: "![att](./att-1.png)", Handled: true}, nil
+		},
+	})
+
+	first, err := conv.Convert(input)
+	require.NoError(t, err)
+	assert.Equal(t, HookCacheStats{Hits: 0, Misses: 3}, first.CacheStats)
+
+	second, err := conv.Convert(input)
+	require.NoError(t, err)
+	assert.Equal(t, HookCacheStats{Hits: 3, Misses: 0}, second.CacheStats)
+
+	assert.Equal(t, 2, linkCalls)
+	assert.Equal(t, 1, mediaCalls)
+	assert.Equal(t, first.Markdown, second.Markdown)
+	assert.Equal(t, first.Warnings, second.Warnings)
+	require.Len(t, second.Warnings, 1)
+	assert.Equal(t, WarningUnresolvedReference, second.Warnings[0].Type)
+}
+
+func TestHookCacheDoesNotCacheHookErrors(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"A","marks":[{"type":"link","attrs":{"href":"https://example.com/a"}}]}]}]}`)
+
+	cache := NewLRUHookCache(0, HookCacheOptions{})
+	conv := newTestConverter(t, Config{
+		HookCache: cache,
+		LinkHook: func(context.Context, LinkRenderInput) (LinkRenderOutput, error) {
+			return LinkRenderOutput{}, assert.AnError
+		},
+	})
+
+	_, err := conv.Convert(input)
+	require.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestHookCacheSkipsCachedReferencesInBatchRequest(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"A","marks":[{"type":"link","attrs":{"href":"https://example.com/a"}}]}]}]}`)
+
+	calls := 0
+	conv := newTestConverter(t, Config{
+		HookCache: NewLRUHookCache(0, HookCacheOptions{}),
+		BatchResolver: BatchResolverFunc(func(_ context.Context, req BatchRequest) (BatchResponse, error) {
+			calls++
+			require.Len(t, req.Links, 1)
+			return BatchResponse{Links: []LinkResolution{{Output: LinkRenderOutput{Href: "./a.md", Handled: true}}}}, nil
+		}),
+	})
+
+	first, err := conv.Convert(input)
+	require.NoError(t, err)
+	second, err := conv.Convert(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "[A](./a.md)\n", second.Markdown)
+	assert.Equal(t, first.Markdown, second.Markdown)
+	assert.Equal(t, HookCacheStats{Hits: 1}, second.CacheStats)
+}
+
+func TestResultOmitsZeroCacheStats(t *testing.T) {
+	data, err := json.Marshal(Result{Markdown: "x"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"markdown":"x"}`, string(data))
+}
+
+func TestResolveWithHookCacheSharesEntriesWithLookup(t *testing.T) {
+	cache := NewLRUHookCache(4, HookCacheOptions{})
+	input := LinkRenderInput{Href: "page.md"}
+	var hits []bool
+	calls := 0
+	resolve := func() (LinkRenderOutput, error) {
+		calls++
+		return LinkRenderOutput{Href: "https://example.com/page", Handled: true}, nil
+	}
+	count := func(hit bool) { hits = append(hits, hit) }
+
+	for range 2 {
+		output, err := ResolveWithHookCache(cache, "link_render", input, resolve, count)
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/page", output.Href)
+	}
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, []bool{false, true}, hits)
+
+	output, err, ok := LookupHookCache[LinkRenderInput, LinkRenderOutput](cache, "link_render", input)
+	require.True(t, ok)
+	require.NoError(t, err)
+	assert.True(t, output.Handled)
+}
@@ -0,0 +1,76 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// HookPolicy applies Config.ResolutionMode to the results of link, media and other
+// resolution hooks. The converter and the renderers that accept its hooks all go through
+// it, so ErrUnresolved, strict mode and hook failures are reported the same way by every
+// output format.
+type HookPolicy struct {
+	ResolutionMode ResolutionMode
+	// Warn records a warning; renderers pass their own addWarning.
+	Warn func(warnType WarningType, nodeType, message string)
+}
+
+// Error interprets err, returned by the named hook (for example "link") while resolving
+// reference (for example `link reference "https://example.com"`). For ErrUnresolved in
+// best-effort mode it records a warning and returns nil so the caller falls back to its
+// default rendering; otherwise it returns the error that ends the conversion.
+func (p HookPolicy) Error(nodeType, hook, reference string, err error) error {
+	if !errors.Is(err, ErrUnresolved) {
+		return fmt.Errorf("%s hook failed: %w", hook, err)
+	}
+	if p.ResolutionMode == ResolutionStrict {
+		return fmt.Errorf("unresolved %s: %w", reference, err)
+	}
+	if p.Warn != nil {
+		p.Warn(WarningUnresolvedReference, nodeType, fmt.Sprintf("unresolved %s; using fallback rendering", reference))
+	}
+	return nil
+}
+
+// LinkError applies Error to a LinkHook failure for input.
+func (p HookPolicy) LinkError(nodeType string, input LinkRenderInput, err error) error {
+	return p.Error(nodeType, "link", fmt.Sprintf("link reference %q", input.Href), err)
+}
+
+// MediaError applies Error to a MediaHook failure for input. The media is identified by
+// its ID, or by its URL when it has none.
+func (p HookPolicy) MediaError(nodeType string, input MediaRenderInput, err error) error {
+	reference := input.ID
+	if reference == "" {
+		reference = input.URL
+	}
+	return p.Error(nodeType, "media", fmt.Sprintf("media reference %q", reference), err)
+}
+
+// CheckLinkRenderOutput validates a LinkHook result and trims its href and title. It
+// reports false when the hook did not handle the link.
+func CheckLinkRenderOutput(output LinkRenderOutput) (LinkRenderOutput, bool, error) {
+	if !output.Handled {
+		return LinkRenderOutput{}, false, nil
+	}
+	if err := validateLinkRenderOutput(output); err != nil {
+		return LinkRenderOutput{}, false, fmt.Errorf("invalid link hook output: %w", err)
+	}
+
+	output.Href = strings.TrimSpace(output.Href)
+	output.Title = strings.TrimSpace(output.Title)
+	return output, true, nil
+}
+
+// CheckMediaRenderOutput validates a MediaHook result. It reports false when the hook did
+// not handle the media.
+func CheckMediaRenderOutput(output MediaRenderOutput) (MediaRenderOutput, bool, error) {
+	if !output.Handled {
+		return MediaRenderOutput{}, false, nil
+	}
+	if err := validateMediaRenderOutput(output); err != nil {
+		return MediaRenderOutput{}, false, fmt.Errorf("invalid media hook output: %w", err)
+	}
+	return output, true, nil
+}
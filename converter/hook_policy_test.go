@@ -0,0 +1,62 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookPolicyUnresolvedBestEffortWarns(t *testing.T) {
+	var warnings []string
+	policy := HookPolicy{
+		ResolutionMode: ResolutionBestEffort,
+		Warn: func(warnType WarningType, nodeType, message string) {
+			assert.Equal(t, WarningUnresolvedReference, warnType)
+			warnings = append(warnings, nodeType+": "+message)
+		},
+	}
+
+	err := policy.MediaError("media", MediaRenderInput{URL: "https://example.com/a.png"}, fmt.Errorf("lookup: %w", ErrUnresolved))
+	require.NoError(t, err)
+	assert.Equal(t, []string{`media: unresolved media reference "https://example.com/a.png"; using fallback rendering`}, warnings)
+}
+
+func TestHookPolicyUnresolvedStrictFails(t *testing.T) {
+	policy := HookPolicy{
+		ResolutionMode: ResolutionStrict,
+		Warn: func(WarningType, string, string) {
+			t.Fatal("strict mode must not warn")
+		},
+	}
+
+	err := policy.LinkError("link", LinkRenderInput{Href: "page.md"}, ErrUnresolved)
+	require.ErrorIs(t, err, ErrUnresolved)
+	assert.EqualError(t, err, `unresolved link reference "page.md": unresolved link or media reference`)
+}
+
+func TestHookPolicyWrapsHookFailures(t *testing.T) {
+	boom := errors.New("boom")
+	policy := HookPolicy{ResolutionMode: ResolutionBestEffort}
+
+	err := policy.Error("mention", "mention", `mention "abc"`, boom)
+	require.ErrorIs(t, err, boom)
+	assert.EqualError(t, err, "mention hook failed: boom")
+}
+
+func TestCheckLinkRenderOutput(t *testing.T) {
+	out, handled, err := CheckLinkRenderOutput(LinkRenderOutput{Href: " /page ", Title: " T ", Handled: true})
+	require.NoError(t, err)
+	assert.True(t, handled)
+	assert.Equal(t, "/page", out.Href)
+	assert.Equal(t, "T", out.Title)
+
+	_, handled, err = CheckLinkRenderOutput(LinkRenderOutput{Href: "/page"})
+	require.NoError(t, err)
+	assert.False(t, handled)
+
+	_, _, err = CheckLinkRenderOutput(LinkRenderOutput{Handled: true})
+	assert.ErrorContains(t, err, "invalid link hook output")
+}
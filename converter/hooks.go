@@ -21,24 +21,27 @@ const (
 // ConvertOptions carries optional per-conversion context.
 type ConvertOptions struct {
 	SourcePath string
+	// Metadata is written as YAML front matter before the Markdown, and passed to hooks
+	// as DocumentMetadata.
+	Metadata map[string]any
 }
 
 // LinkMetadata exposes common typed metadata for link hooks.
 type LinkMetadata struct {
-	PageID       string
-	SpaceKey     string
-	AttachmentID string
-	Filename     string
-	Anchor       string
+	PageID       string `json:"pageId,omitempty"`
+	SpaceKey     string `json:"spaceKey,omitempty"`
+	AttachmentID string `json:"attachmentId,omitempty"`
+	Filename     string `json:"filename,omitempty"`
+	Anchor       string `json:"anchor,omitempty"`
 }
 
 // MediaMetadata exposes common typed metadata for media hooks.
 type MediaMetadata struct {
-	PageID       string
-	SpaceKey     string
-	AttachmentID string
-	Filename     string
-	Anchor       string
+	PageID       string `json:"pageId,omitempty"`
+	SpaceKey     string `json:"spaceKey,omitempty"`
+	AttachmentID string `json:"attachmentId,omitempty"`
+	Filename     string `json:"filename,omitempty"`
+	Anchor       string `json:"anchor,omitempty"`
 }
 
 // LinkRenderHook can rewrite link output during ADF -> Markdown conversion.
@@ -56,6 +59,9 @@ type LinkRenderInput struct {
 	Text       string
 	Meta       LinkMetadata
 	Attrs      map[string]any
+	// DocumentMetadata is ConvertOptions.Metadata of the document being converted.
+	// It is left out of the HookCache key.
+	DocumentMetadata map[string]any `json:"-"`
 }
 
 // LinkRenderOutput contains hook-provided link rendering data.
@@ -75,6 +81,9 @@ type MediaRenderInput struct {
 	Alt        string
 	Meta       MediaMetadata
 	Attrs      map[string]any
+	// DocumentMetadata is ConvertOptions.Metadata of the document being converted.
+	// It is left out of the HookCache key.
+	DocumentMetadata map[string]any `json:"-"`
 }
 
 // MediaRenderOutput contains hook-provided markdown for media rendering.
@@ -82,3 +91,52 @@ type MediaRenderOutput struct {
 	Markdown string
 	Handled  bool
 }
+
+// LinkMarkRenderInput builds the link hook input for a link mark, as the Markdown
+// converter does. It reports false when the mark has no href.
+func LinkMarkRenderInput(sourcePath string, mark Mark) (LinkRenderInput, bool) {
+	href, _ := mark.Attrs["href"].(string)
+	if href == "" {
+		return LinkRenderInput{}, false
+	}
+	title, _ := mark.Attrs["title"].(string)
+
+	return LinkRenderInput{
+		Source:     "mark",
+		SourcePath: sourcePath,
+		Href:       href,
+		Title:      title,
+		Text:       "",
+		Meta:       linkMetadataFromAttrs(mark.Attrs, href),
+		Attrs:      cloneAnyMap(mark.Attrs),
+	}, true
+}
+
+// InlineCardRenderInput builds the link hook input for an inlineCard node.
+func InlineCardRenderInput(sourcePath string, node Node) LinkRenderInput {
+	title, url := inlineCardLinkData(node)
+	return LinkRenderInput{
+		Source:     "inlineCard",
+		SourcePath: sourcePath,
+		Href:       url,
+		Title:      title,
+		Text:       title,
+		Meta:       linkMetadataFromAttrs(node.Attrs, url),
+		Attrs:      cloneAnyMap(node.Attrs),
+	}
+}
+
+// MediaNodeRenderInput builds the media hook input for a media node.
+func MediaNodeRenderInput(sourcePath string, node Node) MediaRenderInput {
+	id := node.GetStringAttr("id", "")
+	url := node.GetStringAttr("url", "")
+	return MediaRenderInput{
+		SourcePath: sourcePath,
+		MediaType:  node.GetStringAttr("type", ""),
+		ID:         id,
+		URL:        url,
+		Alt:        node.GetStringAttr("alt", ""),
+		Meta:       mediaMetadataFromAttrs(node.Attrs, id, url),
+		Attrs:      cloneAnyMap(node.Attrs),
+	}
+}
@@ -2,7 +2,6 @@ package converter
 
 import (
 	"errors"
-	"fmt"
 	"net/url"
 	"path"
 	"strings"
@@ -14,85 +13,77 @@ const (
 	linkHookCacheHrefKey     = "__jac_link_hook_href"
 	linkHookCacheTitleKey    = "__jac_link_hook_title"
 	linkHookCacheTextOnlyKey = "__jac_link_hook_text_only"
-)
 
-func (s *state) applyLinkRenderHook(nodeType string, input LinkRenderInput) (LinkRenderOutput, bool, error) {
-	if s.config.LinkHook == nil {
-		return LinkRenderOutput{}, false, nil
-	}
+	linkRenderCacheKind  = "link_render"
+	mediaRenderCacheKind = "media_render"
+)
 
-	if err := s.checkContext(); err != nil {
-		return LinkRenderOutput{}, false, err
-	}
+func (s *state) hasLinkResolver() bool {
+	return s.config.LinkHook != nil || s.batch != nil
+}
 
-	output, err := s.config.LinkHook(s.ctx, input)
-	if err != nil {
-		if errors.Is(err, ErrUnresolved) {
-			if s.config.ResolutionMode == ResolutionStrict {
-				return LinkRenderOutput{}, false, fmt.Errorf("unresolved link reference %q: %w", input.Href, err)
-			}
-			s.addWarning(
-				WarningUnresolvedReference,
-				nodeType,
-				fmt.Sprintf("unresolved link reference %q; using fallback rendering", input.Href),
-			)
+func (s *state) applyLinkRenderHook(nodeType string, input LinkRenderInput) (LinkRenderOutput, bool, error) {
+	resolution, batched := s.batch.lookupLink(input)
+	if !batched {
+		if s.config.LinkHook == nil {
 			return LinkRenderOutput{}, false, nil
 		}
-		return LinkRenderOutput{}, false, fmt.Errorf("link hook failed: %w", err)
-	}
-
-	if !output.Handled {
-		return LinkRenderOutput{}, false, nil
-	}
-
-	if err := validateLinkRenderOutput(output); err != nil {
-		return LinkRenderOutput{}, false, fmt.Errorf("invalid link hook output: %w", err)
+		if err := s.checkContext(); err != nil {
+			return LinkRenderOutput{}, false, err
+		}
 	}
 
-	output.Href = strings.TrimSpace(output.Href)
-	output.Title = strings.TrimSpace(output.Title)
-
-	return output, true, nil
+	output, err := ResolveWithHookCache(s.config.HookCache, linkRenderCacheKind, input, func() (LinkRenderOutput, error) {
+		if batched {
+			return resolution.Output, resolution.Err
+		}
+		return s.config.LinkHook(s.ctx, input)
+	}, s.cacheStats.count)
+	return s.finishLinkRender(nodeType, input, output, err)
 }
 
-func (s *state) applyMediaRenderHook(nodeType string, input MediaRenderInput) (MediaRenderOutput, bool, error) {
-	if s.config.MediaHook == nil {
-		return MediaRenderOutput{}, false, nil
+// hookPolicy returns the unresolved-reference policy for this conversion.
+func (s *state) hookPolicy() HookPolicy {
+	return HookPolicy{
+		ResolutionMode: s.config.ResolutionMode,
+		Warn:           s.addWarning,
 	}
+}
 
-	if err := s.checkContext(); err != nil {
-		return MediaRenderOutput{}, false, err
+// finishLinkRender applies unresolved-reference policy and validation to a link resolution.
+func (s *state) finishLinkRender(nodeType string, input LinkRenderInput, output LinkRenderOutput, err error) (LinkRenderOutput, bool, error) {
+	if err != nil {
+		return LinkRenderOutput{}, false, s.hookPolicy().LinkError(nodeType, input, err)
 	}
+	return CheckLinkRenderOutput(output)
+}
 
-	output, err := s.config.MediaHook(s.ctx, input)
-	if err != nil {
-		if errors.Is(err, ErrUnresolved) {
-			reference := input.ID
-			if reference == "" {
-				reference = input.URL
-			}
-			if s.config.ResolutionMode == ResolutionStrict {
-				return MediaRenderOutput{}, false, fmt.Errorf("unresolved media reference %q: %w", reference, err)
-			}
-			s.addWarning(
-				WarningUnresolvedReference,
-				nodeType,
-				fmt.Sprintf("unresolved media reference %q; using fallback rendering", reference),
-			)
+func (s *state) applyMediaRenderHook(nodeType string, input MediaRenderInput) (MediaRenderOutput, bool, error) {
+	resolution, batched := s.batch.lookupMedia(input)
+	if !batched {
+		if s.config.MediaHook == nil {
 			return MediaRenderOutput{}, false, nil
 		}
-		return MediaRenderOutput{}, false, fmt.Errorf("media hook failed: %w", err)
+		if err := s.checkContext(); err != nil {
+			return MediaRenderOutput{}, false, err
+		}
 	}
 
-	if !output.Handled {
-		return MediaRenderOutput{}, false, nil
-	}
+	output, err := ResolveWithHookCache(s.config.HookCache, mediaRenderCacheKind, input, func() (MediaRenderOutput, error) {
+		if batched {
+			return resolution.Output, resolution.Err
+		}
+		return s.config.MediaHook(s.ctx, input)
+	}, s.cacheStats.count)
+	return s.finishMediaRender(nodeType, input, output, err)
+}
 
-	if err := validateMediaRenderOutput(output); err != nil {
-		return MediaRenderOutput{}, false, fmt.Errorf("invalid media hook output: %w", err)
+// finishMediaRender applies unresolved-reference policy and validation to a media resolution.
+func (s *state) finishMediaRender(nodeType string, input MediaRenderInput, output MediaRenderOutput, err error) (MediaRenderOutput, bool, error) {
+	if err != nil {
+		return MediaRenderOutput{}, false, s.hookPolicy().MediaError(nodeType, input, err)
 	}
-
-	return output, true, nil
+	return CheckMediaRenderOutput(output)
 }
 
 func validateLinkRenderOutput(output LinkRenderOutput) error {
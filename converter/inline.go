@@ -80,12 +80,25 @@ func (s *state) convertMention(node Node) (string, error) {
 // convertStatus converts a status node to text representation
 func (s *state) convertStatus(node Node) (string, error) {
 	text := node.GetStringAttr("text", "Unknown")
-	if s.config.StatusStyle == StatusText {
+	switch s.config.StatusStyle {
+	case StatusText:
 		return text, nil
+	case StatusGitLab:
+		return renderGitLabStatus(text, node.GetStringAttr("color", "")), nil
 	}
 	return fmt.Sprintf("[Status: %s]", text), nil
 }
 
+// convertPlaceholder renders the prompt text of a placeholder node as a {{text}} token,
+// or drops it.
+func (s *state) convertPlaceholder(node Node) string {
+	text := strings.TrimSpace(node.GetStringAttr("text", ""))
+	if s.config.PlaceholderStyle != PlaceholderBraces || text == "" {
+		return ""
+	}
+	return "{{" + text + "}}"
+}
+
 // convertDate converts a date node to ISO 8601 format
 func (s *state) convertDate(node Node) (string, error) {
 	timestamp := node.GetStringAttr("timestamp", "")
@@ -130,18 +143,7 @@ func (s *state) convertInlineCard(node Node) (string, error) {
 	title, url := s.getInlineCardLinkData(node)
 	hookHandled := false
 
-	hookOutput, handled, err := s.applyLinkRenderHook(
-		node.Type,
-		LinkRenderInput{
-			Source:     "inlineCard",
-			SourcePath: s.options.SourcePath,
-			Href:       url,
-			Title:      title,
-			Text:       title,
-			Meta:       linkMetadataFromAttrs(node.Attrs, url),
-			Attrs:      cloneAnyMap(node.Attrs),
-		},
-	)
+	hookOutput, handled, err := s.applyLinkRenderHook(node.Type, s.inlineCardRenderInput(node))
 	if err != nil {
 		return "", err
 	}
@@ -211,6 +213,13 @@ func (s *state) convertInlineCard(node Node) (string, error) {
 	return "[Smart Link]", nil
 }
 
+// inlineCardRenderInput builds the link hook input for an inlineCard node.
+func (s *state) inlineCardRenderInput(node Node) LinkRenderInput {
+	input := InlineCardRenderInput(s.options.SourcePath, node)
+	input.DocumentMetadata = s.options.Metadata
+	return input
+}
+
 func rewriteInlineCardAttrs(attrs map[string]any, title, href string) map[string]any {
 	rewritten := cloneAnyMap(attrs)
 	if rewritten == nil {
@@ -262,6 +271,12 @@ func firstNonEmptyTrimmed(values ...string) string {
 }
 
 func (s *state) getInlineCardLinkData(node Node) (string, string) {
+	return inlineCardLinkData(node)
+}
+
+// inlineCardLinkData returns the display title and URL of an inlineCard, preferring
+// the name and url of its data attribute.
+func inlineCardLinkData(node Node) (string, string) {
 	url := node.GetStringAttr("url", "")
 	title := ""
 	if url != "" {
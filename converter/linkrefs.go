@@ -0,0 +1,172 @@
+package converter
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// maxLinkNameLength caps the length of generated reference names.
+const maxLinkNameLength = 40
+
+// linkReferences collects reference link definitions until they are written out.
+type linkReferences struct {
+	pending []linkDefinition
+	// labels maps href and title to the name of a named reference.
+	labels map[string]string
+	used   map[string]bool
+	count  int
+}
+
+type linkDefinition struct {
+	label string
+	href  string
+	title string
+}
+
+// referenceLinkClosing closes a link with a reference label and records its definition.
+func (s *state) referenceLinkClosing(mark Mark) (string, error) {
+	href, title, ok, err := s.resolveLinkMark(mark)
+	if err != nil || !ok {
+		return "", err
+	}
+	if s.config.WikiLinks && title == "" && isWikiLinkTarget(href) {
+		return "]]", nil
+	}
+	return "][" + s.addLinkReference(href, title) + "]", nil
+}
+
+// addLinkReference returns the label for a link. LinkNumbered numbers every link;
+// LinkNamed names links after their target and reuses the name for the same target.
+func (s *state) addLinkReference(href, title string) string {
+	if s.links == nil {
+		s.links = &linkReferences{labels: map[string]string{}, used: map[string]bool{}}
+	}
+	refs := s.links
+
+	if s.config.LinkStyle == LinkNumbered {
+		refs.count++
+		label := strconv.Itoa(refs.count)
+		refs.pending = append(refs.pending, linkDefinition{label: label, href: href, title: title})
+		return label
+	}
+
+	key := href + "\x00" + title
+	if label, ok := refs.labels[key]; ok {
+		return label
+	}
+	base := linkReferenceName(href)
+	label := base
+	for i := 2; refs.used[label]; i++ {
+		label = fmt.Sprintf("%s-%d", base, i)
+	}
+	refs.labels[key] = label
+	refs.used[label] = true
+	refs.pending = append(refs.pending, linkDefinition{label: label, href: href, title: title})
+	return label
+}
+
+// appendLinkDefinitions writes the pending definitions after markdown, separated by a
+// blank line.
+func (s *state) appendLinkDefinitions(markdown string) string {
+	if s.links == nil || len(s.links.pending) == 0 {
+		return markdown
+	}
+
+	var sb strings.Builder
+	if trimmed := strings.TrimRight(markdown, "\n"); trimmed != "" {
+		sb.WriteString(trimmed)
+		sb.WriteString("\n\n")
+	}
+	for _, def := range s.links.pending {
+		href := def.href
+		if href == "" || strings.ContainsAny(href, " \t<>") {
+			href = "<" + strings.NewReplacer("<", "%3C", ">", "%3E").Replace(href) + ">"
+		}
+		sb.WriteString("[" + def.label + "]: " + href + quoteLinkTitle(def.title) + "\n")
+	}
+	sb.WriteString("\n")
+	s.links.pending = nil
+	return sb.String()
+}
+
+// convertSections converts top-level blocks and writes link definitions before each
+// heading, so that they close the section they belong to.
+func (s *state) convertSections(content []Node) (string, error) {
+	var sb strings.Builder
+	for _, child := range content {
+		if err := s.checkContext(); err != nil {
+			return "", err
+		}
+		if child.Type == "heading" {
+			section := s.appendLinkDefinitions(sb.String())
+			sb.Reset()
+			sb.WriteString(section)
+		}
+		res, err := s.convertNode(child)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(res)
+	}
+	return sb.String(), nil
+}
+
+// linkReferenceName derives a reference name from the last path segment of href, or
+// from its host or fragment when the path is empty.
+func linkReferenceName(href string) string {
+	name := ""
+	if parsed, err := url.Parse(href); err == nil {
+		segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+		name = segments[len(segments)-1]
+		if unescaped, err := url.PathUnescape(name); err == nil {
+			name = unescaped
+		}
+		if slugifyLinkName(name) == "" {
+			name = strings.TrimPrefix(parsed.Hostname(), "www.")
+		}
+		if slugifyLinkName(name) == "" {
+			name = parsed.Fragment
+		}
+	}
+
+	if name = slugifyLinkName(name); name == "" {
+		return "link"
+	}
+	return name
+}
+
+// slugifyLinkName lowercases name and joins its letters and digits with hyphens.
+func slugifyLinkName(name string) string {
+	var sb strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			if hyphen && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			hyphen = false
+			sb.WriteRune(r)
+			continue
+		}
+		hyphen = true
+	}
+
+	slug := sb.String()
+	if len(slug) > maxLinkNameLength {
+		slug = strings.TrimRight(slug[:maxLinkNameLength], "-")
+	}
+	return slug
+}
+
+// quoteLinkTitle returns title as a quoted link title with a leading space, or an empty
+// string when there is no title.
+func quoteLinkTitle(title string) string {
+	if title == "" {
+		return ""
+	}
+	escaped := strings.ReplaceAll(title, "\\", "\\\\")
+	escaped = strings.ReplaceAll(escaped, "\"", "\\\"")
+	return " \"" + escaped + "\""
+}
@@ -0,0 +1,81 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const linkStyleInput = `{"type":"doc","content":[
+	{"type":"paragraph","content":[
+		{"type":"text","text":"See "},
+		{"type":"text","text":"design","marks":[{"type":"link","attrs":{"href":"https://example.atlassian.net/wiki/spaces/ENG/pages/123/Design+Doc?src=search"}}]},
+		{"type":"text","text":" and "},
+		{"type":"text","text":"home","marks":[{"type":"link","attrs":{"href":"https://www.example.com/","title":"Home \"page\""}}]},
+		{"type":"text","text":"."}
+	]},
+	{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Next"}]},
+	{"type":"paragraph","content":[
+		{"type":"text","text":"Again","marks":[{"type":"link","attrs":{"href":"https://example.atlassian.net/wiki/spaces/ENG/pages/123/Design+Doc?src=search"}}]},
+		{"type":"text","text":" and "},
+		{"type":"text","text":"other","marks":[{"type":"strong"},{"type":"link","attrs":{"href":"https://other.org/Design-Doc"}}]}
+	]}
+]}`
+
+func TestConvertNumberedLinks(t *testing.T) {
+	result, err := newTestConverter(t, Config{LinkStyle: LinkNumbered}).Convert([]byte(linkStyleInput))
+	require.NoError(t, err)
+	assert.Equal(t, "See [design][1] and [home][2].\n\n"+
+		"## Next\n\n"+
+		"[Again][3] and **[other][4]**\n\n"+
+		"[1]: https://example.atlassian.net/wiki/spaces/ENG/pages/123/Design+Doc?src=search\n"+
+		"[2]: https://www.example.com/ \"Home \\\"page\\\"\"\n"+
+		"[3]: https://example.atlassian.net/wiki/spaces/ENG/pages/123/Design+Doc?src=search\n"+
+		"[4]: https://other.org/Design-Doc\n", result.Markdown)
+}
+
+func TestConvertNamedLinksBySection(t *testing.T) {
+	result, err := newTestConverter(t, Config{
+		LinkStyle:       LinkNamed,
+		LinkDefinitions: LinkDefinitionsSection,
+	}).Convert([]byte(linkStyleInput))
+	require.NoError(t, err)
+	assert.Equal(t, "See [design][design-doc] and [home][example-com].\n\n"+
+		"[design-doc]: https://example.atlassian.net/wiki/spaces/ENG/pages/123/Design+Doc?src=search\n"+
+		"[example-com]: https://www.example.com/ \"Home \\\"page\\\"\"\n\n"+
+		"## Next\n\n"+
+		"[Again][design-doc] and **[other][design-doc-2]**\n\n"+
+		"[design-doc-2]: https://other.org/Design-Doc\n", result.Markdown)
+}
+
+func TestConvertReferenceLinksInParallelAndChunks(t *testing.T) {
+	conv := newTestConverter(t, Config{LinkStyle: LinkNumbered, Parallelism: 4})
+
+	result, err := conv.Convert([]byte(linkStyleInput))
+	require.NoError(t, err)
+	assert.Contains(t, result.Markdown, "[Again][3]")
+
+	chunks, err := conv.Chunk([]byte(linkStyleInput), ChunkOptions{MaxChars: 80})
+	require.NoError(t, err)
+	require.NotEmpty(t, chunks.Chunks)
+	assert.Contains(t, chunks.Chunks[0].Markdown, "[design](https://example.atlassian.net/")
+}
+
+func TestLinkReferenceName(t *testing.T) {
+	assert.Equal(t, "design-doc", linkReferenceName("https://example.com/pages/1/Design+Doc"))
+	assert.Equal(t, "caf-menu", linkReferenceName("https://example.com/Caf%C3%A9%20Menu"))
+	assert.Equal(t, "example-com", linkReferenceName("https://www.example.com/?q=1"))
+	assert.Equal(t, "section", linkReferenceName("#section"))
+	assert.Equal(t, "link", linkReferenceName("mailto:"))
+}
+
+func TestLinkStyleValidation(t *testing.T) {
+	_, err := New(Config{LinkStyle: "footnote"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "linkStyle")
+
+	_, err = New(Config{LinkDefinitions: "page"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "linkDefinitions")
+}
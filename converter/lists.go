@@ -3,6 +3,7 @@ package converter
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
 )
 
 // convertListItems iterates over list items, converts them, and applies indentation with the provided marker.
@@ -19,12 +20,14 @@ func (s *state) convertListItems(content []Node, childType string, getMarker fun
 			continue
 		}
 
-		itemContent, err := s.convertListItemContent(item.Content)
+		marker := getMarker(i)
+		itemContent, err := s.withWrapIndent(len(marker), 0, func() (string, error) {
+			return s.convertListItemContent(item.Content)
+		})
 		if err != nil {
 			return "", err
 		}
 
-		marker := getMarker(i)
 		indented := s.indent(itemContent, marker)
 		sb.WriteString(indented)
 		sb.WriteString("\n")
@@ -60,7 +63,9 @@ func (s *state) convertTaskList(node Node) (string, error) {
 
 	for _, item := range node.Content {
 		if item.Type == "taskList" {
-			res, err := s.convertTaskList(item)
+			res, err := s.withWrapIndent(2, 0, func() (string, error) {
+				return s.convertTaskList(item)
+			})
 			if err != nil {
 				return "", err
 			}
@@ -101,9 +106,16 @@ func (s *state) convertTaskItem(node Node) (string, error) {
 	if state == "DONE" {
 		marker = "- [x] "
 	}
+	if s.config.CommonMark {
+		// Escaped brackets keep the checkbox as text in CommonMark and GFM alike.
+		marker = strings.ReplaceAll(strings.ReplaceAll(marker, "[", `\[`), "]", `\]`)
+	}
 
 	// Convert content using inline content converter to support marks
-	itemContent, err := s.convertInlineContent(node.Content)
+	itemContent, err := s.withWrapIndent(utf8.RuneCountInString(marker), 0, func() (string, error) {
+		content, err := s.convertInlineContent(node.Content)
+		return s.wrapText(content), err
+	})
 	if err != nil {
 		return "", err
 	}
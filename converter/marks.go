@@ -99,15 +99,18 @@ func (s *state) isKnownMark(markType string) bool {
 	}
 }
 
-// getOpeningDelimiterForMark returns the opening delimiter for a mark
-func (s *state) getOpeningDelimiterForMark(mark Mark, useUnderscoreForEm bool) (string, error) {
-	prefix, _, err := s.convertMarkFull(mark, useUnderscoreForEm)
+// markOpening returns the opening Markdown delimiter for a mark.
+func (m markdownFormat) markOpening(mark Mark, useUnderscoreForEm bool) (string, error) {
+	prefix, _, err := m.convertMarkFull(mark, useUnderscoreForEm)
 	return prefix, err
 }
 
-// getClosingDelimiterForMark returns the closing delimiter for a mark
-func (s *state) getClosingDelimiterForMark(mark Mark, useUnderscoreForEm bool) (string, error) {
-	_, suffix, err := s.convertMarkFull(mark, useUnderscoreForEm)
+// markClosing returns the closing Markdown delimiter for a mark.
+func (m markdownFormat) markClosing(mark Mark, useUnderscoreForEm bool) (string, error) {
+	if mark.Type == "link" && m.config.LinkStyle != LinkInline {
+		return m.referenceLinkClosing(mark)
+	}
+	_, suffix, err := m.convertMarkFull(mark, useUnderscoreForEm)
 	return suffix, err
 }
 
@@ -139,65 +142,21 @@ func (s *state) convertMarkFull(mark Mark, useUnderscoreForEm bool) (string, str
 			return "", "", nil
 		}
 	case "link":
-		// Extract href and title from attrs
-		if mark.Attrs == nil {
-			// No attributes - just return plain text
-			return "", "", nil
+		href, title, ok, err := s.resolveLinkMark(mark)
+		if err != nil || !ok {
+			// No href or text-only hook result - just return plain text
+			return "", "", err
 		}
-		href, hasHref := mark.Attrs["href"].(string)
-		if !hasHref || href == "" {
-			// No href - just return plain text
-			return "", "", nil
-		}
-		title, _ := mark.Attrs["title"].(string)
-
-		if s.config.LinkHook != nil {
-			hookOutput := LinkRenderOutput{}
-			handled := false
-
-			if cachedOutput, ok := loadLinkHookCache(mark.Attrs); ok {
-				hookOutput = cachedOutput
-				handled = cachedOutput.Handled
-			} else {
-				var err error
-				hookOutput, handled, err = s.applyLinkRenderHook(
-					mark.Type,
-					LinkRenderInput{
-						Source:     "mark",
-						SourcePath: s.options.SourcePath,
-						Href:       href,
-						Title:      title,
-						Text:       "",
-						Meta:       linkMetadataFromAttrs(mark.Attrs, href),
-						Attrs:      cloneAnyMap(mark.Attrs),
-					},
-				)
-				if err != nil {
-					return "", "", err
-				}
-				storeLinkHookCache(mark.Attrs, hookOutput, handled)
-			}
 
-			if handled {
-				if hookOutput.TextOnly {
-					return "", "", nil
-				}
-				href = hookOutput.Href
-				title = hookOutput.Title
-			}
+		if s.config.WikiLinks && title == "" && isWikiLinkTarget(href) {
+			return "[[" + href + "|", "]]", nil
 		}
 
 		// Build link syntax: [text](href) or [text](href "title")
 		opening := "["
 		closing := "](" + href
 
-		if title != "" {
-			// Escape quotes in title
-			escapedTitle := strings.ReplaceAll(title, "\\", "\\\\")
-			escapedTitle = strings.ReplaceAll(escapedTitle, "\"", "\\\"")
-			closing += " \"" + escapedTitle + "\""
-		}
-		closing += ")"
+		closing += quoteLinkTitle(title) + ")"
 
 		return opening, closing, nil
 	case "subsup":
@@ -242,7 +201,7 @@ func (s *state) convertMarkFull(mark Mark, useUnderscoreForEm bool) (string, str
 		case ColorIgnore:
 			return "", "", nil
 		case ColorHTML:
-			color, ok := sanitizeCSSColor(mark.GetStringAttr("color", ""))
+			color, ok := SanitizeCSSColor(mark.GetStringAttr("color", ""))
 			if !ok {
 				if raw := mark.GetStringAttr("color", ""); raw != "" {
 					s.addWarning(WarningDroppedFeature, mark.Type, fmt.Sprintf("invalid color value dropped: %q", raw))
@@ -251,7 +210,7 @@ func (s *state) convertMarkFull(mark Mark, useUnderscoreForEm bool) (string, str
 			}
 			return `<span style="color: ` + color + `">`, "</span>", nil
 		case ColorPandoc:
-			color, ok := sanitizeCSSColor(mark.GetStringAttr("color", ""))
+			color, ok := SanitizeCSSColor(mark.GetStringAttr("color", ""))
 			if !ok {
 				if raw := mark.GetStringAttr("color", ""); raw != "" {
 					s.addWarning(WarningDroppedFeature, mark.Type, fmt.Sprintf("invalid color value dropped: %q", raw))
@@ -267,7 +226,7 @@ func (s *state) convertMarkFull(mark Mark, useUnderscoreForEm bool) (string, str
 		case ColorIgnore:
 			return "", "", nil
 		case ColorHTML:
-			color, ok := sanitizeCSSColor(mark.GetStringAttr("color", ""))
+			color, ok := SanitizeCSSColor(mark.GetStringAttr("color", ""))
 			if !ok {
 				if raw := mark.GetStringAttr("color", ""); raw != "" {
 					s.addWarning(WarningDroppedFeature, mark.Type, fmt.Sprintf("invalid color value dropped: %q", raw))
@@ -276,7 +235,7 @@ func (s *state) convertMarkFull(mark Mark, useUnderscoreForEm bool) (string, str
 			}
 			return `<span style="background-color: ` + color + `">`, "</span>", nil
 		case ColorPandoc:
-			color, ok := sanitizeCSSColor(mark.GetStringAttr("color", ""))
+			color, ok := SanitizeCSSColor(mark.GetStringAttr("color", ""))
 			if !ok {
 				if raw := mark.GetStringAttr("color", ""); raw != "" {
 					s.addWarning(WarningDroppedFeature, mark.Type, fmt.Sprintf("invalid color value dropped: %q", raw))
@@ -284,6 +243,8 @@ func (s *state) convertMarkFull(mark Mark, useUnderscoreForEm bool) (string, str
 				return "", "", nil
 			}
 			return "[", `]{style="background-color: ` + color + `;"}`, nil
+		case ColorObsidian:
+			return "==", "==", nil
 		default:
 			return "", "", nil
 		}
@@ -300,7 +261,60 @@ func (s *state) convertMarkFull(mark Mark, useUnderscoreForEm bool) (string, str
 	}
 }
 
-func sanitizeCSSColor(raw string) (string, bool) {
+// resolveLinkMark returns the href and title of a link mark after the link hook runs.
+// It reports false when the link renders as plain text: the mark has no href or the
+// hook returned a text-only result. Hook results are cached in the mark attrs because
+// opening and closing delimiters are resolved separately.
+func (s *state) resolveLinkMark(mark Mark) (string, string, bool, error) {
+	if mark.Attrs == nil {
+		return "", "", false, nil
+	}
+	href, hasHref := mark.Attrs["href"].(string)
+	if !hasHref || href == "" {
+		return "", "", false, nil
+	}
+	title, _ := mark.Attrs["title"].(string)
+
+	if s.hasLinkResolver() {
+		hookOutput := LinkRenderOutput{}
+		handled := false
+
+		if cachedOutput, ok := loadLinkHookCache(mark.Attrs); ok {
+			hookOutput = cachedOutput
+			handled = cachedOutput.Handled
+		} else {
+			input, _ := s.linkMarkRenderInput(mark)
+			var err error
+			hookOutput, handled, err = s.applyLinkRenderHook(mark.Type, input)
+			if err != nil {
+				return "", "", false, err
+			}
+			storeLinkHookCache(mark.Attrs, hookOutput, handled)
+		}
+
+		if handled {
+			if hookOutput.TextOnly {
+				return "", "", false, nil
+			}
+			href = hookOutput.Href
+			title = hookOutput.Title
+		}
+	}
+
+	return href, title, true, nil
+}
+
+// linkMarkRenderInput builds the hook input for a link mark. It reports false when the
+// mark has no href and therefore renders as plain text.
+func (s *state) linkMarkRenderInput(mark Mark) (LinkRenderInput, bool) {
+	input, ok := LinkMarkRenderInput(s.options.SourcePath, mark)
+	input.DocumentMetadata = s.options.Metadata
+	return input, ok
+}
+
+// SanitizeCSSColor returns the trimmed color when it is a hex, named, rgb(a), hsl(a) or
+// var() CSS color, and reports false for anything else.
+func SanitizeCSSColor(raw string) (string, bool) {
 	color := strings.TrimSpace(raw)
 	if color == "" {
 		return "", false
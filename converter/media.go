@@ -47,18 +47,7 @@ func (s *state) convertMedia(node Node) (string, error) {
 	alt := node.GetStringAttr("alt", "")
 	url := node.GetStringAttr("url", "")
 
-	hookOutput, handled, err := s.applyMediaRenderHook(
-		node.Type,
-		MediaRenderInput{
-			SourcePath: s.options.SourcePath,
-			MediaType:  mediaType,
-			ID:         id,
-			URL:        url,
-			Alt:        alt,
-			Meta:       mediaMetadataFromAttrs(node.Attrs, id, url),
-			Attrs:      cloneAnyMap(node.Attrs),
-		},
-	)
+	hookOutput, handled, err := s.applyMediaRenderHook(node.Type, s.mediaRenderInput(node))
 	if err != nil {
 		return "", err
 	}
@@ -74,6 +63,12 @@ func (s *state) convertMedia(node Node) (string, error) {
 		return fmt.Sprintf("![%s](%s)", alt, url), nil
 	}
 
+	if s.config.WikiLinks && url == "" && s.config.MediaBaseURL == "" {
+		if embed, ok := wikiEmbed(mediaType, id, alt); ok {
+			return embed, nil
+		}
+	}
+
 	// Internal media resolved via configured base URL.
 	if url == "" && id != "" && s.config.MediaBaseURL != "" {
 		if alt == "" {
@@ -120,3 +115,10 @@ func (s *state) convertMedia(node Node) (string, error) {
 	}
 	return fmt.Sprintf("[Media: %s]", id), nil
 }
+
+// mediaRenderInput builds the media hook input for a media node.
+func (s *state) mediaRenderInput(node Node) MediaRenderInput {
+	input := MediaNodeRenderInput(s.options.SourcePath, node)
+	input.DocumentMetadata = s.options.Metadata
+	return input
+}
@@ -0,0 +1,67 @@
+package converter
+
+import (
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// renderCallout renders a > [!type] Title callout, as written by Obsidian and GitLab.
+// The fold marker is "-" for collapsed Obsidian callouts and empty otherwise.
+func (s *state) renderCallout(keyword, fold, title, content string) string {
+	header := "> [!" + keyword + "]" + fold
+	if title != "" {
+		header += " " + title
+	}
+	quoted := s.blockquoteContent(content, "")
+	if quoted == "" {
+		return header + "\n\n"
+	}
+	return header + "\n" + quoted + "\n\n"
+}
+
+// isWikiLinkTarget reports whether href names a note rather than a URL, path or anchor,
+// so that it can be written as a [[wiki link]].
+func isWikiLinkTarget(href string) bool {
+	if href == "" || strings.ContainsAny(href, "|\n") || strings.Contains(href, "]]") || strings.Contains(href, "[[") {
+		return false
+	}
+	switch href[0] {
+	case '/', '#', '.', '?':
+		return false
+	}
+	parsed, err := url.Parse(href)
+	return err != nil || parsed.Scheme == ""
+}
+
+// wikiEmbed renders media without a URL as an Obsidian ![[embed]]. Files without an
+// extension are not embedded, since they would read back as images.
+func wikiEmbed(mediaType, id, alt string) (string, bool) {
+	if !isWikiLinkTarget(id) || (mediaType == "file" && path.Ext(id) == "") {
+		return "", false
+	}
+	// A numeric alias would be read as the embed width, and "Image" is the default alt
+	// of Markdown images rather than a name.
+	if _, err := strconv.Atoi(alt); err == nil || alt == id || alt == "Image" || strings.ContainsAny(alt, "|\n") || strings.Contains(alt, "]]") {
+		alt = ""
+	}
+	if alt == "" {
+		return "![[" + id + "]]", true
+	}
+	return "![[" + id + "|" + alt + "]]", true
+}
+
+// collapseWikiLink rewrites the [[target|text]] link written to sb from start as
+// [[target]] when its text is the target itself.
+func collapseWikiLink(sb *strings.Builder, start int) {
+	content := sb.String()
+	inner, opened := strings.CutPrefix(content[start:], "[[")
+	inner, closed := strings.CutSuffix(inner, "]]")
+	target, text, aliased := strings.Cut(inner, "|")
+	if !opened || !closed || !aliased || target != text {
+		return
+	}
+	sb.Reset()
+	sb.WriteString(content[:start] + "[[" + target + "]]")
+}
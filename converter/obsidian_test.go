@@ -0,0 +1,55 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertObsidianCalloutsAndHighlights(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"panel","attrs":{"panelType":"success","title":"Done"},"content":[
+			{"type":"paragraph","content":[
+				{"type":"text","text":"Ship "},
+				{"type":"text","text":"it","marks":[{"type":"backgroundColor","attrs":{"color":"#fff0b3"}}]}
+			]}
+		]},
+		{"type":"expand","attrs":{"title":"More"},"content":[
+			{"type":"paragraph","content":[{"type":"text","text":"hidden"}]},
+			{"type":"nestedExpand","content":[{"type":"paragraph","content":[{"type":"text","text":"deep"}]}]}
+		]}
+	]}`)
+	result, err := newTestConverter(t, Config{
+		PanelStyle:           PanelObsidian,
+		ExpandStyle:          ExpandObsidian,
+		BackgroundColorStyle: ColorObsidian,
+	}).Convert(input)
+	require.NoError(t, err)
+	assert.Equal(t, "> [!success] Done\n> Ship ==it==\n\n"+
+		"> [!note]- More\n> hidden\n> \n>> [!note]-\n>> deep\n", result.Markdown)
+}
+
+func TestConvertWikiLinks(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[
+			{"type":"text","text":"Guide","marks":[{"type":"link","attrs":{"href":"Setup#Install"}}]},
+			{"type":"text","text":" "},
+			{"type":"text","text":"site","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]},
+			{"type":"text","text":" "},
+			{"type":"text","text":"titled","marks":[{"type":"link","attrs":{"href":"Setup","title":"Tip"}}]},
+			{"type":"text","text":" "},
+			{"type":"text","text":"Setup","marks":[{"type":"link","attrs":{"href":"Setup"}}]}
+		]},
+		{"type":"mediaSingle","content":[{"type":"media","attrs":{"type":"file","id":"diagram.png","alt":"Diagram"}}]},
+		{"type":"mediaSingle","content":[{"type":"media","attrs":{"type":"file","id":"pic.png","alt":"Image"}}]},
+		{"type":"mediaSingle","content":[{"type":"media","attrs":{"type":"file","id":"abc-123"}}]}
+	]}`)
+
+	result, err := newTestConverter(t, Config{WikiLinks: true}).Convert(input)
+	require.NoError(t, err)
+	assert.Equal(t, "[[Setup#Install|Guide]] [site](https://example.com) [titled](Setup \"Tip\") [[Setup]]\n\n"+
+		"![[diagram.png|Diagram]]\n\n"+
+		"![[pic.png]]\n\n"+
+		"[File: abc-123]\n", result.Markdown)
+}
@@ -0,0 +1,101 @@
+package converter
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// blockResult holds the rendered output of a single top-level block.
+type blockResult struct {
+	markdown   string
+	warnings   []Warning
+	references References
+	err        error
+}
+
+// shouldConvertInParallel reports whether the document qualifies for parallel rendering.
+// Reference links are numbered across the document, so they are rendered sequentially.
+func (s *state) shouldConvertInParallel(doc Doc) bool {
+	return s.config.Parallelism > 1 && doc.Type == "doc" && len(doc.Content) > 1 && s.config.LinkStyle == LinkInline
+}
+
+// convertDocParallel renders top-level blocks on a bounded worker pool and merges
+// output and warnings in document order, matching sequential conversion exactly.
+func (s *state) convertDocParallel(content []Node) (string, error) {
+	workers := s.config.Parallelism
+	if workers > len(content) {
+		workers = len(content)
+	}
+
+	results := make([]blockResult, len(content))
+	jobs := make(chan int)
+
+	// firstFailed tracks the lowest block index that returned an error. Blocks after it
+	// are skipped because sequential conversion would never have reached them.
+	var firstFailed atomic.Int64
+	firstFailed.Store(int64(len(content)))
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				if int64(index) > firstFailed.Load() {
+					continue
+				}
+
+				blockState := &state{
+					config:     s.config,
+					ctx:        s.ctx,
+					options:    s.options,
+					batch:      s.batch,
+					cacheStats: s.cacheStats,
+				}
+				blockState.format = markdownFormat{blockState}
+				blockState.references.addNode(&content[index], childPath("", index))
+				markdown, err := blockState.convertNode(content[index])
+				results[index] = blockResult{
+					markdown:   markdown,
+					warnings:   blockState.warnings,
+					references: blockState.references.refs,
+					err:        err,
+				}
+				if err != nil {
+					lowerFirstFailed(&firstFailed, int64(index))
+				}
+			}
+		}()
+	}
+
+	for index := range content {
+		if int64(index) > firstFailed.Load() {
+			break
+		}
+		jobs <- index
+	}
+	close(jobs)
+	wg.Wait()
+
+	var sb strings.Builder
+	for _, result := range results {
+		if result.err != nil {
+			return "", result.err
+		}
+		s.warnings = append(s.warnings, result.warnings...)
+		s.references.merge(result.references)
+		sb.WriteString(result.markdown)
+	}
+
+	return finishDocument(sb.String()), nil
+}
+
+func lowerFirstFailed(firstFailed *atomic.Int64, index int64) {
+	for {
+		current := firstFailed.Load()
+		if index >= current || firstFailed.CompareAndSwap(current, index) {
+			return
+		}
+	}
+}
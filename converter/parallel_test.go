@@ -0,0 +1,131 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallelMatchesSequentialGoldenFiles(t *testing.T) {
+	testDataDir := "../testdata"
+
+	err := filepath.Walk(testDataDir, func(path string, info os.FileInfo, err error) error {
+		require.NoError(t, err)
+		if info.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		t.Run(path, func(t *testing.T) {
+			input, err := os.ReadFile(path)
+			require.NoError(t, err)
+
+			cfg := goldenConfigForPath(path)
+			sequential, seqErr := newTestConverter(t, cfg).Convert(input)
+
+			cfg.Parallelism = 4
+			parallel, parErr := newTestConverter(t, cfg).Convert(input)
+
+			if seqErr != nil {
+				require.Error(t, parErr)
+				assert.Equal(t, seqErr.Error(), parErr.Error())
+				return
+			}
+			require.NoError(t, parErr)
+			assert.Equal(t, sequential, parallel)
+		})
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestParallelMergesWarningsInDocumentOrder(t *testing.T) {
+	var blocks []string
+	for i := 0; i < 20; i++ {
+		blocks = append(blocks, fmt.Sprintf(`{"type":"unknown%d"}`, i))
+	}
+	input := []byte(`{"type":"doc","content":[` + strings.Join(blocks, ",") + `]}`)
+
+	conv := newTestConverter(t, Config{Parallelism: 3})
+	result, err := conv.Convert(input)
+	require.NoError(t, err)
+
+	require.Len(t, result.Warnings, 20)
+	for i, warning := range result.Warnings {
+		assert.Equal(t, fmt.Sprintf("unknown%d", i), warning.NodeType)
+	}
+}
+
+func TestParallelRunsHooksConcurrently(t *testing.T) {
+	var blocks []string
+	for i := 0; i < 8; i++ {
+		blocks = append(blocks, fmt.Sprintf(`{"type":"paragraph","content":[{"type":"text","text":"Link %d","marks":[{"type":"link","attrs":{"href":"https://example.com/%d"}}]}]}`, i, i))
+	}
+	input := []byte(`{"type":"doc","content":[` + strings.Join(blocks, ",") + `]}`)
+
+	var inFlight, maxInFlight atomic.Int32
+	conv := newTestConverter(t, Config{
+		Parallelism: 4,
+		LinkHook: func(_ context.Context, in LinkRenderInput) (LinkRenderOutput, error) {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				seen := maxInFlight.Load()
+				if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			return LinkRenderOutput{Href: strings.Replace(in.Href, "https://example.com/", "./", 1), Handled: true}, nil
+		},
+	})
+
+	result, err := conv.Convert(input)
+	require.NoError(t, err)
+
+	assert.Greater(t, maxInFlight.Load(), int32(1))
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(4))
+	assert.True(t, strings.HasPrefix(result.Markdown, "[Link 0](./0)\n\n[Link 1](./1)\n\n"))
+	assert.True(t, strings.HasSuffix(result.Markdown, "[Link 7](./7)\n"))
+}
+
+func TestParallelReturnsFirstErrorInDocumentOrder(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[{"type":"text","text":"ok"}]},
+		{"type":"paragraph","content":[{"type":"text","text":"slow","marks":[{"type":"link","attrs":{"href":"https://example.com/slow"}}]}]},
+		{"type":"paragraph","content":[{"type":"text","text":"fast","marks":[{"type":"link","attrs":{"href":"https://example.com/fast"}}]}]}
+	]}`)
+
+	conv := newTestConverter(t, Config{
+		Parallelism: 3,
+		LinkHook: func(_ context.Context, in LinkRenderInput) (LinkRenderOutput, error) {
+			if strings.HasSuffix(in.Href, "/slow") {
+				time.Sleep(20 * time.Millisecond)
+			}
+			return LinkRenderOutput{}, errors.New(in.Href)
+		},
+	})
+
+	_, err := conv.Convert(input)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "https://example.com/slow")
+}
+
+func TestParallelHonorsContextCancellation(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"a"}]},{"type":"paragraph","content":[{"type":"text","text":"b"}]}]}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	conv := newTestConverter(t, Config{Parallelism: 2})
+	_, err := conv.ConvertWithContext(ctx, input, ConvertOptions{})
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,359 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PlainTextResult holds the output of a plain-text conversion.
+type PlainTextResult struct {
+	Text string `json:"text"`
+	// Spans map byte ranges of Text back to the ADF nodes they were rendered from,
+	// in ascending order.
+	Spans    []TextSpan `json:"spans,omitempty"`
+	Warnings []Warning  `json:"warnings,omitempty"`
+}
+
+// TextSpan maps Text[Start:End] (byte offsets) to the JSON Pointer path of an ADF node.
+type TextSpan struct {
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Path  string `json:"path"`
+}
+
+// plainTextWriter renders ADF as plain text. It records spans while writing, so node
+// paths are tracked next to the output rather than in the nodes or the text.
+type plainTextWriter struct {
+	*state
+	buf   []byte
+	spans []TextSpan
+	// pending counts the line breaks owed before the next text. They are dropped at the
+	// start of the document and become a single space inside table cells.
+	pending int
+	// lineStart is set when the next text starts a line and gets the list prefixes.
+	lineStart bool
+	// lists holds the enclosing list items, innermost last.
+	lists []plainListItem
+	// cell is the offset where the current table cell starts, or -1 outside tables.
+	cell int
+	// letters counts the non-space characters written, to detect blank blocks.
+	letters int
+}
+
+// plainListItem is a list item being written. Its marker prefixes the first line and
+// is replaced by spaces on later lines.
+type plainListItem struct {
+	marker  string
+	started bool
+}
+
+// plainTextCheckpoint is the writer position restored when a block turns out blank.
+type plainTextCheckpoint struct {
+	size, spans, pending, letters int
+	lineStart                     bool
+	lists                         []plainListItem
+}
+
+// ConvertPlainText takes an ADF JSON document and returns plain text without Markdown syntax.
+func (c *Converter) ConvertPlainText(input []byte) (PlainTextResult, error) {
+	return c.ConvertPlainTextWithContext(context.Background(), input, ConvertOptions{})
+}
+
+// ConvertPlainTextWithContext takes an ADF JSON document and returns plain text for search
+// indexing, with spans that map text offsets back to ADF node paths. Marks, link targets and
+// block decorations are dropped; mention, status, emoji, date and card text is kept.
+// Link and media hooks and extension handlers are not invoked.
+func (c *Converter) ConvertPlainTextWithContext(ctx context.Context, input []byte, opts ConvertOptions) (PlainTextResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := ctx.Err(); err != nil {
+		return PlainTextResult{}, err
+	}
+
+	var doc Doc
+	if err := json.Unmarshal(input, &doc); err != nil {
+		return PlainTextResult{}, fmt.Errorf("failed to parse ADF JSON: %w", err)
+	}
+
+	w := &plainTextWriter{
+		state: &state{
+			config:  c.config,
+			ctx:     ctx,
+			options: opts,
+		},
+		lineStart: true,
+		cell:      -1,
+	}
+	if err := w.writeNodes(doc.Content, ""); err != nil {
+		return PlainTextResult{}, err
+	}
+	if err := w.checkContext(); err != nil {
+		return PlainTextResult{}, err
+	}
+
+	if len(w.buf) > 0 {
+		w.buf = append(w.buf, '\n')
+	}
+	return PlainTextResult{Text: string(w.buf), Spans: w.spans, Warnings: w.warnings}, nil
+}
+
+func (w *plainTextWriter) writeNodes(content []Node, parentPath string) error {
+	for index, child := range content {
+		if err := w.writeNode(child, childPath(parentPath, index)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *plainTextWriter) writeNode(node Node, path string) error {
+	if err := w.checkContext(); err != nil {
+		return err
+	}
+
+	switch node.Type {
+	case "text":
+		w.writeText(node.Text, path)
+
+	case "paragraph", "heading", "taskItem", "decisionItem", "codeBlock":
+		return w.writeBlock(2, func() error {
+			return w.writeNodes(node.Content, path)
+		})
+
+	case "blockquote", "panel", "layoutSection", "layoutColumn", "mediaSingle", "mediaGroup", "bodiedExtension",
+		"listItem", "tableRow", "tableHeader", "tableCell":
+		return w.writeNodes(node.Content, path)
+
+	case "expand", "nestedExpand":
+		if title := node.GetStringAttr("title", ""); title != "" {
+			if err := w.writeBlock(2, func() error {
+				w.writeText(title, path)
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		return w.writeNodes(node.Content, path)
+
+	case "bulletList", "orderedList", "taskList", "decisionList":
+		return w.writeList(node, path)
+
+	case "table":
+		return w.writeTable(node, path)
+
+	case "rule", "placeholder", "extension", "inlineExtension":
+
+	case "hardBreak":
+		w.pending++
+
+	case "mention", "status":
+		w.writeText(node.GetStringAttr("text", ""), path)
+
+	case "emoji":
+		w.writeText(firstNonEmptyTrimmed(node.GetStringAttr("text", ""), node.GetStringAttr("fallback", ""), node.GetStringAttr("shortName", "")), path)
+
+	case "date":
+		date, err := w.convertDate(node)
+		if err != nil {
+			return err
+		}
+		w.writeText(date, path)
+
+	case "inlineCard":
+		title, url := w.getInlineCardLinkData(node)
+		w.writeText(firstNonEmptyTrimmed(title, url), path)
+
+	case "media":
+		return w.writeBlock(2, func() error {
+			w.writeText(node.GetStringAttr("alt", ""), path)
+			return nil
+		})
+
+	default:
+		switch w.config.UnknownNodes {
+		case UnknownError:
+			return fmt.Errorf("unknown node type: %s", node.Type)
+		case UnknownSkip:
+			w.addWarning(WarningUnknownNode, node.Type, fmt.Sprintf("unknown node skipped: %s", node.Type))
+		default:
+			w.addWarning(WarningUnknownNode, node.Type, fmt.Sprintf("unknown node rendered as placeholder: %s", node.Type))
+			w.writeText(fmt.Sprintf("[Unknown node: %s]", node.Type), "")
+		}
+	}
+	return nil
+}
+
+// writeText writes text and, when path is set, records the span it occupies.
+func (w *plainTextWriter) writeText(text, path string) {
+	start := -1
+	for _, r := range text {
+		if r == '\n' {
+			w.pending++
+			continue
+		}
+		if w.cell == len(w.buf) && unicode.IsSpace(r) {
+			continue
+		}
+		w.flush()
+		if start < 0 {
+			start = len(w.buf)
+		}
+		w.buf = utf8.AppendRune(w.buf, r)
+		if !unicode.IsSpace(r) {
+			w.letters++
+		}
+	}
+	if start >= 0 && path != "" {
+		w.spans = append(w.spans, TextSpan{Start: start, End: len(w.buf), Path: path})
+	}
+}
+
+// flush writes the pending line breaks and, at the start of a line, the list prefixes.
+func (w *plainTextWriter) flush() {
+	if w.pending > 0 {
+		switch {
+		case w.cell >= 0:
+			if len(w.buf) > w.cell {
+				w.buf = append(w.buf, ' ')
+			}
+		case len(w.buf) > 0:
+			w.buf = append(w.buf, strings.Repeat("\n", w.pending)...)
+		}
+		w.pending = 0
+		w.lineStart = true
+	}
+	if !w.lineStart {
+		return
+	}
+	w.lineStart = false
+	for i := range w.lists {
+		if w.lists[i].started {
+			w.buf = append(w.buf, strings.Repeat(" ", utf8.RuneCountInString(w.lists[i].marker))...)
+			continue
+		}
+		w.buf = append(w.buf, w.lists[i].marker...)
+		w.lists[i].started = true
+	}
+}
+
+// writeBlock writes a block with render and leaves breaks line breaks after it. A block
+// without any visible text is removed again.
+func (w *plainTextWriter) writeBlock(breaks int, render func() error) error {
+	mark := w.checkpoint()
+	if err := render(); err != nil {
+		return err
+	}
+	if w.letters == mark.letters {
+		w.restore(mark)
+		return nil
+	}
+	w.pending = breaks
+	return nil
+}
+
+func (w *plainTextWriter) checkpoint() plainTextCheckpoint {
+	return plainTextCheckpoint{
+		size:      len(w.buf),
+		spans:     len(w.spans),
+		pending:   w.pending,
+		letters:   w.letters,
+		lineStart: w.lineStart,
+		lists:     append([]plainListItem(nil), w.lists...),
+	}
+}
+
+func (w *plainTextWriter) restore(mark plainTextCheckpoint) {
+	w.buf = w.buf[:mark.size]
+	w.spans = w.spans[:mark.spans]
+	w.pending = mark.pending
+	w.letters = mark.letters
+	w.lineStart = mark.lineStart
+	w.lists = mark.lists
+}
+
+// writeList writes one item per line, prefixed with "• " or, for ordered lists, "N. ".
+func (w *plainTextWriter) writeList(node Node, path string) error {
+	order := node.GetIntAttr("order", 1)
+	letters := w.letters
+
+	for index, item := range node.Content {
+		itemPath := childPath(path, index)
+		marker := "• "
+		if node.Type == "orderedList" {
+			marker = strconv.Itoa(order+index) + ". "
+		}
+
+		w.lists = append(w.lists, plainListItem{marker: marker})
+		err := w.writeBlock(1, func() error {
+			if item.Type == "listItem" {
+				return w.writeNodes(item.Content, itemPath)
+			}
+			return w.writeNode(item, itemPath)
+		})
+		w.lists = w.lists[:len(w.lists)-1]
+		if err != nil {
+			return err
+		}
+	}
+
+	if w.letters > letters {
+		w.pending = 2
+	}
+	return nil
+}
+
+// writeTable writes one line per row with tab-separated cells.
+func (w *plainTextWriter) writeTable(node Node, path string) error {
+	for rowIndex, row := range node.Content {
+		rowPath := childPath(path, rowIndex)
+		for cellIndex, cell := range row.Content {
+			w.flush()
+			if cellIndex > 0 {
+				w.buf = append(w.buf, '\t')
+			}
+			if err := w.writeCell(cell, childPath(rowPath, cellIndex)); err != nil {
+				return err
+			}
+		}
+		w.pending = 1
+	}
+	if len(node.Content) > 0 {
+		w.pending = 2
+	}
+	return nil
+}
+
+// writeCell writes the content of a table cell on one line, without surrounding spaces.
+func (w *plainTextWriter) writeCell(cell Node, path string) error {
+	lists, start := w.lists, w.cell
+	w.lists, w.cell, w.lineStart = nil, len(w.buf), true
+
+	err := w.writeNodes(cell.Content, path)
+
+	w.truncate(w.cell + len(bytes.TrimRightFunc(w.buf[w.cell:], unicode.IsSpace)))
+	w.lists, w.cell, w.pending, w.lineStart = lists, start, 0, false
+	return err
+}
+
+// truncate cuts the output to size and clips the spans that reach past it.
+func (w *plainTextWriter) truncate(size int) {
+	w.buf = w.buf[:size]
+	for len(w.spans) > 0 {
+		last := &w.spans[len(w.spans)-1]
+		if last.End <= size {
+			return
+		}
+		if last.Start < size {
+			last.End = size
+			return
+		}
+		w.spans = w.spans[:len(w.spans)-1]
+	}
+}
@@ -0,0 +1,104 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertPlainTextDropsMarkdownSyntax(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Release notes"}]},
+		{"type":"paragraph","content":[
+			{"type":"text","text":"Bold","marks":[{"type":"strong"}]},
+			{"type":"text","text":" and "},
+			{"type":"text","text":"link","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]},
+			{"type":"text","text":" by "},
+			{"type":"mention","attrs":{"id":"u1","text":"@Ada"}},
+			{"type":"text","text":" is "},
+			{"type":"status","attrs":{"text":"DONE","color":"green"}}
+		]},
+		{"type":"bulletList","content":[
+			{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"one"}]}]},
+			{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"two"}]}]}
+		]},
+		{"type":"panel","attrs":{"panelType":"info"},"content":[{"type":"paragraph","content":[{"type":"text","text":"Heads up","marks":[{"type":"em"}]}]}]},
+		{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"x := 1"}]},
+		{"type":"table","content":[
+			{"type":"tableRow","content":[
+				{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"Key"}]}]},
+				{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"Value"}]}]}
+			]},
+			{"type":"tableRow","content":[
+				{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"a"}]}]},
+				{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"b"}]}]}
+			]}
+		]}
+	]}`)
+
+	result, err := newTestConverter(t, Config{}).ConvertPlainText(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Release notes\n\nBold and link by @Ada is DONE\n\n• one\n• two\n\nHeads up\n\nx := 1\n\nKey\tValue\na\tb\n", result.Text)
+}
+
+func TestConvertPlainTextMapsSpansToNodePaths(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[
+			{"type":"text","text":"Héllo ","marks":[{"type":"strong"}]},
+			{"type":"text","text":"world"}
+		]},
+		{"type":"expand","attrs":{"title":"More"},"content":[
+			{"type":"paragraph","content":[{"type":"mention","attrs":{"id":"u1","text":"@Ada"}}]}
+		]}
+	]}`)
+
+	result, err := newTestConverter(t, Config{}).ConvertPlainText(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Héllo world\n\nMore\n\n@Ada\n", result.Text)
+	assert.Equal(t, []TextSpan{
+		{Start: 0, End: 7, Path: "/content/0/content/0"},
+		{Start: 7, End: 12, Path: "/content/0/content/1"},
+		{Start: 14, End: 18, Path: "/content/1"},
+		{Start: 20, End: 24, Path: "/content/1/content/0/content/0"},
+	}, result.Spans)
+
+	for _, span := range result.Spans {
+		assert.NotEmpty(t, result.Text[span.Start:span.End])
+	}
+}
+
+func TestConvertPlainTextKeepsNestedListsAndNumbering(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[{"type":"orderedList","attrs":{"order":2},"content":[
+		{"type":"listItem","content":[
+			{"type":"paragraph","content":[{"type":"text","text":"first"}]},
+			{"type":"bulletList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"nested"}]}]}]}
+		]},
+		{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"second"}]}]}
+	]}]}`)
+
+	result, err := newTestConverter(t, Config{}).ConvertPlainText(input)
+	require.NoError(t, err)
+	assert.Equal(t, "2. first\n\n   • nested\n3. second\n", result.Text)
+}
+
+func TestConvertPlainTextKeepsPrivateUseRunes(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[{"type":"text","text":"a\ue000b\ue001c\ue002"}]},
+		{"type":"table","content":[{"type":"tableRow","content":[
+			{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":" x"}]},{"type":"paragraph","content":[{"type":"text","text":"y"}]}]}
+		]}]}
+	]}`)
+
+	result, err := newTestConverter(t, Config{}).ConvertPlainText(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "a\ue000b\ue001c\ue002\n\nx y\n", result.Text)
+	assert.Equal(t, []TextSpan{
+		{Start: 0, End: 12, Path: "/content/0/content/0"},
+		{Start: 14, End: 15, Path: "/content/1/content/0/content/0/content/0/content/0"},
+		{Start: 16, End: 17, Path: "/content/1/content/0/content/0/content/1/content/0"},
+	}, result.Spans)
+}
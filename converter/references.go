@@ -0,0 +1,253 @@
+package converter
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// References lists the external references of a document in document order.
+// Every entry carries the JSON Pointer path of its ADF node, e.g. "/content/0/content/2".
+type References struct {
+	Links       []LinkReference       `json:"links,omitempty"`
+	Media       []MediaReference      `json:"media,omitempty"`
+	Mentions    []MentionReference    `json:"mentions,omitempty"`
+	InlineCards []InlineCardReference `json:"inlineCards,omitempty"`
+	Emoji       []EmojiReference      `json:"emoji,omitempty"`
+	Extensions  []ExtensionReference  `json:"extensions,omitempty"`
+}
+
+// LinkReference describes a link mark on a text node.
+type LinkReference struct {
+	Path  string       `json:"path"`
+	Href  string       `json:"href"`
+	Title string       `json:"title,omitempty"`
+	Text  string       `json:"text,omitempty"`
+	Meta  LinkMetadata `json:"meta,omitzero"`
+}
+
+// MediaReference describes a media node.
+type MediaReference struct {
+	Path       string `json:"path"`
+	MediaType  string `json:"mediaType,omitempty"`
+	ID         string `json:"id,omitempty"`
+	Collection string `json:"collection,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Filename   string `json:"filename,omitempty"`
+	Alt        string `json:"alt,omitempty"`
+}
+
+// MentionReference describes a user mention.
+type MentionReference struct {
+	Path string `json:"path"`
+	ID   string `json:"id,omitempty"`
+	Text string `json:"text,omitempty"`
+}
+
+// InlineCardReference describes an inlineCard node.
+type InlineCardReference struct {
+	Path string       `json:"path"`
+	URL  string       `json:"url"`
+	Meta LinkMetadata `json:"meta,omitzero"`
+}
+
+// EmojiReference describes an emoji node.
+type EmojiReference struct {
+	Path      string `json:"path"`
+	ShortName string `json:"shortName,omitempty"`
+	ID        string `json:"id,omitempty"`
+	Text      string `json:"text,omitempty"`
+}
+
+// ExtensionReference describes an extension, inlineExtension or bodiedExtension node.
+type ExtensionReference struct {
+	Path          string `json:"path"`
+	NodeType      string `json:"nodeType"`
+	ExtensionKey  string `json:"extensionKey,omitempty"`
+	ExtensionType string `json:"extensionType,omitempty"`
+}
+
+// CollectReferences walks doc and returns every link, media item, mention, inlineCard,
+// emoji and extension it contains. It is meant for renderers with their own traversal;
+// the converter records the references of its results while rendering instead.
+func CollectReferences(doc Doc) References {
+	var refs References
+	refs.collect(doc.Content, "")
+	return refs
+}
+
+func (r *References) collect(content []Node, parentPath string) {
+	for index, node := range content {
+		nodePath := parentPath + "/content/" + strconv.Itoa(index)
+		r.add(node, nodePath)
+		r.collect(node.Content, nodePath)
+	}
+}
+
+func (r *References) add(node Node, nodePath string) {
+	switch node.Type {
+	case "text":
+		for _, mark := range node.Marks {
+			if mark.Type != "link" {
+				continue
+			}
+			href := mark.GetStringAttr("href", "")
+			if href == "" {
+				continue
+			}
+			r.Links = append(r.Links, LinkReference{
+				Path:  nodePath,
+				Href:  href,
+				Title: mark.GetStringAttr("title", ""),
+				Text:  node.Text,
+				Meta:  linkMetadataFromAttrs(mark.Attrs, href),
+			})
+		}
+	case "media", "mediaInline":
+		id := node.GetStringAttr("id", "")
+		mediaURL := node.GetStringAttr("url", "")
+		r.Media = append(r.Media, MediaReference{
+			Path:       nodePath,
+			MediaType:  node.GetStringAttr("type", ""),
+			ID:         id,
+			Collection: node.GetStringAttr("collection", ""),
+			URL:        mediaURL,
+			Filename:   mediaMetadataFromAttrs(node.Attrs, id, mediaURL).Filename,
+			Alt:        node.GetStringAttr("alt", ""),
+		})
+	case "mention":
+		r.Mentions = append(r.Mentions, MentionReference{
+			Path: nodePath,
+			ID:   node.GetStringAttr("id", ""),
+			Text: node.GetStringAttr("text", ""),
+		})
+	case "inlineCard":
+		cardURL := node.GetStringAttr("url", "")
+		r.InlineCards = append(r.InlineCards, InlineCardReference{
+			Path: nodePath,
+			URL:  cardURL,
+			Meta: linkMetadataFromAttrs(node.Attrs, cardURL),
+		})
+	case "emoji":
+		r.Emoji = append(r.Emoji, EmojiReference{
+			Path:      nodePath,
+			ShortName: node.GetStringAttr("shortName", ""),
+			ID:        node.GetStringAttr("id", ""),
+			Text:      node.GetStringAttr("text", ""),
+		})
+	case "extension", "inlineExtension", "bodiedExtension":
+		r.Extensions = append(r.Extensions, ExtensionReference{
+			Path:          nodePath,
+			NodeType:      node.Type,
+			ExtensionKey:  node.GetStringAttr("extensionKey", ""),
+			ExtensionType: node.GetStringAttr("extensionType", ""),
+		})
+	}
+}
+
+// referenceRecorder records references as the conversion reaches their nodes. Nodes are
+// passed by value and do not know their paths, so the path of each parent is kept under
+// the first node of its content, which every copy of the parent shares.
+type referenceRecorder struct {
+	refs References
+	// parents maps the first child of a parent that has not been rendered yet to the
+	// parent's path.
+	parents map[*Node]string
+}
+
+// addNode records node, found at nodePath, and prepares its children to be recorded when
+// it is rendered. Structural children, such as list items and table cells, are recorded
+// right away because their parents render them without going through convertNode.
+func (r *referenceRecorder) addNode(node *Node, nodePath string) {
+	r.refs.add(*node, nodePath)
+	if len(node.Content) == 0 {
+		return
+	}
+	if r.parents == nil {
+		r.parents = make(map[*Node]string)
+	}
+	if isStructuralNode(node.Type) {
+		r.addContent(node.Content, nodePath)
+		return
+	}
+	r.parents[&node.Content[0]] = nodePath
+}
+
+func (r *referenceRecorder) addContent(content []Node, parentPath string) {
+	for index := range content {
+		r.addNode(&content[index], childPath(parentPath, index))
+	}
+}
+
+// enter records content when its parent is rendered. Content is recorded once, however
+// often its parent is rendered; content the recorder does not know, such as the children
+// of nodes built during rendering, is left out.
+func (r *referenceRecorder) enter(content []Node) {
+	if len(content) == 0 {
+		return
+	}
+	parentPath, ok := r.parents[&content[0]]
+	if !ok {
+		return
+	}
+	delete(r.parents, &content[0])
+	r.addContent(content, parentPath)
+}
+
+// merge appends the references recorded by other, for example by a parallel block state.
+func (r *referenceRecorder) merge(other References) {
+	r.refs.Links = append(r.refs.Links, other.Links...)
+	r.refs.Media = append(r.refs.Media, other.Media...)
+	r.refs.Mentions = append(r.refs.Mentions, other.Mentions...)
+	r.refs.InlineCards = append(r.refs.InlineCards, other.InlineCards...)
+	r.refs.Emoji = append(r.refs.Emoji, other.Emoji...)
+	r.refs.Extensions = append(r.refs.Extensions, other.Extensions...)
+}
+
+// references returns the recorded references in document order. Renderers may reach
+// nodes out of order, for example when a table renders its header row first.
+func (r *referenceRecorder) references() References {
+	refs := r.refs
+	sortByPath(refs.Links, func(ref LinkReference) string { return ref.Path })
+	sortByPath(refs.Media, func(ref MediaReference) string { return ref.Path })
+	sortByPath(refs.Mentions, func(ref MentionReference) string { return ref.Path })
+	sortByPath(refs.InlineCards, func(ref InlineCardReference) string { return ref.Path })
+	sortByPath(refs.Emoji, func(ref EmojiReference) string { return ref.Path })
+	sortByPath(refs.Extensions, func(ref ExtensionReference) string { return ref.Path })
+	return refs
+}
+
+// isStructuralNode reports whether nodes of nodeType are rendered by their parent
+// directly rather than dispatched through convertNode.
+func isStructuralNode(nodeType string) bool {
+	switch nodeType {
+	case "listItem", "taskList", "taskItem", "decisionItem", "tableRow", "tableHeader", "tableCell", "layoutColumn":
+		return true
+	default:
+		return false
+	}
+}
+
+func sortByPath[T any](refs []T, path func(T) string) {
+	sort.SliceStable(refs, func(i, j int) bool {
+		return pathBefore(path(refs[i]), path(refs[j]))
+	})
+}
+
+// pathBefore reports whether JSON Pointer path a comes before b in document order.
+func pathBefore(a, b string) bool {
+	aParts := strings.Split(a, "/")
+	bParts := strings.Split(b, "/")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		if aParts[i] == bParts[i] {
+			continue
+		}
+		aIndex, aErr := strconv.Atoi(aParts[i])
+		bIndex, bErr := strconv.Atoi(bParts[i])
+		if aErr != nil || bErr != nil {
+			return aParts[i] < bParts[i]
+		}
+		return aIndex < bIndex
+	}
+	return len(aParts) < len(bParts)
+}
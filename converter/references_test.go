@@ -0,0 +1,121 @@
+package converter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertReturnsReferences(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[
+			{"type":"text","text":"Spec","marks":[{"type":"strong"},{"type":"link","attrs":{"href":"https://confluence.example/wiki/spaces/ENG/pages/123/Spec#Intro","pageId":"123","spaceKey":"ENG"}}]},
+			{"type":"text","text":" by "},
+			{"type":"mention","attrs":{"id":"user-1","text":"@Ada"}},
+			{"type":"emoji","attrs":{"shortName":":smile:","id":"1f604","text":"😄"}},
+			{"type":"inlineCard","attrs":{"url":"https://jira.example/browse/ENG-1"}}
+		]},
+		{"type":"mediaSingle","content":[{"type":"media","attrs":{"type":"file","id":"att-1","collection":"contentId-123","alt":"diagram.png"}}]},
+		{"type":"bodiedExtension","attrs":{"extensionKey":"details","extensionType":"com.atlassian.confluence.macro.core"},"content":[
+			{"type":"paragraph","content":[{"type":"text","text":"nested","marks":[{"type":"link","attrs":{"href":"https://example.com/files/report.pdf"}}]}]}
+		]}
+	]}`)
+
+	conv := newTestConverter(t, Config{})
+	result, err := conv.Convert(input)
+	require.NoError(t, err)
+
+	refs := result.References
+	assert.Equal(t, []LinkReference{
+		{
+			Path: "/content/0/content/0",
+			Href: "https://confluence.example/wiki/spaces/ENG/pages/123/Spec#Intro",
+			Text: "Spec",
+			Meta: LinkMetadata{PageID: "123", SpaceKey: "ENG", Filename: "Spec", Anchor: "Intro"},
+		},
+		{
+			Path: "/content/2/content/0/content/0",
+			Href: "https://example.com/files/report.pdf",
+			Text: "nested",
+			Meta: LinkMetadata{Filename: "report.pdf"},
+		},
+	}, refs.Links)
+	assert.Equal(t, []MentionReference{{Path: "/content/0/content/2", ID: "user-1", Text: "@Ada"}}, refs.Mentions)
+	assert.Equal(t, []EmojiReference{{Path: "/content/0/content/3", ShortName: ":smile:", ID: "1f604", Text: "😄"}}, refs.Emoji)
+	assert.Equal(t, []InlineCardReference{{
+		Path: "/content/0/content/4",
+		URL:  "https://jira.example/browse/ENG-1",
+		Meta: LinkMetadata{Filename: "ENG-1"},
+	}}, refs.InlineCards)
+	assert.Equal(t, []MediaReference{{
+		Path:       "/content/1/content/0",
+		MediaType:  "file",
+		ID:         "att-1",
+		Collection: "contentId-123",
+		Alt:        "diagram.png",
+	}}, refs.Media)
+	assert.Equal(t, []ExtensionReference{{
+		Path:          "/content/2",
+		NodeType:      "bodiedExtension",
+		ExtensionKey:  "details",
+		ExtensionType: "com.atlassian.confluence.macro.core",
+	}}, refs.Extensions)
+}
+
+func TestReferencesMediaFilenameFromAttrsOrURL(t *testing.T) {
+	refs := CollectReferences(Doc{Type: "doc", Content: []Node{
+		{Type: "media", Attrs: map[string]interface{}{"type": "file", "id": "att-1", "fileName": "spec.pdf"}},
+		{Type: "media", Attrs: map[string]interface{}{"type": "external", "url": "https://cdn.example/img/logo.png"}},
+	}})
+
+	require.Len(t, refs.Media, 2)
+	assert.Equal(t, "spec.pdf", refs.Media[0].Filename)
+	assert.Equal(t, "logo.png", refs.Media[1].Filename)
+}
+
+func TestResultOmitsEmptyReferences(t *testing.T) {
+	conv := newTestConverter(t, Config{})
+	result, err := conv.Convert([]byte(`{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"plain"}]}]}`))
+	require.NoError(t, err)
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "references")
+}
+
+func TestRecordedReferencesMatchDocument(t *testing.T) {
+	files, err := filepath.Glob("../testdata/*/*.json")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	sequential := newTestConverter(t, Config{})
+	parallel := newTestConverter(t, Config{Parallelism: 4})
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			input, err := os.ReadFile(file)
+			require.NoError(t, err)
+			var doc Doc
+			require.NoError(t, json.Unmarshal(input, &doc))
+			expected := CollectReferences(doc)
+
+			result, err := sequential.Convert(input)
+			require.NoError(t, err)
+			assert.Equal(t, expected, result.References, "markdown")
+
+			result, err = parallel.Convert(input)
+			require.NoError(t, err)
+			assert.Equal(t, expected, result.References, "parallel markdown")
+
+			if wiki, err := sequential.ConvertWiki(input); err == nil {
+				assert.Equal(t, expected, wiki.References, "wiki")
+			}
+			if asciidoc, err := sequential.ConvertAsciiDoc(input); err == nil {
+				assert.Equal(t, expected, asciidoc.References, "asciidoc")
+			}
+		})
+	}
+}
@@ -4,6 +4,10 @@ package converter
 type Result struct {
 	Markdown string    `json:"markdown"`
 	Warnings []Warning `json:"warnings,omitempty"`
+	// CacheStats reports HookCache hits and misses; it is zero when no cache is configured.
+	CacheStats HookCacheStats `json:"cacheStats,omitzero"`
+	// References lists links, media, mentions, inlineCards, emoji and extensions of the input.
+	References References `json:"references,omitzero"`
 }
 
 // WarningType categorizes conversion warnings.
@@ -16,6 +20,7 @@ const (
 	WarningExtensionFallback   WarningType = "extension_fallback"
 	WarningMissingAttribute    WarningType = "missing_attribute"
 	WarningUnresolvedReference WarningType = "unresolved_reference"
+	WarningTruncated           WarningType = "truncated"
 )
 
 // Warning represents a non-fatal issue encountered during conversion.
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"html"
 	"strings"
+	"unicode/utf8"
 )
 
 // convertTable converts a table node to markdown/HTML depending on config.
@@ -24,7 +25,16 @@ func (s *state) convertTable(node Node) (string, error) {
 		}
 	}
 
+	if mode != TableList {
+		// Table cells are laid out on one line each, so their text is not wrapped.
+		noWrap := s.noWrap
+		s.noWrap = true
+		defer func() { s.noWrap = noWrap }()
+	}
+
 	switch mode {
+	case TableList:
+		return s.renderTableList(node)
 	case TableHTML:
 		return s.renderTableHTML(node)
 	case TablePandoc:
@@ -52,6 +62,101 @@ func (s *state) convertTable(node Node) (string, error) {
 	}
 }
 
+// renderTableList renders a table as a bullet list with one item per row, for output
+// without pipe tables. Each item starts with the row's first cell and nests the other
+// cells below it, labelled with their column header when the table has a header row.
+func (s *state) renderTableList(node Node) (string, error) {
+	var rows []Node
+	for _, rowNode := range node.Content {
+		if rowNode.Type == "tableRow" {
+			rows = append(rows, rowNode)
+		}
+	}
+	if len(rows) == 0 {
+		return "", nil
+	}
+	if s.hasTableSpans(node) {
+		s.addWarning(WarningDroppedFeature, node.Type, "list table does not support colspan/rowspan; cells listed in order")
+	}
+
+	var headers []string
+	if len(rows) > 1 && s.rowHasHeaders(rows[0]) {
+		for _, cellNode := range rows[0].Content {
+			label, err := s.convertChildren(cellNode.Content)
+			if err != nil {
+				return "", err
+			}
+			headers = append(headers, strings.Join(strings.Fields(label), " "))
+		}
+		rows = rows[1:]
+	}
+
+	marker := fmt.Sprintf("%c ", s.config.BulletMarker)
+	var items []string
+	loose := false
+	for _, rowNode := range rows {
+		var cells []string
+		for i, cellNode := range rowNode.Content {
+			label := ""
+			if i < len(headers) {
+				label = headers[i]
+			}
+			indent, lead := len(marker), 0
+			if i > 0 {
+				indent *= 2
+			}
+			if label != "" {
+				lead = utf8.RuneCountInString(label) + 2
+			}
+			content, err := s.withWrapIndent(indent, lead, func() (string, error) {
+				return s.convertListItemContent(cellNode.Content)
+			})
+			if err != nil {
+				return "", err
+			}
+			if cell := tableListCell(label, cellNode, content); cell != "" {
+				cells = append(cells, cell)
+			}
+		}
+		if len(cells) == 0 {
+			continue
+		}
+
+		item := cells[0]
+		for _, cell := range cells[1:] {
+			item += "\n" + s.indent(cell, marker)
+		}
+		item = strings.TrimRight(s.indent(item, marker), "\n")
+		loose = loose || strings.Contains(item, "\n\n")
+		items = append(items, item)
+	}
+
+	if len(items) == 0 {
+		return "", nil
+	}
+	// Blank lines inside any item make the list loose, so keep its rows evenly spaced.
+	separator := "\n"
+	if loose {
+		separator = "\n\n"
+	}
+	return strings.Join(items, separator) + "\n\n", nil
+}
+
+// tableListCell prefixes cell content with its column header. Content that does not
+// start with a paragraph goes below the label.
+func tableListCell(label string, cellNode Node, content string) string {
+	if label == "" {
+		return content
+	}
+	if content == "" {
+		return label + ":"
+	}
+	if len(cellNode.Content) > 0 && cellNode.Content[0].Type == "paragraph" {
+		return label + ": " + content
+	}
+	return label + ":\n\n" + content
+}
+
 func (s *state) isComplexTable(node Node) bool {
 	for _, rowNode := range node.Content {
 		if rowNode.Type != "tableRow" {
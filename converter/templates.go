@@ -0,0 +1,170 @@
+package converter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// inlineNodeTypes lists the ADF nodes that sit inside a paragraph. Templates for them are
+// used as is; templates for every other node are rendered as a block.
+var inlineNodeTypes = map[string]bool{
+	"text":            true,
+	"hardBreak":       true,
+	"mention":         true,
+	"emoji":           true,
+	"date":            true,
+	"status":          true,
+	"inlineCard":      true,
+	"inlineExtension": true,
+	"placeholder":     true,
+	"mediaInline":     true,
+}
+
+// templateNodeTypes lists the nodes the converter renders, which are the ones templates
+// can replace. doc and text nodes are excluded.
+var templateNodeTypes = map[string]bool{
+	"paragraph": true, "heading": true, "blockquote": true, "rule": true, "hardBreak": true,
+	"codeBlock": true, "bulletList": true, "orderedList": true, "listItem": true,
+	"taskList": true, "taskItem": true, "decisionList": true, "decisionItem": true,
+	"table": true, "tableRow": true, "tableHeader": true, "tableCell": true,
+	"panel": true, "expand": true, "nestedExpand": true, "layoutSection": true, "layoutColumn": true,
+	"mediaSingle": true, "mediaGroup": true, "media": true,
+	"emoji": true, "mention": true, "status": true, "date": true, "inlineCard": true, "placeholder": true,
+	"extension": true, "inlineExtension": true, "bodiedExtension": true,
+}
+
+// templateFuncs are the functions available to Config.Templates besides the text/template
+// builtins.
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	"quote": func(content string) string {
+		return prefixTemplateLines(content, "> ")
+	},
+	"indent": func(width int, content string) string {
+		return prefixTemplateLines(content, strings.Repeat(" ", width))
+	},
+}
+
+// TemplateData is the value Config.Templates are executed with.
+type TemplateData struct {
+	// Type is the ADF node type.
+	Type string
+	// Attrs holds the node attributes as they appear in the ADF.
+	Attrs map[string]any
+	// Text is the node text, or its text attribute for nodes such as status and mention.
+	Text string
+	// Content is the Markdown of the node's children, without trailing newlines.
+	Content string
+}
+
+// Attr returns the named attribute formatted as a string, or "" when it is not set.
+func (d TemplateData) Attr(name string) string {
+	value, ok := d.Attrs[name]
+	if !ok || value == nil {
+		return ""
+	}
+	if text, ok := value.(string); ok {
+		return text
+	}
+	return fmt.Sprint(value)
+}
+
+// nodeTemplates maps node types to their parsed templates.
+type nodeTemplates map[string]*template.Template
+
+// parseTemplates parses Config.Templates, reporting the first invalid one in key order.
+func (c Config) parseTemplates() (nodeTemplates, error) {
+	if len(c.Templates) == 0 {
+		return nil, nil
+	}
+
+	nodeTypes := make([]string, 0, len(c.Templates))
+	for nodeType := range c.Templates {
+		nodeTypes = append(nodeTypes, nodeType)
+	}
+	sort.Strings(nodeTypes)
+
+	parsed := make(nodeTemplates, len(nodeTypes))
+	for _, nodeType := range nodeTypes {
+		switch strings.TrimSpace(nodeType) {
+		case "":
+			return nil, fmt.Errorf("templates contains empty key")
+		case "doc", "text":
+			return nil, fmt.Errorf("templates cannot override %q nodes", nodeType)
+		}
+		if !templateNodeTypes[nodeType] {
+			return nil, fmt.Errorf("templates contains unknown node type %q", nodeType)
+		}
+		tmpl, err := template.New(nodeType).Funcs(templateFuncs).Parse(c.Templates[nodeType])
+		if err != nil {
+			return nil, fmt.Errorf("invalid template for %q: %w", nodeType, err)
+		}
+		parsed[nodeType] = tmpl
+	}
+	return parsed, nil
+}
+
+// convertTemplateNode renders node with the template configured for its type.
+func (s *state) convertTemplateNode(tmpl *template.Template, node Node) (string, error) {
+	var content string
+	var err error
+	if hasInlineChildren(node) {
+		content, err = s.convertInlineContent(node.Content)
+	} else {
+		content, err = s.convertChildren(node.Content)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	data := TemplateData{
+		Type:    node.Type,
+		Attrs:   node.Attrs,
+		Text:    node.Text,
+		Content: strings.TrimRight(content, "\n"),
+	}
+	if data.Text == "" {
+		data.Text = node.GetStringAttr("text", "")
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("template for %q: %w", node.Type, err)
+	}
+
+	if inlineNodeTypes[node.Type] {
+		return sb.String(), nil
+	}
+	result := strings.TrimRight(sb.String(), "\n")
+	if result == "" {
+		return "", nil
+	}
+	return result + "\n\n", nil
+}
+
+// hasInlineChildren reports whether node holds inline content, like a paragraph.
+func hasInlineChildren(node Node) bool {
+	for _, child := range node.Content {
+		if inlineNodeTypes[child.Type] {
+			return true
+		}
+	}
+	return false
+}
+
+// prefixTemplateLines adds prefix to every line of content, leaving blank lines without
+// trailing spaces for indentation.
+func prefixTemplateLines(content, prefix string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if line == "" && strings.TrimSpace(prefix) == "" {
+			continue
+		}
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
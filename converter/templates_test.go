@@ -0,0 +1,98 @@
+package converter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertTemplates(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"panel","attrs":{"panelType":"warning"},"content":[
+			{"type":"paragraph","content":[{"type":"text","text":"Mind the "},{"type":"text","text":"gap","marks":[{"type":"strong"}]}]},
+			{"type":"paragraph","content":[{"type":"text","text":"Second"}]}
+		]},
+		{"type":"paragraph","content":[
+			{"type":"text","text":"State: "},
+			{"type":"status","attrs":{"text":"In Progress","color":"blue"}},
+			{"type":"text","text":" by "},
+			{"type":"mention","attrs":{"id":"abc","text":"@Ann"}}
+		]},
+		{"type":"expand","attrs":{"title":"Details"},"content":[{"type":"paragraph","content":[{"type":"text","text":"hidden"}]}]}
+	]}`)
+
+	conv := newTestConverter(t, Config{Templates: map[string]string{
+		"panel":   `> **{{.Attr "panelType" | upper}}**` + "\n>\n" + `{{quote .Content}}`,
+		"status":  "`{{.Text}}`",
+		"mention": `{{.Text}} ({{.Attr "id"}})`,
+		"expand":  `#### {{.Attr "title"}}` + "\n\n{{.Content}}\n\n\n",
+	}})
+
+	result, err := conv.Convert(input)
+	require.NoError(t, err)
+	assert.Equal(t, "> **WARNING**\n>\n> Mind the **gap**\n> \n> Second\n\n"+
+		"State: `In Progress` by @Ann (abc)\n\n"+
+		"#### Details\n\nhidden\n", result.Markdown)
+}
+
+func TestConvertTemplatesOnlyApplyToMarkdown(t *testing.T) {
+	conv := newTestConverter(t, Config{Templates: map[string]string{"status": "`{{.Text}}`"}})
+	input := []byte(`{"type":"doc","content":[{"type":"paragraph","content":[{"type":"status","attrs":{"text":"DONE"}}]}]}`)
+
+	result, err := conv.ConvertPlainText(input)
+	require.NoError(t, err)
+	assert.NotContains(t, result.Text, "`")
+}
+
+func TestConvertTemplatesExecutionError(t *testing.T) {
+	conv := newTestConverter(t, Config{Templates: map[string]string{"rule": "{{.Missing}}"}})
+
+	_, err := conv.Convert([]byte(`{"type":"doc","content":[{"type":"rule"}]}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `template for "rule"`)
+}
+
+func TestTemplatesValidation(t *testing.T) {
+	_, err := New(Config{Templates: map[string]string{"status": "{{.Text"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid template for "status"`)
+
+	_, err = New(Config{Templates: map[string]string{"panel": "{{shout .Content}}"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `function "shout" not defined`)
+
+	_, err = New(Config{Templates: map[string]string{" ": "x"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "templates contains empty key")
+
+	_, err = New(Config{Templates: map[string]string{"text": "x"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `cannot override "text"`)
+
+	_, err = New(Config{Templates: map[string]string{"pannel": "x"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown node type "pannel"`)
+}
+
+func TestTemplateNodeTypesAreRendered(t *testing.T) {
+	conv := newTestConverter(t, Config{})
+	for nodeType := range templateNodeTypes {
+		input, err := json.Marshal(Doc{Type: "doc", Content: []Node{{Type: nodeType}}})
+		require.NoError(t, err)
+		result, err := conv.Convert(input)
+		require.NoError(t, err, nodeType)
+		for _, warning := range result.Warnings {
+			assert.NotEqual(t, WarningUnknownNode, warning.Type, nodeType)
+		}
+	}
+}
+
+func TestTemplateDataAttr(t *testing.T) {
+	data := TemplateData{Attrs: map[string]any{"title": "T", "level": float64(2), "none": nil}}
+	assert.Equal(t, "T", data.Attr("title"))
+	assert.Equal(t, "2", data.Attr("level"))
+	assert.Equal(t, "", data.Attr("none"))
+	assert.Equal(t, "", data.Attr("missing"))
+}
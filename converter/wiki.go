@@ -0,0 +1,934 @@
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wikiSpanRune prefixes the formatting delimiters of marks in rendered inline content
+// until resolveWikiSpans knows the characters on both sides of each span.
+const (
+	wikiSpanRune   = '\uE000'
+	wikiSpanMarker = string(wikiSpanRune)
+)
+
+var (
+	wikiMarkdownImageRe = regexp.MustCompile(`^!\[([^\]]*)\]\(([^)\s]+)(?:\s+"[^"]*")?\)$`)
+	wikiMarkdownLinkRe  = regexp.MustCompile(`^\[([^\]]*)\]\(([^)\s]+)(?:\s+"[^"]*")?\)$`)
+)
+
+// wikiPanelColors maps panel types to Jira panel background colors.
+var wikiPanelColors = map[string]string{
+	"info":    "#deebff",
+	"note":    "#eae6ff",
+	"success": "#e3fcef",
+	"warning": "#fffae6",
+	"error":   "#ffebe6",
+}
+
+// wikiStatusColors maps status colors to the text color of the rendered status.
+var wikiStatusColors = map[string]string{
+	"purple": "#403294",
+	"blue":   "#0747a6",
+	"red":    "#bf2600",
+	"yellow": "#ff8b00",
+	"green":  "#006644",
+}
+
+// wikiEmoticons maps emoji short names to Jira wiki emoticons.
+var wikiEmoticons = map[string]string{
+	":slight_smile:":       ":)",
+	":smiley:":             ":D",
+	":disappointed:":       ":(",
+	":stuck_out_tongue:":   ":P",
+	":wink:":               ";)",
+	":thumbsup:":           "(y)",
+	":thumbsdown:":         "(n)",
+	":information_source:": "(i)",
+	":white_check_mark:":   "(/)",
+	":x:":                  "(x)",
+	":warning:":            "(!)",
+	":heavy_plus_sign:":    "(+)",
+	":heavy_minus_sign:":   "(-)",
+	":question:":           "(?)",
+	":bulb:":               "(on)",
+	":star:":               "(*)",
+}
+
+// WikiResult holds the output of a Jira wiki markup conversion.
+type WikiResult struct {
+	Markup   string    `json:"markup"`
+	Warnings []Warning `json:"warnings,omitempty"`
+	// CacheStats reports HookCache hits and misses; it is zero when no cache is configured.
+	CacheStats HookCacheStats `json:"cacheStats,omitzero"`
+	// References lists links, media, mentions, inlineCards, emoji and extensions of the input.
+	References References `json:"references,omitzero"`
+}
+
+// wikiFormat renders Jira wiki markup.
+type wikiFormat struct {
+	*state
+	// listPrefix is the marker prefix of the enclosing lists, e.g. "*#".
+	listPrefix string
+}
+
+// ConvertWiki takes an ADF JSON document and returns Jira wiki markup.
+func (c *Converter) ConvertWiki(input []byte) (WikiResult, error) {
+	return c.ConvertWikiWithContext(context.Background(), input, ConvertOptions{})
+}
+
+// ConvertWikiWithContext takes an ADF JSON document and returns Jira wiki markup as
+// accepted by Jira Server, Data Center and the v2 REST API. It uses the same node
+// dispatch, link and media hooks, batch resolution and hook cache as Markdown output.
+// Extension handlers are not invoked because they produce Markdown. Content that wiki
+// markup cannot express is approximated and reported as a warning.
+func (c *Converter) ConvertWikiWithContext(ctx context.Context, input []byte, opts ConvertOptions) (WikiResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := ctx.Err(); err != nil {
+		return WikiResult{}, err
+	}
+
+	var doc Doc
+	if err := json.Unmarshal(input, &doc); err != nil {
+		return WikiResult{}, fmt.Errorf("failed to parse ADF JSON: %w", err)
+	}
+
+	s := &state{
+		config:     c.config,
+		ctx:        ctx,
+		options:    opts,
+		cacheStats: &hookCacheCounter{},
+	}
+	s.format = &wikiFormat{state: s}
+	if err := s.resolveBatch(doc.Content); err != nil {
+		return WikiResult{}, err
+	}
+
+	s.references.addContent(doc.Content, "")
+	markup, err := s.convertNode(Node{Type: "doc", Content: doc.Content})
+	if err != nil {
+		return WikiResult{}, err
+	}
+	if err := s.checkContext(); err != nil {
+		return WikiResult{}, err
+	}
+
+	return WikiResult{
+		Markup:     markup,
+		Warnings:   s.warnings,
+		CacheStats: s.cacheStats.stats(),
+		References: s.references.references(),
+	}, nil
+}
+
+// renderNode renders node types whose wiki markup form differs from Markdown.
+// It reports false for types that share the Markdown rendering path.
+func (w *wikiFormat) renderNode(node Node) (string, bool, error) {
+	switch node.Type {
+	case "text":
+		return escapeWikiText(node.Text), true, nil
+
+	case "paragraph":
+		content, err := w.convertInlineContent(node.Content)
+		if err != nil || content == "" {
+			return "", true, err
+		}
+		return content + "\n\n", true, nil
+
+	case "heading":
+		level := min(max(headingLevel(node)+w.config.HeadingOffset, 1), 6)
+		content, err := w.convertInlineContent(node.Content)
+		if err != nil || content == "" {
+			return "", true, err
+		}
+		return fmt.Sprintf("h%d. %s\n\n", level, content), true, nil
+
+	case "blockquote":
+		content, err := w.convertChildren(node.Content)
+		if err != nil || strings.TrimSpace(content) == "" {
+			return "", true, err
+		}
+		return "{quote}\n" + strings.TrimRight(content, "\n") + "\n{quote}\n\n", true, nil
+
+	case "rule":
+		return "----\n\n", true, nil
+
+	case "hardBreak":
+		return `\\`, true, nil
+
+	case "codeBlock":
+		return w.convertWikiCodeBlock(node), true, nil
+
+	case "bulletList", "orderedList", "taskList", "decisionList":
+		content, err := w.convertWikiList(node)
+		return content, true, err
+
+	case "listItem", "taskItem", "decisionItem":
+		line, nested, err := w.convertWikiListItem(node)
+		return line + "\n" + nested, true, err
+
+	case "table":
+		content, err := w.convertWikiTable(node)
+		return content, true, err
+
+	case "panel":
+		content, err := w.convertWikiPanel(node)
+		return content, true, err
+
+	case "expand", "nestedExpand":
+		content, err := w.convertChildren(node.Content)
+		if err != nil || strings.TrimSpace(content) == "" {
+			return "", true, err
+		}
+		w.addWarning(WarningDroppedFeature, node.Type, "expand rendered as panel; wiki markup cannot collapse content")
+		params := ""
+		if title := wikiMacroParam(node.GetStringAttr("title", "")); title != "" {
+			params = ":title=" + title
+		}
+		return "{panel" + params + "}\n" + strings.TrimRight(content, "\n") + "\n{panel}\n\n", true, nil
+
+	case "layoutSection":
+		w.addWarning(WarningDroppedFeature, node.Type, "layout columns rendered sequentially; wiki markup has no column layout")
+		content, err := w.convertChildren(node.Content)
+		return content, true, err
+
+	case "layoutColumn", "mediaSingle":
+		content, err := w.convertChildren(node.Content)
+		if err != nil || strings.TrimSpace(content) == "" {
+			return "", true, err
+		}
+		if node.Type == "mediaSingle" {
+			content = strings.TrimRight(content, "\n") + "\n\n"
+		}
+		return content, true, nil
+
+	case "mediaGroup":
+		items := make([]string, 0, len(node.Content))
+		for _, child := range node.Content {
+			item, err := w.convertNode(child)
+			if err != nil {
+				return "", true, err
+			}
+			if item != "" {
+				items = append(items, item)
+			}
+		}
+		if len(items) == 0 {
+			return "", true, nil
+		}
+		return strings.Join(items, " ") + "\n\n", true, nil
+
+	case "media":
+		content, err := w.convertWikiMedia(node)
+		return content, true, err
+
+	case "mention":
+		return w.convertWikiMention(node), true, nil
+
+	case "status":
+		return w.convertWikiStatus(node), true, nil
+
+	case "emoji":
+		if emoticon, ok := wikiEmoticons[node.GetStringAttr("shortName", "")]; ok {
+			return emoticon, true, nil
+		}
+		content, err := w.convertEmoji(node)
+		return escapeWikiText(content), true, err
+
+	case "date":
+		content, err := w.convertDate(node)
+		return escapeWikiText(content), true, err
+
+	case "inlineCard":
+		content, err := w.convertWikiInlineCard(node)
+		return content, true, err
+
+	case "extension", "inlineExtension", "bodiedExtension":
+		content, err := w.convertWikiExtension(node)
+		return content, true, err
+	}
+
+	return "", false, nil
+}
+
+func (w *wikiFormat) convertWikiCodeBlock(node Node) string {
+	var sb strings.Builder
+	for _, child := range node.Content {
+		sb.WriteString(child.Text)
+	}
+
+	language := node.GetStringAttr("language", "")
+	if mapped, ok := w.config.LanguageMap[language]; ok {
+		language = mapped
+	}
+	language = wikiMacroParam(language)
+
+	code := strings.TrimRight(sb.String(), "\n")
+	lower := strings.ToLower(code)
+	if strings.Contains(lower, "{code") {
+		// A {code} tag in the content would close the macro early.
+		if !strings.Contains(lower, "{noformat") {
+			if language != "" {
+				w.addWarning(WarningDroppedFeature, node.Type, fmt.Sprintf("code block language %q dropped; content containing {code} is rendered as {noformat}", language))
+			}
+			return "{noformat}\n" + code + "\n{noformat}\n\n"
+		}
+		w.addWarning(WarningDroppedFeature, node.Type, "code block contains both {code} and {noformat}; the macro may close early")
+	}
+
+	opening := "{code}"
+	if language != "" {
+		opening = "{code:" + language + "}"
+	}
+	return opening + "\n" + code + "\n{code}\n\n"
+}
+
+// convertWikiList renders bullet, ordered, task and decision lists with nested
+// marker prefixes such as "*#". Task and decision lists become bullet lists.
+func (w *wikiFormat) convertWikiList(node Node) (string, error) {
+	marker := "*"
+	switch node.Type {
+	case "orderedList":
+		marker = "#"
+		if order := node.GetIntAttr("order", 1); order != 1 {
+			w.addWarning(WarningDroppedFeature, node.Type, fmt.Sprintf("ordered list start %d dropped; wiki markup lists always start at 1", order))
+		}
+	case "taskList":
+		w.addWarning(WarningDroppedFeature, node.Type, "task list rendered as bullet list; wiki markup has no checkboxes")
+	case "decisionList":
+		w.addWarning(WarningDroppedFeature, node.Type, "decision list rendered as bullet list")
+	}
+
+	parentPrefix := w.listPrefix
+	content, err := w.convertWikiListItems(node, parentPrefix+marker)
+	if err != nil {
+		return "", err
+	}
+	if parentPrefix == "" {
+		content += "\n"
+	}
+	return content, nil
+}
+
+// convertWikiListItems renders the items of a list with the given marker prefix.
+// Task lists nested directly in a task list share its warning and go one level deeper.
+func (w *wikiFormat) convertWikiListItems(node Node, prefix string) (string, error) {
+	parentPrefix := w.listPrefix
+	w.listPrefix = prefix
+	defer func() { w.listPrefix = parentPrefix }()
+
+	var sb strings.Builder
+	for _, item := range node.Content {
+		if err := w.checkContext(); err != nil {
+			return "", err
+		}
+		if item.Type == "taskList" {
+			nested, err := w.convertWikiListItems(item, prefix+"*")
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(nested)
+			continue
+		}
+
+		line, nested, err := w.convertWikiListItem(item)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(prefix + " " + line + "\n")
+		sb.WriteString(nested)
+	}
+	return sb.String(), nil
+}
+
+// convertWikiListItem returns the text of a list item's marker line and its nested
+// content. Paragraphs share the marker line separated by line breaks.
+func (w *wikiFormat) convertWikiListItem(item Node) (string, string, error) {
+	switch item.Type {
+	case "taskItem", "decisionItem":
+		content, err := w.convertInlineContent(item.Content)
+		if err != nil {
+			return "", "", err
+		}
+		icon := "( )"
+		if item.GetStringAttr("state", "") == "DONE" || (item.Type == "decisionItem" && item.GetStringAttr("state", "DECIDED") == "DECIDED") {
+			icon = "(/)"
+		} else if item.Type == "decisionItem" {
+			icon = "(?)"
+		}
+		return strings.TrimSpace(icon + " " + content), "", nil
+	}
+
+	var lines []string
+	var nested strings.Builder
+	for _, child := range item.Content {
+		switch child.Type {
+		case "paragraph":
+			content, err := w.convertInlineContent(child.Content)
+			if err != nil {
+				return "", "", err
+			}
+			if content != "" {
+				lines = append(lines, content)
+			}
+		case "bulletList", "orderedList", "taskList", "decisionList":
+			content, err := w.convertWikiList(child)
+			if err != nil {
+				return "", "", err
+			}
+			nested.WriteString(content)
+		default:
+			content, err := w.convertNode(child)
+			if err != nil {
+				return "", "", err
+			}
+			if strings.TrimSpace(content) == "" {
+				continue
+			}
+			w.addWarning(WarningDroppedFeature, child.Type, fmt.Sprintf("%s inside list item ends the list in wiki markup", child.Type))
+			nested.WriteString(strings.TrimRight(content, "\n") + "\n")
+		}
+	}
+
+	return strings.Join(lines, `\\`), nested.String(), nil
+}
+
+// convertWikiTable renders a table with || header cells and | data cells. Blank lines
+// are removed from cell content because they end a wiki table.
+func (w *wikiFormat) convertWikiTable(node Node) (string, error) {
+	var sb strings.Builder
+	for _, row := range node.Content {
+		if row.Type != "tableRow" {
+			continue
+		}
+		delimiter := "|"
+		for _, cell := range row.Content {
+			delimiter = "|"
+			if cell.Type == "tableHeader" {
+				delimiter = "||"
+			}
+			if cell.GetIntAttr("colspan", 1) > 1 || cell.GetIntAttr("rowspan", 1) > 1 {
+				w.addWarning(WarningDroppedFeature, cell.Type, "table cell colspan/rowspan dropped; wiki markup tables cannot merge cells")
+			}
+			if cell.GetStringAttr("background", "") != "" {
+				w.addWarning(WarningDroppedFeature, cell.Type, "table cell background dropped")
+			}
+
+			content, err := w.convertChildren(cell.Content)
+			if err != nil {
+				return "", err
+			}
+			var lines []string
+			for _, line := range strings.Split(content, "\n") {
+				if strings.TrimSpace(line) != "" {
+					lines = append(lines, line)
+				}
+			}
+			cellText := strings.Join(lines, "\n")
+			if cellText == "" {
+				cellText = " "
+			}
+			sb.WriteString(delimiter + cellText)
+		}
+		sb.WriteString(delimiter + "\n")
+	}
+	if sb.Len() == 0 {
+		return "", nil
+	}
+	return sb.String() + "\n", nil
+}
+
+// convertWikiPanel renders a panel as {panel} with the panel type's background color
+// and, unless PanelStyle is none, its label as title.
+func (w *wikiFormat) convertWikiPanel(node Node) (string, error) {
+	content, err := w.convertChildren(node.Content)
+	if err != nil || strings.TrimSpace(content) == "" {
+		return "", err
+	}
+
+	params := ""
+	if w.config.PanelStyle != PanelNone {
+		panelType := strings.ToLower(node.GetStringAttr("panelType", ""))
+		var parts []string
+		if panelType != "" {
+			_, label := panelTypeLabels(panelType)
+			parts = append(parts, "title="+wikiMacroParam(label))
+		}
+		color := wikiPanelColors[panelType]
+		if raw := node.GetStringAttr("panelColor", ""); raw != "" {
+			if sanitized, ok := SanitizeCSSColor(raw); ok && (cssHexColorRe.MatchString(sanitized) || cssNamedColorRe.MatchString(sanitized)) {
+				color = sanitized
+			} else {
+				w.addWarning(WarningDroppedFeature, node.Type, fmt.Sprintf("panel color %q dropped", raw))
+			}
+		}
+		if color != "" {
+			parts = append(parts, "bgColor="+color)
+		}
+		if panelType != "" && panelType != "info" && panelType != "note" && panelType != "success" && panelType != "warning" && panelType != "error" {
+			w.addWarning(WarningDroppedFeature, node.Type, fmt.Sprintf("panel type %q has no wiki color", panelType))
+		}
+		if len(parts) > 0 {
+			params = ":" + strings.Join(parts, "|")
+		}
+	}
+
+	return "{panel" + params + "}\n" + strings.TrimRight(content, "\n") + "\n{panel}\n\n", nil
+}
+
+// convertWikiMedia renders media as !image! embeds or [^attachment] links. Media hook
+// Markdown image and link output is translated; other output is inserted verbatim.
+func (w *wikiFormat) convertWikiMedia(node Node) (string, error) {
+	hookOutput, handled, err := w.applyMediaRenderHook(node.Type, w.mediaRenderInput(node))
+	if err != nil {
+		return "", err
+	}
+	if handled {
+		markdown := strings.TrimSpace(hookOutput.Markdown)
+		if match := wikiMarkdownImageRe.FindStringSubmatch(markdown); match != nil {
+			return wikiImage(match[2], match[1]), nil
+		}
+		if match := wikiMarkdownLinkRe.FindStringSubmatch(markdown); match != nil {
+			return "[" + escapeWikiText(match[1]) + "|" + wikiLinkURL(match[2]) + "]", nil
+		}
+		w.addWarning(WarningDroppedFeature, node.Type, "media hook markdown inserted verbatim into wiki markup")
+		return markdown, nil
+	}
+
+	mediaType := node.GetStringAttr("type", "")
+	id := node.GetStringAttr("id", "")
+	alt := node.GetStringAttr("alt", "")
+	url := node.GetStringAttr("url", "")
+	if url == "" && id != "" && w.config.MediaBaseURL != "" {
+		base := w.config.MediaBaseURL
+		if !strings.HasSuffix(base, "/") {
+			base += "/"
+		}
+		url = base + id
+	}
+
+	if url != "" {
+		if mediaType == "file" {
+			return "[" + escapeWikiText(firstNonEmptyTrimmed(alt, id, url)) + "|" + wikiLinkURL(url) + "]", nil
+		}
+		return wikiImage(url, alt), nil
+	}
+
+	// Attachments are referenced by file name in wiki markup.
+	if filename := firstNonEmptyTrimmed(mediaMetadataFromAttrs(node.Attrs, id, url).Filename, alt); filename != "" {
+		if mediaType == "file" {
+			return "[^" + filename + "]", nil
+		}
+		return "!" + filename + "!", nil
+	}
+
+	if id == "" {
+		if w.config.UnknownNodes == UnknownError {
+			return "", fmt.Errorf("media node missing id")
+		}
+		w.addWarning(WarningMissingAttribute, node.Type, "media node missing id")
+		return escapeWikiText("[Media: (no id)]"), nil
+	}
+	w.addWarning(WarningMissingAttribute, node.Type, fmt.Sprintf("media %q has no url or file name; rendered as placeholder", id))
+	label := "Media"
+	switch mediaType {
+	case "image":
+		label = "Image"
+	case "file":
+		label = "File"
+	}
+	return escapeWikiText(fmt.Sprintf("[%s: %s]", label, id)), nil
+}
+
+func wikiImage(url, alt string) string {
+	alt = wikiMacroParam(alt)
+	if alt == "" {
+		return "!" + url + "!"
+	}
+	return "!" + url + "|alt=" + alt + "!"
+}
+
+// convertWikiMention renders a mention as [~accountid:ID], or as text with MentionText.
+func (w *wikiFormat) convertWikiMention(node Node) string {
+	id := node.GetStringAttr("id", "")
+	text := node.GetStringAttr("text", "")
+	if text == "" {
+		text = "Unknown User"
+	} else if !strings.HasPrefix(text, "@") {
+		text = "@" + text
+	}
+
+	if w.config.MentionStyle == MentionText {
+		return escapeWikiText(text)
+	}
+	if id == "" {
+		w.addWarning(WarningMissingAttribute, node.Type, "mention node missing id")
+		return escapeWikiText(text)
+	}
+	return "[~accountid:" + id + "]"
+}
+
+// convertWikiStatus renders a status as bold text in the status color. With StatusText
+// the text is rendered unformatted.
+func (w *wikiFormat) convertWikiStatus(node Node) string {
+	text := escapeWikiText(node.GetStringAttr("text", "Unknown"))
+	if w.config.StatusStyle == StatusText {
+		return text
+	}
+	if color, ok := wikiStatusColors[strings.ToLower(node.GetStringAttr("color", ""))]; ok {
+		return "{color:" + color + "}*" + text + "*{color}"
+	}
+	return "*" + text + "*"
+}
+
+func (w *wikiFormat) convertWikiInlineCard(node Node) (string, error) {
+	title, url := w.getInlineCardLinkData(node)
+
+	hookOutput, handled, err := w.applyLinkRenderHook(node.Type, w.inlineCardRenderInput(node))
+	if err != nil {
+		return "", err
+	}
+	if handled {
+		if hookOutput.TextOnly {
+			return escapeWikiText(firstNonEmptyTrimmed(hookOutput.Title, title, url)), nil
+		}
+		title = hookOutput.Title
+		url = hookOutput.Href
+	}
+
+	if url == "" {
+		if title != "" {
+			return escapeWikiText(title), nil
+		}
+		if w.config.UnknownNodes == UnknownError {
+			return "", fmt.Errorf("inlineCard missing url and valid data")
+		}
+		w.addWarning(WarningMissingAttribute, node.Type, "inlineCard missing url and valid data")
+		return escapeWikiText("[Smart Link]"), nil
+	}
+	if w.config.InlineCardStyle == InlineCardURL || title == "" || title == url {
+		return "[" + wikiLinkURL(url) + "]", nil
+	}
+	return "[" + escapeWikiText(title) + "|" + wikiLinkURL(url) + "]", nil
+}
+
+// convertWikiExtension renders extensions following Config.Extensions. Bodied
+// extensions keep their body. Extension handlers are not invoked because they produce
+// Markdown.
+func (w *wikiFormat) convertWikiExtension(node Node) (string, error) {
+	extensionKey := node.GetStringAttr("extensionKey", "")
+	if _, ok := w.config.ExtensionHandlers[extensionKey]; ok && extensionKey != "" {
+		w.addWarning(WarningExtensionFallback, node.Type, fmt.Sprintf("extension handler %q not used for wiki markup", extensionKey))
+	}
+
+	if node.Type == "bodiedExtension" {
+		w.addWarning(WarningDroppedFeature, node.Type, fmt.Sprintf("bodied extension %q rendered as its body", extensionKey))
+		return w.convertChildren(node.Content)
+	}
+
+	extensionType := firstNonEmptyTrimmed(node.GetStringAttr("extensionType", ""), extensionKey, node.Type)
+	switch strategy := w.config.Extensions.ModeFor(extensionType); strategy {
+	case ExtensionStrip:
+		w.addWarning(WarningDroppedFeature, node.Type, fmt.Sprintf("extension %q stripped", extensionType))
+		return "", nil
+	case ExtensionText:
+		text := escapeWikiText(node.GetStringAttr("text", ""))
+		if len(node.Content) > 0 {
+			children, err := w.convertChildren(node.Content)
+			if err != nil {
+				return "", err
+			}
+			if trimmed := strings.TrimSpace(children); trimmed != "" {
+				text = trimmed
+			}
+		}
+		if text == "" {
+			w.addWarning(WarningExtensionFallback, node.Type, fmt.Sprintf("extension %q has no fallback text", extensionType))
+		}
+		return text, nil
+	case ExtensionJSON:
+		data, err := json.MarshalIndent(extensionJSONNode{Type: node.Type, Attrs: node.Attrs, Content: node.Content}, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal extension node: %w", err)
+		}
+		if node.Type == "inlineExtension" {
+			compact, err := json.Marshal(extensionJSONNode{Type: node.Type, Attrs: node.Attrs, Content: node.Content})
+			if err != nil {
+				return "", fmt.Errorf("failed to marshal extension node: %w", err)
+			}
+			return "{{" + escapeWikiText(string(compact)) + "}}", nil
+		}
+		return "{code:json}\n" + string(data) + "\n{code}\n\n", nil
+	default:
+		return "", fmt.Errorf("unknown extension strategy: %s", strategy)
+	}
+}
+
+// markOpening returns the opening wiki markup for a mark and reports marks that
+// wiki markup cannot express. It is called once per opened mark.
+func (w *wikiFormat) markOpening(mark Mark, _ bool) (string, error) {
+	switch mark.Type {
+	case "strong":
+		return wikiSpanMarker + "*", nil
+	case "em":
+		return wikiSpanMarker + "_", nil
+	case "strike":
+		return wikiSpanMarker + "-", nil
+	case "code":
+		return "{{", nil
+	case "underline":
+		if w.config.UnderlineStyle == UnderlineIgnore {
+			return "", nil
+		}
+		return wikiSpanMarker + "+", nil
+	case "subsup":
+		switch mark.GetStringAttr("type", "") {
+		case "sub":
+			return wikiSpanMarker + "~", nil
+		case "sup":
+			return wikiSpanMarker + "^", nil
+		}
+		return "", nil
+	case "textColor":
+		if color, ok := wikiColor(mark); ok {
+			return "{color:" + color + "}", nil
+		}
+		if raw := mark.GetStringAttr("color", ""); raw != "" {
+			w.addWarning(WarningDroppedFeature, mark.Type, fmt.Sprintf("invalid color value dropped: %q", raw))
+		}
+		return "", nil
+	case "backgroundColor":
+		w.addWarning(WarningDroppedFeature, mark.Type, "background color dropped; wiki markup has no text highlight")
+		return "", nil
+	case "link":
+		_, _, ok, err := w.resolveLinkMark(mark)
+		if err != nil || !ok {
+			return "", err
+		}
+		return "[", nil
+	default:
+		return "", nil
+	}
+}
+
+// markClosing returns the closing wiki markup for a mark.
+func (w *wikiFormat) markClosing(mark Mark, _ bool) (string, error) {
+	switch mark.Type {
+	case "code":
+		return "}}", nil
+	case "textColor":
+		if _, ok := wikiColor(mark); ok {
+			return "{color}", nil
+		}
+		return "", nil
+	case "link":
+		href, _, ok, err := w.resolveLinkMark(mark)
+		if err != nil || !ok {
+			return "", err
+		}
+		return "|" + wikiLinkURL(href) + "]", nil
+	case "backgroundColor":
+		return "", nil
+	default:
+		return w.markOpening(mark, false)
+	}
+}
+
+// resolveWikiSpans replaces the marked formatting delimiters in content. A span whose
+// opening follows a word character, or whose closing precedes one, uses the braced
+// {*}bold{*} form on both ends, since Jira only reads plain delimiters at word edges.
+func resolveWikiSpans(content string) string {
+	if !strings.Contains(content, wikiSpanMarker) {
+		return content
+	}
+
+	runes := []rune(content)
+	opened := make(map[rune]int)
+	braced := make(map[int]bool)
+	for i := 0; i+1 < len(runes); i++ {
+		if runes[i] != wikiSpanRune {
+			continue
+		}
+		delimiter := runes[i+1]
+		start, ok := opened[delimiter]
+		if !ok {
+			opened[delimiter] = i
+			continue
+		}
+		delete(opened, delimiter)
+		before, after := ' ', ' '
+		if start > 0 {
+			before = runes[start-1]
+		}
+		if i+2 < len(runes) {
+			after = runes[i+2]
+		}
+		if isWikiWordRune(before) || isWikiWordRune(after) {
+			braced[start], braced[i] = true, true
+		}
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != wikiSpanRune {
+			sb.WriteRune(runes[i])
+			continue
+		}
+		if i+1 == len(runes) {
+			break
+		}
+		if braced[i] {
+			sb.WriteString("{" + string(runes[i+1]) + "}")
+		} else {
+			sb.WriteRune(runes[i+1])
+		}
+		i++
+	}
+	return sb.String()
+}
+
+// wikiColor returns a hex or named color usable in a {color} macro.
+func wikiColor(mark Mark) (string, bool) {
+	color, ok := SanitizeCSSColor(mark.GetStringAttr("color", ""))
+	if !ok || !(cssHexColorRe.MatchString(color) || cssNamedColorRe.MatchString(color)) {
+		return "", false
+	}
+	return color, true
+}
+
+// wikiLinkURL percent-encodes the characters that would end a link destination.
+func wikiLinkURL(url string) string {
+	return strings.NewReplacer("|", "%7C", "]", "%5D").Replace(url)
+}
+
+// wikiMacroParam removes characters that would end a macro parameter.
+func wikiMacroParam(value string) string {
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		switch r {
+		case '|', '{', '}', '\n', '\r':
+			return -1
+		}
+		return r
+	}, value))
+}
+
+// escapeWikiText backslash-escapes characters that would start wiki markup. Brackets,
+// braces and pipes are always escaped; formatting characters and "!" only where they
+// could open or close a span, so hyphenated words and spaced dashes stay readable.
+// Headings, quotes and list markers are escaped at the start of a line, and "??" so it
+// does not open a citation. A backslash that would combine with the next character
+// into an escape or a line break is written as the &#92; character reference.
+func escapeWikiText(text string) string {
+	if !strings.ContainsAny(text, "[]{}|!*_+^~-?&\\\n"+wikiSpanMarker) &&
+		wikiLineMarker([]rune(strings.TrimLeftFunc(text, unicode.IsSpace))) == 0 {
+		return text
+	}
+
+	runes := []rune(text)
+	var sb strings.Builder
+	lineStart := true
+	skip := 0
+	for i, r := range runes {
+		if skip > 0 {
+			skip--
+			continue
+		}
+		prev, next := ' ', ' '
+		if i > 0 {
+			prev = runes[i-1]
+		}
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		if lineStart && !unicode.IsSpace(r) {
+			lineStart = false
+			if marker := wikiLineMarker(runes[i:]); marker > 0 {
+				sb.WriteString(string(runes[i : i+marker-1]))
+				sb.WriteRune('\\')
+				sb.WriteRune(runes[i+marker-1])
+				skip = marker - 1
+				continue
+			}
+		}
+
+		switch r {
+		case '\n':
+			lineStart = true
+		case '\\':
+			if i+1 == len(runes) || next < utf8.RuneSelf && (unicode.IsPunct(next) || unicode.IsSymbol(next)) {
+				sb.WriteString("&#92;")
+				continue
+			}
+		case '&':
+			if next == '#' {
+				sb.WriteString("&#38;")
+				continue
+			}
+		case '?':
+			if prev == '?' || next == '?' {
+				sb.WriteRune('\\')
+			}
+		case '[', ']', '{', '}', '|':
+			sb.WriteRune('\\')
+		case '!':
+			if !unicode.IsSpace(next) {
+				sb.WriteRune('\\')
+			}
+		case '*', '_', '+', '^', '~', '-':
+			opens := !isWikiWordRune(prev) && !unicode.IsSpace(next)
+			closes := !unicode.IsSpace(prev) && !isWikiWordRune(next)
+			if opens || closes {
+				sb.WriteRune('\\')
+			}
+		case wikiSpanRune:
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// wikiLineMarker returns the length of the heading, quote or list marker that line
+// starts with, ending with the character to escape, or zero when there is none.
+func wikiLineMarker(line []rune) int {
+	switch {
+	case len(line) >= 3 && line[0] == 'h' && line[1] >= '1' && line[1] <= '6' && line[2] == '.':
+		return 3
+	case len(line) >= 3 && line[0] == 'b' && line[1] == 'q' && line[2] == '.':
+		return 3
+	}
+	for i, r := range line {
+		switch {
+		case r == '*' || r == '#' || r == '-' && i == 0:
+			continue
+		case i > 0 && unicode.IsSpace(r):
+			return 1
+		}
+		return 0
+	}
+	return 0
+}
+
+func isWikiWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func (*wikiFormat) keepMark(Mark) bool {
+	return true
+}
+
+func (*wikiFormat) renderText(placeholder, text string, _ []Mark) string {
+	return escapeWikiText(placeholder + text)
+}
+
+func (*wikiFormat) finishInline(content string) string {
+	return resolveWikiSpans(content)
+}
@@ -0,0 +1,230 @@
+package converter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertWikiRendersBlocks(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"heading","attrs":{"level":1},"content":[{"type":"text","text":"Release notes"}]},
+		{"type":"bulletList","content":[
+			{"type":"listItem","content":[
+				{"type":"paragraph","content":[{"type":"text","text":"one"}]},
+				{"type":"orderedList","content":[
+					{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"nested"}]}]}
+				]}
+			]},
+			{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"two"}]}]}
+		]},
+		{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"x := 1"}]},
+		{"type":"panel","attrs":{"panelType":"info"},"content":[{"type":"paragraph","content":[{"type":"text","text":"Heads up"}]}]},
+		{"type":"table","content":[
+			{"type":"tableRow","content":[
+				{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"Key"}]}]},
+				{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"Value"}]}]}
+			]},
+			{"type":"tableRow","content":[
+				{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"a"}]}]},
+				{"type":"tableCell","content":[]}
+			]}
+		]},
+		{"type":"blockquote","content":[{"type":"paragraph","content":[{"type":"text","text":"quoted"}]}]},
+		{"type":"rule"}
+	]}`)
+
+	result, err := newTestConverter(t, Config{}).ConvertWiki(input)
+	require.NoError(t, err)
+
+	expected := "h1. Release notes\n\n" +
+		"* one\n*# nested\n* two\n\n" +
+		"{code:go}\nx := 1\n{code}\n\n" +
+		"{panel:title=Info|bgColor=#deebff}\nHeads up\n{panel}\n\n" +
+		"||Key||Value||\n|a| |\n\n" +
+		"{quote}\nquoted\n{quote}\n\n" +
+		"----\n"
+	assert.Equal(t, expected, result.Markup)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestConvertWikiRendersInlineContent(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[
+			{"type":"text","text":"Bold","marks":[{"type":"strong"}]},
+			{"type":"text","text":", "},
+			{"type":"text","text":"docs","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]},
+			{"type":"text","text":", "},
+			{"type":"text","text":"x","marks":[{"type":"code"}]},
+			{"type":"hardBreak"},
+			{"type":"text","text":"red","marks":[{"type":"textColor","attrs":{"color":"#ff0000"}}]},
+			{"type":"text","text":" by "},
+			{"type":"mention","attrs":{"id":"u1","text":"@Ada"}},
+			{"type":"text","text":" is "},
+			{"type":"status","attrs":{"text":"DONE","color":"green"}},
+			{"type":"text","text":" "},
+			{"type":"emoji","attrs":{"shortName":":thumbsup:"}}
+		]}
+	]}`)
+
+	result, err := newTestConverter(t, Config{}).ConvertWiki(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "*Bold*, [docs|https://example.com], {{x}}\\\\{color:#ff0000}red{color} by [~accountid:u1] is {color:#006644}*DONE*{color} (y)\n", result.Markup)
+}
+
+func TestConvertWikiEscapesText(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[{"type":"text","text":"Use [brackets], {braces} | pipes, *stars* and well-known - dashes!"}]}
+	]}`)
+
+	result, err := newTestConverter(t, Config{}).ConvertWiki(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, `Use \[brackets\], \{braces\} \| pipes, \*stars\* and well-known - dashes!`+"\n", result.Markup)
+}
+
+func TestConvertWikiWarnsOnUnsupportedFeatures(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[
+			{"type":"text","text":"marked","marks":[{"type":"backgroundColor","attrs":{"color":"#ffff00"}}]}
+		]},
+		{"type":"orderedList","attrs":{"order":3},"content":[
+			{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"third"}]}]}
+		]},
+		{"type":"taskList","content":[
+			{"type":"taskItem","attrs":{"state":"DONE"},"content":[{"type":"text","text":"shipped"}]},
+			{"type":"taskList","content":[
+				{"type":"taskItem","attrs":{"state":"TODO"},"content":[{"type":"text","text":"follow up"}]}
+			]}
+		]},
+		{"type":"expand","attrs":{"title":"Details"},"content":[{"type":"paragraph","content":[{"type":"text","text":"hidden"}]}]},
+		{"type":"table","content":[
+			{"type":"tableRow","content":[
+				{"type":"tableCell","attrs":{"colspan":2},"content":[{"type":"paragraph","content":[{"type":"text","text":"wide"}]}]}
+			]}
+		]}
+	]}`)
+
+	result, err := newTestConverter(t, Config{}).ConvertWiki(input)
+	require.NoError(t, err)
+
+	expected := "marked\n\n" +
+		"# third\n\n" +
+		"* (/) shipped\n** ( ) follow up\n\n" +
+		"{panel:title=Details}\nhidden\n{panel}\n\n" +
+		"|wide|\n"
+	assert.Equal(t, expected, result.Markup)
+
+	require.Len(t, result.Warnings, 5)
+	for _, warning := range result.Warnings {
+		assert.Equal(t, WarningDroppedFeature, warning.Type)
+	}
+	assert.Equal(t, "backgroundColor", result.Warnings[0].NodeType)
+	assert.Equal(t, "orderedList", result.Warnings[1].NodeType)
+	assert.Equal(t, "taskList", result.Warnings[2].NodeType)
+	assert.Equal(t, "expand", result.Warnings[3].NodeType)
+	assert.Equal(t, "tableCell", result.Warnings[4].NodeType)
+}
+
+func TestConvertWikiUsesHooks(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[
+			{"type":"text","text":"page","marks":[{"type":"link","attrs":{"href":"https://wiki.example.com/page"}}]}
+		]},
+		{"type":"mediaSingle","content":[{"type":"media","attrs":{"type":"file","id":"m1","alt":"diagram"}}]}
+	]}`)
+
+	converter := newTestConverter(t, Config{
+		LinkHook: func(_ context.Context, in LinkRenderInput) (LinkRenderOutput, error) {
+			return LinkRenderOutput{Href: "https://local/page", Title: in.Title, Handled: true}, nil
+		},
+		MediaHook: func(_ context.Context, in MediaRenderInput) (MediaRenderOutput, error) {
+			return MediaRenderOutput{Markdown: "![" + in.Alt + "](https://cdn.example.com/" + in.ID + ")", Handled: true}, nil
+		},
+	})
+
+	result, err := converter.ConvertWiki(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "[page|https://local/page]\n\n!https://cdn.example.com/m1|alt=diagram!\n", result.Markup)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestConvertWikiRendersMediaWithoutURLAsAttachment(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"mediaSingle","content":[{"type":"media","attrs":{"type":"file","id":"m1","alt":"screenshot.png"}}]},
+		{"type":"mediaSingle","content":[{"type":"media","attrs":{"type":"file","id":"m2"}}]}
+	]}`)
+
+	result, err := newTestConverter(t, Config{}).ConvertWiki(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "[^screenshot.png]\n\n\\[File: m2\\]\n", result.Markup)
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, WarningMissingAttribute, result.Warnings[0].Type)
+}
+
+func TestConvertWikiEscapesBlockSyntaxAndBackslashes(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[{"type":"text","text":"h1. title\n  * item\n# item\n- item\nbq. quote"}]},
+		{"type":"paragraph","content":[{"type":"text","text":"C:\\dir\\ a\\\\b \\[x] &#1; ??cite??"}]}
+	]}`)
+
+	result, err := newTestConverter(t, Config{}).ConvertWiki(input)
+	require.NoError(t, err)
+
+	expected := "h1\\. title\n  \\* item\n\\# item\n\\- item\nbq\\. quote\n\n" +
+		`C:\dir\ a&#92;\b &#92;\[x\] &#38;#1; \?\?cite\?\?` + "\n"
+	assert.Equal(t, expected, result.Markup)
+}
+
+func TestConvertWikiBracesIntrawordMarks(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[
+			{"type":"text","text":"un"},
+			{"type":"text","text":"believ","marks":[{"type":"em"},{"type":"strong"}]},
+			{"type":"text","text":"able, "},
+			{"type":"text","text":"x"},
+			{"type":"text","text":"2","marks":[{"type":"subsup","attrs":{"type":"sup"}}]},
+			{"type":"text","text":" and "},
+			{"type":"text","text":"plain","marks":[{"type":"underline"}]}
+		]}
+	]}`)
+
+	result, err := newTestConverter(t, Config{}).ConvertWiki(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "un{_}*believ*{_}able, x{^}2{^} and +plain+\n", result.Markup)
+}
+
+func TestConvertWikiEncodesPipesInLinks(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[
+			{"type":"text","text":"a|b]","marks":[{"type":"link","attrs":{"href":"https://example.com/?q=a|b"}}]},
+			{"type":"text","text":" "},
+			{"type":"inlineCard","attrs":{"url":"https://example.com/x|y"}}
+		]}
+	]}`)
+
+	result, err := newTestConverter(t, Config{}).ConvertWiki(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, `[a\|b\]|https://example.com/?q=a%7Cb] [https://example.com/x%7Cy]`+"\n", result.Markup)
+}
+
+func TestConvertWikiRendersCodeWithCodeTagAsNoformat(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"codeBlock","attrs":{"language":"text"},"content":[{"type":"text","text":"{code}\nx"}]}
+	]}`)
+
+	result, err := newTestConverter(t, Config{}).ConvertWiki(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "{noformat}\n{code}\nx\n{noformat}\n", result.Markup)
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, WarningDroppedFeature, result.Warnings[0].Type)
+	assert.Equal(t, "codeBlock", result.Warnings[0].NodeType)
+}
@@ -0,0 +1,220 @@
+package converter
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// orderedMarkerRe matches words that would start an ordered list at the start of a line.
+var orderedMarkerRe = regexp.MustCompile(`^[0-9]+[.)]`)
+
+// withWrapIndent renders content whose lines the caller prefixes with indent columns.
+// lead is the width of an extra prefix on the first line only, such as a bold panel label.
+func (s *state) withWrapIndent(indent, lead int, render func() (string, error)) (string, error) {
+	s.wrapIndent += indent
+	s.wrapLead += lead
+	defer func() {
+		s.wrapIndent -= indent
+		// Once the block is rendered its first line has been written.
+		s.wrapLead = 0
+	}()
+	return render()
+}
+
+// wrapText soft-wraps inline Markdown at single spaces so that its lines fit WrapWidth
+// once the prefixes of enclosing blocks are added. Fenced blocks are left alone.
+func (s *state) wrapText(text string) string {
+	if s.config.WrapWidth <= 0 || s.noWrap {
+		return text
+	}
+
+	width := s.config.WrapWidth - s.wrapIndent
+	firstWidth := width - s.wrapLead
+	s.wrapLead = 0
+
+	lines := strings.Split(text, "\n")
+	inFence := false
+	for i, line := range lines {
+		if startsWithFence(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if i == 0 {
+			lines[i] = wrapLine(line, firstWidth, width)
+		} else {
+			lines[i] = wrapLine(line, width, width)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrapLine greedily fills lines of width columns, the first one firstWidth wide. Words
+// longer than a line are kept whole.
+func wrapLine(line string, firstWidth, width int) string {
+	words := wrapWords(line)
+	if len(words) < 2 {
+		return line
+	}
+
+	var sb strings.Builder
+	limit := firstWidth
+	column := 0
+	for i, word := range words {
+		wordWidth := utf8.RuneCountInString(word)
+		if i > 0 {
+			if column+1+wordWidth > limit && canBreakBetween(words[i-1], word) {
+				sb.WriteString("\n")
+				limit = width
+				column = 0
+			} else {
+				sb.WriteString(" ")
+				column++
+			}
+		}
+		sb.WriteString(word)
+		column += wordWidth
+	}
+	return sb.String()
+}
+
+// wrapWords splits line at single spaces. Code spans, bracketed links, images, wiki links
+// and Pandoc spans, and HTML tags or autolinks stay whole, as do runs of spaces, which a
+// line break would collapse.
+func wrapWords(line string) []string {
+	var words []string
+	start := 0
+	for i := 0; i < len(line); {
+		switch line[i] {
+		case '\\':
+			i += 2
+		case '`':
+			i = skipCodeSpan(line, i)
+		case '[':
+			i = skipBracketGroup(line, i)
+		case '<':
+			i = skipAngleGroup(line, i)
+		case ' ':
+			single := i > start && line[i-1] != ' ' && i+1 < len(line) && line[i+1] != ' '
+			if single {
+				words = append(words, line[start:i])
+				start = i + 1
+			}
+			i++
+		default:
+			i++
+		}
+	}
+	return append(words, line[start:])
+}
+
+// canBreakBetween reports whether next may start a new line without changing how the
+// paragraph parses: it must not read as a block marker, and prev must not end in a
+// backslash, which would turn the line break into a hard break.
+func canBreakBetween(prev, next string) bool {
+	if next == "" || strings.HasSuffix(prev, "\\") {
+		return false
+	}
+	if strings.ContainsRune("#>-+*=|~<:!?$%{", rune(next[0])) || strings.HasPrefix(next, "```") {
+		return false
+	}
+	return !orderedMarkerRe.MatchString(next)
+}
+
+// skipCodeSpan returns the index after the code span opening at start, or after the
+// backtick run when it is never closed.
+func skipCodeSpan(line string, start int) int {
+	run := start
+	for run < len(line) && line[run] == '`' {
+		run++
+	}
+	fence := line[start:run]
+	for i := run; i < len(line); {
+		next := strings.Index(line[i:], fence)
+		if next < 0 {
+			break
+		}
+		end := i + next + len(fence)
+		if end >= len(line) || line[end] != '`' {
+			return end
+		}
+		for end < len(line) && line[end] == '`' {
+			end++
+		}
+		i = end
+	}
+	return run
+}
+
+// skipBracketGroup returns the index after a balanced [...] group and the (...), {...}
+// or [...] that directly follows it, or start+1 when the bracket is never closed.
+func skipBracketGroup(line string, start int) int {
+	end := matchingBracket(line, start, '[', ']')
+	if end < 0 {
+		return start + 1
+	}
+	end++
+	if end >= len(line) {
+		return end
+	}
+	var closer byte
+	switch line[end] {
+	case '(':
+		closer = ')'
+	case '{':
+		closer = '}'
+	case '[':
+		closer = ']'
+	default:
+		return end
+	}
+	if next := matchingBracket(line, end, line[end], closer); next >= 0 {
+		return next + 1
+	}
+	return end
+}
+
+// matchingBracket returns the index of the closer that balances the opener at start,
+// skipping escaped characters and code spans, or -1 when there is none.
+func matchingBracket(line string, start int, opener, closer byte) int {
+	depth := 0
+	for i := start; i < len(line); {
+		switch line[i] {
+		case '\\':
+			i += 2
+			continue
+		case '`':
+			i = skipCodeSpan(line, i)
+			continue
+		case opener:
+			depth++
+		case closer:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+		i++
+	}
+	return -1
+}
+
+// skipAngleGroup returns the index after an HTML tag or autolink, or start+1 for a plain
+// less-than sign.
+func skipAngleGroup(line string, start int) int {
+	if start+1 >= len(line) {
+		return start + 1
+	}
+	next := line[start+1]
+	isTag := next == '/' || next == '!' || next >= 'a' && next <= 'z' || next >= 'A' && next <= 'Z'
+	if !isTag {
+		return start + 1
+	}
+	if end := strings.IndexByte(line[start:], '>'); end >= 0 {
+		return start + end + 1
+	}
+	return start + 1
+}
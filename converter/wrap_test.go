@@ -0,0 +1,55 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertWrapWidth(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[
+			{"type":"text","text":"The quick brown fox jumps over the lazy dog and then "},
+			{"type":"text","text":"reads the design doc","marks":[{"type":"link","attrs":{"href":"https://example.com/pages/123/Design?x=1"}}]},
+			{"type":"text","text":" before running "},
+			{"type":"text","text":"go test ./...","marks":[{"type":"code"}]},
+			{"type":"text","text":" and filing - 1. not a list # nor a heading"}
+		]},
+		{"type":"bulletList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[
+			{"type":"text","text":"A list item with enough words that it needs to wrap"}
+		]}]}]},
+		{"type":"panel","attrs":{"panelType":"info"},"content":[{"type":"paragraph","content":[
+			{"type":"text","text":"A panel paragraph with enough words that it needs to wrap"}
+		]}]},
+		{"type":"table","content":[{"type":"tableRow","content":[{"type":"tableCell","content":[{"type":"paragraph","content":[
+			{"type":"text","text":"A table cell with enough words that it would wrap"}
+		]}]}]}]}
+	]}`)
+
+	result, err := newTestConverter(t, Config{WrapWidth: 30, PanelStyle: PanelBold}).Convert(input)
+	require.NoError(t, err)
+	assert.Equal(t, "The quick brown fox jumps over\n"+
+		"the lazy dog and then\n"+
+		"[reads the design doc](https://example.com/pages/123/Design?x=1)\n"+
+		"before running `go test ./...`\n"+
+		"and filing - 1. not a list #\n"+
+		"nor a heading\n\n"+
+		"- A list item with enough\n"+
+		"  words that it needs to wrap\n\n"+
+		"> **Info**: A panel paragraph\n"+
+		"> with enough words that it\n"+
+		"> needs to wrap\n\n"+
+		"| A table cell with enough words that it would wrap |\n"+
+		"| --- |\n", result.Markdown)
+}
+
+func TestWrapLineKeepsMarkersOffLineStarts(t *testing.T) {
+	assert.Equal(t, "aaaa -\nb", wrapLine("aaaa - b", 6, 6))
+	assert.Equal(t, "aaaa 12.\nb", wrapLine("aaaa 12. b", 4, 4))
+	assert.Equal(t, "a\\ b\nc", wrapLine("a\\ b c", 1, 1))
+	assert.Equal(t, "a  b\nc", wrapLine("a  b c", 1, 1))
+	assert.Equal(t, "[a b]{.x}\nc", wrapLine("[a b]{.x} c", 1, 1))
+	assert.Equal(t, "x <span title=\"a b\">\ny", wrapLine(`x <span title="a b"> y`, 1, 1))
+	assert.Equal(t, "``a ` b``\nc", wrapLine("``a ` b`` c", 1, 1))
+}
@@ -2,11 +2,14 @@ module github.com/rgonek/jira-adf-converter
 
 go 1.25.5
 
+require (
+	github.com/stretchr/testify v1.11.1
+	github.com/yuin/goldmark v1.7.16
+	golang.org/x/net v0.50.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/stretchr/testify v1.11.1 // indirect
-	github.com/yuin/goldmark v1.7.16 // indirect
-	golang.org/x/net v0.50.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
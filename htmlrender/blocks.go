@@ -0,0 +1,389 @@
+package htmlrender
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// panelColors holds the background and accent colors of a panel type.
+type panelColors struct {
+	background string
+	accent     string
+}
+
+var panelPalette = map[string]panelColors{
+	"info":    {background: "#deebff", accent: "#0052cc"},
+	"note":    {background: "#eae6ff", accent: "#5243aa"},
+	"success": {background: "#e3fcef", accent: "#00875a"},
+	"warning": {background: "#fffae6", accent: "#ff991f"},
+	"error":   {background: "#ffebe6", accent: "#de350b"},
+}
+
+var defaultPanelColors = panelColors{background: "#f4f5f7", accent: "#6b778c"}
+
+// renderParagraph renders a paragraph; empty paragraphs are dropped.
+func (s *state) renderParagraph(node converter.Node) (string, error) {
+	content, err := s.renderInline(node.Content)
+	if err != nil {
+		return "", err
+	}
+	if content == "" {
+		return "", nil
+	}
+	return "<p" + alignmentStyle(node) + ">" + content + "</p>\n", nil
+}
+
+// renderHeading renders a heading with Config.HeadingOffset applied, clamped to h1-h6.
+func (s *state) renderHeading(node converter.Node) (string, error) {
+	level := node.GetIntAttr("level", 0)
+	if level <= 0 {
+		level = node.Level
+	}
+	if level <= 0 {
+		level = 1
+	}
+	level = min(max(level+s.config.HeadingOffset, 1), 6)
+
+	content, err := s.renderInline(node.Content)
+	if err != nil {
+		return "", err
+	}
+	if content == "" {
+		return "", nil
+	}
+	return fmt.Sprintf("<h%d%s>%s</h%d>\n", level, alignmentStyle(node), content, level), nil
+}
+
+// alignmentStyle returns a text-align style attribute from the align/layout attrs or an
+// alignment mark, or an empty string.
+func alignmentStyle(node converter.Node) string {
+	alignment := node.GetStringAttr("align", "")
+	if alignment == "" {
+		alignment = node.GetStringAttr("layout", "")
+	}
+	for _, mark := range node.Marks {
+		if mark.Type == "alignment" && alignment == "" {
+			alignment = mark.GetStringAttr("align", "")
+		}
+	}
+
+	switch alignment {
+	case "left", "center", "right":
+		return attr("style", "text-align:"+alignment)
+	case "end":
+		return attr("style", "text-align:right")
+	default:
+		return ""
+	}
+}
+
+func (s *state) renderBlockquote(node converter.Node) (string, error) {
+	content, err := s.renderChildren(node.Content)
+	if err != nil {
+		return "", err
+	}
+	if content == "" {
+		return "", nil
+	}
+	decoration := s.decorate("", "margin:8px 0;padding:0 12px;border-left:3px solid #dfe1e6;color:#42526e", "")
+	return "<blockquote" + decoration + ">\n" + content + "</blockquote>\n", nil
+}
+
+// renderCodeBlock renders a codeBlock as <pre><code> with a language-* class.
+func (s *state) renderCodeBlock(node converter.Node) (string, error) {
+	var sb strings.Builder
+	for _, child := range node.Content {
+		sb.WriteString(child.Text)
+	}
+
+	codeAttrs := ""
+	if language := strings.TrimSpace(node.GetStringAttr("language", "")); language != "" {
+		codeAttrs = attr("class", "language-"+language)
+	}
+	decoration := s.decorate("adf-code-block", "margin:8px 0;padding:8px 12px;background:#f4f5f7;border-radius:3px;overflow:auto;font-family:monospace", "")
+	return "<pre" + decoration + "><code" + codeAttrs + ">" + html.EscapeString(sb.String()) + "</code></pre>\n", nil
+}
+
+// renderList renders bulletList and orderedList nodes.
+func (s *state) renderList(node converter.Node) (string, error) {
+	tag := "ul"
+	listAttrs := ""
+	if node.Type == "orderedList" {
+		tag = "ol"
+		if order := node.GetIntAttr("order", 1); order != 1 {
+			listAttrs = fmt.Sprintf(` start="%d"`, order)
+		}
+	}
+
+	var sb strings.Builder
+	for _, item := range node.Content {
+		if item.Type != "listItem" {
+			if s.config.UnknownNodes == converter.UnknownError {
+				return "", fmt.Errorf("expected listItem child, got %s", item.Type)
+			}
+			s.addWarning(converter.WarningUnknownNode, item.Type, fmt.Sprintf("unexpected list child %s, expected listItem", item.Type))
+			continue
+		}
+		rendered, err := s.renderListItem(item)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(rendered)
+	}
+
+	return "<" + tag + listAttrs + ">\n" + sb.String() + "</" + tag + ">\n", nil
+}
+
+func (s *state) renderListItem(node converter.Node) (string, error) {
+	content, err := s.renderItemContent(node.Content)
+	if err != nil {
+		return "", err
+	}
+	return "<li>" + content + "</li>\n", nil
+}
+
+// renderItemContent renders list item and table cell content. A leading paragraph is
+// rendered without <p> so that simple items stay tight.
+func (s *state) renderItemContent(content []converter.Node) (string, error) {
+	if len(content) == 0 {
+		return "", nil
+	}
+
+	var sb strings.Builder
+	rest := content
+	if content[0].Type == "paragraph" {
+		inline, err := s.renderInline(content[0].Content)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(inline)
+		rest = content[1:]
+	}
+	if len(rest) == 0 {
+		return sb.String(), nil
+	}
+
+	blocks, err := s.renderChildren(rest)
+	if err != nil {
+		return "", err
+	}
+	if sb.Len() > 0 {
+		sb.WriteString("\n")
+	}
+	sb.WriteString(blocks)
+	return sb.String(), nil
+}
+
+// renderTaskList renders a taskList. Nested task lists are rendered inside the list.
+func (s *state) renderTaskList(node converter.Node) (string, error) {
+	var sb strings.Builder
+	for _, item := range node.Content {
+		var rendered string
+		var err error
+		switch item.Type {
+		case "taskItem":
+			rendered, err = s.renderTaskItem(item)
+		case "taskList":
+			rendered, err = s.renderTaskList(item)
+			rendered = "<li" + s.decorate("", "list-style:none", "") + ">\n" + rendered + "</li>\n"
+		default:
+			if s.config.UnknownNodes == converter.UnknownError {
+				return "", fmt.Errorf("taskList expects taskItem child, got %s", item.Type)
+			}
+			s.addWarning(converter.WarningUnknownNode, item.Type, fmt.Sprintf("unexpected task list child %s", item.Type))
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(rendered)
+	}
+
+	decoration := s.decorate("adf-task-list", "list-style:none;padding-left:20px", "")
+	return "<ul" + decoration + ">\n" + sb.String() + "</ul>\n", nil
+}
+
+// renderTaskItem renders a taskItem with a disabled checkbox, or a ballot box character
+// in inline-styles mode where email clients drop form controls.
+func (s *state) renderTaskItem(node converter.Node) (string, error) {
+	done := node.GetStringAttr("state", "TODO") == "DONE"
+	content, err := s.renderInline(node.Content)
+	if err != nil {
+		return "", err
+	}
+
+	taskState := "TODO"
+	box := `<input type="checkbox" disabled>`
+	if done {
+		taskState = "DONE"
+		box = `<input type="checkbox" checked disabled>`
+	}
+	if s.inlineStyles() {
+		box = "☐"
+		if done {
+			box = "☑"
+		}
+	}
+
+	decoration := s.decorate("adf-task-item", "", "")
+	return "<li" + decoration + dataAttr("state", taskState) + ">" + box + " " + content + "</li>\n", nil
+}
+
+func (s *state) renderDecisionList(node converter.Node) (string, error) {
+	content, err := s.renderChildren(node.Content)
+	if err != nil {
+		return "", err
+	}
+	decoration := s.decorate("adf-decision-list", "list-style:none;padding-left:20px", "")
+	return "<ul" + decoration + ">\n" + content + "</ul>\n", nil
+}
+
+func (s *state) renderDecisionItem(node converter.Node) (string, error) {
+	content, err := s.renderInline(node.Content)
+	if err != nil {
+		return "", err
+	}
+
+	decisionState := node.GetStringAttr("state", "DECIDED")
+	marker := "✓"
+	if decisionState != "DECIDED" {
+		marker = "?"
+	}
+	decoration := s.decorate("adf-decision-item", "", "")
+	return "<li" + decoration + dataAttr("state", decisionState) + ">" + marker + " " + content + "</li>\n", nil
+}
+
+// renderPanel renders a panel as <aside>, or as a styled <div> in inline-styles mode.
+// A valid panelColor attr overrides the background of the panel type.
+func (s *state) renderPanel(node converter.Node) (string, error) {
+	content, err := s.renderChildren(node.Content)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(content) == "" {
+		return "", nil
+	}
+
+	panelType := strings.ToLower(node.GetStringAttr("panelType", ""))
+	if panelType == "" {
+		panelType = "info"
+	}
+	colors, ok := panelPalette[panelType]
+	if !ok {
+		colors = defaultPanelColors
+	}
+
+	background := "background-color:" + colors.background
+	dataStyle := ""
+	if raw := node.GetStringAttr("panelColor", ""); raw != "" {
+		if color, ok := converter.SanitizeCSSColor(raw); ok {
+			background = ""
+			dataStyle = "background-color:" + color
+		} else {
+			s.addWarning(converter.WarningDroppedFeature, node.Type, fmt.Sprintf("invalid panelColor %q dropped", raw))
+		}
+	}
+
+	tag := "aside"
+	if s.inlineStyles() {
+		tag = "div"
+	}
+	decoration := s.decorate(
+		"adf-panel adf-panel-"+panelType,
+		joinStyles("margin:8px 0;padding:8px 12px;border-left:4px solid "+colors.accent+";border-radius:3px", background),
+		dataStyle,
+	)
+
+	return "<" + tag + decoration + dataAttr("panel-type", panelType) + ">\n" + content + "</" + tag + ">\n", nil
+}
+
+// renderExpand renders expand and nestedExpand nodes as <details>. In inline-styles mode,
+// where <details> is not widely supported, the title is rendered as a bold heading line.
+func (s *state) renderExpand(node converter.Node) (string, error) {
+	content, err := s.renderChildren(node.Content)
+	if err != nil {
+		return "", err
+	}
+	title := node.GetStringAttr("title", "")
+
+	if s.inlineStyles() {
+		var sb strings.Builder
+		sb.WriteString("<div" + s.decorate("", "margin:8px 0;padding:8px 12px;border:1px solid #dfe1e6;border-radius:3px", "") + ">\n")
+		if title != "" {
+			sb.WriteString("<p" + s.decorate("", "margin:0 0 8px;font-weight:bold", "") + ">" + html.EscapeString(title) + "</p>\n")
+		}
+		sb.WriteString(content)
+		sb.WriteString("</div>\n")
+		return sb.String(), nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<details" + s.decorate("adf-expand", "", "") + ">\n")
+	if title != "" {
+		sb.WriteString("<summary>" + html.EscapeString(title) + "</summary>\n")
+	}
+	sb.WriteString(content)
+	sb.WriteString("</details>\n")
+	return sb.String(), nil
+}
+
+// renderLayoutSection renders a layoutSection as a CSS grid whose tracks follow the
+// column width attrs. In inline-styles mode it renders a presentation table instead.
+func (s *state) renderLayoutSection(node converter.Node) (string, error) {
+	var columns []converter.Node
+	for _, child := range node.Content {
+		if child.Type == "layoutColumn" {
+			columns = append(columns, child)
+			continue
+		}
+		s.addWarning(converter.WarningUnknownNode, child.Type, fmt.Sprintf("unexpected layoutSection child %s", child.Type))
+	}
+	if len(columns) == 0 {
+		return "", nil
+	}
+
+	if s.inlineStyles() {
+		return s.renderLayoutTable(columns)
+	}
+
+	tracks := make([]string, 0, len(columns))
+	var sb strings.Builder
+	for _, column := range columns {
+		width := column.GetFloat64Attr("width", 0)
+		if width > 0 {
+			tracks = append(tracks, formatNumber(width)+"fr")
+		} else {
+			tracks = append(tracks, "1fr")
+		}
+
+		content, err := s.renderChildren(column.Content)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString("<div" + s.decorate("adf-layout-column", "", "min-width:0") + ">\n" + content + "</div>\n")
+	}
+
+	gridStyle := "display:grid;grid-template-columns:" + strings.Join(tracks, " ") + ";gap:16px"
+	return "<div" + s.decorate("adf-layout", "", gridStyle) + ">\n" + sb.String() + "</div>\n", nil
+}
+
+func (s *state) renderLayoutTable(columns []converter.Node) (string, error) {
+	var sb strings.Builder
+	sb.WriteString(`<table role="presentation"` + s.decorate("", "width:100%;border-collapse:collapse", "") + ">\n<tr>\n")
+	for _, column := range columns {
+		content, err := s.renderChildren(column.Content)
+		if err != nil {
+			return "", err
+		}
+		widthStyle := ""
+		if width := column.GetFloat64Attr("width", 0); width > 0 {
+			widthStyle = "width:" + formatNumber(width) + "%"
+		}
+		sb.WriteString("<td" + s.decorate("", "vertical-align:top;padding:0 8px", widthStyle) + ">\n" + content + "</td>\n")
+	}
+	sb.WriteString("</tr>\n</table>\n")
+	return sb.String(), nil
+}
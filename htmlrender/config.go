@@ -0,0 +1,122 @@
+package htmlrender
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// StyleMode controls how presentation is attached to rendered elements.
+type StyleMode string
+
+const (
+	// StyleClasses emits semantic "adf-*" class names for an external stylesheet.
+	StyleClasses StyleMode = "classes"
+	// StyleInline emits inline style attributes only and avoids <details>, CSS grid and
+	// form controls, for email clients that strip stylesheets.
+	StyleInline StyleMode = "inline"
+)
+
+// Config holds all renderer configuration options.
+type Config struct {
+	StyleMode         StyleMode                             `json:"styleMode,omitempty"`
+	HeadingOffset     int                                   `json:"headingOffset,omitempty"`
+	DateFormat        string                                `json:"dateFormat,omitempty"`
+	MediaBaseURL      string                                `json:"mediaBaseURL,omitempty"`
+	Extensions        converter.ExtensionRules              `json:"extensions,omitempty"`
+	UnknownNodes      converter.UnknownPolicy               `json:"unknownNodes,omitempty"`
+	UnknownMarks      converter.UnknownPolicy               `json:"unknownMarks,omitempty"`
+	ResolutionMode    converter.ResolutionMode              `json:"resolutionMode,omitempty"`
+	LinkHook          converter.LinkRenderHook              `json:"-"`
+	MediaHook         converter.MediaRenderHook             `json:"-"`
+	ExtensionHandlers map[string]converter.ExtensionHandler `json:"-"`
+}
+
+func (c Config) applyDefaults() Config {
+	if c.StyleMode == "" {
+		c.StyleMode = StyleClasses
+	}
+	if c.DateFormat == "" {
+		c.DateFormat = "2006-01-02"
+	}
+	if c.Extensions.Default == "" {
+		c.Extensions.Default = converter.ExtensionText
+	}
+	if c.UnknownNodes == "" {
+		c.UnknownNodes = converter.UnknownPlaceholder
+	}
+	if c.UnknownMarks == "" {
+		c.UnknownMarks = converter.UnknownSkip
+	}
+	if c.ResolutionMode == "" {
+		c.ResolutionMode = converter.ResolutionBestEffort
+	}
+
+	return c
+}
+
+// clone returns a deep copy of Config for map-backed fields.
+func (c Config) clone() Config {
+	cloned := c
+	if c.Extensions.ByType != nil {
+		cloned.Extensions.ByType = make(map[string]converter.ExtensionMode, len(c.Extensions.ByType))
+		for extensionType, mode := range c.Extensions.ByType {
+			cloned.Extensions.ByType[extensionType] = mode
+		}
+	}
+	if c.ExtensionHandlers != nil {
+		cloned.ExtensionHandlers = make(map[string]converter.ExtensionHandler, len(c.ExtensionHandlers))
+		for key, handler := range c.ExtensionHandlers {
+			cloned.ExtensionHandlers[key] = handler
+		}
+	}
+	return cloned
+}
+
+// Validate checks that config values are valid.
+func (c Config) Validate() error {
+	if c.StyleMode != StyleClasses && c.StyleMode != StyleInline {
+		return fmt.Errorf("invalid styleMode %q", c.StyleMode)
+	}
+	if c.HeadingOffset < 0 || c.HeadingOffset > 5 {
+		return fmt.Errorf("headingOffset must be between 0 and 5, got %d", c.HeadingOffset)
+	}
+	if !isValidExtensionMode(c.Extensions.Default) {
+		return fmt.Errorf("invalid extensions.default %q", c.Extensions.Default)
+	}
+	for extensionType, mode := range c.Extensions.ByType {
+		if strings.TrimSpace(extensionType) == "" {
+			return fmt.Errorf("extensions.byType contains empty key")
+		}
+		if !isValidExtensionMode(mode) {
+			return fmt.Errorf("invalid extensions.byType mode %q for type %q", mode, extensionType)
+		}
+	}
+	if !isValidUnknownPolicy(c.UnknownNodes) {
+		return fmt.Errorf("invalid unknownNodes policy %q", c.UnknownNodes)
+	}
+	if !isValidUnknownPolicy(c.UnknownMarks) {
+		return fmt.Errorf("invalid unknownMarks policy %q", c.UnknownMarks)
+	}
+	if c.ResolutionMode != converter.ResolutionBestEffort && c.ResolutionMode != converter.ResolutionStrict {
+		return fmt.Errorf("invalid resolutionMode %q", c.ResolutionMode)
+	}
+	for key, handler := range c.ExtensionHandlers {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("extensionHandlers contains empty key")
+		}
+		if handler == nil {
+			return fmt.Errorf("extensionHandlers[%q] is nil", key)
+		}
+	}
+	return nil
+}
+
+func isValidExtensionMode(mode converter.ExtensionMode) bool {
+	return mode == converter.ExtensionJSON || mode == converter.ExtensionText || mode == converter.ExtensionStrip
+}
+
+func isValidUnknownPolicy(policy converter.UnknownPolicy) bool {
+	return policy == converter.UnknownError || policy == converter.UnknownSkip || policy == converter.UnknownPlaceholder
+}
@@ -0,0 +1,124 @@
+package htmlrender
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+type extensionJSONNode struct {
+	Type    string                 `json:"type"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Content []converter.Node       `json:"content,omitempty"`
+}
+
+// renderExtension renders extension, inlineExtension and bodiedExtension nodes. A
+// registered handler's Markdown is rendered to HTML inside an "adf-extension" element
+// whose metadata becomes data-* attributes. Unhandled bodied extensions keep their
+// body; other extensions follow Config.Extensions.
+func (s *state) renderExtension(node converter.Node) (string, error) {
+	inline := node.Type == "inlineExtension"
+	tag, suffix := "div", "\n"
+	if inline {
+		tag, suffix = "span", ""
+	}
+
+	extensionKey := node.GetStringAttr("extensionKey", "")
+	if handler, ok := s.config.ExtensionHandlers[extensionKey]; ok && extensionKey != "" {
+		output, err := handler.ToMarkdown(s.ctx, converter.ExtensionRenderInput{
+			SourcePath: s.options.SourcePath,
+			Node:       node,
+		})
+		if err != nil {
+			return "", err
+		}
+		if output.Handled {
+			body, err := s.renderMarkdown(output.Markdown, inline)
+			if err != nil {
+				return "", err
+			}
+
+			keys := make([]string, 0, len(output.Metadata))
+			for k := range output.Metadata {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
+			var sb strings.Builder
+			sb.WriteString("<" + tag + s.decorate("adf-extension", "", "") + dataAttr("extension-key", extensionKey))
+			for _, k := range keys {
+				sb.WriteString(dataAttr(k, output.Metadata[k]))
+			}
+			sb.WriteString(">" + suffix + body + "</" + tag + ">" + suffix)
+			return sb.String(), nil
+		}
+	}
+
+	if node.Type == "bodiedExtension" {
+		content, err := s.renderChildren(node.Content)
+		if err != nil {
+			return "", err
+		}
+		openTag := "<div" + s.decorate("adf-bodied-extension", "", "") +
+			dataAttr("extension-key", extensionKey) +
+			dataAttr("extension-type", node.GetStringAttr("extensionType", "")) + ">\n"
+		return openTag + content + "</div>\n", nil
+	}
+
+	extensionType := firstNonEmpty(node.GetStringAttr("extensionType", ""), extensionKey, node.Type)
+	switch mode := s.config.Extensions.ModeFor(extensionType); mode {
+	case converter.ExtensionStrip:
+		s.addWarning(converter.WarningDroppedFeature, node.Type, fmt.Sprintf("extension %q stripped", extensionType))
+		return "", nil
+	case converter.ExtensionText:
+		text := html.EscapeString(node.GetStringAttr("text", ""))
+		if len(node.Content) > 0 {
+			content, err := s.renderInline(node.Content)
+			if err != nil {
+				return "", err
+			}
+			if strings.TrimSpace(content) != "" {
+				text = content
+			}
+		}
+		if text == "" {
+			s.addWarning(converter.WarningExtensionFallback, node.Type, fmt.Sprintf("extension %q has no fallback text", extensionType))
+			return "", nil
+		}
+		if inline {
+			return text, nil
+		}
+		return "<p>" + text + "</p>\n", nil
+	case converter.ExtensionJSON:
+		return s.renderExtensionJSON(node, inline)
+	default:
+		return "", fmt.Errorf("unknown extension strategy: %s", mode)
+	}
+}
+
+func (s *state) renderExtensionJSON(node converter.Node, inline bool) (string, error) {
+	payload := extensionJSONNode{
+		Type:    node.Type,
+		Attrs:   node.Attrs,
+		Content: node.Content,
+	}
+
+	if inline {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal extension node: %w", err)
+		}
+		return "<code" + s.decorate("adf-extension-json", "font-family:monospace", "") + ">" + html.EscapeString(string(data)) + "</code>", nil
+	}
+
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal extension node: %w", err)
+	}
+	decoration := s.decorate("adf-extension-json", "margin:8px 0;padding:8px 12px;background:#f4f5f7;border-radius:3px;overflow:auto;font-family:monospace", "")
+	return "<pre" + decoration + "><code>" + html.EscapeString(string(data)) + "</code></pre>\n", nil
+}
@@ -0,0 +1,64 @@
+package htmlrender
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// applyLinkHook calls Config.LinkHook and applies the same unresolved-reference policy
+// and output validation as the Markdown converter.
+func (s *state) applyLinkHook(nodeType string, input converter.LinkRenderInput) (converter.LinkRenderOutput, bool, error) {
+	if s.config.LinkHook == nil {
+		return converter.LinkRenderOutput{}, false, nil
+	}
+	if err := s.checkContext(); err != nil {
+		return converter.LinkRenderOutput{}, false, err
+	}
+
+	output, err := s.config.LinkHook(s.ctx, input)
+	if err != nil {
+		return converter.LinkRenderOutput{}, false, s.hooks.LinkError(nodeType, input, err)
+	}
+	return converter.CheckLinkRenderOutput(output)
+}
+
+// applyMediaHook calls Config.MediaHook and applies the same unresolved-reference policy
+// and output validation as the Markdown converter.
+func (s *state) applyMediaHook(nodeType string, input converter.MediaRenderInput) (converter.MediaRenderOutput, bool, error) {
+	if s.config.MediaHook == nil {
+		return converter.MediaRenderOutput{}, false, nil
+	}
+	if err := s.checkContext(); err != nil {
+		return converter.MediaRenderOutput{}, false, err
+	}
+
+	output, err := s.config.MediaHook(s.ctx, input)
+	if err != nil {
+		return converter.MediaRenderOutput{}, false, s.hooks.MediaError(nodeType, input, err)
+	}
+	return converter.CheckMediaRenderOutput(output)
+}
+
+// renderMarkdown renders hook or extension handler Markdown to HTML. Raw HTML in the
+// Markdown is omitted and dangerous link destinations are dropped. With inline set, a
+// single wrapping paragraph is removed.
+func (s *state) renderMarkdown(markdown string, inline bool) (string, error) {
+	var buf bytes.Buffer
+	if err := s.markdown.Convert([]byte(markdown), &buf); err != nil {
+		return "", fmt.Errorf("failed to render markdown: %w", err)
+	}
+
+	rendered := buf.String()
+	if !inline {
+		return rendered, nil
+	}
+
+	trimmed := strings.TrimSpace(rendered)
+	if strings.HasPrefix(trimmed, "<p>") && strings.HasSuffix(trimmed, "</p>") && strings.Count(trimmed, "<p>") == 1 {
+		return strings.TrimSuffix(strings.TrimPrefix(trimmed, "<p>"), "</p>"), nil
+	}
+	return trimmed, nil
+}
@@ -0,0 +1,236 @@
+// Package htmlrender renders Jira ADF documents directly to semantic, sanitized HTML.
+//
+// Output is an HTML fragment. All text and attribute values are escaped, link and media
+// URLs are restricted to safe schemes, and colors are validated before they reach a
+// style attribute. Link and media hooks and extension handlers use the same contracts
+// as the converter package; Markdown returned by media hooks and extension handlers is
+// rendered to HTML with raw HTML omitted.
+package htmlrender
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// Renderer renders ADF to HTML.
+type Renderer struct {
+	config   Config
+	markdown goldmark.Markdown
+}
+
+// state holds the per-render state, making the renderer thread-safe.
+type state struct {
+	config   Config
+	markdown goldmark.Markdown
+	ctx      context.Context
+	options  converter.ConvertOptions
+	warnings []converter.Warning
+	hooks    converter.HookPolicy
+}
+
+// New creates a new Renderer with the given config.
+func New(config Config) (*Renderer, error) {
+	cfg := config.applyDefaults().clone()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Renderer{
+		config:   cfg,
+		markdown: goldmark.New(goldmark.WithExtensions(extension.GFM)),
+	}, nil
+}
+
+// Render takes an ADF JSON document and returns an HTML fragment.
+func (r *Renderer) Render(input []byte) (Result, error) {
+	return r.RenderWithContext(context.Background(), input, converter.ConvertOptions{})
+}
+
+// RenderWithContext takes an ADF JSON document and returns an HTML fragment.
+func (r *Renderer) RenderWithContext(ctx context.Context, input []byte, opts converter.ConvertOptions) (Result, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+
+	var doc converter.Doc
+	if err := json.Unmarshal(input, &doc); err != nil {
+		return Result{}, fmt.Errorf("failed to parse ADF JSON: %w", err)
+	}
+
+	s := &state{
+		config:   r.config,
+		markdown: r.markdown,
+		ctx:      ctx,
+		options:  opts,
+	}
+	s.hooks = converter.HookPolicy{ResolutionMode: r.config.ResolutionMode, Warn: s.addWarning}
+
+	output, err := s.renderChildren(doc.Content)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := s.checkContext(); err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		HTML:       output,
+		Warnings:   s.warnings,
+		References: converter.CollectReferences(doc),
+	}, nil
+}
+
+func (s *state) renderNode(node converter.Node) (string, error) {
+	if err := s.checkContext(); err != nil {
+		return "", err
+	}
+
+	switch node.Type {
+	case "doc":
+		return s.renderChildren(node.Content)
+
+	case "paragraph":
+		return s.renderParagraph(node)
+
+	case "heading":
+		return s.renderHeading(node)
+
+	case "blockquote":
+		return s.renderBlockquote(node)
+
+	case "rule":
+		return "<hr>\n", nil
+
+	case "hardBreak":
+		return "<br>", nil
+
+	case "codeBlock":
+		return s.renderCodeBlock(node)
+
+	case "bulletList", "orderedList":
+		return s.renderList(node)
+
+	case "listItem":
+		return s.renderListItem(node)
+
+	case "taskList":
+		return s.renderTaskList(node)
+
+	case "taskItem":
+		return s.renderTaskItem(node)
+
+	case "decisionList":
+		return s.renderDecisionList(node)
+
+	case "decisionItem":
+		return s.renderDecisionItem(node)
+
+	case "text":
+		return s.renderText(node)
+
+	case "emoji":
+		return s.renderEmoji(node)
+
+	case "mention":
+		return s.renderMention(node)
+
+	case "status":
+		return s.renderStatus(node)
+
+	case "date":
+		return s.renderDate(node)
+
+	case "inlineCard":
+		return s.renderInlineCard(node)
+
+	case "table":
+		return s.renderTable(node)
+
+	case "panel":
+		return s.renderPanel(node)
+
+	case "expand", "nestedExpand":
+		return s.renderExpand(node)
+
+	case "layoutSection":
+		return s.renderLayoutSection(node)
+
+	case "layoutColumn":
+		return s.renderChildren(node.Content)
+
+	case "mediaSingle":
+		return s.renderMediaSingle(node)
+
+	case "mediaGroup":
+		return s.renderMediaGroup(node)
+
+	case "media", "mediaInline":
+		return s.renderMedia(node)
+
+	case "caption":
+		return s.renderInline(node.Content)
+
+	case "placeholder":
+		return "", nil
+
+	case "extension", "inlineExtension", "bodiedExtension":
+		return s.renderExtension(node)
+
+	default:
+		switch s.config.UnknownNodes {
+		case converter.UnknownError:
+			return "", fmt.Errorf("unknown node type: %s", node.Type)
+		case converter.UnknownSkip:
+			s.addWarning(converter.WarningUnknownNode, node.Type, fmt.Sprintf("unknown node skipped: %s", node.Type))
+			return "", nil
+		default:
+			s.addWarning(converter.WarningUnknownNode, node.Type, fmt.Sprintf("unknown node rendered as placeholder: %s", node.Type))
+			return html.EscapeString(fmt.Sprintf("[Unknown node: %s]", node.Type)), nil
+		}
+	}
+}
+
+func (s *state) addWarning(warnType converter.WarningType, nodeType, message string) {
+	s.warnings = append(s.warnings, converter.Warning{
+		Type:     warnType,
+		NodeType: nodeType,
+		Message:  message,
+	})
+}
+
+func (s *state) checkContext() error {
+	if s.ctx == nil {
+		return nil
+	}
+
+	select {
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	default:
+		return nil
+	}
+}
+
+// renderChildren renders a slice of nodes and concatenates their results.
+func (s *state) renderChildren(content []converter.Node) (string, error) {
+	var sb strings.Builder
+	for _, child := range content {
+		rendered, err := s.renderNode(child)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(rendered)
+	}
+	return sb.String(), nil
+}
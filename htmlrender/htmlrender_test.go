@@ -0,0 +1,293 @@
+package htmlrender
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRenderer(t *testing.T, cfg Config) *Renderer {
+	t.Helper()
+	r, err := New(cfg)
+	require.NoError(t, err)
+	return r
+}
+
+type staticExtensionHandler struct {
+	output converter.ExtensionRenderOutput
+}
+
+func (h staticExtensionHandler) ToMarkdown(ctx context.Context, in converter.ExtensionRenderInput) (converter.ExtensionRenderOutput, error) {
+	return h.output, nil
+}
+
+func (h staticExtensionHandler) FromMarkdown(ctx context.Context, in converter.ExtensionParseInput) (converter.ExtensionParseOutput, error) {
+	return converter.ExtensionParseOutput{}, nil
+}
+
+func TestRenderBasicBlocksAndMarks(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"heading","attrs":{"level":1},"content":[{"type":"text","text":"Title"}]},
+		{"type":"paragraph","content":[
+			{"type":"text","text":"Bold","marks":[{"type":"strong"}]},
+			{"type":"text","text":" <tag> & "},
+			{"type":"text","text":"red","marks":[{"type":"textColor","attrs":{"color":"#ff0000"}}]}
+		]},
+		{"type":"orderedList","attrs":{"order":3},"content":[
+			{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"three"}]}]}
+		]},
+		{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"if a < b {}"}]}
+	]}`)
+
+	result, err := newTestRenderer(t, Config{HeadingOffset: 1}).Render(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "<h2>Title</h2>\n"+
+		`<p><strong>Bold</strong> &lt;tag&gt; &amp; <span style="color:#ff0000">red</span></p>`+"\n"+
+		"<ol start=\"3\">\n<li>three</li>\n</ol>\n"+
+		`<pre class="adf-code-block"><code class="language-go">if a &lt; b {}</code></pre>`+"\n", result.HTML)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestRenderPanelExpandStatusAndMention(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"panel","attrs":{"panelType":"warning"},"content":[
+			{"type":"paragraph","content":[
+				{"type":"status","attrs":{"text":"In Progress","color":"blue"}},
+				{"type":"text","text":" "},
+				{"type":"mention","attrs":{"id":"u1","text":"Ada"}}
+			]}
+		]},
+		{"type":"expand","attrs":{"title":"More <info>"},"content":[
+			{"type":"paragraph","content":[{"type":"text","text":"hidden"}]}
+		]}
+	]}`)
+
+	result, err := newTestRenderer(t, Config{}).Render(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, `<aside class="adf-panel adf-panel-warning" data-panel-type="warning">`+"\n"+
+		`<p><span class="adf-status adf-status-blue" data-color="blue">In Progress</span> <span class="adf-mention" data-mention-id="u1">@Ada</span></p>`+"\n"+
+		"</aside>\n"+
+		"<details class=\"adf-expand\">\n<summary>More &lt;info&gt;</summary>\n<p>hidden</p>\n</details>\n", result.HTML)
+}
+
+func TestRenderLayoutSectionAsGrid(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"layoutSection","content":[
+			{"type":"layoutColumn","attrs":{"width":66.66},"content":[{"type":"paragraph","content":[{"type":"text","text":"left"}]}]},
+			{"type":"layoutColumn","attrs":{"width":33.34},"content":[{"type":"paragraph","content":[{"type":"text","text":"right"}]}]}
+		]}
+	]}`)
+
+	result, err := newTestRenderer(t, Config{}).Render(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, `<div class="adf-layout" style="display:grid;grid-template-columns:66.66fr 33.34fr;gap:16px">`+"\n"+
+		`<div class="adf-layout-column" style="min-width:0">`+"\n<p>left</p>\n</div>\n"+
+		`<div class="adf-layout-column" style="min-width:0">`+"\n<p>right</p>\n</div>\n"+
+		"</div>\n", result.HTML)
+}
+
+func TestRenderTableWithSpansAndWidths(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"table","content":[
+			{"type":"tableRow","content":[
+				{"type":"tableHeader","attrs":{"colspan":2,"colwidth":[100,150]},"content":[{"type":"paragraph","content":[{"type":"text","text":"Wide"}]}]}
+			]},
+			{"type":"tableRow","content":[
+				{"type":"tableCell","attrs":{"rowspan":2,"background":"#ffebe6"},"content":[{"type":"paragraph","content":[{"type":"text","text":"Tall"}]}]},
+				{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"b"}]}]}
+			]},
+			{"type":"tableRow","content":[
+				{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"c"}]}]}
+			]}
+		]}
+	]}`)
+
+	result, err := newTestRenderer(t, Config{}).Render(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "<table class=\"adf-table\">\n"+
+		"<thead>\n<tr>\n"+`<th colspan="2" style="width:250px">Wide</th>`+"\n</tr>\n</thead>\n"+
+		"<tbody>\n<tr>\n"+`<td rowspan="2" style="background-color:#ffebe6">Tall</td>`+"\n<td>b</td>\n</tr>\n"+
+		"<tr>\n<td>c</td>\n</tr>\n</tbody>\n"+
+		"</table>\n", result.HTML)
+}
+
+func TestRenderInlineStylesMode(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"panel","attrs":{"panelType":"info"},"content":[{"type":"paragraph","content":[{"type":"text","text":"note"}]}]},
+		{"type":"expand","attrs":{"title":"More"},"content":[{"type":"paragraph","content":[{"type":"text","text":"body"}]}]},
+		{"type":"layoutSection","content":[
+			{"type":"layoutColumn","attrs":{"width":50},"content":[{"type":"paragraph","content":[{"type":"text","text":"a"}]}]}
+		]},
+		{"type":"taskList","content":[{"type":"taskItem","attrs":{"state":"DONE"},"content":[{"type":"text","text":"done"}]}]}
+	]}`)
+
+	result, err := newTestRenderer(t, Config{StyleMode: StyleInline}).Render(input)
+	require.NoError(t, err)
+
+	assert.NotContains(t, result.HTML, "class=")
+	assert.NotContains(t, result.HTML, "<details")
+	assert.NotContains(t, result.HTML, "display:grid")
+	assert.NotContains(t, result.HTML, "<input")
+	assert.Contains(t, result.HTML, `<div style="margin:8px 0;padding:8px 12px;border-left:4px solid #0052cc;border-radius:3px;background-color:#deebff" data-panel-type="info">`)
+	assert.Contains(t, result.HTML, `<p style="margin:0 0 8px;font-weight:bold">More</p>`)
+	assert.Contains(t, result.HTML, `<table role="presentation" style="width:100%;border-collapse:collapse">`)
+	assert.Contains(t, result.HTML, `<td style="vertical-align:top;padding:0 8px;width:50%">`)
+	assert.Contains(t, result.HTML, `data-state="DONE">☑ done</li>`)
+}
+
+func TestRenderSanitizesURLsAndColors(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[
+			{"type":"text","text":"bad","marks":[{"type":"link","attrs":{"href":"javascript:alert(1)"}}]},
+			{"type":"text","text":" x","marks":[{"type":"textColor","attrs":{"color":"red;background:url(x)"}}]},
+			{"type":"text","text":" ok","marks":[{"type":"link","attrs":{"href":"https://example.com/?a=1&b=\"2\""}}]}
+		]},
+		{"type":"mediaSingle","content":[{"type":"media","attrs":{"type":"external","url":"data:text/html,<script>"}}]}
+	]}`)
+
+	result, err := newTestRenderer(t, Config{}).Render(input)
+	require.NoError(t, err)
+
+	assert.Contains(t, result.HTML, `<p>bad x<a href="https://example.com/?a=1&amp;b=&#34;2&#34;"> ok</a></p>`)
+	assert.NotContains(t, result.HTML, "javascript:")
+	assert.NotContains(t, result.HTML, "<script>")
+	require.Len(t, result.Warnings, 4)
+	assert.Equal(t, converter.WarningDroppedFeature, result.Warnings[0].Type)
+	assert.Equal(t, converter.WarningDroppedFeature, result.Warnings[1].Type)
+	assert.Equal(t, converter.WarningDroppedFeature, result.Warnings[2].Type)
+	assert.Equal(t, converter.WarningMissingAttribute, result.Warnings[3].Type)
+}
+
+func TestRenderGroupsAdjacentLinkedTextAndCallsLinkHookOnce(t *testing.T) {
+	calls := 0
+	cfg := Config{
+		LinkHook: func(ctx context.Context, in converter.LinkRenderInput) (converter.LinkRenderOutput, error) {
+			calls++
+			assert.Equal(t, "mark", in.Source)
+			assert.Equal(t, "docs/page.md", in.SourcePath)
+			return converter.LinkRenderOutput{Href: "/wiki/page", Handled: true}, nil
+		},
+	}
+	input := []byte(`{"type":"doc","content":[{"type":"paragraph","content":[
+		{"type":"text","text":"read ","marks":[{"type":"link","attrs":{"href":"https://jira/browse/X-1"}}]},
+		{"type":"text","text":"this","marks":[{"type":"link","attrs":{"href":"https://jira/browse/X-1"}},{"type":"strong"}]}
+	]}]}`)
+
+	result, err := newTestRenderer(t, cfg).RenderWithContext(context.Background(), input, converter.ConvertOptions{SourcePath: "docs/page.md"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "<p><a href=\"/wiki/page\">read <strong>this</strong></a></p>\n", result.HTML)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRenderLinkHookUnresolvedFollowsResolutionMode(t *testing.T) {
+	hook := func(ctx context.Context, in converter.LinkRenderInput) (converter.LinkRenderOutput, error) {
+		return converter.LinkRenderOutput{}, converter.ErrUnresolved
+	}
+	input := []byte(`{"type":"doc","content":[{"type":"paragraph","content":[
+		{"type":"text","text":"x","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]}
+	]}]}`)
+
+	result, err := newTestRenderer(t, Config{LinkHook: hook}).Render(input)
+	require.NoError(t, err)
+	assert.Equal(t, "<p><a href=\"https://example.com\">x</a></p>\n", result.HTML)
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, converter.WarningUnresolvedReference, result.Warnings[0].Type)
+
+	_, err = newTestRenderer(t, Config{LinkHook: hook, ResolutionMode: converter.ResolutionStrict}).Render(input)
+	require.ErrorIs(t, err, converter.ErrUnresolved)
+}
+
+func TestRenderMediaHookMarkdownIsRenderedWithoutRawHTML(t *testing.T) {
+	cfg := Config{
+		MediaHook: func(ctx context.Context, in converter.MediaRenderInput) (converter.MediaRenderOutput, error) {
+			assert.Equal(t, "att-1", in.ID)
+			return converter.MediaRenderOutput{Markdown: `![diagram](assets/diagram.png)<script>x</script>`, Handled: true}, nil
+		},
+	}
+	input := []byte(`{"type":"doc","content":[
+		{"type":"mediaSingle","content":[{"type":"media","attrs":{"type":"file","id":"att-1"}}]}
+	]}`)
+
+	result, err := newTestRenderer(t, cfg).Render(input)
+	require.NoError(t, err)
+
+	assert.Contains(t, result.HTML, `<img src="assets/diagram.png" alt="diagram">`)
+	assert.NotContains(t, result.HTML, "<script>")
+}
+
+func TestRenderMediaFallbacks(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"mediaSingle","attrs":{"width":50},"content":[
+			{"type":"media","attrs":{"type":"file","id":"img-1","alt":"Chart"}},
+			{"type":"caption","content":[{"type":"text","text":"Figure 1"}]}
+		]},
+		{"type":"mediaGroup","content":[{"type":"media","attrs":{"type":"file","id":"doc-2"}}]}
+	]}`)
+
+	result, err := newTestRenderer(t, Config{MediaBaseURL: "https://cdn.example.com/media"}).Render(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, `<figure class="adf-media-single" style="width:50%">`+"\n"+
+		`<a class="adf-media-file" href="https://cdn.example.com/media/img-1">Chart</a>`+"\n"+
+		"<figcaption>Figure 1</figcaption>\n</figure>\n"+
+		`<div class="adf-media-group">`+"\n"+
+		`<a class="adf-media-file" href="https://cdn.example.com/media/doc-2">doc-2</a>`+"\n</div>\n", result.HTML)
+}
+
+func TestRenderExtensions(t *testing.T) {
+	cfg := Config{
+		ExtensionHandlers: map[string]converter.ExtensionHandler{
+			"jira-chart": staticExtensionHandler{output: converter.ExtensionRenderOutput{
+				Markdown: "**Chart** for _sprint_",
+				Metadata: map[string]string{"chartId": "42", "bad name": "x"},
+				Handled:  true,
+			}},
+		},
+		Extensions: converter.ExtensionRules{ByType: map[string]converter.ExtensionMode{"com.example.json": converter.ExtensionJSON}},
+	}
+	input := []byte(`{"type":"doc","content":[
+		{"type":"extension","attrs":{"extensionKey":"jira-chart","extensionType":"com.atlassian"}},
+		{"type":"bodiedExtension","attrs":{"extensionKey":"box","extensionType":"com.example"},"content":[
+			{"type":"paragraph","content":[{"type":"text","text":"inside"}]}
+		]},
+		{"type":"extension","attrs":{"extensionKey":"other","extensionType":"com.example.text","text":"Fallback <text>"}},
+		{"type":"extension","attrs":{"extensionKey":"raw","extensionType":"com.example.json"}}
+	]}`)
+
+	result, err := newTestRenderer(t, cfg).Render(input)
+	require.NoError(t, err)
+
+	assert.Contains(t, result.HTML, `<div class="adf-extension" data-extension-key="jira-chart" data-chartid="42">`+"\n"+
+		"<p><strong>Chart</strong> for <em>sprint</em></p>\n</div>\n")
+	assert.Contains(t, result.HTML, `<div class="adf-bodied-extension" data-extension-key="box" data-extension-type="com.example">`+"\n<p>inside</p>\n</div>\n")
+	assert.Contains(t, result.HTML, "<p>Fallback &lt;text&gt;</p>\n")
+	assert.Contains(t, result.HTML, `<pre class="adf-extension-json"><code>{`)
+}
+
+func TestRenderUnknownNodePolicy(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[{"type":"mystery"}]}`)
+
+	result, err := newTestRenderer(t, Config{}).Render(input)
+	require.NoError(t, err)
+	assert.Equal(t, "[Unknown node: mystery]", result.HTML)
+	require.Len(t, result.Warnings, 1)
+
+	_, err = newTestRenderer(t, Config{UnknownNodes: converter.UnknownError}).Render(input)
+	require.Error(t, err)
+}
+
+func TestConfigValidate(t *testing.T) {
+	_, err := New(Config{StyleMode: "fancy"})
+	require.EqualError(t, err, `invalid styleMode "fancy"`)
+
+	_, err = New(Config{HeadingOffset: 6})
+	require.EqualError(t, err, "headingOffset must be between 0 and 5, got 6")
+}
@@ -0,0 +1,300 @@
+package htmlrender
+
+import (
+	"fmt"
+	"html"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// statusColors holds the background and text colors of a status lozenge.
+type statusColors struct {
+	background string
+	text       string
+}
+
+var statusPalette = map[string]statusColors{
+	"neutral": {background: "#dfe1e6", text: "#42526e"},
+	"purple":  {background: "#eae6ff", text: "#403294"},
+	"blue":    {background: "#deebff", text: "#0747a6"},
+	"red":     {background: "#ffebe6", text: "#bf2600"},
+	"yellow":  {background: "#fff0b3", text: "#172b4d"},
+	"green":   {background: "#e3fcef", text: "#006644"},
+}
+
+// renderInline renders inline content. Adjacent text nodes that share a link mark are
+// wrapped in a single anchor, so the link hook runs once per link.
+func (s *state) renderInline(content []converter.Node) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(content); {
+		link, ok := findLinkMark(content[i])
+		if !ok {
+			rendered, err := s.renderNode(content[i])
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(rendered)
+			i++
+			continue
+		}
+
+		end := i + 1
+		for end < len(content) {
+			next, ok := findLinkMark(content[end])
+			if !ok || !reflect.DeepEqual(next.Attrs, link.Attrs) {
+				break
+			}
+			end++
+		}
+
+		var inner strings.Builder
+		for _, node := range content[i:end] {
+			rendered, err := s.renderText(node)
+			if err != nil {
+				return "", err
+			}
+			inner.WriteString(rendered)
+		}
+
+		rendered, err := s.wrapLink(link, inner.String())
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(rendered)
+		i = end
+	}
+	return sb.String(), nil
+}
+
+func findLinkMark(node converter.Node) (converter.Mark, bool) {
+	if node.Type != "text" {
+		return converter.Mark{}, false
+	}
+	for _, mark := range node.Marks {
+		if mark.Type == "link" {
+			return mark, true
+		}
+	}
+	return converter.Mark{}, false
+}
+
+// wrapLink wraps rendered content in an anchor for a link mark. Links without an href,
+// text-only hook results and unsafe URLs render the content alone.
+func (s *state) wrapLink(link converter.Mark, content string) (string, error) {
+	input, ok := converter.LinkMarkRenderInput(s.options.SourcePath, link)
+	if !ok {
+		return content, nil
+	}
+
+	href, title := input.Href, input.Title
+	output, handled, err := s.applyLinkHook(link.Type, input)
+	if err != nil {
+		return "", err
+	}
+	if handled {
+		if output.TextOnly {
+			return content, nil
+		}
+		href, title = output.Href, output.Title
+	}
+
+	safeHref, ok := safeURL(href)
+	if !ok {
+		s.addWarning(converter.WarningDroppedFeature, link.Type, fmt.Sprintf("unsafe link href dropped: %q", href))
+		return content, nil
+	}
+
+	anchorAttrs := attr("href", safeHref)
+	if title != "" {
+		anchorAttrs += attr("title", title)
+	}
+	return "<a" + anchorAttrs + ">" + content + "</a>", nil
+}
+
+// renderText renders a text node with its formatting marks. Link marks are applied by
+// renderInline; the first mark in the list becomes the outermost element.
+func (s *state) renderText(node converter.Node) (string, error) {
+	result := html.EscapeString(node.Text)
+	for i := len(node.Marks) - 1; i >= 0; i-- {
+		opening, closing, err := s.markTags(node.Marks[i])
+		if err != nil {
+			return "", err
+		}
+		result = opening + result + closing
+	}
+	return result, nil
+}
+
+func (s *state) markTags(mark converter.Mark) (string, string, error) {
+	switch mark.Type {
+	case "strong":
+		return "<strong>", "</strong>", nil
+	case "em":
+		return "<em>", "</em>", nil
+	case "strike":
+		return "<s>", "</s>", nil
+	case "underline":
+		return "<u>", "</u>", nil
+	case "code":
+		return "<code" + s.decorate("", "padding:0 2px;background-color:#f4f5f7;border-radius:3px;font-family:monospace", "") + ">", "</code>", nil
+	case "subsup":
+		switch mark.GetStringAttr("type", "") {
+		case "sub":
+			return "<sub>", "</sub>", nil
+		case "sup":
+			return "<sup>", "</sup>", nil
+		}
+		return "", "", nil
+	case "textColor", "backgroundColor":
+		raw := mark.GetStringAttr("color", "")
+		color, ok := converter.SanitizeCSSColor(raw)
+		if !ok {
+			if raw != "" {
+				s.addWarning(converter.WarningDroppedFeature, mark.Type, fmt.Sprintf("invalid color value dropped: %q", raw))
+			}
+			return "", "", nil
+		}
+		property := "color:"
+		if mark.Type == "backgroundColor" {
+			property = "background-color:"
+		}
+		return "<span" + attr("style", property+color) + ">", "</span>", nil
+	case "link", "annotation":
+		return "", "", nil
+	default:
+		if s.config.UnknownMarks == converter.UnknownError {
+			return "", "", fmt.Errorf("unknown mark type: %s", mark.Type)
+		}
+		if s.config.UnknownMarks == converter.UnknownPlaceholder {
+			s.addWarning(converter.WarningUnknownMark, mark.Type, fmt.Sprintf("unknown mark rendered as placeholder: %s", mark.Type))
+			return html.EscapeString(fmt.Sprintf("[Unknown mark: %s]", mark.Type)), "", nil
+		}
+		s.addWarning(converter.WarningUnknownMark, mark.Type, fmt.Sprintf("unknown mark skipped: %s", mark.Type))
+		return "", "", nil
+	}
+}
+
+// renderMention renders a mention as a span carrying the account id.
+func (s *state) renderMention(node converter.Node) (string, error) {
+	id := node.GetStringAttr("id", "")
+	text := node.GetStringAttr("text", "")
+	if text == "" {
+		text = "Unknown User"
+	} else if !strings.HasPrefix(text, "@") {
+		text = "@" + text
+	}
+
+	idAttr := ""
+	if id == "" {
+		s.addWarning(converter.WarningMissingAttribute, node.Type, "mention node missing id")
+	} else {
+		idAttr = dataAttr("mention-id", id)
+	}
+
+	decoration := s.decorate("adf-mention", "padding:0 4px;border-radius:10px;background-color:#ebecf0;color:#172b4d", "")
+	return "<span" + decoration + idAttr + ">" + html.EscapeString(text) + "</span>", nil
+}
+
+// renderStatus renders a status lozenge colored by its color attr.
+func (s *state) renderStatus(node converter.Node) (string, error) {
+	text := node.GetStringAttr("text", "Unknown")
+	color := strings.ToLower(node.GetStringAttr("color", "neutral"))
+	colors, ok := statusPalette[color]
+	if !ok {
+		color = "neutral"
+		colors = statusPalette[color]
+	}
+
+	decoration := s.decorate(
+		"adf-status adf-status-"+color,
+		fmt.Sprintf("display:inline-block;padding:0 4px;border-radius:3px;background-color:%s;color:%s;font-size:11px;font-weight:bold;text-transform:uppercase", colors.background, colors.text),
+		"",
+	)
+	return "<span" + decoration + dataAttr("color", color) + ">" + html.EscapeString(text) + "</span>", nil
+}
+
+func (s *state) renderEmoji(node converter.Node) (string, error) {
+	shortName := node.GetStringAttr("shortName", "")
+	text := firstNonEmpty(node.GetStringAttr("text", ""), node.GetStringAttr("fallback", ""), shortName)
+	if text == "" {
+		if s.config.UnknownNodes == converter.UnknownError {
+			return "", fmt.Errorf("emoji node missing shortName and fallback")
+		}
+		s.addWarning(converter.WarningMissingAttribute, node.Type, "emoji node missing shortName and fallback")
+		return "", nil
+	}
+
+	titleAttr := ""
+	if shortName != "" {
+		titleAttr = attr("title", shortName)
+	}
+	return "<span" + s.decorate("adf-emoji", "", "") + titleAttr + ">" + html.EscapeString(text) + "</span>", nil
+}
+
+// renderDate renders a date node as <time> with an ISO 8601 datetime attribute and
+// Config.DateFormat text.
+func (s *state) renderDate(node converter.Node) (string, error) {
+	timestamp := node.GetStringAttr("timestamp", "")
+
+	var ts int64
+	if _, err := fmt.Sscanf(timestamp, "%d", &ts); err != nil {
+		if s.config.UnknownNodes == converter.UnknownError {
+			return "", fmt.Errorf("date node has invalid timestamp format: %s", timestamp)
+		}
+		s.addWarning(converter.WarningMissingAttribute, node.Type, fmt.Sprintf("date node has invalid timestamp format: %s", timestamp))
+		return html.EscapeString("[Date: invalid]"), nil
+	}
+
+	// Millisecond timestamps are detected with the same cutoff as the Markdown converter.
+	if ts > 10000000000 {
+		ts = ts / 1000
+	}
+
+	t := time.Unix(ts, 0).UTC()
+	return "<time" + attr("datetime", t.Format("2006-01-02")) + ">" + html.EscapeString(t.Format(s.config.DateFormat)) + "</time>", nil
+}
+
+// renderInlineCard renders an inlineCard as a link titled with the card name.
+func (s *state) renderInlineCard(node converter.Node) (string, error) {
+	input := converter.InlineCardRenderInput(s.options.SourcePath, node)
+	if input.Href == "" {
+		if s.config.UnknownNodes == converter.UnknownError {
+			return "", fmt.Errorf("inlineCard missing url and valid data")
+		}
+		s.addWarning(converter.WarningMissingAttribute, node.Type, "inlineCard missing url and valid data")
+		return html.EscapeString("[Smart Link]"), nil
+	}
+
+	href, title := input.Href, input.Title
+	output, handled, err := s.applyLinkHook(node.Type, input)
+	if err != nil {
+		return "", err
+	}
+	if handled {
+		if output.TextOnly {
+			return html.EscapeString(firstNonEmpty(output.Title, title)), nil
+		}
+		href = output.Href
+		title = firstNonEmpty(output.Title, title)
+	}
+
+	safeHref, ok := safeURL(href)
+	if !ok {
+		s.addWarning(converter.WarningDroppedFeature, node.Type, fmt.Sprintf("unsafe link href dropped: %q", href))
+		return html.EscapeString(title), nil
+	}
+
+	return "<a" + s.decorate("adf-inline-card", "", "") + attr("href", safeHref) + ">" + html.EscapeString(title) + "</a>", nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
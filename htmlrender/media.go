@@ -0,0 +1,128 @@
+package htmlrender
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// renderMediaSingle renders a mediaSingle as <figure>, with a caption child as
+// <figcaption>. The width attr is a percentage unless widthType is "pixel".
+func (s *state) renderMediaSingle(node converter.Node) (string, error) {
+	var body, caption strings.Builder
+	for _, child := range node.Content {
+		rendered, err := s.renderNode(child)
+		if err != nil {
+			return "", err
+		}
+		if child.Type == "caption" {
+			caption.WriteString(rendered)
+			continue
+		}
+		body.WriteString(rendered)
+	}
+	if body.Len() == 0 && caption.Len() == 0 {
+		return "", nil
+	}
+
+	widthStyle := ""
+	if width := node.GetFloat64Attr("width", 0); width > 0 {
+		unit := "%"
+		if node.GetStringAttr("widthType", "") == "pixel" {
+			unit = "px"
+		}
+		widthStyle = "width:" + formatNumber(width) + unit
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<figure" + s.decorate("adf-media-single", "margin:8px 0", widthStyle) + ">\n")
+	sb.WriteString(body.String())
+	sb.WriteString("\n")
+	if caption.Len() > 0 {
+		sb.WriteString("<figcaption>" + caption.String() + "</figcaption>\n")
+	}
+	sb.WriteString("</figure>\n")
+	return sb.String(), nil
+}
+
+func (s *state) renderMediaGroup(node converter.Node) (string, error) {
+	var sb strings.Builder
+	for _, child := range node.Content {
+		rendered, err := s.renderNode(child)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(rendered)
+		sb.WriteString("\n")
+	}
+	if sb.Len() == 0 {
+		return "", nil
+	}
+	return "<div" + s.decorate("adf-media-group", "margin:8px 0", "") + ">\n" + sb.String() + "</div>\n", nil
+}
+
+// renderMedia renders media and mediaInline nodes. Media hook Markdown is rendered to
+// HTML; otherwise files become links, other media with a URL become images, and media
+// without a resolvable URL become a placeholder.
+func (s *state) renderMedia(node converter.Node) (string, error) {
+	hookOutput, handled, err := s.applyMediaHook(node.Type, converter.MediaNodeRenderInput(s.options.SourcePath, node))
+	if err != nil {
+		return "", err
+	}
+	if handled {
+		return s.renderMarkdown(hookOutput.Markdown, true)
+	}
+
+	mediaType := node.GetStringAttr("type", "")
+	id := node.GetStringAttr("id", "")
+	alt := node.GetStringAttr("alt", "")
+
+	src := node.GetStringAttr("url", "")
+	if src == "" && id != "" && s.config.MediaBaseURL != "" {
+		base := s.config.MediaBaseURL
+		if !strings.HasSuffix(base, "/") {
+			base += "/"
+		}
+		src = base + id
+	}
+
+	if src != "" {
+		safeSrc, ok := safeURL(src)
+		if ok {
+			if mediaType == "file" {
+				label := firstNonEmpty(node.GetStringAttr("filename", ""), alt, id, "File")
+				return "<a" + s.decorate("adf-media-file", "", "") + attr("href", safeSrc) + ">" + html.EscapeString(label) + "</a>", nil
+			}
+
+			imgAttrs := attr("src", safeSrc) + attr("alt", firstNonEmpty(alt, "Image"))
+			if width := node.GetIntAttr("width", 0); width > 0 {
+				imgAttrs += fmt.Sprintf(` width="%d"`, width)
+			}
+			if height := node.GetIntAttr("height", 0); height > 0 {
+				imgAttrs += fmt.Sprintf(` height="%d"`, height)
+			}
+			return "<img" + imgAttrs + s.decorate("adf-media", "max-width:100%;height:auto", "") + ">", nil
+		}
+		s.addWarning(converter.WarningDroppedFeature, node.Type, fmt.Sprintf("unsafe media url dropped: %q", src))
+	}
+
+	label := "Media"
+	switch mediaType {
+	case "image":
+		label = "Image"
+	case "file":
+		label = "File"
+	}
+	if id == "" {
+		if s.config.UnknownNodes == converter.UnknownError {
+			return "", fmt.Errorf("media node missing id")
+		}
+		s.addWarning(converter.WarningMissingAttribute, node.Type, "media node missing id")
+		id = "(no id)"
+	}
+
+	placeholder := fmt.Sprintf("[%s: %s]", label, id)
+	return "<span" + s.decorate("adf-media-placeholder", "color:#6b778c", "") + ">" + html.EscapeString(placeholder) + "</span>", nil
+}
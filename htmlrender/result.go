@@ -0,0 +1,11 @@
+package htmlrender
+
+import "github.com/rgonek/jira-adf-converter/converter"
+
+// Result holds the output of an HTML render.
+type Result struct {
+	HTML     string              `json:"html"`
+	Warnings []converter.Warning `json:"warnings,omitempty"`
+	// References lists links, media, mentions, inlineCards, emoji and extensions of the input.
+	References converter.References `json:"references,omitzero"`
+}
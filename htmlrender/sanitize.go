@@ -0,0 +1,88 @@
+package htmlrender
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	safeURLSchemes = map[string]bool{
+		"http":   true,
+		"https":  true,
+		"mailto": true,
+		"tel":    true,
+	}
+
+	dataAttrNameRe = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+)
+
+// safeURL returns the trimmed URL when it is relative or uses an allowed scheme.
+// URLs that do not parse, such as ones containing control characters, are rejected.
+func safeURL(raw string) (string, bool) {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return "", false
+	}
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return "", false
+	}
+	if parsed.Scheme == "" {
+		return value, true
+	}
+	return value, safeURLSchemes[strings.ToLower(parsed.Scheme)]
+}
+
+// attr renders a single attribute with an escaped value.
+func attr(name, value string) string {
+	return " " + name + `="` + html.EscapeString(value) + `"`
+}
+
+// dataAttr renders a data-* attribute, dropping names that are not valid attribute names.
+func dataAttr(name, value string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !dataAttrNameRe.MatchString(name) {
+		return ""
+	}
+	return attr("data-"+name, value)
+}
+
+// decorate returns the class and style attributes of an element. classes are emitted in
+// StyleClasses mode and presentation styles in StyleInline mode; dataStyle carries
+// content-derived styles such as widths and colors and is emitted in both modes.
+func (s *state) decorate(classes, presentation, dataStyle string) string {
+	var sb strings.Builder
+	style := dataStyle
+	if s.inlineStyles() {
+		style = joinStyles(presentation, dataStyle)
+	} else if classes != "" {
+		sb.WriteString(attr("class", classes))
+	}
+	if style != "" {
+		sb.WriteString(attr("style", style))
+	}
+	return sb.String()
+}
+
+func (s *state) inlineStyles() bool {
+	return s.config.StyleMode == StyleInline
+}
+
+// joinStyles joins non-empty CSS declaration lists with semicolons.
+func joinStyles(styles ...string) string {
+	parts := make([]string, 0, len(styles))
+	for _, style := range styles {
+		style = strings.Trim(strings.TrimSpace(style), ";")
+		if style != "" {
+			parts = append(parts, style)
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,142 @@
+package htmlrender
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// renderTable renders a table. Leading rows made only of header cells form the <thead>;
+// colspan, rowspan, colwidth and background cell attrs are preserved.
+func (s *state) renderTable(node converter.Node) (string, error) {
+	var rows []converter.Node
+	for _, child := range node.Content {
+		if child.Type != "tableRow" {
+			s.addWarning(converter.WarningUnknownNode, child.Type, fmt.Sprintf("unexpected table child %s", child.Type))
+			continue
+		}
+		rows = append(rows, child)
+	}
+	if len(rows) == 0 {
+		return "", nil
+	}
+
+	headerRows := 0
+	for headerRows < len(rows) && isHeaderRow(rows[headerRows]) {
+		headerRows++
+	}
+
+	tableStyle := ""
+	if width := node.GetFloat64Attr("width", 0); width > 0 {
+		tableStyle = "width:" + formatNumber(width) + "px"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<table" + s.decorate("adf-table", "border-collapse:collapse;margin:8px 0", tableStyle) + ">\n")
+	if headerRows > 0 {
+		sb.WriteString("<thead>\n")
+		if err := s.renderTableRows(&sb, rows[:headerRows]); err != nil {
+			return "", err
+		}
+		sb.WriteString("</thead>\n")
+	}
+	if headerRows < len(rows) {
+		sb.WriteString("<tbody>\n")
+		if err := s.renderTableRows(&sb, rows[headerRows:]); err != nil {
+			return "", err
+		}
+		sb.WriteString("</tbody>\n")
+	}
+	sb.WriteString("</table>\n")
+	return sb.String(), nil
+}
+
+func isHeaderRow(row converter.Node) bool {
+	if len(row.Content) == 0 {
+		return false
+	}
+	for _, cell := range row.Content {
+		if cell.Type != "tableHeader" {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *state) renderTableRows(sb *strings.Builder, rows []converter.Node) error {
+	for _, row := range rows {
+		sb.WriteString("<tr>\n")
+		for _, cell := range row.Content {
+			var tag string
+			switch cell.Type {
+			case "tableHeader":
+				tag = "th"
+			case "tableCell":
+				tag = "td"
+			default:
+				s.addWarning(converter.WarningUnknownNode, cell.Type, fmt.Sprintf("unexpected table row child %s", cell.Type))
+				continue
+			}
+			rendered, err := s.renderTableCell(cell, tag)
+			if err != nil {
+				return err
+			}
+			sb.WriteString(rendered)
+		}
+		sb.WriteString("</tr>\n")
+	}
+	return nil
+}
+
+func (s *state) renderTableCell(cell converter.Node, tag string) (string, error) {
+	content, err := s.renderItemContent(cell.Content)
+	if err != nil {
+		return "", err
+	}
+
+	var cellAttrs strings.Builder
+	if colspan := cell.GetIntAttr("colspan", 1); colspan > 1 {
+		cellAttrs.WriteString(fmt.Sprintf(` colspan="%d"`, colspan))
+	}
+	if rowspan := cell.GetIntAttr("rowspan", 1); rowspan > 1 {
+		cellAttrs.WriteString(fmt.Sprintf(` rowspan="%d"`, rowspan))
+	}
+
+	var dataStyles []string
+	if width := cellWidth(cell); width > 0 {
+		dataStyles = append(dataStyles, "width:"+formatNumber(width)+"px")
+	}
+	if raw := cell.GetStringAttr("background", ""); raw != "" {
+		if color, ok := converter.SanitizeCSSColor(raw); ok {
+			dataStyles = append(dataStyles, "background-color:"+color)
+		} else {
+			s.addWarning(converter.WarningDroppedFeature, cell.Type, fmt.Sprintf("invalid cell background %q dropped", raw))
+		}
+	}
+
+	presentation := "border:1px solid #dfe1e6;padding:4px 8px;vertical-align:top;text-align:left"
+	if tag == "th" {
+		presentation += ";background-color:#f4f5f7"
+	}
+	decoration := s.decorate("", presentation, joinStyles(dataStyles...))
+
+	return "<" + tag + cellAttrs.String() + decoration + ">" + content + "</" + tag + ">\n", nil
+}
+
+// cellWidth returns the total pixel width of a cell's colwidth attr, or 0 when unset.
+func cellWidth(cell converter.Node) float64 {
+	widths, ok := cell.Attrs["colwidth"].([]interface{})
+	if !ok {
+		return 0
+	}
+	total := 0.0
+	for _, value := range widths {
+		width, ok := value.(float64)
+		if !ok || width <= 0 {
+			return 0
+		}
+		total += width
+	}
+	return total
+}
@@ -0,0 +1,103 @@
+package mdconverter
+
+import (
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// admonitionPanelTypes maps the admonition types of MkDocs Material, Docusaurus and the
+// Hugo admonition shortcode to panel types.
+var admonitionPanelTypes = map[string]string{
+	"note":      "note",
+	"abstract":  "note",
+	"summary":   "note",
+	"tldr":      "note",
+	"example":   "note",
+	"quote":     "note",
+	"cite":      "note",
+	"secondary": "note",
+	"info":      "info",
+	"todo":      "info",
+	"question":  "info",
+	"help":      "info",
+	"faq":       "info",
+	"tip":       "success",
+	"hint":      "success",
+	"important": "success",
+	"success":   "success",
+	"check":     "success",
+	"done":      "success",
+	"warning":   "warning",
+	"caution":   "warning",
+	"attention": "warning",
+	"danger":    "error",
+	"error":     "error",
+	"failure":   "error",
+	"fail":      "error",
+	"missing":   "error",
+	"bug":       "error",
+}
+
+func (s *state) convertAdmonitionNode(node *AdmonitionNode) (converter.Node, bool, error) {
+	if node.Collapsible {
+		if !s.shouldDetectExpandAdmonition(node.Flavor) {
+			return pandocLiteralParagraph(node.Literal()), true, nil
+		}
+
+		expandType := "expand"
+		if s.admonitionExpandDepth > 0 {
+			expandType = "nestedExpand"
+		}
+
+		s.admonitionExpandDepth++
+		content, err := s.convertBlockFragment(node.Body())
+		s.admonitionExpandDepth--
+		if err != nil {
+			return converter.Node{}, false, err
+		}
+
+		expand := converter.Node{
+			Type:    expandType,
+			Content: ensureBlockContent(content),
+		}
+		if node.Title != "" {
+			expand.Attrs = map[string]interface{}{
+				"title": node.Title,
+			}
+		}
+		return expand, true, nil
+	}
+
+	if !s.shouldDetectPanelAdmonition(node.Flavor) {
+		return pandocLiteralParagraph(node.Literal()), true, nil
+	}
+
+	content, err := s.convertBlockFragment(node.Body())
+	if err != nil {
+		return converter.Node{}, false, err
+	}
+
+	panelType, ok := admonitionPanelTypes[node.Keyword]
+	if !ok {
+		panelType = "note"
+	}
+	panel := converter.Node{
+		Type: "panel",
+		Attrs: map[string]interface{}{
+			"panelType": panelType,
+		},
+		Content: ensureBlockContent(content),
+	}
+	if node.Title != "" {
+		panel.Attrs["title"] = node.Title
+	}
+	return panel, true, nil
+}
+
+// ensureBlockContent returns content, or a single empty paragraph when an admonition or
+// callout has no body, since ADF panels and expands need at least one block.
+func ensureBlockContent(content []converter.Node) []converter.Node {
+	if len(content) == 0 {
+		return []converter.Node{{Type: "paragraph"}}
+	}
+	return content
+}
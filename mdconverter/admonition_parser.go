@@ -0,0 +1,360 @@
+package mdconverter
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+var KindAdmonition = ast.NewNodeKind("Admonition")
+
+// AdmonitionFlavor names the documentation site syntax an admonition was written in.
+type AdmonitionFlavor string
+
+const (
+	AdmonitionMkDocs     AdmonitionFlavor = "mkdocs"
+	AdmonitionDocusaurus AdmonitionFlavor = "docusaurus"
+	AdmonitionHugo       AdmonitionFlavor = "hugo"
+)
+
+var (
+	mkDocsAdmonitionPattern     = regexp.MustCompile(`^(!!!|\?\?\?\+?)[ \t]+([A-Za-z][\w-]*)(?:[ \t]+[\w-]+)*(?:[ \t]+"(.*)")?[ \t]*$`)
+	docusaurusAdmonitionPattern = regexp.MustCompile(`^(:{3,})[ \t]*([A-Za-z]+)(?:\[(.*)\])?(?:[ \t]+(.*?))?[ \t]*$`)
+	hugoShortcodeOpenPattern    = regexp.MustCompile(`^\{\{([<%])\s*(admonition|details)(\s.*?)?\s*[>%]\}\}[ \t]*$`)
+	hugoShortcodeClosePattern   = regexp.MustCompile(`^\{\{[<%]\s*/\s*(admonition|details)\s*[>%]\}\}[ \t]*$`)
+)
+
+// AdmonitionNode is a MkDocs Material, Docusaurus or Hugo admonition. Collapsible
+// admonitions (??? blocks and Hugo details or open=false shortcodes) become expands;
+// the others become panels.
+type AdmonitionNode struct {
+	ast.BaseBlock
+	Flavor      AdmonitionFlavor
+	Keyword     string
+	Title       string
+	Collapsible bool
+	opening     string
+	closing     string
+	bodyLines   []string
+}
+
+func (n *AdmonitionNode) Kind() ast.NodeKind {
+	return KindAdmonition
+}
+
+func (n *AdmonitionNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"Flavor":  string(n.Flavor),
+		"Keyword": n.Keyword,
+		"Title":   n.Title,
+	}, nil)
+}
+
+func (n *AdmonitionNode) Body() string {
+	return strings.Join(n.bodyLines, "\n")
+}
+
+// Literal returns the admonition as it was written, for when its detection is off.
+func (n *AdmonitionNode) Literal() string {
+	lines := []string{n.opening}
+	if n.Flavor == AdmonitionMkDocs {
+		for _, line := range n.bodyLines {
+			if line != "" {
+				line = "    " + line
+			}
+			lines = append(lines, line)
+		}
+	} else {
+		lines = append(lines, n.bodyLines...)
+	}
+	if n.closing != "" {
+		lines = append(lines, n.closing)
+	}
+	return strings.Join(lines, "\n")
+}
+
+type MkDocsAdmonitionParser struct{}
+
+func NewMkDocsAdmonitionParser() parser.BlockParser {
+	return &MkDocsAdmonitionParser{}
+}
+
+func (p *MkDocsAdmonitionParser) Trigger() []byte {
+	return []byte{'!', '?'}
+}
+
+func (p *MkDocsAdmonitionParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, _ := reader.PeekLine()
+	opening := strings.TrimLeft(trimLineEnding(string(line)), " \t")
+	match := mkDocsAdmonitionPattern.FindStringSubmatch(opening)
+	if match == nil {
+		return nil, parser.NoChildren
+	}
+
+	return &AdmonitionNode{
+		Flavor:      AdmonitionMkDocs,
+		Keyword:     strings.ToLower(match[2]),
+		Title:       strings.TrimSpace(match[3]),
+		Collapsible: strings.HasPrefix(match[1], "???"),
+		opening:     opening,
+	}, parser.NoChildren
+}
+
+// Continue collects the blank and indented lines after the opening line, which form the
+// body, and closes the admonition at the first line that is not indented.
+func (p *MkDocsAdmonitionParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	admonition := node.(*AdmonitionNode)
+	line, _ := reader.PeekLine()
+	rawLine := trimLineEnding(string(line))
+	switch {
+	case strings.TrimSpace(rawLine) == "":
+		admonition.bodyLines = append(admonition.bodyLines, "")
+	case strings.HasPrefix(rawLine, "    "):
+		admonition.bodyLines = append(admonition.bodyLines, rawLine[4:])
+	case strings.HasPrefix(rawLine, "\t"):
+		admonition.bodyLines = append(admonition.bodyLines, rawLine[1:])
+	default:
+		return parser.Close
+	}
+	return parser.Continue | parser.NoChildren
+}
+
+func (p *MkDocsAdmonitionParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {
+	admonition := node.(*AdmonitionNode)
+	admonition.bodyLines = trimTrailingBlankLines(admonition.bodyLines)
+}
+
+func (p *MkDocsAdmonitionParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (p *MkDocsAdmonitionParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+type DocusaurusAdmonitionParser struct{}
+
+func NewDocusaurusAdmonitionParser() parser.BlockParser {
+	return &DocusaurusAdmonitionParser{}
+}
+
+func (p *DocusaurusAdmonitionParser) Trigger() []byte {
+	return []byte{':'}
+}
+
+// Open reads a :::type fence up to the bare fence of the same length that closes it.
+// Admonitions nested with the same fence length are matched in pairs.
+func (p *DocusaurusAdmonitionParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, _ := reader.PeekLine()
+	opening := strings.TrimLeft(trimLineEnding(string(line)), " \t")
+	fenceLength, keyword, title, ok := parseDocusaurusOpening(opening)
+	if !ok {
+		return nil, parser.NoChildren
+	}
+
+	node := &AdmonitionNode{
+		Flavor:  AdmonitionDocusaurus,
+		Keyword: keyword,
+		Title:   title,
+		opening: opening,
+	}
+	reader.AdvanceLine()
+
+	depth := 1
+	for {
+		nextLine, _ := reader.PeekLine()
+		if len(nextLine) == 0 {
+			break
+		}
+		rawLine := trimLineEnding(string(nextLine))
+		leftTrimmed := strings.TrimLeft(rawLine, " \t")
+		if nestedLength, _, _, nested := parseDocusaurusOpening(leftTrimmed); nested && nestedLength == fenceLength {
+			depth++
+		} else if isPandocDivClosingFence(leftTrimmed, fenceLength) {
+			depth--
+			if depth == 0 {
+				// The parser moves past the closing line once Open returns.
+				node.closing = leftTrimmed
+				break
+			}
+		}
+		node.bodyLines = append(node.bodyLines, rawLine)
+		reader.AdvanceLine()
+	}
+
+	return node, parser.NoChildren
+}
+
+func (p *DocusaurusAdmonitionParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	return parser.Close
+}
+
+func (p *DocusaurusAdmonitionParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (p *DocusaurusAdmonitionParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (p *DocusaurusAdmonitionParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// parseDocusaurusOpening parses a :::type line with an optional [Title] or trailing
+// title. Only known admonition types match, so Pandoc fenced divs are left alone.
+func parseDocusaurusOpening(line string) (int, string, string, bool) {
+	match := docusaurusAdmonitionPattern.FindStringSubmatch(line)
+	if match == nil {
+		return 0, "", "", false
+	}
+	keyword := strings.ToLower(match[2])
+	if _, ok := admonitionPanelTypes[keyword]; !ok {
+		return 0, "", "", false
+	}
+	title := strings.TrimSpace(match[3])
+	if title == "" {
+		title = strings.TrimSpace(match[4])
+	}
+	return len(match[1]), keyword, title, true
+}
+
+type HugoAdmonitionParser struct{}
+
+func NewHugoAdmonitionParser() parser.BlockParser {
+	return &HugoAdmonitionParser{}
+}
+
+func (p *HugoAdmonitionParser) Trigger() []byte {
+	return []byte{'{'}
+}
+
+// Open reads an admonition or details shortcode up to its closing shortcode, matching
+// nested shortcodes of the same name in pairs.
+func (p *HugoAdmonitionParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, _ := reader.PeekLine()
+	opening := strings.TrimLeft(trimLineEnding(string(line)), " \t")
+	match := hugoShortcodeOpenPattern.FindStringSubmatch(opening)
+	if match == nil {
+		return nil, parser.NoChildren
+	}
+
+	name := match[2]
+	node := &AdmonitionNode{Flavor: AdmonitionHugo, opening: opening}
+	named, positional := parseHugoShortcodeParams(match[3])
+	if name == "details" {
+		node.Keyword = "note"
+		node.Title = named["summary"]
+		node.Collapsible = true
+	} else {
+		// The admonition shortcode takes type, title and open by name or by position.
+		for index, key := range []string{"type", "title", "open"} {
+			if _, ok := named[key]; !ok && index < len(positional) {
+				named[key] = positional[index]
+			}
+		}
+		node.Keyword = "note"
+		if admonitionType := strings.TrimSpace(named["type"]); admonitionType != "" {
+			node.Keyword = strings.ToLower(admonitionType)
+		}
+		node.Title = named["title"]
+		node.Collapsible = strings.EqualFold(named["open"], "false")
+	}
+	reader.AdvanceLine()
+
+	depth := 1
+	for {
+		nextLine, _ := reader.PeekLine()
+		if len(nextLine) == 0 {
+			break
+		}
+		rawLine := trimLineEnding(string(nextLine))
+		leftTrimmed := strings.TrimLeft(rawLine, " \t")
+		if nested := hugoShortcodeOpenPattern.FindStringSubmatch(leftTrimmed); nested != nil && nested[2] == name {
+			depth++
+		} else if closing := hugoShortcodeClosePattern.FindStringSubmatch(leftTrimmed); closing != nil && closing[1] == name {
+			depth--
+			if depth == 0 {
+				// The parser moves past the closing line once Open returns.
+				node.closing = leftTrimmed
+				break
+			}
+		}
+		node.bodyLines = append(node.bodyLines, rawLine)
+		reader.AdvanceLine()
+	}
+
+	return node, parser.NoChildren
+}
+
+func (p *HugoAdmonitionParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	return parser.Close
+}
+
+func (p *HugoAdmonitionParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (p *HugoAdmonitionParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (p *HugoAdmonitionParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// parseHugoShortcodeParams splits shortcode parameters into named and positional
+// values. Values may be bare, "quoted" with backslash escapes, or `raw`.
+func parseHugoShortcodeParams(raw string) (map[string]string, []string) {
+	named := map[string]string{}
+	var positional []string
+
+	rest := strings.TrimSpace(raw)
+	for rest != "" {
+		key := ""
+		if eq := strings.IndexByte(rest, '='); eq > 0 && !strings.ContainsAny(rest[:eq], " \t\"`") {
+			key = rest[:eq]
+			rest = rest[eq+1:]
+		}
+
+		var value string
+		switch {
+		case strings.HasPrefix(rest, `"`):
+			var sb strings.Builder
+			i := 1
+			for ; i < len(rest) && rest[i] != '"'; i++ {
+				if rest[i] == '\\' && i+1 < len(rest) {
+					i++
+				}
+				sb.WriteByte(rest[i])
+			}
+			value, rest = sb.String(), rest[min(i+1, len(rest)):]
+		case strings.HasPrefix(rest, "`"):
+			end := strings.IndexByte(rest[1:], '`')
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			value, rest = rest[1:end+1], rest[min(end+2, len(rest)):]
+		default:
+			end := strings.IndexAny(rest, " \t")
+			if end < 0 {
+				end = len(rest)
+			}
+			value, rest = rest[:end], rest[end:]
+		}
+
+		if key != "" {
+			named[key] = value
+		} else {
+			positional = append(positional, value)
+		}
+		rest = strings.TrimSpace(rest)
+	}
+	return named, positional
+}
+
+func trimTrailingBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,182 @@
+package mdconverter
+
+import (
+	"testing"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func convertReverseDoc(t *testing.T, cfg ReverseConfig, markdown string) converter.Doc {
+	t.Helper()
+
+	conv, err := New(cfg)
+	require.NoError(t, err)
+	result, err := conv.Convert(markdown)
+	require.NoError(t, err)
+	return decodeReverseDoc(t, result.ADF)
+}
+
+func TestMkDocsAdmonitionParsing(t *testing.T) {
+	doc := convertReverseDoc(t, ReverseConfig{PanelDetection: PanelDetectMkDocs, ExpandDetection: ExpandDetectMkDocs},
+		"!!! tip \"Heads up\"\n    First\n\n    - item\n\n"+
+			"??? danger\n    Hidden\n\n    ???+ note \"Inner\"\n        Deeper\n\n"+
+			"After")
+
+	require.Len(t, doc.Content, 3)
+	panel := doc.Content[0]
+	assert.Equal(t, "panel", panel.Type)
+	assert.Equal(t, "success", panel.Attrs["panelType"])
+	assert.Equal(t, "Heads up", panel.Attrs["title"])
+	require.Len(t, panel.Content, 2)
+	assert.Equal(t, "bulletList", panel.Content[1].Type)
+
+	expand := doc.Content[1]
+	assert.Equal(t, "expand", expand.Type)
+	assert.Nil(t, expand.Attrs)
+	require.Len(t, expand.Content, 2)
+	assert.Equal(t, "nestedExpand", expand.Content[1].Type)
+	assert.Equal(t, "Inner", expand.Content[1].Attrs["title"])
+
+	assert.Equal(t, "paragraph", doc.Content[2].Type)
+	assert.Equal(t, "After", doc.Content[2].Content[0].Text)
+}
+
+func TestDocusaurusAdmonitionParsing(t *testing.T) {
+	doc := convertReverseDoc(t, ReverseConfig{PanelDetection: PanelDetectDocusaurus, ExpandDetection: ExpandDetectDocusaurus},
+		"::::warning[Careful]\n\nOuter\n\n:::info Inner\n\nDeep\n\n:::\n\n::::\n\n"+
+			":::caution\nSame length\n:::note\nNested\n:::\n:::\n\n"+
+			"<details><summary>More</summary>\n\nhidden\n\n</details>\n")
+
+	require.Len(t, doc.Content, 3)
+	outer := doc.Content[0]
+	assert.Equal(t, "panel", outer.Type)
+	assert.Equal(t, "warning", outer.Attrs["panelType"])
+	assert.Equal(t, "Careful", outer.Attrs["title"])
+	require.Len(t, outer.Content, 2)
+	assert.Equal(t, "info", outer.Content[1].Attrs["panelType"])
+	assert.Equal(t, "Inner", outer.Content[1].Attrs["title"])
+
+	sameLength := doc.Content[1]
+	require.Len(t, sameLength.Content, 2)
+	assert.Equal(t, "warning", sameLength.Attrs["panelType"])
+	assert.Equal(t, "note", sameLength.Content[1].Attrs["panelType"])
+
+	assert.Equal(t, "expand", doc.Content[2].Type)
+	assert.Equal(t, "More", doc.Content[2].Attrs["title"])
+}
+
+func TestDocusaurusAdmonitionLeavesPandocDivsAlone(t *testing.T) {
+	doc := convertReverseDoc(t, ReverseConfig{PanelDetection: PanelDetectDocusaurus, ExpandDetection: ExpandDetectPandoc},
+		":::{.details summary=\"More\"}\nhidden\n:::\n")
+
+	require.Len(t, doc.Content, 1)
+	assert.Equal(t, "expand", doc.Content[0].Type)
+	assert.Equal(t, "More", doc.Content[0].Attrs["title"])
+}
+
+func TestHugoAdmonitionParsing(t *testing.T) {
+	doc := convertReverseDoc(t, ReverseConfig{PanelDetection: PanelDetectHugo, ExpandDetection: ExpandDetectHugo},
+		"{{< admonition type=\"warning\" title=\"Say \\\"when\\\"\" >}}\nBody\n{{< /admonition >}}\n\n"+
+			"{{% admonition bug `Raw title` false %}}\nCollapsed\n{{% /admonition %}}\n\n"+
+			"{{< details summary=\"More\" >}}\nOuter\n{{< details summary=\"Inner\" >}}\nInner body\n{{< /details >}}\n{{< /details >}}\nTail\n")
+
+	require.Len(t, doc.Content, 4)
+	assert.Equal(t, "panel", doc.Content[0].Type)
+	assert.Equal(t, "warning", doc.Content[0].Attrs["panelType"])
+	assert.Equal(t, `Say "when"`, doc.Content[0].Attrs["title"])
+
+	assert.Equal(t, "expand", doc.Content[1].Type)
+	assert.Equal(t, "Raw title", doc.Content[1].Attrs["title"])
+
+	details := doc.Content[2]
+	assert.Equal(t, "expand", details.Type)
+	require.Len(t, details.Content, 2)
+	assert.Equal(t, "nestedExpand", details.Content[1].Type)
+	assert.Equal(t, "Inner", details.Content[1].Attrs["title"])
+	assert.Equal(t, "Tail", doc.Content[3].Content[0].Text)
+}
+
+func TestEmptyAdmonitionsGetAnEmptyParagraph(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      ReverseConfig
+		markdown string
+		nodeType string
+	}{
+		{"mkdocs panel", ReverseConfig{PanelDetection: PanelDetectMkDocs}, "!!! note \"Title\"\n", "panel"},
+		{"mkdocs expand", ReverseConfig{ExpandDetection: ExpandDetectMkDocs}, "??? note \"Title\"\n", "expand"},
+		{"docusaurus", ReverseConfig{PanelDetection: PanelDetectDocusaurus}, ":::tip Title\n:::\n", "panel"},
+		{"hugo", ReverseConfig{PanelDetection: PanelDetectHugo}, "{{< admonition type=\"note\" title=\"Title\" >}}\n{{< /admonition >}}\n", "panel"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := convertReverseDoc(t, tt.cfg, tt.markdown)
+
+			require.Len(t, doc.Content, 1)
+			assert.Equal(t, tt.nodeType, doc.Content[0].Type)
+			assert.Equal(t, "Title", doc.Content[0].Attrs["title"])
+			assert.Equal(t, []converter.Node{{Type: "paragraph"}}, doc.Content[0].Content)
+		})
+	}
+}
+
+func TestAllDetectionLeavesAdmonitionsAlone(t *testing.T) {
+	doc := convertReverseDoc(t, ReverseConfig{
+		PanelDetection:  PanelDetectAll,
+		ExpandDetection: ExpandDetectAll,
+	}, "!!! note \"Title\"\n    Body\n\n:::tip\nBody\n:::\n")
+
+	for _, node := range doc.Content {
+		assert.NotEqual(t, "expand", node.Type)
+		assert.NotEqual(t, "panel", node.Type)
+	}
+}
+
+func TestAdmonitionFallsBackToTextWhenDetectionIsOff(t *testing.T) {
+	doc := convertReverseDoc(t, ReverseConfig{PanelDetection: PanelDetectMkDocs, ExpandDetection: ExpandDetectNone},
+		"??? note \"Title\"\n    Body\n")
+
+	require.Len(t, doc.Content, 1)
+	assert.Equal(t, "paragraph", doc.Content[0].Type)
+	assert.Equal(t, "??? note \"Title\"\n    Body", doc.Content[0].Content[0].Text)
+}
+
+func TestAdmonitionRoundTrip(t *testing.T) {
+	input := []byte(`{"version":1,"type":"doc","content":[
+		{"type":"panel","attrs":{"panelType":"warning","title":"Careful"},"content":[
+			{"type":"paragraph","content":[{"type":"text","text":"Outer"}]},
+			{"type":"panel","attrs":{"panelType":"info"},"content":[{"type":"paragraph","content":[{"type":"text","text":"Inner"}]}]}
+		]},
+		{"type":"expand","attrs":{"title":"More"},"content":[{"type":"paragraph","content":[{"type":"text","text":"hidden"}]}]}
+	]}`)
+
+	for _, flavor := range []string{"mkdocs", "docusaurus", "hugo"} {
+		t.Run(flavor, func(t *testing.T) {
+			forward, err := converter.New(converter.Config{
+				PanelStyle:  converter.PanelStyle(flavor),
+				ExpandStyle: converter.ExpandStyle(flavor),
+			})
+			require.NoError(t, err)
+			markdown, err := forward.Convert(input)
+			require.NoError(t, err)
+
+			doc := convertReverseDoc(t, ReverseConfig{
+				PanelDetection:  PanelDetection(flavor),
+				ExpandDetection: ExpandDetection(flavor),
+			}, markdown.Markdown)
+
+			require.Len(t, doc.Content, 2, markdown.Markdown)
+			panel := doc.Content[0]
+			assert.Equal(t, "panel", panel.Type)
+			assert.Equal(t, "warning", panel.Attrs["panelType"])
+			assert.Equal(t, "Careful", panel.Attrs["title"])
+			require.Len(t, panel.Content, 2)
+			assert.Equal(t, "info", panel.Content[1].Attrs["panelType"])
+
+			assert.Equal(t, "expand", doc.Content[1].Type)
+			assert.Equal(t, "More", doc.Content[1].Attrs["title"])
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package mdconverter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/yuin/goldmark/text"
+)
+
+// BatchResolver resolves every link and image reference of a document in a single call
+// before ADF is built. It is an alternative to calling LinkHook and MediaHook once per
+// reference, for resolvers backed by remote APIs.
+type BatchResolver interface {
+	ResolveBatch(ctx context.Context, req BatchRequest) (BatchResponse, error)
+}
+
+// BatchResolverFunc adapts a plain function to the BatchResolver interface.
+type BatchResolverFunc func(ctx context.Context, req BatchRequest) (BatchResponse, error)
+
+// ResolveBatch calls f(ctx, req).
+func (f BatchResolverFunc) ResolveBatch(ctx context.Context, req BatchRequest) (BatchResponse, error) {
+	return f(ctx, req)
+}
+
+// BatchRequest lists the unique references collected from a document.
+// Inputs are identical to what LinkHook and MediaHook would receive.
+type BatchRequest struct {
+	SourcePath       string
+	DocumentMetadata map[string]any `json:"-"`
+	Links            []LinkParseInput
+	Media            []MediaParseInput
+}
+
+// BatchResponse holds resolutions aligned by index with BatchRequest.Links and
+// BatchRequest.Media. References without a resolution (shorter slices) fall back to
+// the per-reference hooks, if configured.
+type BatchResponse struct {
+	Links []LinkResolution
+	Media []MediaResolution
+}
+
+// LinkResolution is the batch equivalent of a LinkHook return value.
+// Err follows the same ErrUnresolved/ResolutionMode semantics as LinkHook.
+type LinkResolution struct {
+	Output LinkParseOutput
+	Err    error
+}
+
+// MediaResolution is the batch equivalent of a MediaHook return value.
+// Err follows the same ErrUnresolved/ResolutionMode semantics as MediaHook.
+type MediaResolution struct {
+	Output MediaParseOutput
+	Err    error
+}
+
+type linkBatchKey struct {
+	destination string
+	title       string
+	text        string
+	kind        string
+}
+
+type mediaBatchKey struct {
+	destination string
+	alt         string
+	kind        string
+}
+
+// batchResolutions holds resolved references for a single conversion.
+type batchResolutions struct {
+	links map[linkBatchKey]LinkResolution
+	media map[mediaBatchKey]MediaResolution
+}
+
+// batchCollector records hook inputs during the collection pass.
+type batchCollector struct {
+	req       BatchRequest
+	seenLinks map[linkBatchKey]bool
+	seenMedia map[mediaBatchKey]bool
+}
+
+func newLinkBatchKey(in LinkParseInput) linkBatchKey {
+	kind, _ := in.Raw["kind"].(string)
+	return linkBatchKey{destination: in.Destination, title: in.Title, text: in.Text, kind: kind}
+}
+
+func newMediaBatchKey(in MediaParseInput) mediaBatchKey {
+	kind, _ := in.Raw["kind"].(string)
+	return mediaBatchKey{destination: in.Destination, alt: in.Alt, kind: kind}
+}
+
+func (b *batchResolutions) lookupLink(in LinkParseInput) (LinkResolution, bool) {
+	if b == nil {
+		return LinkResolution{}, false
+	}
+	resolution, ok := b.links[newLinkBatchKey(in)]
+	return resolution, ok
+}
+
+func (b *batchResolutions) lookupMedia(in MediaParseInput) (MediaResolution, bool) {
+	if b == nil {
+		return MediaResolution{}, false
+	}
+	resolution, ok := b.media[newMediaBatchKey(in)]
+	return resolution, ok
+}
+
+func (c *batchCollector) addLink(in LinkParseInput) {
+	key := newLinkBatchKey(in)
+	if c.seenLinks[key] {
+		return
+	}
+	c.seenLinks[key] = true
+	c.req.Links = append(c.req.Links, in)
+}
+
+func (c *batchCollector) addMedia(in MediaParseInput) {
+	key := newMediaBatchKey(in)
+	if c.seenMedia[key] {
+		return
+	}
+	c.seenMedia[key] = true
+	c.req.Media = append(c.req.Media, in)
+}
+
+// resolveBatch runs a collection pass over the document, which records every link and
+// media hook input without resolving it, then resolves them in one call. References
+// already present in HookCache are not requested. Extension handlers are not invoked
+// during the collection pass.
+func (s *state) resolveBatch() error {
+	if s.config.BatchResolver == nil {
+		return nil
+	}
+
+	collector := &batchCollector{
+		req:       BatchRequest{SourcePath: s.options.SourcePath, DocumentMetadata: s.metadata},
+		seenLinks: make(map[linkBatchKey]bool),
+		seenMedia: make(map[mediaBatchKey]bool),
+	}
+	collecting := &state{
+		config:    s.config,
+		ctx:       s.ctx,
+		options:   s.options,
+		metadata:  s.metadata,
+		source:    s.source,
+		parser:    s.parser,
+		collector: collector,
+	}
+	root := collecting.parser.Parser().Parse(text.NewReader(collecting.source))
+	if _, err := collecting.convertDocument(root); err != nil {
+		return err
+	}
+
+	batch := &batchResolutions{
+		links: make(map[linkBatchKey]LinkResolution),
+		media: make(map[mediaBatchKey]MediaResolution),
+	}
+	req := BatchRequest{SourcePath: s.options.SourcePath, DocumentMetadata: s.metadata}
+	for _, input := range collector.req.Links {
+		if output, err, ok := converter.LookupHookCache[LinkParseInput, LinkParseOutput](s.config.HookCache, linkParseCacheKind, input); ok {
+			batch.links[newLinkBatchKey(input)] = LinkResolution{Output: output, Err: err}
+			continue
+		}
+		req.Links = append(req.Links, input)
+	}
+	for _, input := range collector.req.Media {
+		if output, err, ok := converter.LookupHookCache[MediaParseInput, MediaParseOutput](s.config.HookCache, mediaParseCacheKind, input); ok {
+			batch.media[newMediaBatchKey(input)] = MediaResolution{Output: output, Err: err}
+			continue
+		}
+		req.Media = append(req.Media, input)
+	}
+	s.batch = batch
+	if len(req.Links) == 0 && len(req.Media) == 0 {
+		return nil
+	}
+	if err := s.checkContext(); err != nil {
+		return err
+	}
+
+	resp, err := s.config.BatchResolver.ResolveBatch(s.ctx, req)
+	if err != nil {
+		return fmt.Errorf("batch resolver failed: %w", err)
+	}
+	if len(resp.Links) > len(req.Links) || len(resp.Media) > len(req.Media) {
+		return fmt.Errorf("batch resolver returned more resolutions than requested references")
+	}
+
+	for index, resolution := range resp.Links {
+		batch.links[newLinkBatchKey(req.Links[index])] = resolution
+	}
+	for index, resolution := range resp.Media {
+		batch.media[newMediaBatchKey(req.Media[index])] = resolution
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package mdconverter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchResolverResolvesAllReferencesInOneCall(t *testing.T) {
+	markdown := "[Page](../page.md) and [Page](../page.md)\n\n![Diagram](./diagram.png)\n\n::: { .details summary=\"More\" }\n\n[Other](../other.md)\n\n:::\n"
+
+	calls := 0
+	conv := newHookReverseConverter(t, ReverseConfig{
+		ExpandDetection: ExpandDetectPandoc,
+		LinkHook: func(context.Context, LinkParseInput) (LinkParseOutput, error) {
+			t.Fatal("per-link hook must not be called for batch-resolved references")
+			return LinkParseOutput{}, nil
+		},
+		BatchResolver: BatchResolverFunc(func(_ context.Context, req BatchRequest) (BatchResponse, error) {
+			calls++
+			assert.Equal(t, "docs/page.md", req.SourcePath)
+			require.Len(t, req.Links, 2)
+			assert.Equal(t, "../page.md", req.Links[0].Destination)
+			assert.Equal(t, "page.md", req.Links[0].Meta.Filename)
+			assert.Equal(t, "../other.md", req.Links[1].Destination)
+			require.Len(t, req.Media, 1)
+			assert.Equal(t, "./diagram.png", req.Media[0].Destination)
+
+			return BatchResponse{
+				Links: []LinkResolution{
+					{Output: LinkParseOutput{Destination: "https://confluence.example/pages/1", Handled: true}},
+					{Err: ErrUnresolved},
+				},
+				Media: []MediaResolution{
+					{Output: MediaParseOutput{MediaType: "file", ID: "att-9", Handled: true}},
+				},
+			}, nil
+		}),
+	})
+
+	result, err := conv.ConvertWithContext(context.Background(), markdown, ConvertOptions{SourcePath: "docs/page.md"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	doc := decodeADFDoc(t, result.ADF)
+	require.Len(t, doc.Content, 3)
+	first := doc.Content[0].Content[0]
+	assert.Equal(t, "https://confluence.example/pages/1", first.Marks[0].Attrs["href"])
+	assert.Equal(t, "att-9", doc.Content[1].Content[0].Attrs["id"])
+
+	expand := doc.Content[2]
+	assert.Equal(t, "expand", expand.Type)
+	assert.Equal(t, "../other.md", expand.Content[0].Content[0].Marks[0].Attrs["href"])
+
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, converter.WarningUnresolvedReference, result.Warnings[0].Type)
+}
+
+func TestBatchResolverStrictUnresolvedFailsConversion(t *testing.T) {
+	conv := newHookReverseConverter(t, ReverseConfig{
+		ResolutionMode: ResolutionStrict,
+		BatchResolver: BatchResolverFunc(func(context.Context, BatchRequest) (BatchResponse, error) {
+			return BatchResponse{Media: []MediaResolution{{Err: ErrUnresolved}}}, nil
+		}),
+	})
+
+	_, err := conv.Convert("![Diagram](./diagram.png)")
+	require.ErrorIs(t, err, ErrUnresolved)
+}
+
+func TestBatchResolverFallsBackToHooksAndReportsErrors(t *testing.T) {
+	conv := newHookReverseConverter(t, ReverseConfig{
+		LinkHook: func(_ context.Context, in LinkParseInput) (LinkParseOutput, error) {
+			assert.Equal(t, "../b.md", in.Destination)
+			return LinkParseOutput{Destination: "https://example.com/b", Handled: true}, nil
+		},
+		BatchResolver: BatchResolverFunc(func(context.Context, BatchRequest) (BatchResponse, error) {
+			return BatchResponse{Links: []LinkResolution{{Output: LinkParseOutput{Destination: "https://example.com/a", Handled: true}}}}, nil
+		}),
+	})
+
+	result, err := conv.Convert("[A](../a.md) [B](../b.md)")
+	require.NoError(t, err)
+	doc := decodeADFDoc(t, result.ADF)
+	paragraph := doc.Content[0]
+	assert.Equal(t, "https://example.com/a", paragraph.Content[0].Marks[0].Attrs["href"])
+	assert.Equal(t, "https://example.com/b", paragraph.Content[2].Marks[0].Attrs["href"])
+
+	conv = newHookReverseConverter(t, ReverseConfig{
+		BatchResolver: BatchResolverFunc(func(context.Context, BatchRequest) (BatchResponse, error) {
+			return BatchResponse{}, errors.New("api down")
+		}),
+	})
+	_, err = conv.Convert("[A](../a.md)")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "batch resolver failed: api down")
+}
@@ -8,6 +8,10 @@ import (
 )
 
 func (s *state) convertParagraphNode(node *ast.Paragraph) (converter.Node, bool, error) {
+	if tocNode, ok := s.tryConvertTOCParagraph(node); ok {
+		return tocNode, true, nil
+	}
+
 	content, err := s.convertInlineChildren(node, newMarkStack())
 	if err != nil {
 		return converter.Node{}, false, err
@@ -27,6 +31,10 @@ func (s *state) convertParagraphNode(node *ast.Paragraph) (converter.Node, bool,
 }
 
 func (s *state) convertTextBlockNode(node *ast.TextBlock) (converter.Node, bool, error) {
+	// The parser leaves an empty text block where link reference definitions were.
+	if node.Lines().Len() == 0 && !node.HasChildren() {
+		return converter.Node{}, false, nil
+	}
 	content, err := s.convertInlineChildren(node, newMarkStack())
 	if err != nil {
 		return converter.Node{}, false, err
@@ -115,6 +123,12 @@ func (s *state) convertBlockquoteNode(node *ast.Blockquote) (converter.Node, boo
 		return converter.Node{}, false, err
 	}
 
+	if alertNode, ok, err := s.tryConvertGitLabAlert(node, content); err != nil || ok {
+		return alertNode, ok, err
+	}
+	if calloutNode, ok, err := s.tryConvertObsidianCallout(node, content); err != nil || ok {
+		return calloutNode, ok, err
+	}
 	if panelNode, ok := s.tryConvertPanelBlockquote(content); ok {
 		return panelNode, true, nil
 	}
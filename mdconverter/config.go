@@ -47,7 +47,10 @@ const (
 	ColorDetectNone   ColorDetection = "none"
 	ColorDetectHTML   ColorDetection = "html"
 	ColorDetectPandoc ColorDetection = "pandoc"
-	ColorDetectAll    ColorDetection = "all"
+	// ColorDetectObsidian detects Obsidian ==highlights== as backgroundColor marks.
+	ColorDetectObsidian ColorDetection = "obsidian"
+	// ColorDetectAll detects HTML and Pandoc colors; Obsidian highlights must be selected.
+	ColorDetectAll ColorDetection = "all"
 )
 
 // AlignmentDetection controls how alignment is reconstructed.
@@ -77,7 +80,9 @@ const (
 	StatusDetectNone    StatusDetection = "none"
 	StatusDetectBracket StatusDetection = "bracket"
 	StatusDetectText    StatusDetection = "text"
-	StatusDetectAll     StatusDetection = "all"
+	// StatusDetectGitLab detects GitLab colour chips followed by bold text (`#0747a6` **DONE**).
+	StatusDetectGitLab StatusDetection = "gitlab"
+	StatusDetectAll    StatusDetection = "all"
 )
 
 // DateDetection controls how date nodes are reconstructed.
@@ -97,7 +102,20 @@ const (
 	PanelDetectBold   PanelDetection = "bold"
 	PanelDetectGitHub PanelDetection = "github"
 	PanelDetectTitle  PanelDetection = "title"
-	PanelDetectAll    PanelDetection = "all"
+	// PanelDetectMkDocs detects MkDocs Material admonitions (!!! note "Title").
+	PanelDetectMkDocs PanelDetection = "mkdocs"
+	// PanelDetectDocusaurus detects Docusaurus admonitions (:::tip Title).
+	PanelDetectDocusaurus PanelDetection = "docusaurus"
+	// PanelDetectHugo detects Hugo admonition shortcodes.
+	PanelDetectHugo PanelDetection = "hugo"
+	// PanelDetectObsidian detects Obsidian callouts (> [!tip] Title).
+	PanelDetectObsidian PanelDetection = "obsidian"
+	// PanelDetectGitLab detects GitLab alerts (> [!caution] Title). The all setting reads
+	// them as GitHub callouts instead, since the type names overlap.
+	PanelDetectGitLab PanelDetection = "gitlab"
+	// PanelDetectAll detects the bold, GitHub and title forms. Docs-site admonitions and
+	// Obsidian callouts must be selected on their own.
+	PanelDetectAll PanelDetection = "all"
 )
 
 // LayoutSectionDetection controls how layout sections are reconstructed.
@@ -132,7 +150,17 @@ const (
 	ExpandDetectBlockquote ExpandDetection = "blockquote"
 	ExpandDetectHTML       ExpandDetection = "html"
 	ExpandDetectPandoc     ExpandDetection = "pandoc"
-	ExpandDetectAll        ExpandDetection = "all"
+	// ExpandDetectMkDocs detects collapsible MkDocs Material admonitions (??? and ???+).
+	ExpandDetectMkDocs ExpandDetection = "mkdocs"
+	// ExpandDetectDocusaurus detects <details> blocks, as ExpandDetectHTML does.
+	ExpandDetectDocusaurus ExpandDetection = "docusaurus"
+	// ExpandDetectHugo detects Hugo admonition shortcodes with open=false.
+	ExpandDetectHugo ExpandDetection = "hugo"
+	// ExpandDetectObsidian detects foldable Obsidian callouts (> [!note]- Title).
+	ExpandDetectObsidian ExpandDetection = "obsidian"
+	// ExpandDetectAll detects the blockquote, HTML and Pandoc forms. Docs-site admonitions
+	// and Obsidian callouts must be selected on their own.
+	ExpandDetectAll ExpandDetection = "all"
 )
 
 // InlineCardDetection controls how inline cards are reconstructed.
@@ -155,6 +183,15 @@ const (
 	DecisionDetectAll   DecisionDetection = "all"
 )
 
+// FrontMatterDetection controls whether a leading front matter block is read into
+// Result.Metadata.
+type FrontMatterDetection string
+
+const (
+	FrontMatterDetectNone FrontMatterDetection = "none"
+	FrontMatterDetectYAML FrontMatterDetection = "yaml"
+)
+
 // ReverseConfig configures Markdown to ADF conversion behavior.
 type ReverseConfig struct {
 	MentionDetection         MentionDetection         `json:"mentionDetection,omitempty"`
@@ -171,7 +208,13 @@ type ReverseConfig struct {
 	ExpandDetection          ExpandDetection          `json:"expandDetection,omitempty"`
 	InlineCardDetection      InlineCardDetection      `json:"inlineCardDetection,omitempty"`
 	TableGridDetection       bool                     `json:"tableGridDetection,omitempty"`
+	WikiLinkDetection        bool                     `json:"wikiLinkDetection,omitempty"`
+	CommentDetection         bool                     `json:"commentDetection,omitempty"`
+	MultilineQuoteDetection  bool                     `json:"multilineQuoteDetection,omitempty"`
+	TOCDetection             bool                     `json:"tocDetection,omitempty"`
+	MathBlockDetection       bool                     `json:"mathBlockDetection,omitempty"`
 	DecisionDetection        DecisionDetection        `json:"decisionDetection,omitempty"`
+	FrontMatterDetection     FrontMatterDetection     `json:"frontMatterDetection,omitempty"`
 
 	DateFormat        string                                `json:"dateFormat,omitempty"`
 	HeadingOffset     int                                   `json:"headingOffset,omitempty"`
@@ -182,6 +225,8 @@ type ReverseConfig struct {
 	ResolutionMode    ResolutionMode                        `json:"resolutionMode,omitempty"`
 	LinkHook          LinkParseHook                         `json:"-"`
 	MediaHook         MediaParseHook                        `json:"-"`
+	BatchResolver     BatchResolver                         `json:"-"`
+	HookCache         HookCache                             `json:"-"`
 	ExtensionHandlers map[string]converter.ExtensionHandler `json:"-"`
 }
 
@@ -230,6 +275,9 @@ func (c ReverseConfig) applyDefaults() ReverseConfig {
 	if c.DecisionDetection == "" {
 		c.DecisionDetection = DecisionDetectEmoji
 	}
+	if c.FrontMatterDetection == "" {
+		c.FrontMatterDetection = FrontMatterDetectYAML
+	}
 	if c.DateFormat == "" {
 		c.DateFormat = "2006-01-02"
 	}
@@ -247,6 +295,8 @@ func (c ReverseConfig) clone() ReverseConfig {
 	cloned.EmojiRegistry = cloneStringMap(c.EmojiRegistry)
 	cloned.LinkHook = c.LinkHook
 	cloned.MediaHook = c.MediaHook
+	cloned.BatchResolver = c.BatchResolver
+	cloned.HookCache = c.HookCache
 	cloned.ExtensionHandlers = cloneExtensionHandlerMap(c.ExtensionHandlers)
 	return cloned
 }
@@ -279,6 +329,7 @@ func (c ReverseConfig) Validate() error {
 	if c.ColorDetection != ColorDetectNone &&
 		c.ColorDetection != ColorDetectHTML &&
 		c.ColorDetection != ColorDetectPandoc &&
+		c.ColorDetection != ColorDetectObsidian &&
 		c.ColorDetection != ColorDetectAll {
 		return fmt.Errorf("invalid colorDetection %q", c.ColorDetection)
 	}
@@ -300,6 +351,7 @@ func (c ReverseConfig) Validate() error {
 	if c.StatusDetection != StatusDetectNone &&
 		c.StatusDetection != StatusDetectBracket &&
 		c.StatusDetection != StatusDetectText &&
+		c.StatusDetection != StatusDetectGitLab &&
 		c.StatusDetection != StatusDetectAll {
 		return fmt.Errorf("invalid statusDetection %q", c.StatusDetection)
 	}
@@ -314,6 +366,11 @@ func (c ReverseConfig) Validate() error {
 		c.PanelDetection != PanelDetectBold &&
 		c.PanelDetection != PanelDetectGitHub &&
 		c.PanelDetection != PanelDetectTitle &&
+		c.PanelDetection != PanelDetectMkDocs &&
+		c.PanelDetection != PanelDetectDocusaurus &&
+		c.PanelDetection != PanelDetectHugo &&
+		c.PanelDetection != PanelDetectObsidian &&
+		c.PanelDetection != PanelDetectGitLab &&
 		c.PanelDetection != PanelDetectAll {
 		return fmt.Errorf("invalid panelDetection %q", c.PanelDetection)
 	}
@@ -341,6 +398,10 @@ func (c ReverseConfig) Validate() error {
 		c.ExpandDetection != ExpandDetectBlockquote &&
 		c.ExpandDetection != ExpandDetectHTML &&
 		c.ExpandDetection != ExpandDetectPandoc &&
+		c.ExpandDetection != ExpandDetectMkDocs &&
+		c.ExpandDetection != ExpandDetectDocusaurus &&
+		c.ExpandDetection != ExpandDetectHugo &&
+		c.ExpandDetection != ExpandDetectObsidian &&
 		c.ExpandDetection != ExpandDetectAll {
 		return fmt.Errorf("invalid expandDetection %q", c.ExpandDetection)
 	}
@@ -358,6 +419,10 @@ func (c ReverseConfig) Validate() error {
 		c.DecisionDetection != DecisionDetectAll {
 		return fmt.Errorf("invalid decisionDetection %q", c.DecisionDetection)
 	}
+	if c.FrontMatterDetection != FrontMatterDetectNone &&
+		c.FrontMatterDetection != FrontMatterDetectYAML {
+		return fmt.Errorf("invalid frontMatterDetection %q", c.FrontMatterDetection)
+	}
 
 	if c.HeadingOffset < -5 || c.HeadingOffset > 5 {
 		return fmt.Errorf("headingOffset must be between -5 and 5, got %d", c.HeadingOffset)
@@ -408,6 +473,20 @@ func (c ReverseConfig) needsPandocBlockExtension() bool {
 		len(c.ExtensionHandlers) > 0
 }
 
+// needsAdmonitionParser reports whether panels or expands of a docs-site flavor are
+// detected, which needs that flavor's block parser. The all settings do not include them.
+func (c ReverseConfig) needsAdmonitionParser(panels PanelDetection, expands ExpandDetection) bool {
+	return c.PanelDetection == panels || c.ExpandDetection == expands
+}
+
+func (c ReverseConfig) needsStatusChipParser() bool {
+	return c.StatusDetection == StatusDetectGitLab || c.StatusDetection == StatusDetectAll
+}
+
+func (c ReverseConfig) needsHighlightParser() bool {
+	return c.ColorDetection == ColorDetectObsidian
+}
+
 func hasDateReferenceTokens(format string) bool {
 	format = strings.TrimSpace(format)
 	if format == "" {
@@ -0,0 +1,39 @@
+package mdconverter
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// splitFrontMatter separates a YAML front matter block at the very start of markdown from
+// the body. The block opens with a "---" line and closes with a "---" or "..." line, and
+// must hold a non-empty YAML mapping. The block's lines are kept as blank lines so that
+// line numbers in the body do not shift. found is false, and markdown is returned
+// unchanged, when there is no such block; a block holding only comments, such as a
+// "# Title" between two rules, is Markdown.
+func splitFrontMatter(markdown string) (metadata map[string]any, body string, found bool) {
+	markdown = strings.TrimPrefix(markdown, "\ufeff")
+	first, rest, ok := strings.Cut(markdown, "\n")
+	if !ok || strings.TrimRight(first, " \t\r") != "---" {
+		return nil, markdown, false
+	}
+
+	var yamlLines []string
+	for lineCount := 1; ; lineCount++ {
+		line, next, more := strings.Cut(rest, "\n")
+		trimmed := strings.TrimRight(line, " \t\r")
+		if trimmed == "---" || trimmed == "..." {
+			err := yaml.Unmarshal([]byte(strings.Join(yamlLines, "\n")), &metadata)
+			if err != nil || len(metadata) == 0 {
+				return nil, markdown, false
+			}
+			return metadata, strings.Repeat("\n", lineCount+1) + next, true
+		}
+		if !more {
+			return nil, markdown, false
+		}
+		yamlLines = append(yamlLines, line)
+		rest = next
+	}
+}
@@ -0,0 +1,139 @@
+package mdconverter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertFrontMatter(t *testing.T) {
+	input := "---\ntitle: Design\npageId: \"12345\"\nlabels: [api, draft]\nversion: 7\n---\n\n# Heading\n\nBody\n"
+
+	result, err := newHookReverseConverter(t, ReverseConfig{}).Convert(input)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"title":   "Design",
+		"pageId":  "12345",
+		"labels":  []any{"api", "draft"},
+		"version": 7,
+	}, result.Metadata)
+
+	doc := decodeADFDoc(t, result.ADF)
+	require.Len(t, doc.Content, 2)
+	assert.Equal(t, "heading", doc.Content[0].Type)
+	assert.Equal(t, "paragraph", doc.Content[1].Type)
+}
+
+func TestConvertFrontMatterRequiresMapping(t *testing.T) {
+	for name, input := range map[string]string{
+		"scalar":     "---\nJust text\n---\n",
+		"unclosed":   "---\ntitle: Design\n",
+		"not first":  "Intro\n\n---\ntitle: Design\n---\n",
+		"invalid":    "---\ntitle: [unterminated\n---\n",
+		"thematic":   "---\n\nParagraph\n",
+		"dots close": "---\ntitle: Design\n...\nBody\n",
+		"comments":   "---\n# Title\n---\n\nBody\n",
+		"empty":      "---\n---\n\nBody\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := newHookReverseConverter(t, ReverseConfig{}).Convert(input)
+			require.NoError(t, err)
+			if name == "dots close" {
+				assert.Equal(t, map[string]any{"title": "Design"}, result.Metadata)
+				return
+			}
+			assert.Nil(t, result.Metadata)
+			assert.NotEmpty(t, decodeADFDoc(t, result.ADF).Content)
+		})
+	}
+}
+
+func TestCommentOnlyFrontMatterStaysMarkdown(t *testing.T) {
+	result, err := newHookReverseConverter(t, ReverseConfig{}).Convert("---\n# Title\n---\n\nBody\n")
+	require.NoError(t, err)
+	assert.Nil(t, result.Metadata)
+
+	doc := decodeADFDoc(t, result.ADF)
+	require.Len(t, doc.Content, 4)
+	assert.Equal(t, "rule", doc.Content[0].Type)
+	assert.Equal(t, "heading", doc.Content[1].Type)
+	assert.Equal(t, "Title", doc.Content[1].Content[0].Text)
+}
+
+func TestFrontMatterDetectionNone(t *testing.T) {
+	result, err := newHookReverseConverter(t, ReverseConfig{FrontMatterDetection: FrontMatterDetectNone}).Convert("---\ntitle: Design\n---\n\nBody\n")
+	require.NoError(t, err)
+	assert.Nil(t, result.Metadata)
+
+	doc := decodeADFDoc(t, result.ADF)
+	require.NotEmpty(t, doc.Content)
+	assert.Equal(t, "rule", doc.Content[0].Type)
+}
+
+func TestHooksReceiveFrontMatter(t *testing.T) {
+	input := "---\nspaceKey: ENG\n---\n\n[page](https://example.com/page) ![img](https://example.com/a.png)\n"
+	want := map[string]any{"spaceKey": "ENG"}
+
+	var linkMetadata, mediaMetadata map[string]any
+	conv := newHookReverseConverter(t, ReverseConfig{
+		LinkHook: func(ctx context.Context, in LinkParseInput) (LinkParseOutput, error) {
+			linkMetadata = in.DocumentMetadata
+			return LinkParseOutput{}, nil
+		},
+		MediaHook: func(ctx context.Context, in MediaParseInput) (MediaParseOutput, error) {
+			mediaMetadata = in.DocumentMetadata
+			return MediaParseOutput{}, nil
+		},
+	})
+	_, err := conv.Convert(input)
+	require.NoError(t, err)
+	assert.Equal(t, want, linkMetadata)
+	assert.Equal(t, want, mediaMetadata)
+
+	var request BatchRequest
+	conv = newHookReverseConverter(t, ReverseConfig{BatchResolver: BatchResolverFunc(func(ctx context.Context, req BatchRequest) (BatchResponse, error) {
+		request = req
+		return BatchResponse{}, nil
+	})})
+	_, err = conv.Convert(input)
+	require.NoError(t, err)
+	assert.Equal(t, want, request.DocumentMetadata)
+}
+
+func TestFrontMatterRoundTrip(t *testing.T) {
+	metadata := map[string]any{"title": "Design", "labels": []any{"api"}, "version": 3}
+	forward, err := converter.New(converter.Config{})
+	require.NoError(t, err)
+	adf := []byte(`{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"Body"}]}]}`)
+
+	rendered, err := forward.ConvertWithContext(context.Background(), adf, converter.ConvertOptions{Metadata: metadata})
+	require.NoError(t, err)
+
+	result, err := newHookReverseConverter(t, ReverseConfig{}).Convert(rendered.Markdown)
+	require.NoError(t, err)
+	assert.Equal(t, metadata, result.Metadata)
+	assert.JSONEq(t, string(adf), string(result.ADF))
+}
+
+func TestHookCacheIgnoresFrontMatter(t *testing.T) {
+	calls := 0
+	conv := newHookReverseConverter(t, ReverseConfig{
+		HookCache: converter.NewLRUHookCache(0, HookCacheOptions{}),
+		LinkHook: func(ctx context.Context, in LinkParseInput) (LinkParseOutput, error) {
+			calls++
+			return LinkParseOutput{Destination: "https://confluence.example/pages/1", Handled: true}, nil
+		},
+	})
+
+	first, err := conv.Convert("---\ntitle: One\n---\n\n[Page](../page.md)\n")
+	require.NoError(t, err)
+	assert.Equal(t, HookCacheStats{Misses: 1}, first.CacheStats)
+
+	second, err := conv.Convert("---\ntitle: Two\n---\n\n[Page](../page.md)\n")
+	require.NoError(t, err)
+	assert.Equal(t, HookCacheStats{Hits: 1}, second.CacheStats)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,121 @@
+package mdconverter
+
+import (
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/yuin/goldmark/ast"
+)
+
+// tocExtensionType is the extensionType given to tables of contents, matching the
+// Confluence toc macro.
+const tocExtensionType = "com.atlassian.confluence.macro.core"
+
+// gitLabAlertPanelTypes maps GitLab alert types to panel types by colour.
+var gitLabAlertPanelTypes = map[string]string{
+	"note":      "info",
+	"important": "note",
+	"tip":       "success",
+	"warning":   "warning",
+	"caution":   "error",
+}
+
+// gitLabChipStatusColors maps the colour chips written for statuses back to status colors.
+var gitLabChipStatusColors = map[string]string{
+	"#42526e": "neutral",
+	"#403294": "purple",
+	"#0747a6": "blue",
+	"#bf2600": "red",
+	"#ff8b00": "yellow",
+	"#006644": "green",
+}
+
+// tryConvertGitLabAlert converts a > [!type] Title GitLab alert to a panel. Unknown
+// types stay blockquotes, as GitLab renders them.
+func (s *state) tryConvertGitLabAlert(node *ast.Blockquote, content []converter.Node) (converter.Node, bool, error) {
+	if s.config.PanelDetection != PanelDetectGitLab {
+		return converter.Node{}, false, nil
+	}
+
+	match, paragraph := s.calloutHeader(node)
+	if match == nil || match[2] != "" {
+		return converter.Node{}, false, nil
+	}
+	panelType, ok := gitLabAlertPanelTypes[strings.ToLower(match[1])]
+	if !ok {
+		return converter.Node{}, false, nil
+	}
+
+	body, err := s.convertCalloutBody(paragraph, content)
+	if err != nil {
+		return converter.Node{}, false, err
+	}
+	panel := converter.Node{
+		Type: "panel",
+		Attrs: map[string]interface{}{
+			"panelType": panelType,
+		},
+		Content: ensureBlockContent(body),
+	}
+	if title := strings.TrimSpace(match[3]); title != "" {
+		panel.Attrs["title"] = title
+	}
+	return panel, true, nil
+}
+
+// tryConvertTOCParagraph converts a paragraph holding only [[_TOC_]] or [TOC] to a table
+// of contents extension.
+func (s *state) tryConvertTOCParagraph(node *ast.Paragraph) (converter.Node, bool) {
+	if !s.config.TOCDetection || node.Lines().Len() != 1 {
+		return converter.Node{}, false
+	}
+	segment := node.Lines().At(0)
+	switch strings.TrimSpace(string(segment.Value(s.source))) {
+	case "[[_TOC_]]", "[TOC]":
+		return converter.Node{
+			Type: "extension",
+			Attrs: map[string]interface{}{
+				"extensionType": tocExtensionType,
+				"extensionKey":  "toc",
+			},
+		}, true
+	}
+	return converter.Node{}, false
+}
+
+func (s *state) convertMathBlockNode(node *MathBlockNode) (converter.Node, bool, error) {
+	var body strings.Builder
+	lines := node.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		body.Write(segment.Value(s.source))
+	}
+
+	codeBlock := converter.Node{
+		Type: "codeBlock",
+		Attrs: map[string]interface{}{
+			"language": "math",
+		},
+	}
+	if textValue := strings.TrimRight(body.String(), "\n"); textValue != "" {
+		codeBlock.Content = []converter.Node{
+			{
+				Type: "text",
+				Text: textValue,
+			},
+		}
+	}
+	return codeBlock, true, nil
+}
+
+func (s *state) convertStatusChipNode(node *StatusChipNode) []converter.Node {
+	return []converter.Node{
+		{
+			Type: "status",
+			Attrs: map[string]interface{}{
+				"text":  strings.TrimSpace(node.Label),
+				"color": node.Color,
+			},
+		},
+	}
+}
@@ -0,0 +1,169 @@
+package mdconverter
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var (
+	KindMathBlock  = ast.NewNodeKind("MathBlock")
+	KindStatusChip = ast.NewNodeKind("StatusChip")
+)
+
+var statusChipPattern = regexp.MustCompile("^`(#[0-9A-Fa-f]{6})` \\*\\*([^*`\\n]+)\\*\\*")
+
+// MathBlockNode is a $$ fenced math block.
+type MathBlockNode struct {
+	ast.BaseBlock
+}
+
+func (n *MathBlockNode) Kind() ast.NodeKind {
+	return KindMathBlock
+}
+
+func (n *MathBlockNode) IsRaw() bool {
+	return true
+}
+
+func (n *MathBlockNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// StatusChipNode is a GitLab colour chip followed by bold status text.
+type StatusChipNode struct {
+	ast.BaseInline
+	Color string
+	Label string
+}
+
+func (n *StatusChipNode) Kind() ast.NodeKind {
+	return KindStatusChip
+}
+
+func (n *StatusChipNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"Color": n.Color,
+		"Label": n.Label,
+	}, nil)
+}
+
+// isFenceLine reports whether line holds only the given fence, allowing up to three
+// spaces of indentation and trailing whitespace.
+func isFenceLine(line []byte, fence string) bool {
+	indent := 0
+	for indent < len(line) && line[indent] == ' ' {
+		indent++
+	}
+	return indent < 4 && bytes.Equal(util.TrimRightSpace(line[indent:]), []byte(fence))
+}
+
+type MultilineQuoteParser struct{}
+
+// NewMultilineQuoteParser returns a parser for GitLab >>> multiline blockquotes. The
+// blockquote it produces is converted like any other blockquote.
+func NewMultilineQuoteParser() parser.BlockParser {
+	return &MultilineQuoteParser{}
+}
+
+func (p *MultilineQuoteParser) Trigger() []byte {
+	return []byte{'>'}
+}
+
+func (p *MultilineQuoteParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, _ := reader.PeekLine()
+	if !isFenceLine(line, ">>>") {
+		return nil, parser.NoChildren
+	}
+	reader.AdvanceToEOL()
+	return ast.NewBlockquote(), parser.HasChildren
+}
+
+func (p *MultilineQuoteParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, _ := reader.PeekLine()
+	if isFenceLine(line, ">>>") {
+		reader.AdvanceToEOL()
+		return parser.Close
+	}
+	return parser.Continue | parser.HasChildren
+}
+
+func (p *MultilineQuoteParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (p *MultilineQuoteParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (p *MultilineQuoteParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+type MathBlockParser struct{}
+
+func NewMathBlockParser() parser.BlockParser {
+	return &MathBlockParser{}
+}
+
+func (p *MathBlockParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+func (p *MathBlockParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, _ := reader.PeekLine()
+	if !isFenceLine(line, "$$") {
+		return nil, parser.NoChildren
+	}
+	reader.AdvanceToEOL()
+	return &MathBlockNode{}, parser.NoChildren
+}
+
+func (p *MathBlockParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, segment := reader.PeekLine()
+	if isFenceLine(line, "$$") {
+		reader.AdvanceToEOL()
+		return parser.Close
+	}
+	node.Lines().Append(segment)
+	reader.AdvanceToEOL()
+	return parser.Continue | parser.NoChildren
+}
+
+func (p *MathBlockParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (p *MathBlockParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (p *MathBlockParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+type StatusChipParser struct{}
+
+// NewStatusChipParser returns a parser for statuses written as a colour chip and bold
+// text. Chips in other colours are left to the code span parser.
+func NewStatusChipParser() parser.InlineParser {
+	return &StatusChipParser{}
+}
+
+func (p *StatusChipParser) Trigger() []byte {
+	return []byte{'`'}
+}
+
+func (p *StatusChipParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	match := statusChipPattern.FindSubmatchIndex(line)
+	if match == nil {
+		return nil
+	}
+	color, ok := gitLabChipStatusColors[string(bytes.ToLower(line[match[2]:match[3]]))]
+	if !ok {
+		return nil
+	}
+	block.Advance(match[1])
+	return &StatusChipNode{Color: color, Label: string(line[match[4]:match[5]])}
+}
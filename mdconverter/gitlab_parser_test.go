@@ -0,0 +1,139 @@
+package mdconverter
+
+import (
+	"testing"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var gitLabReverseConfig = ReverseConfig{
+	PanelDetection:          PanelDetectGitLab,
+	StatusDetection:         StatusDetectGitLab,
+	MultilineQuoteDetection: true,
+	TOCDetection:            true,
+	MathBlockDetection:      true,
+}
+
+func TestGitLabAlertParsing(t *testing.T) {
+	doc := convertReverseDoc(t, gitLabReverseConfig,
+		"> [!important] Heads up\n> Read\n> this\n\n"+
+			"> [!caution]\n> Broken\n\n"+
+			"> [!custom]\n> Plain\n")
+
+	require.Len(t, doc.Content, 3)
+	panel := doc.Content[0]
+	assert.Equal(t, "panel", panel.Type)
+	assert.Equal(t, "note", panel.Attrs["panelType"])
+	assert.Equal(t, "Heads up", panel.Attrs["title"])
+	assert.Equal(t, "Read this", paragraphText(panel.Content[0]))
+
+	assert.Equal(t, "error", doc.Content[1].Attrs["panelType"])
+	assert.Nil(t, doc.Content[1].Attrs["title"])
+	assert.Equal(t, "blockquote", doc.Content[2].Type)
+}
+
+func TestEmptyGitLabAlertGetsAnEmptyParagraph(t *testing.T) {
+	doc := convertReverseDoc(t, ReverseConfig{PanelDetection: PanelDetectGitLab}, "> [!caution] Title\n")
+
+	require.Len(t, doc.Content, 1)
+	assert.Equal(t, "panel", doc.Content[0].Type)
+	assert.Equal(t, "Title", doc.Content[0].Attrs["title"])
+	assert.Equal(t, []converter.Node{{Type: "paragraph"}}, doc.Content[0].Content)
+}
+
+func TestGitLabMultilineQuoteParsing(t *testing.T) {
+	doc := convertReverseDoc(t, gitLabReverseConfig,
+		">>>\nFirst\n\n- item\n>>>\n\nAfter\n\n>>>\n> [!tip]\n> Inside\n>>>\n")
+
+	require.Len(t, doc.Content, 3)
+	quote := doc.Content[0]
+	assert.Equal(t, "blockquote", quote.Type)
+	require.Len(t, quote.Content, 2)
+	assert.Equal(t, "First", paragraphText(quote.Content[0]))
+	assert.Equal(t, "bulletList", quote.Content[1].Type)
+
+	assert.Equal(t, "After", paragraphText(doc.Content[1]))
+
+	nested := doc.Content[2]
+	assert.Equal(t, "blockquote", nested.Type)
+	require.Len(t, nested.Content, 1)
+	assert.Equal(t, "panel", nested.Content[0].Type)
+	assert.Equal(t, "success", nested.Content[0].Attrs["panelType"])
+}
+
+func TestGitLabTOCAndMathParsing(t *testing.T) {
+	doc := convertReverseDoc(t, gitLabReverseConfig, "[[_TOC_]]\n\n[TOC]\n\n$$\na^2 + b^2\n= c^2\n$$\n\nInline $`x`$\n")
+
+	require.Len(t, doc.Content, 4)
+	for _, node := range doc.Content[:2] {
+		assert.Equal(t, "extension", node.Type)
+		assert.Equal(t, "toc", node.Attrs["extensionKey"])
+	}
+
+	math := doc.Content[2]
+	assert.Equal(t, "codeBlock", math.Type)
+	assert.Equal(t, "math", math.Attrs["language"])
+	assert.Equal(t, "a^2 + b^2\n= c^2", math.Content[0].Text)
+
+	inline := doc.Content[3].Content
+	require.Len(t, inline, 3)
+	assert.Equal(t, "x", inline[1].Text)
+	assert.Equal(t, []converter.Mark{{Type: "code"}}, inline[1].Marks)
+}
+
+func TestGitLabStatusChipParsing(t *testing.T) {
+	doc := convertReverseDoc(t, gitLabReverseConfig, "`#0747A6` **IN PROGRESS** and `#123456` **bold**")
+
+	content := doc.Content[0].Content
+	require.Len(t, content, 5)
+	assert.Equal(t, "status", content[0].Type)
+	assert.Equal(t, "IN PROGRESS", content[0].Attrs["text"])
+	assert.Equal(t, "blue", content[0].Attrs["color"])
+	assert.Equal(t, "#123456", content[2].Text)
+	assert.Equal(t, []converter.Mark{{Type: "code"}}, content[2].Marks)
+	assert.Equal(t, "bold", content[4].Text)
+}
+
+func TestGitLabSyntaxIsTextWhenDetectionIsOff(t *testing.T) {
+	doc := convertReverseDoc(t, ReverseConfig{}, "[[_TOC_]]\n\n>>>\nQuoted\n>>>\n")
+
+	require.Len(t, doc.Content, 4)
+	assert.Equal(t, "paragraph", doc.Content[0].Type)
+	assert.Equal(t, "[[", doc.Content[0].Content[0].Text)
+	assert.Equal(t, "blockquote", doc.Content[1].Type)
+	assert.Equal(t, "Quoted", paragraphText(doc.Content[2]))
+}
+
+func TestGitLabRoundTrip(t *testing.T) {
+	input := []byte(`{"version":1,"type":"doc","content":[
+		{"type":"extension","attrs":{"extensionType":"com.atlassian.confluence.macro.core","extensionKey":"toc"}},
+		{"type":"panel","attrs":{"panelType":"success","title":"Done"},"content":[{"type":"paragraph","content":[{"type":"text","text":"Shipped"}]}]},
+		{"type":"paragraph","content":[{"type":"status","attrs":{"text":"BLOCKED","color":"red"}}]},
+		{"type":"expand","attrs":{"title":"More"},"content":[{"type":"paragraph","content":[{"type":"text","text":"hidden"}]}]},
+		{"type":"codeBlock","attrs":{"language":"math"},"content":[{"type":"text","text":"e^{i\\pi} = -1"}]}
+	]}`)
+
+	forward, err := converter.New(converter.Config{
+		PanelStyle:  converter.PanelGitLab,
+		StatusStyle: converter.StatusGitLab,
+		TOCStyle:    converter.TOCGitLab,
+	})
+	require.NoError(t, err)
+	markdown, err := forward.Convert(input)
+	require.NoError(t, err)
+
+	doc := convertReverseDoc(t, gitLabReverseConfig, markdown.Markdown)
+
+	require.Len(t, doc.Content, 5, markdown.Markdown)
+	assert.Equal(t, "toc", doc.Content[0].Attrs["extensionKey"])
+	assert.Equal(t, "success", doc.Content[1].Attrs["panelType"])
+	assert.Equal(t, "Done", doc.Content[1].Attrs["title"])
+	status := doc.Content[2].Content[0]
+	assert.Equal(t, "BLOCKED", status.Attrs["text"])
+	assert.Equal(t, "red", status.Attrs["color"])
+	assert.Equal(t, "expand", doc.Content[3].Type)
+	assert.Equal(t, "More", doc.Content[3].Attrs["title"])
+	assert.Equal(t, "math", doc.Content[4].Attrs["language"])
+}
@@ -0,0 +1,30 @@
+package mdconverter
+
+import "github.com/rgonek/jira-adf-converter/converter"
+
+const (
+	linkParseCacheKind  = "link_parse"
+	mediaParseCacheKind = "media_parse"
+)
+
+// HookCache stores link and media hook results across conversions.
+// The same cache instance may be shared with converter.Config.HookCache.
+type HookCache = converter.HookCache
+
+// HookCacheEntry is a cached hook result.
+type HookCacheEntry = converter.HookCacheEntry
+
+// HookCacheStats reports hook cache usage for a single conversion.
+type HookCacheStats = converter.HookCacheStats
+
+// HookCacheOptions controls entry expiry for the built-in HookCache implementations.
+type HookCacheOptions = converter.HookCacheOptions
+
+// countHookCache records a HookCache hit or miss for this conversion.
+func (s *state) countHookCache(hit bool) {
+	if hit {
+		s.cacheStats.Hits++
+		return
+	}
+	s.cacheStats.Misses++
+}
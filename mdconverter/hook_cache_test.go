@@ -0,0 +1,72 @@
+package mdconverter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookCacheSharesResultsAcrossConversions(t *testing.T) {
+	markdown := "[Page](../page.md) [Missing](../missing.md)\n\n![Diagram](./diagram.png)\n"
+
+	linkCalls, mediaCalls := 0, 0
+	conv := newHookReverseConverter(t, ReverseConfig{
+		HookCache: converter.NewLRUHookCache(0, HookCacheOptions{}),
+		LinkHook: func(_ context.Context, in LinkParseInput) (LinkParseOutput, error) {
+			linkCalls++
+			if in.Destination == "../missing.md" {
+				return LinkParseOutput{}, ErrUnresolved
+			}
+			return LinkParseOutput{Destination: "https://confluence.example/pages/1", Handled: true}, nil
+		},
+		MediaHook: func(context.Context, MediaParseInput) (MediaParseOutput, error) {
+			mediaCalls++
+			return MediaParseOutput{MediaType: "file", ID: "att-1", Handled: true}, nil
+		},
+	})
+
+	first, err := conv.Convert(markdown)
+	require.NoError(t, err)
+	assert.Equal(t, HookCacheStats{Misses: 3}, first.CacheStats)
+
+	second, err := conv.Convert(markdown)
+	require.NoError(t, err)
+	assert.Equal(t, HookCacheStats{Hits: 3}, second.CacheStats)
+
+	assert.Equal(t, 2, linkCalls)
+	assert.Equal(t, 1, mediaCalls)
+	assert.JSONEq(t, string(first.ADF), string(second.ADF))
+	require.Len(t, second.Warnings, 1)
+	assert.Equal(t, converter.WarningUnresolvedReference, second.Warnings[0].Type)
+}
+
+func TestHookCacheIsSharedSafelyWithForwardConverter(t *testing.T) {
+	cache := converter.NewLRUHookCache(0, HookCacheOptions{})
+
+	forward, err := converter.New(converter.Config{
+		HookCache: cache,
+		LinkHook: func(context.Context, converter.LinkRenderInput) (converter.LinkRenderOutput, error) {
+			return converter.LinkRenderOutput{Href: "../page.md", Handled: true}, nil
+		},
+	})
+	require.NoError(t, err)
+	_, err = forward.Convert([]byte(`{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"Page","marks":[{"type":"link","attrs":{"href":"../page.md"}}]}]}]}`))
+	require.NoError(t, err)
+
+	calls := 0
+	reverse := newHookReverseConverter(t, ReverseConfig{
+		HookCache: cache,
+		LinkHook: func(context.Context, LinkParseInput) (LinkParseOutput, error) {
+			calls++
+			return LinkParseOutput{Destination: "https://confluence.example/pages/1", Handled: true}, nil
+		},
+	})
+	result, err := reverse.Convert("[Page](../page.md)")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, calls, "forward and reverse entries must not collide")
+	assert.Equal(t, HookCacheStats{Misses: 1}, result.CacheStats)
+}
@@ -34,14 +34,19 @@ type LinkParseHook func(ctx context.Context, in LinkParseInput) (LinkParseOutput
 // MediaParseHook can map markdown image/file destinations to media attributes.
 type MediaParseHook func(ctx context.Context, in MediaParseInput) (MediaParseOutput, error)
 
-// LinkParseInput describes a markdown link being parsed.
+// LinkParseInput describes a markdown link being parsed. Source is "link" for Markdown
+// links and "wikiLink" for Obsidian [[wiki links]], whose Destination is the note name.
 type LinkParseInput struct {
+	Source      string
 	SourcePath  string
 	Destination string
 	Title       string
 	Text        string
 	Meta        LinkMetadata
 	Raw         map[string]any
+	// DocumentMetadata holds the front matter of the document being converted.
+	// It is left out of the HookCache key.
+	DocumentMetadata map[string]any `json:"-"`
 }
 
 // LinkParseOutput contains hook-provided link parsing overrides.
@@ -60,6 +65,9 @@ type MediaParseInput struct {
 	Alt         string
 	Meta        MediaMetadata
 	Raw         map[string]any
+	// DocumentMetadata holds the front matter of the document being converted.
+	// It is left out of the HookCache key.
+	DocumentMetadata map[string]any `json:"-"`
 }
 
 // MediaParseOutput contains hook-provided media parsing overrides.
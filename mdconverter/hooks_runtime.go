@@ -11,15 +11,31 @@ import (
 )
 
 func (s *state) applyLinkParseHook(input LinkParseInput) (LinkParseOutput, bool, error) {
-	if s.config.LinkHook == nil {
+	if s.collector != nil {
+		s.collector.addLink(input)
 		return LinkParseOutput{}, false, nil
 	}
-
-	if err := s.checkContext(); err != nil {
-		return LinkParseOutput{}, false, err
+	resolution, batched := s.batch.lookupLink(input)
+	if !batched {
+		if s.config.LinkHook == nil {
+			return LinkParseOutput{}, false, nil
+		}
+		if err := s.checkContext(); err != nil {
+			return LinkParseOutput{}, false, err
+		}
 	}
 
-	output, err := s.config.LinkHook(s.ctx, input)
+	output, err := converter.ResolveWithHookCache(s.config.HookCache, linkParseCacheKind, input, func() (LinkParseOutput, error) {
+		if batched {
+			return resolution.Output, resolution.Err
+		}
+		return s.config.LinkHook(s.ctx, input)
+	}, s.countHookCache)
+	return s.finishLinkParse(input, output, err)
+}
+
+// finishLinkParse applies unresolved-reference policy and validation to a link resolution.
+func (s *state) finishLinkParse(input LinkParseInput, output LinkParseOutput, err error) (LinkParseOutput, bool, error) {
 	if err != nil {
 		if errors.Is(err, ErrUnresolved) {
 			if s.config.ResolutionMode == ResolutionStrict {
@@ -50,15 +66,31 @@ func (s *state) applyLinkParseHook(input LinkParseInput) (LinkParseOutput, bool,
 }
 
 func (s *state) applyMediaParseHook(input MediaParseInput) (MediaParseOutput, bool, error) {
-	if s.config.MediaHook == nil {
+	if s.collector != nil {
+		s.collector.addMedia(input)
 		return MediaParseOutput{}, false, nil
 	}
-
-	if err := s.checkContext(); err != nil {
-		return MediaParseOutput{}, false, err
+	resolution, batched := s.batch.lookupMedia(input)
+	if !batched {
+		if s.config.MediaHook == nil {
+			return MediaParseOutput{}, false, nil
+		}
+		if err := s.checkContext(); err != nil {
+			return MediaParseOutput{}, false, err
+		}
 	}
 
-	output, err := s.config.MediaHook(s.ctx, input)
+	output, err := converter.ResolveWithHookCache(s.config.HookCache, mediaParseCacheKind, input, func() (MediaParseOutput, error) {
+		if batched {
+			return resolution.Output, resolution.Err
+		}
+		return s.config.MediaHook(s.ctx, input)
+	}, s.countHookCache)
+	return s.finishMediaParse(input, output, err)
+}
+
+// finishMediaParse applies unresolved-reference policy and validation to a media resolution.
+func (s *state) finishMediaParse(input MediaParseInput, output MediaParseOutput, err error) (MediaParseOutput, bool, error) {
 	if err != nil {
 		if errors.Is(err, ErrUnresolved) {
 			if s.config.ResolutionMode == ResolutionStrict {
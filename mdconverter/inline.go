@@ -103,6 +103,7 @@ func (s *state) convertInlineNode(node ast.Node, stack *markStack) ([]converter.
 
 		hookOutput, handled, err := s.applyLinkParseHook(
 			LinkParseInput{
+				Source:      "link",
 				SourcePath:  s.options.SourcePath,
 				Destination: href,
 				Title:       title,
@@ -112,6 +113,7 @@ func (s *state) convertInlineNode(node ast.Node, stack *markStack) ([]converter.
 					"kind":  "link",
 					"title": title,
 				},
+				DocumentMetadata: s.metadata,
 			},
 		)
 		if err != nil {
@@ -173,89 +175,73 @@ func (s *state) convertInlineNode(node ast.Node, stack *markStack) ([]converter.
 	case *PandocSpanNode:
 		return s.convertPandocSpanNode(typed, stack)
 
+	case *WikiLinkNode:
+		return s.convertWikiLinkNode(typed, stack)
+
+	case *HighlightNode:
+		return s.convertHighlightNode(typed, stack)
+
+	case *ObsidianInlineCommentNode:
+		return nil, nil
+
+	case *StatusChipNode:
+		return s.convertStatusChipNode(typed), nil
+
 	case *ast.Image:
 		rawAlt := strings.TrimSpace(string(typed.Text(s.source)))
-		alt := rawAlt
-		if alt == "" {
-			alt = "Image"
-		}
 		href := strings.TrimSpace(string(typed.Destination))
+		return s.convertMediaReference(href, rawAlt, "image", "image")
 
-		hookOutput, handled, err := s.applyMediaParseHook(
-			MediaParseInput{
-				SourcePath:  s.options.SourcePath,
-				Destination: href,
-				Alt:         rawAlt,
-				Meta:        mediaMetadataFromDestination(href),
-				Raw: map[string]any{
-					"kind": "image",
-				},
-			},
-		)
-		if err != nil {
-			return nil, err
+	default:
+		if node.HasChildren() {
+			return s.convertInlineChildren(node, stack)
 		}
-		if handled {
-			resolvedAlt := strings.TrimSpace(hookOutput.Alt)
-			if resolvedAlt == "" {
-				resolvedAlt = alt
-			}
+		return s.warnUnknownInline(node, stack), nil
+	}
+}
 
-			mediaAttrs := map[string]interface{}{
-				"type": strings.ToLower(strings.TrimSpace(hookOutput.MediaType)),
-			}
-			if hookOutput.ID != "" {
-				mediaAttrs["id"] = hookOutput.ID
-			}
-			if hookOutput.URL != "" {
-				mediaAttrs["url"] = hookOutput.URL
-			}
-			if resolvedAlt != "" {
-				mediaAttrs["alt"] = resolvedAlt
-			}
+// convertMediaReference converts an image or embed destination to a mediaSingle node,
+// through the media hook when one is configured. fallbackType is the media type used
+// when the hook does not handle the destination.
+func (s *state) convertMediaReference(href, rawAlt, kind, fallbackType string) ([]converter.Node, error) {
+	alt := rawAlt
+	// Embeds have no alt text of their own, so they are not given the default one.
+	if alt == "" && fallbackType == "image" && kind != "embed" {
+		alt = "Image"
+	}
 
-			return []converter.Node{
-				{
-					Type: "mediaSingle",
-					Content: []converter.Node{
-						{
-							Type:  "media",
-							Attrs: mediaAttrs,
-						},
-					},
-				},
-			}, nil
+	hookOutput, handled, err := s.applyMediaParseHook(
+		MediaParseInput{
+			SourcePath:  s.options.SourcePath,
+			Destination: href,
+			Alt:         rawAlt,
+			Meta:        mediaMetadataFromDestination(href),
+			Raw: map[string]any{
+				"kind": kind,
+			},
+			DocumentMetadata: s.metadata,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if handled {
+		resolvedAlt := strings.TrimSpace(hookOutput.Alt)
+		if resolvedAlt == "" {
+			resolvedAlt = alt
 		}
 
 		mediaAttrs := map[string]interface{}{
-			"type": "image",
+			"type": strings.ToLower(strings.TrimSpace(hookOutput.MediaType)),
 		}
-		if href != "" {
-			mediaID := href
-			strippedToID := false
-			if s.config.MediaBaseURL != "" && strings.HasPrefix(href, s.config.MediaBaseURL) {
-				candidateID := strings.TrimPrefix(href, s.config.MediaBaseURL)
-				if candidateID != "" {
-					mediaID = candidateID
-					strippedToID = true
-				}
-			}
-
-			lowerHref := strings.ToLower(href)
-			if strippedToID {
-				mediaAttrs["id"] = mediaID
-				if alt != "" {
-					mediaAttrs["alt"] = alt
-				}
-			} else if strings.HasPrefix(lowerHref, "http://") || strings.HasPrefix(lowerHref, "https://") {
-				mediaAttrs["url"] = href
-				mediaAttrs["alt"] = alt
-			} else {
-				mediaAttrs["id"] = mediaID
-				if alt != "" {
-					mediaAttrs["alt"] = alt
-				}
-			}
+		if hookOutput.ID != "" {
+			mediaAttrs["id"] = hookOutput.ID
+		}
+		if hookOutput.URL != "" {
+			mediaAttrs["url"] = hookOutput.URL
+		}
+		if resolvedAlt != "" {
+			mediaAttrs["alt"] = resolvedAlt
 		}
 
 		return []converter.Node{
@@ -269,13 +255,50 @@ func (s *state) convertInlineNode(node ast.Node, stack *markStack) ([]converter.
 				},
 			},
 		}, nil
+	}
 
-	default:
-		if node.HasChildren() {
-			return s.convertInlineChildren(node, stack)
+	mediaAttrs := map[string]interface{}{
+		"type": fallbackType,
+	}
+	if href != "" {
+		mediaID := href
+		strippedToID := false
+		if s.config.MediaBaseURL != "" && strings.HasPrefix(href, s.config.MediaBaseURL) {
+			candidateID := strings.TrimPrefix(href, s.config.MediaBaseURL)
+			if candidateID != "" {
+				mediaID = candidateID
+				strippedToID = true
+			}
+		}
+
+		lowerHref := strings.ToLower(href)
+		if strippedToID {
+			mediaAttrs["id"] = mediaID
+			if alt != "" {
+				mediaAttrs["alt"] = alt
+			}
+		} else if strings.HasPrefix(lowerHref, "http://") || strings.HasPrefix(lowerHref, "https://") {
+			mediaAttrs["url"] = href
+			mediaAttrs["alt"] = alt
+		} else {
+			mediaAttrs["id"] = mediaID
+			if alt != "" {
+				mediaAttrs["alt"] = alt
+			}
 		}
-		return s.warnUnknownInline(node, stack), nil
 	}
+
+	return []converter.Node{
+		{
+			Type: "mediaSingle",
+			Content: []converter.Node{
+				{
+					Type:  "media",
+					Attrs: mediaAttrs,
+				},
+			},
+		},
+	}, nil
 }
 
 func (s *state) convertInlineText(textValue string, stack *markStack) []converter.Node {
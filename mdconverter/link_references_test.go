@@ -0,0 +1,52 @@
+package mdconverter
+
+import (
+	"testing"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferenceLinksRoundTrip(t *testing.T) {
+	input := []byte(`{"version":1,"type":"doc","content":[
+		{"type":"paragraph","content":[
+			{"type":"text","text":"Read the "},
+			{"type":"text","text":"design","marks":[{"type":"link","attrs":{"href":"https://example.com/wiki/Design+Doc?src=search","title":"Design"}}]},
+			{"type":"text","text":" twice: "},
+			{"type":"text","text":"again","marks":[{"type":"link","attrs":{"href":"https://example.com/wiki/Design+Doc?src=search","title":"Design"}}]}
+		]},
+		{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Later"}]},
+		{"type":"paragraph","content":[
+			{"type":"text","text":"home","marks":[{"type":"strong"},{"type":"link","attrs":{"href":"https://example.com/"}}]}
+		]}
+	]}`)
+
+	for _, style := range []converter.LinkStyle{converter.LinkNumbered, converter.LinkNamed} {
+		t.Run(string(style), func(t *testing.T) {
+			forward, err := converter.New(converter.Config{
+				LinkStyle:       style,
+				LinkDefinitions: converter.LinkDefinitionsSection,
+			})
+			require.NoError(t, err)
+			markdown, err := forward.Convert(input)
+			require.NoError(t, err)
+
+			doc := convertReverseDoc(t, ReverseConfig{}, markdown.Markdown)
+			require.Len(t, doc.Content, 3, markdown.Markdown)
+
+			paragraph := doc.Content[0].Content
+			require.Len(t, paragraph, 4)
+			for _, index := range []int{1, 3} {
+				require.Len(t, paragraph[index].Marks, 1)
+				assert.Equal(t, "https://example.com/wiki/Design+Doc?src=search", paragraph[index].Marks[0].Attrs["href"])
+				assert.Equal(t, "Design", paragraph[index].Marks[0].Attrs["title"])
+			}
+
+			assert.Equal(t, "heading", doc.Content[1].Type)
+			home := doc.Content[2].Content[0]
+			assert.Equal(t, "home", home.Text)
+			assert.ElementsMatch(t, []string{"strong", "link"}, []string{home.Marks[0].Type, home.Marks[1].Type})
+		})
+	}
+}
@@ -23,6 +23,7 @@ type state struct {
 	config            ReverseConfig
 	ctx               context.Context
 	options           ConvertOptions
+	metadata          map[string]any
 	source            []byte
 	parser            goldmark.Markdown
 	warnings          []converter.Warning
@@ -30,6 +31,11 @@ type state struct {
 	htmlSpanStack     []htmlSpanContext
 	pandocExpandDepth int
 	htmlExpandDepth   int
+	// admonitionExpandDepth counts the collapsible admonitions being converted.
+	admonitionExpandDepth int
+	batch                 *batchResolutions
+	collector             *batchCollector
+	cacheStats            HookCacheStats
 }
 
 // New creates a new reverse Converter with the given config.
@@ -58,6 +64,72 @@ func New(config ReverseConfig) (*Converter, error) {
 			),
 		))
 	}
+	if cfg.needsAdmonitionParser(PanelDetectMkDocs, ExpandDetectMkDocs) {
+		options = append(options, goldmark.WithParserOptions(
+			parser.WithBlockParsers(
+				util.Prioritized(NewMkDocsAdmonitionParser(), 500),
+			),
+		))
+	}
+	if cfg.needsAdmonitionParser(PanelDetectDocusaurus, ExpandDetectDocusaurus) {
+		options = append(options, goldmark.WithParserOptions(
+			parser.WithBlockParsers(
+				util.Prioritized(NewDocusaurusAdmonitionParser(), 499),
+			),
+		))
+	}
+	if cfg.needsAdmonitionParser(PanelDetectHugo, ExpandDetectHugo) {
+		options = append(options, goldmark.WithParserOptions(
+			parser.WithBlockParsers(
+				util.Prioritized(NewHugoAdmonitionParser(), 500),
+			),
+		))
+	}
+	if cfg.WikiLinkDetection {
+		options = append(options, goldmark.WithParserOptions(
+			parser.WithInlineParsers(
+				util.Prioritized(NewWikiLinkParser(), 199),
+			),
+		))
+	}
+	if cfg.needsHighlightParser() {
+		options = append(options, goldmark.WithParserOptions(
+			parser.WithInlineParsers(
+				util.Prioritized(NewHighlightParser(), 79),
+			),
+		))
+	}
+	if cfg.CommentDetection {
+		options = append(options, goldmark.WithParserOptions(
+			parser.WithBlockParsers(
+				util.Prioritized(NewObsidianCommentBlockParser(), 500),
+			),
+			parser.WithInlineParsers(
+				util.Prioritized(NewObsidianCommentParser(), 79),
+			),
+		))
+	}
+	if cfg.needsStatusChipParser() {
+		options = append(options, goldmark.WithParserOptions(
+			parser.WithInlineParsers(
+				util.Prioritized(NewStatusChipParser(), 99),
+			),
+		))
+	}
+	if cfg.MultilineQuoteDetection {
+		options = append(options, goldmark.WithParserOptions(
+			parser.WithBlockParsers(
+				util.Prioritized(NewMultilineQuoteParser(), 500),
+			),
+		))
+	}
+	if cfg.MathBlockDetection {
+		options = append(options, goldmark.WithParserOptions(
+			parser.WithBlockParsers(
+				util.Prioritized(NewMathBlockParser(), 500),
+			),
+		))
+	}
 	if cfg.TableGridDetection {
 		options = append(options, goldmark.WithParserOptions(
 			parser.WithBlockParsers(
@@ -87,17 +159,25 @@ func (c *Converter) ConvertWithContext(ctx context.Context, markdown string, opt
 		return Result{}, err
 	}
 
+	metadata, body := map[string]any(nil), markdown
+	if c.config.FrontMatterDetection == FrontMatterDetectYAML {
+		metadata, body, _ = splitFrontMatter(markdown)
+	}
 	s := &state{
-		config:  c.config,
-		ctx:     ctx,
-		options: opts,
-		source:  []byte(markdown),
-		parser:  c.parser,
+		config:   c.config,
+		ctx:      ctx,
+		options:  opts,
+		metadata: metadata,
+		source:   []byte(body),
+		parser:   c.parser,
 	}
 
 	if err := s.checkContext(); err != nil {
 		return Result{}, err
 	}
+	if err := s.resolveBatch(); err != nil {
+		return Result{}, err
+	}
 
 	root := c.parser.Parser().Parse(text.NewReader(s.source))
 	if err := s.checkContext(); err != nil {
@@ -120,8 +200,11 @@ func (c *Converter) ConvertWithContext(ctx context.Context, markdown string, opt
 	}
 
 	return Result{
-		ADF:      adf,
-		Warnings: s.warnings,
+		ADF:        adf,
+		Metadata:   metadata,
+		Warnings:   s.warnings,
+		CacheStats: s.cacheStats,
+		References: converter.CollectReferences(doc),
 	}, nil
 }
 
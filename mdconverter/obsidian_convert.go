@@ -0,0 +1,210 @@
+package mdconverter
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/yuin/goldmark/ast"
+)
+
+// obsidianHighlightColor is the backgroundColor given to ==highlights==, Jira's yellow.
+const obsidianHighlightColor = "#fff0b3"
+
+var obsidianCalloutPattern = regexp.MustCompile(`^\[!([A-Za-z][\w-]*)\]([+-]?)(?:[ \t]+(.*?))?[ \t]*$`)
+
+// imageExtensions are the embed extensions read back as images; other extensions are
+// files. Embeds without an extension are images, like Markdown images.
+var imageExtensions = map[string]bool{
+	".apng": true, ".avif": true, ".bmp": true, ".gif": true, ".jpeg": true, ".jpg": true,
+	".png": true, ".svg": true, ".webp": true,
+}
+
+func (s *state) convertWikiLinkNode(node *WikiLinkNode, stack *markStack) ([]converter.Node, error) {
+	if node.Embed {
+		// A numeric alias sets the embed size, which media nodes do not carry here.
+		alt := node.Alias
+		if _, err := strconv.Atoi(strings.SplitN(alt, "x", 2)[0]); err == nil {
+			alt = ""
+		}
+		mediaType := "image"
+		if ext := strings.ToLower(path.Ext(node.Target)); ext != "" && !imageExtensions[ext] {
+			mediaType = "file"
+		}
+		return s.convertMediaReference(node.Target, alt, "embed", mediaType)
+	}
+
+	text := node.Alias
+	if text == "" {
+		text = node.Target
+	}
+	href := node.Target
+	title := ""
+
+	hookOutput, handled, err := s.applyLinkParseHook(
+		LinkParseInput{
+			Source:      "wikiLink",
+			SourcePath:  s.options.SourcePath,
+			Destination: href,
+			Text:        text,
+			Meta:        linkMetadataFromDestination(href),
+			Raw: map[string]any{
+				"kind": "wikiLink",
+			},
+			DocumentMetadata: s.metadata,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if handled {
+		href = hookOutput.Destination
+		title = hookOutput.Title
+		if hookOutput.ForceCard {
+			return []converter.Node{
+				{
+					Type: "inlineCard",
+					Attrs: map[string]interface{}{
+						"url": href,
+					},
+				},
+			}, nil
+		}
+	}
+
+	mark := converter.Mark{
+		Type: "link",
+		Attrs: map[string]interface{}{
+			"href": href,
+		},
+	}
+	if title != "" {
+		mark.Attrs["title"] = title
+	}
+
+	stack.push(mark)
+	content := s.convertInlineText(text, stack)
+	stack.popByType("link")
+	return content, nil
+}
+
+func (s *state) convertHighlightNode(node *HighlightNode, stack *markStack) ([]converter.Node, error) {
+	if !s.shouldDetectHighlight() {
+		return []converter.Node{newTextNode("=="+node.Content+"==", stack.current())}, nil
+	}
+
+	inlineContent, err := s.convertInlineFragment(node.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	marked := applyMarkToInlineNodes(inlineContent, converter.Mark{
+		Type: "backgroundColor",
+		Attrs: map[string]interface{}{
+			"color": obsidianHighlightColor,
+		},
+	})
+	return applyOuterMarksToInlineNodes(marked, stack.current()), nil
+}
+
+// calloutHeader matches the first line of a blockquote against the > [!type] Title callout
+// pattern. The line is read from the source, since soft breaks are already spaces in the
+// converted content.
+func (s *state) calloutHeader(node *ast.Blockquote) ([]string, *ast.Paragraph) {
+	paragraph, ok := node.FirstChild().(*ast.Paragraph)
+	if !ok || paragraph.Lines().Len() == 0 {
+		return nil, nil
+	}
+	firstSegment := paragraph.Lines().At(0)
+	firstLine := strings.TrimSpace(string(firstSegment.Value(s.source)))
+	match := obsidianCalloutPattern.FindStringSubmatch(firstLine)
+	if match == nil {
+		return nil, nil
+	}
+	return match, paragraph
+}
+
+// convertCalloutBody converts the lines after a callout header, followed by the blocks
+// after the callout's first paragraph.
+func (s *state) convertCalloutBody(paragraph *ast.Paragraph, content []converter.Node) ([]converter.Node, error) {
+	lines := paragraph.Lines()
+	var rest strings.Builder
+	for i := 1; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		rest.Write(segment.Value(s.source))
+	}
+	body, err := s.convertBlockFragment(rest.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > 1 {
+		body = append(body, cloneNodes(content[1:])...)
+	}
+	return body, nil
+}
+
+// tryConvertObsidianCallout converts a > [!type] Title blockquote to a panel, or to an
+// expand when the callout is foldable (a - or + after the type).
+func (s *state) tryConvertObsidianCallout(node *ast.Blockquote, content []converter.Node) (converter.Node, bool, error) {
+	detectPanels := s.shouldDetectPanelObsidian()
+	detectExpands := s.shouldDetectExpandObsidian()
+	if !detectPanels && !detectExpands {
+		return converter.Node{}, false, nil
+	}
+
+	match, paragraph := s.calloutHeader(node)
+	if match == nil {
+		return converter.Node{}, false, nil
+	}
+
+	foldable := match[2] != ""
+	if (foldable && !detectExpands) || (!foldable && !detectPanels) {
+		return converter.Node{}, false, nil
+	}
+
+	body, err := s.convertCalloutBody(paragraph, content)
+	if err != nil {
+		return converter.Node{}, false, err
+	}
+	title := strings.TrimSpace(match[3])
+
+	if foldable {
+		// Expands have no type, and Obsidian output writes them as [!note]-.
+		if calloutType := strings.ToLower(match[1]); calloutType != "note" {
+			s.addWarning(converter.WarningDroppedFeature, "expand", fmt.Sprintf("callout type %q of a foldable callout dropped", calloutType))
+		}
+		expandType := "expand"
+		if isNestedExpandContext(node.Parent()) {
+			expandType = "nestedExpand"
+		}
+		expand := converter.Node{
+			Type:    expandType,
+			Content: ensureBlockContent(body),
+		}
+		if title != "" {
+			expand.Attrs = map[string]interface{}{
+				"title": title,
+			}
+		}
+		return expand, true, nil
+	}
+
+	panelType, ok := admonitionPanelTypes[strings.ToLower(match[1])]
+	if !ok {
+		panelType = "note"
+	}
+	panel := converter.Node{
+		Type: "panel",
+		Attrs: map[string]interface{}{
+			"panelType": panelType,
+		},
+		Content: ensureBlockContent(body),
+	}
+	if title != "" {
+		panel.Attrs["title"] = title
+	}
+	return panel, true, nil
+}
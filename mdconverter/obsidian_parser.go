@@ -0,0 +1,225 @@
+package mdconverter
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+var (
+	KindWikiLink        = ast.NewNodeKind("WikiLink")
+	KindHighlight       = ast.NewNodeKind("Highlight")
+	KindObsidianComment = ast.NewNodeKind("ObsidianComment")
+	// KindObsidianInlineComment is the kind of comments inside a line of text.
+	KindObsidianInlineComment = ast.NewNodeKind("ObsidianInlineComment")
+)
+
+// WikiLinkNode is an Obsidian [[Note|alias]] link, or a ![[file]] embed.
+type WikiLinkNode struct {
+	ast.BaseInline
+	Target string
+	Alias  string
+	Embed  bool
+}
+
+func (n *WikiLinkNode) Kind() ast.NodeKind {
+	return KindWikiLink
+}
+
+func (n *WikiLinkNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"Target": n.Target,
+		"Alias":  n.Alias,
+	}, nil)
+}
+
+// HighlightNode is an Obsidian ==highlight==.
+type HighlightNode struct {
+	ast.BaseInline
+	Content string
+}
+
+func (n *HighlightNode) Kind() ast.NodeKind {
+	return KindHighlight
+}
+
+func (n *HighlightNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"Content": n.Content,
+	}, nil)
+}
+
+// ObsidianCommentNode is a %%comment%% that spans whole lines. It converts to nothing.
+type ObsidianCommentNode struct {
+	ast.BaseBlock
+}
+
+func (n *ObsidianCommentNode) Kind() ast.NodeKind {
+	return KindObsidianComment
+}
+
+func (n *ObsidianCommentNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// ObsidianInlineCommentNode is a %%comment%% inside a line of text.
+type ObsidianInlineCommentNode struct {
+	ast.BaseInline
+}
+
+func (n *ObsidianInlineCommentNode) Kind() ast.NodeKind {
+	return KindObsidianInlineComment
+}
+
+func (n *ObsidianInlineCommentNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+type WikiLinkParser struct{}
+
+func NewWikiLinkParser() parser.InlineParser {
+	return &WikiLinkParser{}
+}
+
+func (p *WikiLinkParser) Trigger() []byte {
+	return []byte{'[', '!'}
+}
+
+func (p *WikiLinkParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	embed := bytes.HasPrefix(line, []byte("![["))
+	start := 2
+	if embed {
+		start = 3
+	} else if !bytes.HasPrefix(line, []byte("[[")) {
+		return nil
+	}
+
+	closing := bytes.Index(line[start:], []byte("]]"))
+	if closing <= 0 {
+		return nil
+	}
+	inner := string(line[start : start+closing])
+	if strings.ContainsAny(inner, "[\n\r") {
+		return nil
+	}
+
+	target, alias, _ := strings.Cut(inner, "|")
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return nil
+	}
+
+	block.Advance(start + closing + 2)
+	return &WikiLinkNode{Target: target, Alias: strings.TrimSpace(alias), Embed: embed}
+}
+
+type HighlightParser struct{}
+
+func NewHighlightParser() parser.InlineParser {
+	return &HighlightParser{}
+}
+
+func (p *HighlightParser) Trigger() []byte {
+	return []byte{'='}
+}
+
+// Parse reads ==text== on a single line. The text may not start or end with a space,
+// so comparisons such as a == b are left alone.
+func (p *HighlightParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	if len(line) < 5 || !bytes.HasPrefix(line, []byte("==")) || line[2] == '=' || line[2] == ' ' || line[2] == '\t' {
+		return nil
+	}
+
+	for idx := 3; idx+1 < len(line); idx++ {
+		if line[idx] == '\n' || line[idx] == '\r' {
+			return nil
+		}
+		if line[idx] == '=' && line[idx+1] == '=' {
+			if line[idx-1] == ' ' || line[idx-1] == '\t' {
+				return nil
+			}
+			block.Advance(idx + 2)
+			return &HighlightNode{Content: string(line[2:idx])}
+		}
+	}
+	return nil
+}
+
+type ObsidianCommentParser struct{}
+
+func NewObsidianCommentParser() parser.InlineParser {
+	return &ObsidianCommentParser{}
+}
+
+func (p *ObsidianCommentParser) Trigger() []byte {
+	return []byte{'%'}
+}
+
+func (p *ObsidianCommentParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	if !bytes.HasPrefix(line, []byte("%%")) {
+		return nil
+	}
+	closing := bytes.Index(line[2:], []byte("%%"))
+	if closing < 0 {
+		return nil
+	}
+	block.Advance(closing + 4)
+	return &ObsidianInlineCommentNode{}
+}
+
+type ObsidianCommentBlockParser struct{}
+
+func NewObsidianCommentBlockParser() parser.BlockParser {
+	return &ObsidianCommentBlockParser{}
+}
+
+func (p *ObsidianCommentBlockParser) Trigger() []byte {
+	return []byte{'%'}
+}
+
+// Open reads a comment that starts a line, up to the line holding its closing %%.
+// Comments followed by text on their closing line are left to the inline parser.
+func (p *ObsidianCommentBlockParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, _ := reader.PeekLine()
+	trimmed := strings.TrimSpace(string(line))
+	if !strings.HasPrefix(trimmed, "%%") {
+		return nil, parser.NoChildren
+	}
+	if _, after, closed := strings.Cut(trimmed[2:], "%%"); closed {
+		if strings.TrimSpace(after) != "" {
+			return nil, parser.NoChildren
+		}
+		return &ObsidianCommentNode{}, parser.NoChildren
+	}
+
+	// The parser moves past the closing line once Open returns.
+	reader.AdvanceLine()
+	for {
+		nextLine, _ := reader.PeekLine()
+		if len(nextLine) == 0 || bytes.Contains(nextLine, []byte("%%")) {
+			break
+		}
+		reader.AdvanceLine()
+	}
+	return &ObsidianCommentNode{}, parser.NoChildren
+}
+
+func (p *ObsidianCommentBlockParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	return parser.Close
+}
+
+func (p *ObsidianCommentBlockParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (p *ObsidianCommentBlockParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (p *ObsidianCommentBlockParser) CanAcceptIndentedLine() bool {
+	return false
+}
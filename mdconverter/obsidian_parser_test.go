@@ -0,0 +1,230 @@
+package mdconverter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var obsidianReverseConfig = ReverseConfig{
+	ColorDetection:    ColorDetectObsidian,
+	PanelDetection:    PanelDetectObsidian,
+	ExpandDetection:   ExpandDetectObsidian,
+	WikiLinkDetection: true,
+	CommentDetection:  true,
+}
+
+func paragraphText(node converter.Node) string {
+	var text strings.Builder
+	for _, child := range node.Content {
+		text.WriteString(child.Text)
+	}
+	return text.String()
+}
+
+func TestWikiLinkParsing(t *testing.T) {
+	var inputs []LinkParseInput
+	cfg := obsidianReverseConfig
+	cfg.LinkHook = func(_ context.Context, in LinkParseInput) (LinkParseOutput, error) {
+		inputs = append(inputs, in)
+		if in.Source != "wikiLink" {
+			return LinkParseOutput{Handled: false}, nil
+		}
+		return LinkParseOutput{Destination: "https://wiki.example.com/" + in.Destination, Handled: true}, nil
+	}
+
+	doc := convertReverseDoc(t, cfg, "See [[Setup#Install|the guide]], [[Notes]] and [site](https://example.com).")
+
+	require.Len(t, inputs, 3)
+	assert.Equal(t, "wikiLink", inputs[0].Source)
+	assert.Equal(t, "Setup#Install", inputs[0].Destination)
+	assert.Equal(t, "the guide", inputs[0].Text)
+	assert.Equal(t, "wikiLink", inputs[0].Raw["kind"])
+	assert.Equal(t, "link", inputs[2].Source)
+
+	content := doc.Content[0].Content
+	require.Len(t, content, 7)
+	assert.Equal(t, "the guide", content[1].Text)
+	assert.Equal(t, "https://wiki.example.com/Setup#Install", content[1].Marks[0].Attrs["href"])
+	assert.Equal(t, "Notes", content[3].Text)
+	assert.Equal(t, "https://wiki.example.com/Notes", content[3].Marks[0].Attrs["href"])
+	assert.Equal(t, "https://example.com", content[5].Marks[0].Attrs["href"])
+}
+
+func TestWikiLinkWithoutHookKeepsNoteName(t *testing.T) {
+	doc := convertReverseDoc(t, obsidianReverseConfig, "[[Notes]]")
+
+	content := doc.Content[0].Content
+	require.Len(t, content, 1)
+	assert.Equal(t, "text", content[0].Type)
+	assert.Equal(t, "Notes", content[0].Text)
+	assert.Equal(t, "Notes", content[0].Marks[0].Attrs["href"])
+}
+
+func TestWikiEmbedParsing(t *testing.T) {
+	var inputs []MediaParseInput
+	cfg := obsidianReverseConfig
+	cfg.MediaHook = func(_ context.Context, in MediaParseInput) (MediaParseOutput, error) {
+		inputs = append(inputs, in)
+		if in.Destination != "spec.pdf" {
+			return MediaParseOutput{Handled: false}, nil
+		}
+		return MediaParseOutput{MediaType: "file", ID: "att-1", Handled: true}, nil
+	}
+
+	doc := convertReverseDoc(t, cfg, "![[diagram.png|Diagram]]\n\n![[spec.pdf]]\n\n![[photo.jpg|300]]\n")
+
+	require.Len(t, inputs, 3)
+	assert.Equal(t, "embed", inputs[0].Raw["kind"])
+	assert.Equal(t, "Diagram", inputs[0].Alt)
+
+	require.Len(t, doc.Content, 3)
+	image := doc.Content[0].Content[0]
+	assert.Equal(t, "media", image.Type)
+	assert.Equal(t, "image", image.Attrs["type"])
+	assert.Equal(t, "diagram.png", image.Attrs["id"])
+	assert.Equal(t, "Diagram", image.Attrs["alt"])
+
+	file := doc.Content[1].Content[0]
+	assert.Equal(t, "file", file.Attrs["type"])
+	assert.Equal(t, "att-1", file.Attrs["id"])
+
+	sized := doc.Content[2].Content[0]
+	assert.Equal(t, "photo.jpg", sized.Attrs["id"])
+	assert.Nil(t, sized.Attrs["alt"])
+}
+
+func TestObsidianCalloutParsing(t *testing.T) {
+	doc := convertReverseDoc(t, obsidianReverseConfig,
+		"> [!tip] Heads up\n> First line\n> continues\n>\n> - item\n\n"+
+			"> [!faq]- Folded\n> Hidden\n>\n> > [!note]+\n> > Deeper\n\n"+
+			"> [!custom]\n> Unknown type\n\n"+
+			"> Plain quote\n")
+
+	require.Len(t, doc.Content, 4)
+	panel := doc.Content[0]
+	assert.Equal(t, "panel", panel.Type)
+	assert.Equal(t, "success", panel.Attrs["panelType"])
+	assert.Equal(t, "Heads up", panel.Attrs["title"])
+	require.Len(t, panel.Content, 2)
+	assert.Equal(t, "First line continues", paragraphText(panel.Content[0]))
+	assert.Equal(t, "bulletList", panel.Content[1].Type)
+
+	expand := doc.Content[1]
+	assert.Equal(t, "expand", expand.Type)
+	assert.Equal(t, "Folded", expand.Attrs["title"])
+	require.Len(t, expand.Content, 2)
+	assert.Equal(t, "nestedExpand", expand.Content[1].Type)
+	assert.Nil(t, expand.Content[1].Attrs)
+
+	assert.Equal(t, "note", doc.Content[2].Attrs["panelType"])
+	assert.Equal(t, "blockquote", doc.Content[3].Type)
+}
+
+func TestFoldableCalloutTypeIsDroppedWithWarning(t *testing.T) {
+	result, err := newHookReverseConverter(t, obsidianReverseConfig).Convert("> [!tip]- Title\n> Hidden\n\n> [!note]- Plain\n> Hidden\n")
+	require.NoError(t, err)
+
+	doc := decodeADFDoc(t, result.ADF)
+	require.Len(t, doc.Content, 2)
+	assert.Equal(t, "expand", doc.Content[0].Type)
+	assert.Equal(t, "Title", doc.Content[0].Attrs["title"])
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, converter.WarningDroppedFeature, result.Warnings[0].Type)
+	assert.Contains(t, result.Warnings[0].Message, `"tip"`)
+}
+
+func TestObsidianHighlightAndComments(t *testing.T) {
+	doc := convertReverseDoc(t, obsidianReverseConfig,
+		"Keep ==*this*== and a == b %%hidden%% done\n\n%%\nblock\ncomment\n%%\n\nAfter")
+
+	require.Len(t, doc.Content, 2)
+	content := doc.Content[0].Content
+	require.Len(t, content, 3)
+	assert.Equal(t, "Keep ", content[0].Text)
+	assert.Equal(t, "this", content[1].Text)
+	assert.ElementsMatch(t, []converter.Mark{
+		{Type: "em"},
+		{Type: "backgroundColor", Attrs: map[string]interface{}{"color": "#fff0b3"}},
+	}, content[1].Marks)
+	assert.Equal(t, " and a == b  done", content[2].Text)
+	assert.Equal(t, "After", paragraphText(doc.Content[1]))
+}
+
+func TestObsidianSyntaxIsTextWhenDetectionIsOff(t *testing.T) {
+	doc := convertReverseDoc(t, ReverseConfig{}, "[[Notes]] ==mark== %%note%%")
+
+	require.Len(t, doc.Content, 1)
+	assert.Equal(t, "[[Notes]] ==mark== %%note%%", paragraphText(doc.Content[0]))
+}
+
+func TestObsidianRoundTrip(t *testing.T) {
+	input := []byte(`{"version":1,"type":"doc","content":[
+		{"type":"panel","attrs":{"panelType":"warning","title":"Careful"},"content":[
+			{"type":"paragraph","content":[
+				{"type":"text","text":"Read "},
+				{"type":"text","text":"Setup","marks":[{"type":"link","attrs":{"href":"Setup"}}]},
+				{"type":"text","text":" "},
+				{"type":"text","text":"first","marks":[{"type":"backgroundColor","attrs":{"color":"#fff0b3"}}]}
+			]}
+		]},
+		{"type":"expand","attrs":{"title":"More"},"content":[{"type":"paragraph","content":[{"type":"text","text":"hidden"}]}]},
+		{"type":"mediaSingle","content":[{"type":"media","attrs":{"type":"image","id":"diagram.png","alt":"Diagram"}}]}
+	]}`)
+
+	forward, err := converter.New(converter.Config{
+		PanelStyle:           converter.PanelObsidian,
+		ExpandStyle:          converter.ExpandObsidian,
+		BackgroundColorStyle: converter.ColorObsidian,
+		WikiLinks:            true,
+	})
+	require.NoError(t, err)
+	markdown, err := forward.Convert(input)
+	require.NoError(t, err)
+
+	doc := convertReverseDoc(t, obsidianReverseConfig, markdown.Markdown)
+
+	require.Len(t, doc.Content, 3, markdown.Markdown)
+	panel := doc.Content[0]
+	assert.Equal(t, "panel", panel.Type)
+	assert.Equal(t, "warning", panel.Attrs["panelType"])
+	assert.Equal(t, "Careful", panel.Attrs["title"])
+	paragraph := panel.Content[0].Content
+	require.Len(t, paragraph, 4)
+	assert.Equal(t, "Setup", paragraph[1].Marks[0].Attrs["href"])
+	assert.Equal(t, "backgroundColor", paragraph[3].Marks[0].Type)
+
+	assert.Equal(t, "expand", doc.Content[1].Type)
+	assert.Equal(t, "More", doc.Content[1].Attrs["title"])
+
+	media := doc.Content[2].Content[0]
+	assert.Equal(t, "diagram.png", media.Attrs["id"])
+	assert.Equal(t, "Diagram", media.Attrs["alt"])
+}
+
+func TestEmptyObsidianCalloutGetsAnEmptyParagraph(t *testing.T) {
+	doc := convertReverseDoc(t, ReverseConfig{PanelDetection: PanelDetectObsidian}, "> [!tip] Title\n")
+
+	require.Len(t, doc.Content, 1)
+	assert.Equal(t, "panel", doc.Content[0].Type)
+	assert.Equal(t, "Title", doc.Content[0].Attrs["title"])
+	assert.Equal(t, []converter.Node{{Type: "paragraph"}}, doc.Content[0].Content)
+}
+
+func TestAllDetectionLeavesObsidianSyntaxAlone(t *testing.T) {
+	doc := convertReverseDoc(t, ReverseConfig{
+		PanelDetection:  PanelDetectAll,
+		ExpandDetection: ExpandDetectAll,
+		ColorDetection:  ColorDetectAll,
+	}, "> [!tip]- Folded\n> Body\n\nSome ==text==\n")
+
+	for _, node := range doc.Content {
+		assert.NotEqual(t, "expand", node.Type)
+	}
+	last := doc.Content[len(doc.Content)-1]
+	assert.Equal(t, "Some ==text==", paragraphText(last))
+}
@@ -193,7 +193,7 @@ func (s *state) convertPandocDivNode(node *PandocDivNode) (converter.Node, bool,
 
 	if hasPandocClass(node.Classes, "adf-extension") {
 		extensionKey := node.Attrs["key"]
-		if extensionKey != "" && s.config.ExtensionHandlers != nil {
+		if extensionKey != "" && s.config.ExtensionHandlers != nil && s.collector == nil {
 			if handler, ok := s.config.ExtensionHandlers[extensionKey]; ok {
 				metadata := make(map[string]string)
 				for k, v := range node.Attrs {
@@ -203,10 +203,11 @@ func (s *state) convertPandocDivNode(node *PandocDivNode) (converter.Node, bool,
 				}
 
 				input := converter.ExtensionParseInput{
-					SourcePath:   s.options.SourcePath,
-					ExtensionKey: extensionKey,
-					Body:         node.Body(),
-					Metadata:     metadata,
+					SourcePath:       s.options.SourcePath,
+					ExtensionKey:     extensionKey,
+					Body:             node.Body(),
+					Metadata:         metadata,
+					DocumentMetadata: s.metadata,
 				}
 
 				output, err := handler.FromMarkdown(s.ctx, input)
@@ -77,6 +77,28 @@ func (s *state) shouldDetectExpandPandoc() bool {
 	return s.config.ExpandDetection == ExpandDetectPandoc || s.config.ExpandDetection == ExpandDetectAll
 }
 
+// Docs-site admonitions and Obsidian callouts are only read when their flavor is selected;
+// the all settings keep the detection they had before these flavors existed.
+func (s *state) shouldDetectPanelAdmonition(flavor AdmonitionFlavor) bool {
+	return s.config.PanelDetection == PanelDetection(flavor)
+}
+
+func (s *state) shouldDetectExpandAdmonition(flavor AdmonitionFlavor) bool {
+	return s.config.ExpandDetection == ExpandDetection(flavor)
+}
+
+func (s *state) shouldDetectPanelObsidian() bool {
+	return s.config.PanelDetection == PanelDetectObsidian
+}
+
+func (s *state) shouldDetectExpandObsidian() bool {
+	return s.config.ExpandDetection == ExpandDetectObsidian
+}
+
+func (s *state) shouldDetectHighlight() bool {
+	return s.config.needsHighlightParser()
+}
+
 func (s *state) shouldDetectLayoutSectionHTML() bool {
 	return s.config.LayoutSectionDetection == LayoutSectionDetectHTML || s.config.LayoutSectionDetection == LayoutSectionDetectAll
 }
@@ -0,0 +1,31 @@
+package mdconverter
+
+import (
+	"testing"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertReturnsReferences(t *testing.T) {
+	conv, err := New(ReverseConfig{MentionDetection: MentionDetectLink})
+	require.NoError(t, err)
+
+	result, err := conv.Convert("See [Spec](https://example.com/docs/spec.md#usage) and [@Ada](mention:user-1).\n\n![Diagram](https://cdn.example/diagram.png)\n\n[https://jira.example/browse/ENG-1](https://jira.example/browse/ENG-1)\n")
+	require.NoError(t, err)
+
+	refs := result.References
+	require.Len(t, refs.Links, 1)
+	assert.Equal(t, "/content/0/content/1", refs.Links[0].Path)
+	assert.Equal(t, converter.LinkMetadata{Filename: "spec.md", Anchor: "usage"}, refs.Links[0].Meta)
+
+	assert.Equal(t, []converter.MentionReference{{Path: "/content/0/content/3", ID: "user-1", Text: "Ada"}}, refs.Mentions)
+
+	require.Len(t, refs.Media, 1)
+	assert.Equal(t, "diagram.png", refs.Media[0].Filename)
+	assert.Equal(t, "https://cdn.example/diagram.png", refs.Media[0].URL)
+
+	require.Len(t, refs.InlineCards, 1)
+	assert.Equal(t, "https://jira.example/browse/ENG-1", refs.InlineCards[0].URL)
+}
@@ -6,4 +6,11 @@ import "github.com/rgonek/jira-adf-converter/converter"
 type Result struct {
 	ADF      []byte              `json:"adf"`
 	Warnings []converter.Warning `json:"warnings,omitempty"`
+	// Metadata holds the YAML front matter that opened the Markdown, if any.
+	Metadata map[string]any `json:"metadata,omitempty"`
+	// CacheStats reports HookCache hits and misses; it is zero when no cache is configured.
+	CacheStats HookCacheStats `json:"cacheStats,omitzero"`
+	// References lists links, media, mentions, inlineCards, emoji and extensions of the
+	// generated ADF; paths point into the ADF document.
+	References converter.References `json:"references,omitzero"`
 }
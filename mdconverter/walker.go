@@ -53,8 +53,14 @@ func (s *state) convertBlockNode(node ast.Node) (converter.Node, bool, error) {
 		return s.convertTableNode(typed)
 	case *PandocDivNode:
 		return s.convertPandocDivNode(typed)
+	case *AdmonitionNode:
+		return s.convertAdmonitionNode(typed)
 	case *PandocGridTableNode:
 		return s.convertPandocGridTableNode(typed)
+	case *ObsidianCommentNode:
+		return converter.Node{}, false, nil
+	case *MathBlockNode:
+		return s.convertMathBlockNode(typed)
 	default:
 		nodeKind := typed.Kind().String()
 		textValue := strings.TrimSpace(string(node.Text(s.source)))
@@ -365,7 +371,8 @@ func isNestedExpandContext(parent ast.Node) bool {
 }
 
 func (s *state) shouldDetectExpandHTML() bool {
-	return s.config.ExpandDetection == ExpandDetectHTML || s.config.ExpandDetection == ExpandDetectAll
+	return s.config.ExpandDetection == ExpandDetectHTML || s.config.ExpandDetection == ExpandDetectDocusaurus ||
+		s.config.ExpandDetection == ExpandDetectAll
 }
 
 func (s *state) appendConvertedBlock(content []converter.Node, next converter.Node, mergeNextParagraph *bool) []converter.Node {
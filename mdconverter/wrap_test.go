@@ -0,0 +1,49 @@
+package mdconverter
+
+import (
+	"testing"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrappedMarkdownKeepsMeaning(t *testing.T) {
+	input := []byte(`{"version":1,"type":"doc","content":[
+		{"type":"paragraph","content":[
+			{"type":"text","text":"Plain words then "},
+			{"type":"text","text":"bold words that span a break","marks":[{"type":"strong"}]},
+			{"type":"text","text":" and "},
+			{"type":"text","text":"a link with words","marks":[{"type":"link","attrs":{"href":"https://example.com/a?b=c"}}]},
+			{"type":"text","text":" - 2. # > + * = | : ! not markers"},
+			{"type":"hardBreak"},
+			{"type":"text","text":"after the break "},
+			{"type":"text","text":"code with spaces","marks":[{"type":"code"}]}
+		]},
+		{"type":"orderedList","content":[{"type":"listItem","content":[
+			{"type":"paragraph","content":[{"type":"text","text":"An ordered item long enough to wrap a few times"}]},
+			{"type":"bulletList","content":[{"type":"listItem","content":[
+				{"type":"paragraph","content":[{"type":"text","text":"A nested item long enough to wrap a few times"}]}
+			]}]}
+		]}]},
+		{"type":"taskList","content":[{"type":"taskItem","attrs":{"state":"DONE"},"content":[
+			{"type":"text","text":"A task long enough to wrap a few times"}
+		]}]},
+		{"type":"panel","attrs":{"panelType":"warning"},"content":[
+			{"type":"paragraph","content":[{"type":"text","text":"A warning panel long enough to wrap a few times"}]}
+		]},
+		{"type":"blockquote","content":[
+			{"type":"paragraph","content":[{"type":"text","text":"A quote long enough to wrap a few times"}]}
+		]}
+	]}`)
+
+	convert := func(width int) converter.Doc {
+		forward, err := converter.New(converter.Config{WrapWidth: width})
+		require.NoError(t, err)
+		result, err := forward.Convert(input)
+		require.NoError(t, err)
+		return convertReverseDoc(t, ReverseConfig{}, result.Markdown)
+	}
+
+	assert.Equal(t, convert(0), convert(20))
+}
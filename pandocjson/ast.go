@@ -0,0 +1,154 @@
+package pandocjson
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// apiVersion is the Pandoc API version of rendered documents (Pandoc 3.x).
+var apiVersion = []int{1, 23, 1}
+
+// document is the top level of the Pandoc JSON AST.
+type document struct {
+	APIVersion []int                      `json:"pandoc-api-version"`
+	Meta       map[string]json.RawMessage `json:"meta"`
+	Blocks     []element                  `json:"blocks"`
+}
+
+// element is a rendered Pandoc block or inline: a constructor tag and its fields. A
+// constructor without fields has no "c", one field is stored as is and several fields
+// as an array.
+type element struct {
+	T string `json:"t"`
+	C any    `json:"c,omitempty"`
+}
+
+func newElement(tag string, fields ...any) element {
+	switch len(fields) {
+	case 0:
+		return element{T: tag}
+	case 1:
+		return element{T: tag, C: fields[0]}
+	default:
+		return element{T: tag, C: fields}
+	}
+}
+
+// rawElement is a parsed Pandoc block or inline whose fields are decoded on demand.
+type rawElement struct {
+	T string          `json:"t"`
+	C json.RawMessage `json:"c"`
+}
+
+// rawDocument is a parsed Pandoc JSON document.
+type rawDocument struct {
+	APIVersion []int        `json:"pandoc-api-version"`
+	Blocks     []rawElement `json:"blocks"`
+}
+
+// fields decodes the fields of an element into targets, in order. A single target
+// receives "c" itself.
+func (e rawElement) fields(targets ...any) error {
+	if len(targets) == 1 {
+		if err := json.Unmarshal(e.C, targets[0]); err != nil {
+			return fmt.Errorf("invalid %s: %w", e.T, err)
+		}
+		return nil
+	}
+	return decodeTuple(e.T, e.C, targets...)
+}
+
+// decodeTuple decodes a JSON array into targets, in order.
+func decodeTuple(name string, raw json.RawMessage, targets ...any) error {
+	var items []json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+	if len(items) != len(targets) {
+		return fmt.Errorf("invalid %s: expected %d fields, got %d", name, len(targets), len(items))
+	}
+	for i, target := range targets {
+		if err := json.Unmarshal(items[i], target); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// attr is a Pandoc Attr: identifier, classes and key-value pairs. It is encoded as
+// ["id", ["class", ...], [["key", "value"], ...]].
+type attr struct {
+	ID      string
+	Classes []string
+	Pairs   [][2]string
+}
+
+func (a attr) MarshalJSON() ([]byte, error) {
+	classes := a.Classes
+	if classes == nil {
+		classes = []string{}
+	}
+	pairs := a.Pairs
+	if pairs == nil {
+		pairs = [][2]string{}
+	}
+	return json.Marshal([]any{a.ID, classes, pairs})
+}
+
+func (a *attr) UnmarshalJSON(data []byte) error {
+	return decodeTuple("Attr", data, &a.ID, &a.Classes, &a.Pairs)
+}
+
+// hasClass reports whether the attr has the class.
+func (a attr) hasClass(name string) bool {
+	for _, class := range a.Classes {
+		if class == name {
+			return true
+		}
+	}
+	return false
+}
+
+// value returns the value of a key-value pair, or an empty string.
+func (a attr) value(key string) string {
+	for _, pair := range a.Pairs {
+		if pair[0] == key {
+			return pair[1]
+		}
+	}
+	return ""
+}
+
+// with returns the attr with a key-value pair added when the value is not empty.
+func (a attr) with(key, value string) attr {
+	if value != "" {
+		a.Pairs = append(a.Pairs, [2]string{key, value})
+	}
+	return a
+}
+
+// classAttr returns an attr with a single class.
+func classAttr(class string) attr {
+	return attr{Classes: []string{class}}
+}
+
+// target is the [url, title] pair of Pandoc links and images.
+type target [2]string
+
+// caption is a Pandoc Caption: an optional short caption and blocks.
+type caption struct {
+	Short  []element
+	Blocks []element
+}
+
+func (c caption) MarshalJSON() ([]byte, error) {
+	var short any
+	if c.Short != nil {
+		short = c.Short
+	}
+	blocks := c.Blocks
+	if blocks == nil {
+		blocks = []element{}
+	}
+	return json.Marshal([]any{short, blocks})
+}
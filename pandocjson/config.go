@@ -0,0 +1,40 @@
+package pandocjson
+
+import (
+	"fmt"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// Config holds the bridge configuration for both directions.
+type Config struct {
+	// UnknownNodes controls ADF nodes without a Pandoc mapping when rendering and Pandoc
+	// elements this package does not know when parsing.
+	UnknownNodes converter.UnknownPolicy `json:"unknownNodes,omitempty"`
+	UnknownMarks converter.UnknownPolicy `json:"unknownMarks,omitempty"`
+}
+
+func (c Config) applyDefaults() Config {
+	if c.UnknownNodes == "" {
+		c.UnknownNodes = converter.UnknownPlaceholder
+	}
+	if c.UnknownMarks == "" {
+		c.UnknownMarks = converter.UnknownSkip
+	}
+	return c
+}
+
+// Validate checks that config values are valid.
+func (c Config) Validate() error {
+	if !isValidUnknownPolicy(c.UnknownNodes) {
+		return fmt.Errorf("invalid unknownNodes policy %q", c.UnknownNodes)
+	}
+	if !isValidUnknownPolicy(c.UnknownMarks) {
+		return fmt.Errorf("invalid unknownMarks policy %q", c.UnknownMarks)
+	}
+	return nil
+}
+
+func isValidUnknownPolicy(policy converter.UnknownPolicy) bool {
+	return policy == converter.UnknownError || policy == converter.UnknownSkip || policy == converter.UnknownPlaceholder
+}
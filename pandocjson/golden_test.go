@@ -0,0 +1,116 @@
+package pandocjson
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lossyFixtures lists the shared ADF fixtures that do not survive a Pandoc JSON round
+// trip unchanged, with the reason.
+var lossyFixtures = map[string]string{
+	"blocks/heading_align_html.json":                              "align is not an ADF attr; it comes back as layout",
+	"blocks/heading_align_html_marks.json":                        "align is not an ADF attr; it comes back as layout",
+	"decisions/decision_decided.json":                             "decision items hold inline content only",
+	"decisions/decision_formatted.json":                           "decision items hold inline content only",
+	"decisions/decision_list_multiple.json":                       "decision items hold inline content only",
+	"decisions/decision_multiline.json":                           "decision items hold inline content only",
+	"decisions/decision_no_state.json":                            "decision items hold inline content only",
+	"decisions/decision_undecided.json":                           "decision items hold inline content only",
+	"decisions/decision_empty.json":                               "empty blocks are dropped",
+	"edge_cases/empty_paragraph.json":                             "empty blocks are dropped",
+	"media/media_group_empty.json":                                "empty blocks are dropped",
+	"nodes/heading_empty.json":                                    "empty blocks are dropped",
+	"expanders/expand_empty.json":                                 "empty containers get an empty paragraph",
+	"nodes/blockquote_empty.json":                                 "empty containers get an empty paragraph",
+	"panels/panel_empty.json":                                     "empty containers get an empty paragraph",
+	"tables/table_empty_cells.json":                               "empty containers get an empty paragraph",
+	"expanders/expand_in_list.json":                               "nestedExpand outside an expand comes back as expand",
+	"edge_cases/non_text_node_boundary.json":                      "unknown nodes are rendered as placeholder text",
+	"edge_cases/unknown_node.json":                                "unknown nodes are rendered as placeholder text",
+	"extensions/ext_strip.json":                                   "top-level inline nodes are rendered as placeholder text",
+	"extensions/bodied_ext_json.json":                             "task item localIds are not kept",
+	"extensions/bodied_ext_standard.json":                         "task item localIds are not kept",
+	"extensions/ext_text.json":                                    "the text attr of a bodied extension is not kept",
+	"inline/emoji_fallback.json":                                  "emoji fallback comes back as text",
+	"inline/emoji_missing_both.json":                              "emoji fallback comes back as text",
+	"inline/emoji_unicode.json":                                   "emoji fallback comes back as text",
+	"inline/inline_card_empty.json":                               "inline cards keep only their URL",
+	"inline/inline_card_with_data.json":                           "inline cards keep only their URL",
+	"inline/inline_card_with_title_pandoc.json":                   "inline cards keep only their URL",
+	"inline/inlinecard_embed.json":                                "inline cards keep only their URL",
+	"inline/inlinecard_embed_with_text.json":                      "inline cards keep only their URL",
+	"media/media_image_no_alt.json":                               "image media with a url come back with it as id",
+	"media/media_image_url.json":                                  "image media with a url come back with it as id",
+	"media/media_in_table.json":                                   "image media with a url come back with it as id",
+	"marks/bgcolor_html_invalid_injection.json":                   "invalid and unknown marks are dropped",
+	"marks/color_html_invalid_injection.json":                     "invalid and unknown marks are dropped",
+	"marks/link_missing_href.json":                                "invalid and unknown marks are dropped",
+	"marks/multiple_unknown_marks.json":                           "invalid and unknown marks are dropped",
+	"marks/unknown_placeholder_mixed.json":                        "invalid and unknown marks are dropped",
+	"marks/unknown_placeholder_multiple_output.json":              "invalid and unknown marks are dropped",
+	"marks/unknown_placeholder_single.json":                       "invalid and unknown marks are dropped",
+	"marks/unknown_placeholder_with_known_mark.json":              "invalid and unknown marks are dropped",
+	"marks/unknown_placeholder_whitespace_continuity_output.json": "adjacent text with the same marks is merged",
+	"marks/leading_marked_space.json":                             "adjacent text with the same marks is merged",
+	"marks/whitespace_continuity.json":                            "adjacent text with the same marks is merged",
+	"nodes/heading_trailing_space.json":                           "trailing whitespace is trimmed",
+	"marks/link_empty_text.json":                                  "links without text are dropped",
+}
+
+func TestGoldenRoundTrip(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("..", "testdata", "*", "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	c := newTestConverter(t, Config{})
+	for _, path := range paths {
+		fixture := filepath.ToSlash(path[len(filepath.Join("..", "testdata"))+1:])
+		t.Run(fixture, func(t *testing.T) {
+			if reason, ok := lossyFixtures[fixture]; ok {
+				t.Skip(reason)
+			}
+
+			input, err := os.ReadFile(path)
+			require.NoError(t, err)
+			rendered, err := c.Render(input)
+			require.NoError(t, err)
+			parsed, err := c.Parse(rendered.JSON)
+			require.NoError(t, err)
+
+			assert.Equal(t, normalizeGoldenDoc(t, input), normalizeGoldenDoc(t, parsed.ADF))
+		})
+	}
+}
+
+// normalizeGoldenDoc decodes an ADF document into generic JSON, with the defaults that
+// Pandoc JSON does not distinguish filled in: the document version and ordered list order.
+func normalizeGoldenDoc(t *testing.T, data []byte) interface{} {
+	t.Helper()
+
+	var doc converter.Doc
+	require.NoError(t, json.Unmarshal(data, &doc))
+	doc.Version = 1
+	doc.Content = normalizeGoldenNodes(doc.Content)
+
+	normalized, err := json.Marshal(doc)
+	require.NoError(t, err)
+	var generic interface{}
+	require.NoError(t, json.Unmarshal(normalized, &generic))
+	return generic
+}
+
+func normalizeGoldenNodes(nodes []converter.Node) []converter.Node {
+	for i := range nodes {
+		if nodes[i].Type == "orderedList" && nodes[i].GetIntAttr("order", 1) == 1 {
+			delete(nodes[i].Attrs, "order")
+		}
+		nodes[i].Content = normalizeGoldenNodes(nodes[i].Content)
+	}
+	return nodes
+}
@@ -0,0 +1,164 @@
+// Package pandocjson converts between ADF and the Pandoc JSON AST, so that documents can
+// be piped through `pandoc -f json` to DOCX, PDF or EPUB, and any format Pandoc reads can
+// be imported into ADF without going through Markdown text.
+//
+// ADF nodes with a Pandoc counterpart map to it directly. Mentions, status lozenges,
+// dates, emoji, panels, expands, layouts, decisions and extensions become Span and Div
+// elements whose class names the ADF node and whose key-value attributes carry its
+// attrs; class names shared with the Pandoc Markdown strategy ("mention", "details",
+// "layoutSection", "adf-bodied-extension", ...) are kept so existing filters keep
+// working.
+package pandocjson
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// Converter converts ADF to Pandoc JSON and back.
+type Converter struct {
+	config Config
+}
+
+// state holds the per-conversion state, making the converter thread-safe.
+type state struct {
+	config   Config
+	ctx      context.Context
+	options  converter.ConvertOptions
+	warnings []converter.Warning
+
+	expandDepth int
+}
+
+// New creates a new Converter with the given config.
+func New(config Config) (*Converter, error) {
+	cfg := config.applyDefaults()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Converter{
+		config: cfg,
+	}, nil
+}
+
+// Render takes an ADF JSON document and returns a Pandoc JSON document.
+func (c *Converter) Render(input []byte) (RenderResult, error) {
+	return c.RenderWithContext(context.Background(), input, converter.ConvertOptions{})
+}
+
+// RenderWithContext takes an ADF JSON document and returns a Pandoc JSON document.
+func (c *Converter) RenderWithContext(ctx context.Context, input []byte, opts converter.ConvertOptions) (RenderResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := ctx.Err(); err != nil {
+		return RenderResult{}, err
+	}
+
+	var doc converter.Doc
+	if err := json.Unmarshal(input, &doc); err != nil {
+		return RenderResult{}, fmt.Errorf("failed to parse ADF JSON: %w", err)
+	}
+
+	s := c.newState(ctx, opts)
+	blocks, err := s.renderBlocks(doc.Content)
+	if err != nil {
+		return RenderResult{}, err
+	}
+	if err := s.checkContext(); err != nil {
+		return RenderResult{}, err
+	}
+
+	output, err := json.Marshal(document{
+		APIVersion: apiVersion,
+		Meta:       map[string]json.RawMessage{},
+		Blocks:     blocks,
+	})
+	if err != nil {
+		return RenderResult{}, fmt.Errorf("failed to marshal Pandoc JSON: %w", err)
+	}
+
+	return RenderResult{
+		JSON:       output,
+		Warnings:   s.warnings,
+		References: converter.CollectReferences(doc),
+	}, nil
+}
+
+// Parse takes a Pandoc JSON document and returns an ADF document.
+func (c *Converter) Parse(input []byte) (ParseResult, error) {
+	return c.ParseWithContext(context.Background(), input, converter.ConvertOptions{})
+}
+
+// ParseWithContext takes a Pandoc JSON document and returns an ADF document.
+func (c *Converter) ParseWithContext(ctx context.Context, input []byte, opts converter.ConvertOptions) (ParseResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := ctx.Err(); err != nil {
+		return ParseResult{}, err
+	}
+
+	var pandoc rawDocument
+	if err := json.Unmarshal(input, &pandoc); err != nil {
+		return ParseResult{}, fmt.Errorf("failed to parse Pandoc JSON: %w", err)
+	}
+	if len(pandoc.APIVersion) == 0 || pandoc.APIVersion[0] != apiVersion[0] || (len(pandoc.APIVersion) > 1 && pandoc.APIVersion[1] < 21) {
+		return ParseResult{}, fmt.Errorf("unsupported pandoc-api-version %v: expected 1.21 or later", pandoc.APIVersion)
+	}
+
+	s := c.newState(ctx, opts)
+	content, err := s.parseBlocks(pandoc.Blocks)
+	if err != nil {
+		return ParseResult{}, err
+	}
+	if err := s.checkContext(); err != nil {
+		return ParseResult{}, err
+	}
+
+	doc := converter.Doc{
+		Version: 1,
+		Type:    "doc",
+		Content: content,
+	}
+	adf, err := json.Marshal(doc)
+	if err != nil {
+		return ParseResult{}, fmt.Errorf("failed to marshal ADF JSON: %w", err)
+	}
+
+	return ParseResult{
+		Doc:        doc,
+		ADF:        adf,
+		Warnings:   s.warnings,
+		References: converter.CollectReferences(doc),
+	}, nil
+}
+
+func (c *Converter) newState(ctx context.Context, opts converter.ConvertOptions) *state {
+	return &state{
+		config:  c.config,
+		ctx:     ctx,
+		options: opts,
+	}
+}
+
+func (s *state) checkContext() error {
+	if s.ctx == nil {
+		return nil
+	}
+	return s.ctx.Err()
+}
+
+func (s *state) addWarning(warnType converter.WarningType, nodeType, message string) {
+	s.warnings = append(s.warnings, converter.Warning{
+		Type:     warnType,
+		NodeType: nodeType,
+		Message:  message,
+	})
+}
@@ -0,0 +1,232 @@
+package pandocjson
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConverter(t *testing.T, cfg Config) *Converter {
+	t.Helper()
+	c, err := New(cfg)
+	require.NoError(t, err)
+	return c
+}
+
+func TestRenderBasicBlocksAndMarks(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Title"}]},
+		{"type":"paragraph","content":[
+			{"type":"text","text":"Hello ","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]},
+			{"type":"text","text":"bold world","marks":[{"type":"link","attrs":{"href":"https://example.com"}},{"type":"strong"}]},
+			{"type":"text","text":" and "},
+			{"type":"text","text":"x := 1","marks":[{"type":"code"}]}
+		]},
+		{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"fmt.Println()"}]}
+	]}`)
+
+	result, err := newTestConverter(t, Config{}).Render(input)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"pandoc-api-version":[1,23,1],"meta":{},"blocks":[
+		{"t":"Header","c":[2,["",[],[]],[{"t":"Str","c":"Title"}]]},
+		{"t":"Para","c":[
+			{"t":"Link","c":[["",[],[]],[
+				{"t":"Str","c":"Hello"},{"t":"Space"},
+				{"t":"Strong","c":[{"t":"Str","c":"bold"},{"t":"Space"},{"t":"Str","c":"world"}]}
+			],["https://example.com",""]]},
+			{"t":"Space"},{"t":"Str","c":"and"},{"t":"Space"},
+			{"t":"Code","c":[["",[],[]],"x := 1"]}
+		]},
+		{"t":"CodeBlock","c":[["",["go"],[]],"fmt.Println()"]}
+	]}`, string(result.JSON))
+	assert.Empty(t, result.Warnings)
+	require.Len(t, result.References.Links, 2)
+}
+
+func TestRenderInlineNodesAsSpans(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[{"type":"paragraph","content":[
+		{"type":"mention","attrs":{"id":"u1","text":"@Ada"}},
+		{"type":"status","attrs":{"text":"Done","color":"green"}},
+		{"type":"date","attrs":{"timestamp":"1700000000000"}},
+		{"type":"text","text":"red","marks":[{"type":"textColor","attrs":{"color":"#ff0000"}}]}
+	]}]}`)
+
+	result, err := newTestConverter(t, Config{}).Render(input)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"pandoc-api-version":[1,23,1],"meta":{},"blocks":[{"t":"Para","c":[
+		{"t":"Span","c":[["",["mention"],[["mention-id","u1"]]],[{"t":"Str","c":"@Ada"}]]},
+		{"t":"Span","c":[["",["status"],[["color","green"]]],[{"t":"Str","c":"Done"}]]},
+		{"t":"Span","c":[["",["date"],[["timestamp","1700000000000"]]],[{"t":"Str","c":"2023-11-14"}]]},
+		{"t":"Span","c":[["",[],[["style","color: #ff0000;"]]],[{"t":"Str","c":"red"}]]}
+	]}]}`, string(result.JSON))
+}
+
+func TestRenderTaskListAndTable(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"taskList","content":[
+			{"type":"taskItem","attrs":{"state":"DONE"},"content":[{"type":"text","text":"Write"}]},
+			{"type":"taskList","content":[
+				{"type":"taskItem","attrs":{"state":"TODO"},"content":[{"type":"text","text":"Review"}]}
+			]}
+		]},
+		{"type":"table","content":[
+			{"type":"tableRow","content":[{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"H"}]}]}]},
+			{"type":"tableRow","content":[{"type":"tableCell","attrs":{"background":"#eeeeee"},"content":[{"type":"paragraph","content":[{"type":"text","text":"C"}]}]}]}
+		]}
+	]}`)
+
+	result, err := newTestConverter(t, Config{}).Render(input)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"pandoc-api-version":[1,23,1],"meta":{},"blocks":[
+		{"t":"BulletList","c":[[
+			{"t":"Plain","c":[{"t":"Str","c":"☒"},{"t":"Space"},{"t":"Str","c":"Write"}]},
+			{"t":"BulletList","c":[[{"t":"Plain","c":[{"t":"Str","c":"☐"},{"t":"Space"},{"t":"Str","c":"Review"}]}]]}
+		]]},
+		{"t":"Table","c":[
+			["",[],[]],
+			[null,[]],
+			[[{"t":"AlignDefault"},{"t":"ColWidthDefault"}]],
+			[["",[],[]],[[["",[],[]],[[["",[],[]],{"t":"AlignDefault"},1,1,[{"t":"Para","c":[{"t":"Str","c":"H"}]}]]]]]],
+			[[["",[],[]],0,[],[[["",[],[]],[[["",[],[["background","#eeeeee"]]],{"t":"AlignDefault"},1,1,[{"t":"Para","c":[{"t":"Str","c":"C"}]}]]]]]]],
+			[["",[],[]],[]]
+		]}
+	]}`, string(result.JSON))
+}
+
+func TestRoundTripPreservesADFNodes(t *testing.T) {
+	input := `{"version":1,"type":"doc","content":[
+		{"type":"heading","attrs":{"level":1,"layout":"center"},"content":[{"type":"text","text":"Centered"}]},
+		{"type":"panel","attrs":{"panelType":"warning","title":"Careful"},"content":[
+			{"type":"paragraph","content":[
+				{"type":"mention","attrs":{"id":"u1","text":"@Ada"}},
+				{"type":"text","text":" is "},
+				{"type":"status","attrs":{"color":"blue","text":"In Progress"}},
+				{"type":"text","text":" "},
+				{"type":"emoji","attrs":{"id":"1f600","shortName":":grinning:","text":"😀"}}
+			]}
+		]},
+		{"type":"expand","attrs":{"title":"More"},"content":[
+			{"type":"nestedExpand","attrs":{"title":"Inner"},"content":[{"type":"paragraph","content":[{"type":"text","text":"deep"}]}]}
+		]},
+		{"type":"layoutSection","content":[
+			{"type":"layoutColumn","attrs":{"width":50},"content":[{"type":"paragraph","content":[{"type":"text","text":"left"}]}]},
+			{"type":"layoutColumn","attrs":{"width":50},"content":[{"type":"paragraph","content":[{"type":"text","text":"right"}]}]}
+		]},
+		{"type":"decisionList","content":[
+			{"type":"decisionItem","attrs":{"state":"DECIDED"},"content":[{"type":"text","text":"Ship it"}]}
+		]},
+		{"type":"extension","attrs":{"extensionKey":"toc","extensionType":"com.atlassian.confluence.macro.core","parameters":{"macroParams":{"maxLevel":{"value":"3"}}},"text":"body"}},
+		{"type":"bodiedExtension","attrs":{"extensionKey":"note","extensionType":"com.example"},"content":[{"type":"paragraph","content":[{"type":"text","text":"inside"}]}]},
+		{"type":"paragraph","content":[
+			{"type":"inlineExtension","attrs":{"extensionKey":"badge","extensionType":"com.example","text":"new"}},
+			{"type":"text","text":" "},
+			{"type":"inlineCard","attrs":{"url":"https://example.com/page"}},
+			{"type":"text","text":" "},
+			{"type":"text","text":"sub","marks":[{"type":"subsup","attrs":{"type":"sub"}},{"type":"underline"}]}
+		]},
+		{"type":"mediaSingle","attrs":{"layout":"wide","width":80},"content":[
+			{"type":"media","attrs":{"type":"file","id":"abc","collection":"files","alt":"diagram"}},
+			{"type":"caption","content":[{"type":"text","text":"A diagram"}]}
+		]}
+	]}`
+
+	c := newTestConverter(t, Config{})
+	rendered, err := c.Render([]byte(input))
+	require.NoError(t, err)
+	parsed, err := c.Parse(rendered.JSON)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, input, string(parsed.ADF))
+	assert.Empty(t, rendered.Warnings)
+	assert.Empty(t, parsed.Warnings)
+}
+
+func TestParsePandocDocument(t *testing.T) {
+	input := []byte(`{"pandoc-api-version":[1,23,1],"meta":{},"blocks":[
+		{"t":"Para","c":[
+			{"t":"Emph","c":[{"t":"Str","c":"Hi"}]},{"t":"SoftBreak"},
+			{"t":"Quoted","c":[{"t":"DoubleQuote"},[{"t":"Str","c":"there"}]]},
+			{"t":"Note","c":[]}
+		]},
+		{"t":"OrderedList","c":[[3,{"t":"Decimal"},{"t":"Period"}],[[{"t":"Plain","c":[{"t":"Str","c":"three"}]}]]]},
+		{"t":"DefinitionList","c":[[[{"t":"Str","c":"Term"}],[[{"t":"Para","c":[{"t":"Str","c":"Meaning"}]}]]]]},
+		{"t":"Para","c":[{"t":"Str","c":"See"},{"t":"Space"},{"t":"Image","c":[["",[],[]],[{"t":"Str","c":"logo"}],["https://example.com/logo.png",""]]}]},
+		{"t":"RawBlock","c":["html","<hr>"]},
+		{"t":"Div","c":[["",[],[["style","text-align: right;"]]],[{"t":"Para","c":[{"t":"Str","c":"end"}]}]]}
+	]}`)
+
+	result, err := newTestConverter(t, Config{}).Parse(input)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"version":1,"type":"doc","content":[
+		{"type":"paragraph","content":[
+			{"type":"text","text":"Hi","marks":[{"type":"em"}]},
+			{"type":"text","text":" \"there\""}
+		]},
+		{"type":"orderedList","attrs":{"order":3},"content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"three"}]}]}]},
+		{"type":"bulletList","content":[{"type":"listItem","content":[
+			{"type":"paragraph","content":[{"type":"text","text":"Term","marks":[{"type":"strong"}]}]},
+			{"type":"paragraph","content":[{"type":"text","text":"Meaning"}]}
+		]}]},
+		{"type":"paragraph","content":[{"type":"text","text":"See"}]},
+		{"type":"mediaSingle","content":[{"type":"media","attrs":{"type":"external","url":"https://example.com/logo.png","alt":"logo"}}]},
+		{"type":"paragraph","attrs":{"layout":"right"},"content":[{"type":"text","text":"end"}]}
+	]}`, string(result.ADF))
+
+	var types []string
+	for _, warning := range result.Warnings {
+		types = append(types, warning.NodeType)
+	}
+	assert.Equal(t, []string{"Note", "RawBlock"}, types)
+}
+
+func TestParseRejectsUnsupportedAPIVersion(t *testing.T) {
+	_, err := newTestConverter(t, Config{}).Parse([]byte(`{"pandoc-api-version":[1,20],"meta":{},"blocks":[]}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported pandoc-api-version")
+}
+
+func TestUnknownNodePolicy(t *testing.T) {
+	adf := []byte(`{"type":"doc","content":[{"type":"mystery"}]}`)
+	pandoc := []byte(`{"pandoc-api-version":[1,23,1],"meta":{},"blocks":[{"t":"Mystery"}]}`)
+
+	_, err := newTestConverter(t, Config{UnknownNodes: converter.UnknownError}).Render(adf)
+	require.Error(t, err)
+	_, err = newTestConverter(t, Config{UnknownNodes: converter.UnknownError}).Parse(pandoc)
+	require.Error(t, err)
+
+	rendered, err := newTestConverter(t, Config{}).Render(adf)
+	require.NoError(t, err)
+	assert.Contains(t, string(rendered.JSON), "[Unknown")
+	require.Len(t, rendered.Warnings, 1)
+	assert.Equal(t, converter.WarningUnknownNode, rendered.Warnings[0].Type)
+
+	parsed, err := newTestConverter(t, Config{UnknownNodes: converter.UnknownSkip}).Parse(pandoc)
+	require.NoError(t, err)
+	assert.Empty(t, parsed.Doc.Content)
+	require.Len(t, parsed.Warnings, 1)
+}
+
+func TestRenderHonorsCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := newTestConverter(t, Config{}).RenderWithContext(ctx, []byte(`{"type":"doc","content":[]}`), converter.ConvertOptions{})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestConfigValidate(t *testing.T) {
+	_, err := New(Config{UnknownNodes: "bogus"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid unknownNodes policy")
+
+	_, err = New(Config{UnknownMarks: "bogus"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid unknownMarks policy")
+}
@@ -0,0 +1,733 @@
+package pandocjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// Pandoc table structures, decoded from their JSON arrays.
+type (
+	tableCell struct {
+		Attr    attr
+		RowSpan int
+		ColSpan int
+		Blocks  []rawElement
+	}
+	tableRow struct {
+		Attr  attr
+		Cells []tableCell
+	}
+	tableSection struct {
+		Attr attr
+		Rows []tableRow
+	}
+	tableBody struct {
+		Attr attr
+		Head []tableRow
+		Body []tableRow
+	}
+	rawCaption struct {
+		Blocks []rawElement
+	}
+)
+
+func (c *tableCell) UnmarshalJSON(data []byte) error {
+	var alignment json.RawMessage
+	return decodeTuple("Cell", data, &c.Attr, &alignment, &c.RowSpan, &c.ColSpan, &c.Blocks)
+}
+
+func (r *tableRow) UnmarshalJSON(data []byte) error {
+	return decodeTuple("Row", data, &r.Attr, &r.Cells)
+}
+
+func (t *tableSection) UnmarshalJSON(data []byte) error {
+	return decodeTuple("TableHead", data, &t.Attr, &t.Rows)
+}
+
+func (b *tableBody) UnmarshalJSON(data []byte) error {
+	var rowHeadColumns int
+	return decodeTuple("TableBody", data, &b.Attr, &rowHeadColumns, &b.Head, &b.Body)
+}
+
+func (c *rawCaption) UnmarshalJSON(data []byte) error {
+	var short json.RawMessage
+	return decodeTuple("Caption", data, &short, &c.Blocks)
+}
+
+func (s *state) parseBlocks(blocks []rawElement) ([]converter.Node, error) {
+	var nodes []converter.Node
+	for _, block := range blocks {
+		parsed, err := s.parseBlock(block)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, parsed...)
+	}
+	return nodes, nil
+}
+
+func (s *state) parseBlock(block rawElement) ([]converter.Node, error) {
+	if err := s.checkContext(); err != nil {
+		return nil, err
+	}
+
+	switch block.T {
+	case "Plain", "Para":
+		var inlines []rawElement
+		if err := block.fields(&inlines); err != nil {
+			return nil, err
+		}
+		return s.parseParagraph(inlines)
+
+	case "LineBlock":
+		var lines [][]rawElement
+		if err := block.fields(&lines); err != nil {
+			return nil, err
+		}
+		var inlines []rawElement
+		for i, line := range lines {
+			if i > 0 {
+				inlines = append(inlines, rawElement{T: "LineBreak"})
+			}
+			inlines = append(inlines, line...)
+		}
+		return s.parseParagraph(inlines)
+
+	case "CodeBlock":
+		var codeAttr attr
+		var text string
+		if err := block.fields(&codeAttr, &text); err != nil {
+			return nil, err
+		}
+		language := ""
+		if len(codeAttr.Classes) > 0 {
+			language = codeAttr.Classes[0]
+		}
+		return []converter.Node{codeBlock(language, text)}, nil
+
+	case "RawBlock":
+		var format, text string
+		if err := block.fields(&format, &text); err != nil {
+			return nil, err
+		}
+		s.addWarning(converter.WarningDroppedFeature, "RawBlock", fmt.Sprintf("raw %s block dropped", format))
+		return nil, nil
+
+	case "BlockQuote":
+		var blocks []rawElement
+		if err := block.fields(&blocks); err != nil {
+			return nil, err
+		}
+		content, err := s.parseBlocks(blocks)
+		if err != nil {
+			return nil, err
+		}
+		return []converter.Node{{Type: "blockquote", Content: ensureBlocks(content)}}, nil
+
+	case "OrderedList":
+		var listAttributes []json.RawMessage
+		var items [][]rawElement
+		if err := block.fields(&listAttributes, &items); err != nil {
+			return nil, err
+		}
+		list, err := s.parseList("orderedList", items)
+		if err != nil {
+			return nil, err
+		}
+		var start int
+		if len(listAttributes) > 0 && json.Unmarshal(listAttributes[0], &start) == nil && start != 1 {
+			list.Attrs = map[string]interface{}{"order": start}
+		}
+		return []converter.Node{list}, nil
+
+	case "BulletList":
+		var items [][]rawElement
+		if err := block.fields(&items); err != nil {
+			return nil, err
+		}
+		if isTaskList(items) {
+			list, err := s.parseTaskList(items)
+			if err != nil {
+				return nil, err
+			}
+			return []converter.Node{list}, nil
+		}
+		list, err := s.parseList("bulletList", items)
+		if err != nil {
+			return nil, err
+		}
+		return []converter.Node{list}, nil
+
+	case "DefinitionList":
+		return s.parseDefinitionList(block)
+
+	case "Header":
+		var level int
+		var headerAttr attr
+		var inlines []rawElement
+		if err := block.fields(&level, &headerAttr, &inlines); err != nil {
+			return nil, err
+		}
+		level = min(max(level, 1), 6)
+		return s.splitInline(inlines, func(content []converter.Node) converter.Node {
+			return converter.Node{Type: "heading", Attrs: map[string]interface{}{"level": level}, Content: content}
+		})
+
+	case "HorizontalRule":
+		return []converter.Node{{Type: "rule"}}, nil
+
+	case "Table":
+		return s.parseTable(block)
+
+	case "Figure":
+		return s.parseFigure(block)
+
+	case "Div":
+		var divAttr attr
+		var blocks []rawElement
+		if err := block.fields(&divAttr, &blocks); err != nil {
+			return nil, err
+		}
+		return s.parseDiv(divAttr, blocks)
+
+	default:
+		return s.unknownElement(block, true)
+	}
+}
+
+// parseParagraph parses inline content into paragraphs, split around images, which
+// become mediaSingle nodes between them.
+func (s *state) parseParagraph(inlines []rawElement) ([]converter.Node, error) {
+	return s.splitInline(inlines, func(content []converter.Node) converter.Node {
+		return converter.Node{Type: "paragraph", Content: content}
+	})
+}
+
+// splitInline parses inline content and wraps each run of inline nodes with wrap.
+// Media nodes are returned between the wrapped runs as mediaSingle nodes.
+func (s *state) splitInline(inlines []rawElement, wrap func([]converter.Node) converter.Node) ([]converter.Node, error) {
+	content, err := s.parseInlines(inlines, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes, segment []converter.Node
+	flush := func() {
+		if trimmed := trimInline(segment); len(trimmed) > 0 {
+			nodes = append(nodes, wrap(trimmed))
+		}
+		segment = nil
+	}
+	for _, node := range content {
+		if node.Type != "media" {
+			segment = append(segment, node)
+			continue
+		}
+		flush()
+		nodes = append(nodes, converter.Node{Type: "mediaSingle", Content: []converter.Node{node}})
+	}
+	flush()
+	return nodes, nil
+}
+
+// inlineContent parses inline content for nodes that only allow inline children, such
+// as task and decision items. Media nodes are dropped with a warning.
+func (s *state) inlineContent(inlines []rawElement) ([]converter.Node, error) {
+	content, err := s.parseInlines(inlines, nil)
+	if err != nil {
+		return nil, err
+	}
+	filtered := content[:0]
+	for _, node := range content {
+		if node.Type == "media" {
+			s.addWarning(converter.WarningDroppedFeature, node.Type, "image dropped from inline-only content")
+			continue
+		}
+		filtered = append(filtered, node)
+	}
+	return trimInline(filtered), nil
+}
+
+func (s *state) parseList(listType string, items [][]rawElement) (converter.Node, error) {
+	list := converter.Node{Type: listType}
+	for _, item := range items {
+		content, err := s.parseBlocks(item)
+		if err != nil {
+			return converter.Node{}, err
+		}
+		list.Content = append(list.Content, converter.Node{Type: "listItem", Content: ensureBlocks(content)})
+	}
+	return list, nil
+}
+
+// isTaskList reports whether every item of a bullet list starts with a checkbox.
+func isTaskList(items [][]rawElement) bool {
+	for _, item := range items {
+		if _, _, ok := taskItemInlines(item); !ok {
+			return false
+		}
+	}
+	return len(items) > 0
+}
+
+// taskItemInlines returns the inlines after the checkbox of a task list item and
+// whether the box is checked.
+func taskItemInlines(item []rawElement) ([]rawElement, bool, bool) {
+	if len(item) == 0 || (item[0].T != "Plain" && item[0].T != "Para") {
+		return nil, false, false
+	}
+	var inlines []rawElement
+	if err := item[0].fields(&inlines); err != nil || len(inlines) == 0 || inlines[0].T != "Str" {
+		return nil, false, false
+	}
+	var box string
+	if err := inlines[0].fields(&box); err != nil || (box != uncheckedBox && box != checkedBox) {
+		return nil, false, false
+	}
+	return inlines[1:], box == checkedBox, true
+}
+
+// parseTaskList parses a bullet list of checkbox items. Nested bullet lists become nested
+// task lists; other blocks of an item are dropped.
+func (s *state) parseTaskList(items [][]rawElement) (converter.Node, error) {
+	list := converter.Node{Type: "taskList"}
+	for _, item := range items {
+		inlines, checked, _ := taskItemInlines(item)
+		content, err := s.inlineContent(inlines)
+		if err != nil {
+			return converter.Node{}, err
+		}
+		itemState := "TODO"
+		if checked {
+			itemState = "DONE"
+		}
+		list.Content = append(list.Content, converter.Node{
+			Type:    "taskItem",
+			Attrs:   map[string]interface{}{"state": itemState},
+			Content: content,
+		})
+
+		for _, block := range item[1:] {
+			var nested [][]rawElement
+			if block.T != "BulletList" || block.fields(&nested) != nil || !isTaskList(nested) {
+				s.addWarning(converter.WarningDroppedFeature, block.T, "task item block content dropped")
+				continue
+			}
+			nestedList, err := s.parseTaskList(nested)
+			if err != nil {
+				return converter.Node{}, err
+			}
+			list.Content = append(list.Content, nestedList)
+		}
+	}
+	return list, nil
+}
+
+// parseDefinitionList parses a definition list as a bullet list whose items start with
+// the term in bold.
+func (s *state) parseDefinitionList(block rawElement) ([]converter.Node, error) {
+	var definitions []json.RawMessage
+	if err := block.fields(&definitions); err != nil {
+		return nil, err
+	}
+	list := converter.Node{Type: "bulletList"}
+	for _, definition := range definitions {
+		var term []rawElement
+		var bodies [][]rawElement
+		if err := decodeTuple("DefinitionList", definition, &term, &bodies); err != nil {
+			return nil, err
+		}
+		termContent, err := s.inlineContent(term)
+		if err != nil {
+			return nil, err
+		}
+		content := []converter.Node{{Type: "paragraph", Content: addMark(termContent, converter.Mark{Type: "strong"})}}
+		for _, body := range bodies {
+			parsed, err := s.parseBlocks(body)
+			if err != nil {
+				return nil, err
+			}
+			content = append(content, parsed...)
+		}
+		list.Content = append(list.Content, converter.Node{Type: "listItem", Content: content})
+	}
+	if len(list.Content) == 0 {
+		return nil, nil
+	}
+	return []converter.Node{list}, nil
+}
+
+// parseTable parses a table. Head rows and cells with the "header" class become header
+// cells; the caption and foot are appended as body rows and paragraphs.
+func (s *state) parseTable(block rawElement) ([]converter.Node, error) {
+	var tableAttr attr
+	var tableCaption rawCaption
+	var colSpecs json.RawMessage
+	var head, foot tableSection
+	var bodies []tableBody
+	if err := block.fields(&tableAttr, &tableCaption, &colSpecs, &head, &bodies, &foot); err != nil {
+		return nil, err
+	}
+
+	table := converter.Node{Type: "table"}
+	addRows := func(rows []tableRow, header bool) error {
+		for _, row := range rows {
+			tableRow := converter.Node{Type: "tableRow"}
+			for _, cell := range row.Cells {
+				parsed, err := s.parseTableCell(cell, header)
+				if err != nil {
+					return err
+				}
+				tableRow.Content = append(tableRow.Content, parsed)
+			}
+			if len(tableRow.Content) > 0 {
+				table.Content = append(table.Content, tableRow)
+			}
+		}
+		return nil
+	}
+
+	if err := addRows(head.Rows, true); err != nil {
+		return nil, err
+	}
+	for _, body := range bodies {
+		if err := addRows(body.Head, true); err != nil {
+			return nil, err
+		}
+		if err := addRows(body.Body, false); err != nil {
+			return nil, err
+		}
+	}
+	if err := addRows(foot.Rows, false); err != nil {
+		return nil, err
+	}
+	if len(table.Content) == 0 {
+		return nil, nil
+	}
+
+	nodes := []converter.Node{table}
+	captionContent, err := s.parseBlocks(tableCaption.Blocks)
+	if err != nil {
+		return nil, err
+	}
+	return append(nodes, captionContent...), nil
+}
+
+func (s *state) parseTableCell(cell tableCell, header bool) (converter.Node, error) {
+	content, err := s.parseBlocks(cell.Blocks)
+	if err != nil {
+		return converter.Node{}, err
+	}
+	node := converter.Node{Type: "tableCell", Content: ensureBlocks(content)}
+	if header || cell.Attr.hasClass("header") {
+		node.Type = "tableHeader"
+	}
+	attrs := map[string]interface{}{}
+	if cell.ColSpan > 1 {
+		attrs["colspan"] = cell.ColSpan
+	}
+	if cell.RowSpan > 1 {
+		attrs["rowspan"] = cell.RowSpan
+	}
+	if background := cell.Attr.value("background"); background != "" {
+		attrs["background"] = background
+	}
+	if len(attrs) > 0 {
+		node.Attrs = attrs
+	}
+	return node, nil
+}
+
+// parseFigure parses a figure holding an image as a mediaSingle with a caption. Other
+// figures keep their content and caption as blocks.
+func (s *state) parseFigure(block rawElement) ([]converter.Node, error) {
+	var figureAttr attr
+	var figureCaption rawCaption
+	var blocks []rawElement
+	if err := block.fields(&figureAttr, &figureCaption, &blocks); err != nil {
+		return nil, err
+	}
+
+	content, err := s.parseBlocks(blocks)
+	if err != nil {
+		return nil, err
+	}
+	var captionInlines []rawElement
+	for _, captionBlock := range figureCaption.Blocks {
+		var inlines []rawElement
+		if (captionBlock.T == "Plain" || captionBlock.T == "Para") && captionBlock.fields(&inlines) == nil {
+			captionInlines = append(captionInlines, inlines...)
+		}
+	}
+	captionContent, err := s.inlineContent(captionInlines)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(content) != 1 || content[0].Type != "mediaSingle" {
+		if len(captionContent) > 0 {
+			content = append(content, converter.Node{Type: "paragraph", Content: captionContent})
+		}
+		return content, nil
+	}
+
+	media := content[0]
+	attrs := map[string]interface{}{}
+	if layout := figureAttr.value("layout"); layout != "" {
+		attrs["layout"] = layout
+	}
+	if width, err := strconv.ParseFloat(figureAttr.value("width"), 64); err == nil && width > 0 {
+		attrs["width"] = width
+	}
+	if widthType := figureAttr.value("widthType"); widthType != "" {
+		attrs["widthType"] = widthType
+	}
+	media.Attrs = nil
+	if len(attrs) > 0 {
+		media.Attrs = attrs
+	}
+	if len(captionContent) > 0 {
+		media.Content = append(media.Content, converter.Node{Type: "caption", Content: captionContent})
+	}
+	return []converter.Node{media}, nil
+}
+
+// parseDiv parses a Div by its class. Divs without a known class are flattened into
+// their content; a text-align style aligns the paragraphs and headings inside.
+func (s *state) parseDiv(divAttr attr, blocks []rawElement) ([]converter.Node, error) {
+	switch {
+	case divAttr.hasClass("panel"):
+		content, err := s.parseBlocks(blocks)
+		if err != nil {
+			return nil, err
+		}
+		attrs := map[string]interface{}{}
+		for _, key := range []string{"panelType", "panelColor", "panelIcon", "title"} {
+			if value := divAttr.value(key); value != "" {
+				attrs[key] = value
+			}
+		}
+		return []converter.Node{{Type: "panel", Attrs: attrs, Content: ensureBlocks(content)}}, nil
+
+	case divAttr.hasClass("details"):
+		expandType := "expand"
+		if s.expandDepth > 0 {
+			expandType = "nestedExpand"
+		}
+		s.expandDepth++
+		content, err := s.parseBlocks(blocks)
+		s.expandDepth--
+		if err != nil {
+			return nil, err
+		}
+		expand := converter.Node{Type: expandType, Content: ensureBlocks(content)}
+		if title := strings.TrimSpace(divAttr.value("summary")); title != "" {
+			expand.Attrs = map[string]interface{}{"title": title}
+		}
+		return []converter.Node{expand}, nil
+
+	case divAttr.hasClass("layoutSection"):
+		section := converter.Node{Type: "layoutSection"}
+		for _, block := range blocks {
+			var columnAttr attr
+			var columnBlocks []rawElement
+			if block.T != "Div" || block.fields(&columnAttr, &columnBlocks) != nil || !columnAttr.hasClass("layoutColumn") {
+				s.addWarning(converter.WarningDroppedFeature, block.T, "layoutSection content outside a layoutColumn dropped")
+				continue
+			}
+			column, err := s.parseLayoutColumn(columnAttr, columnBlocks)
+			if err != nil {
+				return nil, err
+			}
+			section.Content = append(section.Content, column)
+		}
+		if len(section.Content) == 0 {
+			return nil, nil
+		}
+		return []converter.Node{section}, nil
+
+	case divAttr.hasClass("layoutColumn"):
+		s.addWarning(converter.WarningDroppedFeature, "layoutColumn", "layoutColumn outside a layoutSection flattened")
+		return s.parseBlocks(blocks)
+
+	case divAttr.hasClass("decisionList"):
+		return s.parseDecisionList(blocks)
+
+	case divAttr.hasClass("mediaGroup"):
+		group := converter.Node{Type: "mediaGroup"}
+		for _, block := range blocks {
+			parsed, err := s.parseBlock(block)
+			if err != nil {
+				return nil, err
+			}
+			for _, node := range parsed {
+				if node.Type == "mediaSingle" {
+					group.Content = append(group.Content, node.Content...)
+				}
+			}
+		}
+		if len(group.Content) == 0 {
+			return nil, nil
+		}
+		return []converter.Node{group}, nil
+
+	case divAttr.hasClass("adf-extension"):
+		node := extensionNode("extension", divAttr)
+		var text []string
+		for _, block := range blocks {
+			var codeAttr attr
+			var code string
+			if block.T == "CodeBlock" && block.fields(&codeAttr, &code) == nil {
+				text = append(text, code)
+			}
+		}
+		if len(text) > 0 {
+			node.Attrs["text"] = strings.Join(text, "\n")
+		}
+		return []converter.Node{node}, nil
+
+	case divAttr.hasClass("adf-bodied-extension"):
+		content, err := s.parseBlocks(blocks)
+		if err != nil {
+			return nil, err
+		}
+		node := extensionNode("bodiedExtension", divAttr)
+		node.Content = ensureBlocks(content)
+		return []converter.Node{node}, nil
+	}
+
+	content, err := s.parseBlocks(blocks)
+	if err != nil {
+		return nil, err
+	}
+	if alignment := textAlign(divAttr.value("style")); alignment != "" {
+		for i := range content {
+			if content[i].Type == "paragraph" || content[i].Type == "heading" {
+				if content[i].Attrs == nil {
+					content[i].Attrs = map[string]interface{}{}
+				}
+				content[i].Attrs["layout"] = alignment
+			}
+		}
+	}
+	return content, nil
+}
+
+func (s *state) parseLayoutColumn(columnAttr attr, blocks []rawElement) (converter.Node, error) {
+	content, err := s.parseBlocks(blocks)
+	if err != nil {
+		return converter.Node{}, err
+	}
+	column := converter.Node{Type: "layoutColumn", Content: ensureBlocks(content)}
+	if width, err := strconv.ParseFloat(strings.TrimSuffix(columnAttr.value("width"), "%"), 64); err == nil {
+		column.Attrs = map[string]interface{}{"width": width}
+	}
+	return column, nil
+}
+
+func (s *state) parseDecisionList(blocks []rawElement) ([]converter.Node, error) {
+	list := converter.Node{Type: "decisionList"}
+	for _, block := range blocks {
+		var itemAttr attr
+		var itemBlocks []rawElement
+		if block.T != "Div" || block.fields(&itemAttr, &itemBlocks) != nil || !itemAttr.hasClass("decisionItem") {
+			s.addWarning(converter.WarningDroppedFeature, block.T, "decisionList content outside a decisionItem dropped")
+			continue
+		}
+		var inlines []rawElement
+		for _, itemBlock := range itemBlocks {
+			var blockInlines []rawElement
+			if (itemBlock.T == "Plain" || itemBlock.T == "Para") && itemBlock.fields(&blockInlines) == nil {
+				inlines = append(inlines, blockInlines...)
+			}
+		}
+		content, err := s.inlineContent(inlines)
+		if err != nil {
+			return nil, err
+		}
+		list.Content = append(list.Content, converter.Node{
+			Type:    "decisionItem",
+			Attrs:   map[string]interface{}{"state": firstNonEmpty(itemAttr.value("state"), "DECIDED")},
+			Content: content,
+		})
+	}
+	if len(list.Content) == 0 {
+		return nil, nil
+	}
+	return []converter.Node{list}, nil
+}
+
+// extensionNode builds an extension node from the attr of an extension Div or Span.
+// Parameters that are not valid JSON are dropped.
+func extensionNode(nodeType string, extensionAttr attr) converter.Node {
+	attrs := map[string]interface{}{"extensionKey": extensionAttr.value("key")}
+	if extensionType := extensionAttr.value("extensionType"); extensionType != "" {
+		attrs["extensionType"] = extensionType
+	}
+	if raw := extensionAttr.value("parameters"); raw != "" {
+		var parameters interface{}
+		if err := json.Unmarshal([]byte(raw), &parameters); err == nil {
+			attrs["parameters"] = parameters
+		}
+	}
+	return converter.Node{Type: nodeType, Attrs: attrs}
+}
+
+// textAlign returns the center or right alignment of a CSS text-align declaration.
+func textAlign(style string) string {
+	for _, declaration := range strings.Split(style, ";") {
+		property, value, ok := strings.Cut(declaration, ":")
+		if !ok || strings.TrimSpace(property) != "text-align" {
+			continue
+		}
+		switch value = strings.TrimSpace(value); value {
+		case "center":
+			return "center"
+		case "right", "end":
+			return "right"
+		}
+	}
+	return ""
+}
+
+// unknownElement applies the UnknownNodes policy to a Pandoc element without a mapping.
+func (s *state) unknownElement(e rawElement, block bool) ([]converter.Node, error) {
+	switch s.config.UnknownNodes {
+	case converter.UnknownError:
+		return nil, fmt.Errorf("unknown Pandoc element: %s", e.T)
+	case converter.UnknownSkip:
+		s.addWarning(converter.WarningUnknownNode, e.T, fmt.Sprintf("unknown Pandoc element skipped: %s", e.T))
+		return nil, nil
+	default:
+		s.addWarning(converter.WarningUnknownNode, e.T, fmt.Sprintf("unknown Pandoc element rendered as placeholder: %s", e.T))
+		text := newTextNode(fmt.Sprintf("[Unknown node: %s]", e.T), nil)
+		if block {
+			return []converter.Node{{Type: "paragraph", Content: []converter.Node{text}}}, nil
+		}
+		return []converter.Node{text}, nil
+	}
+}
+
+func codeBlock(language, text string) converter.Node {
+	node := converter.Node{Type: "codeBlock"}
+	if language != "" {
+		node.Attrs = map[string]interface{}{"language": language}
+	}
+	if text != "" {
+		node.Content = []converter.Node{{Type: "text", Text: text}}
+	}
+	return node
+}
+
+// ensureBlocks returns content, or an empty paragraph for containers that require at
+// least one block.
+func ensureBlocks(content []converter.Node) []converter.Node {
+	if len(content) == 0 {
+		return []converter.Node{{Type: "paragraph"}}
+	}
+	return content
+}
@@ -0,0 +1,441 @@
+package pandocjson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// parseInlines parses Pandoc inlines into ADF inline nodes carrying marks. Images become
+// media nodes, which callers move out of the inline content.
+func (s *state) parseInlines(inlines []rawElement, marks []converter.Mark) ([]converter.Node, error) {
+	var content []converter.Node
+	for _, inline := range inlines {
+		nodes, err := s.parseInline(inline, marks)
+		if err != nil {
+			return nil, err
+		}
+		content = appendInlineNodes(content, nodes)
+	}
+	return content, nil
+}
+
+func (s *state) parseInline(inline rawElement, marks []converter.Mark) ([]converter.Node, error) {
+	if err := s.checkContext(); err != nil {
+		return nil, err
+	}
+
+	switch inline.T {
+	case "Str":
+		var text string
+		if err := inline.fields(&text); err != nil {
+			return nil, err
+		}
+		return []converter.Node{newTextNode(text, marks)}, nil
+
+	case "Space", "SoftBreak":
+		return []converter.Node{newTextNode(" ", marks)}, nil
+
+	case "LineBreak":
+		return []converter.Node{{Type: "hardBreak"}}, nil
+
+	case "Emph", "Strong", "Underline", "Strikeout", "Superscript", "Subscript":
+		var children []rawElement
+		if err := inline.fields(&children); err != nil {
+			return nil, err
+		}
+		return s.parseInlines(children, withMark(marks, markFor(inline.T)))
+
+	case "SmallCaps":
+		var children []rawElement
+		if err := inline.fields(&children); err != nil {
+			return nil, err
+		}
+		s.addWarning(converter.WarningDroppedFeature, inline.T, "small caps dropped")
+		return s.parseInlines(children, marks)
+
+	case "Quoted":
+		var quoteType rawElement
+		var children []rawElement
+		if err := inline.fields(&quoteType, &children); err != nil {
+			return nil, err
+		}
+		quote := "\""
+		if quoteType.T == "SingleQuote" {
+			quote = "'"
+		}
+		content, err := s.parseInlines(children, marks)
+		if err != nil {
+			return nil, err
+		}
+		content = append([]converter.Node{newTextNode(quote, marks)}, content...)
+		return append(content, newTextNode(quote, marks)), nil
+
+	case "Cite":
+		var citations []rawElement
+		var children []rawElement
+		if err := inline.fields(&citations, &children); err != nil {
+			return nil, err
+		}
+		return s.parseInlines(children, marks)
+
+	case "Code":
+		var codeAttr attr
+		var text string
+		if err := inline.fields(&codeAttr, &text); err != nil {
+			return nil, err
+		}
+		return []converter.Node{newTextNode(text, codeMarks(marks))}, nil
+
+	case "Math":
+		var mathType rawElement
+		var text string
+		if err := inline.fields(&mathType, &text); err != nil {
+			return nil, err
+		}
+		s.addWarning(converter.WarningDroppedFeature, inline.T, "math rendered as code")
+		return []converter.Node{newTextNode(text, codeMarks(marks))}, nil
+
+	case "RawInline":
+		var format, text string
+		if err := inline.fields(&format, &text); err != nil {
+			return nil, err
+		}
+		s.addWarning(converter.WarningDroppedFeature, inline.T, fmt.Sprintf("raw %s inline dropped", format))
+		return nil, nil
+
+	case "Note":
+		s.addWarning(converter.WarningDroppedFeature, inline.T, "footnote dropped")
+		return nil, nil
+
+	case "Link":
+		var linkAttr attr
+		var children []rawElement
+		var linkTarget target
+		if err := inline.fields(&linkAttr, &children, &linkTarget); err != nil {
+			return nil, err
+		}
+		if linkAttr.hasClass("inline-card") {
+			return []converter.Node{{Type: "inlineCard", Attrs: map[string]interface{}{"url": linkTarget[0]}}}, nil
+		}
+		linkMark := converter.Mark{Type: "link", Attrs: map[string]interface{}{"href": linkTarget[0]}}
+		if linkTarget[1] != "" {
+			linkMark.Attrs["title"] = linkTarget[1]
+		}
+		return s.parseInlines(children, withMark(marks, linkMark))
+
+	case "Image":
+		var imageAttr attr
+		var children []rawElement
+		var imageTarget target
+		if err := inline.fields(&imageAttr, &children, &imageTarget); err != nil {
+			return nil, err
+		}
+		alt, err := s.parseInlines(children, nil)
+		if err != nil {
+			return nil, err
+		}
+		return []converter.Node{mediaNode(imageAttr, imageTarget[0], plainText(alt))}, nil
+
+	case "Span":
+		var spanAttr attr
+		var children []rawElement
+		if err := inline.fields(&spanAttr, &children); err != nil {
+			return nil, err
+		}
+		return s.parseSpan(spanAttr, children, marks)
+
+	default:
+		return s.unknownElement(inline, false)
+	}
+}
+
+// parseSpan parses a Span by its class. Spans without a known class keep their content,
+// with the colors of a style attribute as marks.
+func (s *state) parseSpan(spanAttr attr, children []rawElement, marks []converter.Mark) ([]converter.Node, error) {
+	content, err := s.parseInlines(children, nil)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimSpace(plainText(content))
+
+	switch {
+	case spanAttr.hasClass("mention"):
+		attrs := map[string]interface{}{}
+		if id := spanAttr.value("mention-id"); id != "" {
+			attrs["id"] = id
+		}
+		if text != "" {
+			attrs["text"] = text
+		}
+		return []converter.Node{{Type: "mention", Attrs: attrs}}, nil
+
+	case spanAttr.hasClass("status"):
+		attrs := map[string]interface{}{"text": text}
+		if color := spanAttr.value("color"); color != "" {
+			attrs["color"] = color
+		}
+		return []converter.Node{{Type: "status", Attrs: attrs}}, nil
+
+	case spanAttr.hasClass("date"):
+		timestamp := spanAttr.value("timestamp")
+		if timestamp == "" {
+			parsed, err := time.Parse("2006-01-02", text)
+			if err != nil {
+				return []converter.Node{newTextNode(text, marks)}, nil
+			}
+			timestamp = strconv.FormatInt(parsed.Unix(), 10)
+		}
+		return []converter.Node{{Type: "date", Attrs: map[string]interface{}{"timestamp": timestamp}}}, nil
+
+	case spanAttr.hasClass("emoji"):
+		shortName := firstNonEmpty(spanAttr.value("shortName"), text)
+		attrs := map[string]interface{}{"shortName": shortName}
+		if id := spanAttr.value("id"); id != "" {
+			attrs["id"] = id
+		}
+		if text != "" && text != shortName {
+			attrs["text"] = text
+		}
+		return []converter.Node{{Type: "emoji", Attrs: attrs}}, nil
+
+	case spanAttr.hasClass("placeholder"):
+		return []converter.Node{{Type: "placeholder", Attrs: map[string]interface{}{"text": text}}}, nil
+
+	case spanAttr.hasClass("adf-inline-extension"):
+		node := extensionNode("inlineExtension", spanAttr)
+		if text != "" {
+			node.Attrs["text"] = text
+		}
+		return []converter.Node{node}, nil
+	}
+
+	if spanAttr.hasClass("underline") {
+		marks = withMark(marks, converter.Mark{Type: "underline"})
+	}
+	marks = s.styleMarks(spanAttr.value("style"), marks)
+	return s.parseInlines(children, marks)
+}
+
+// styleMarks adds textColor and backgroundColor marks for the colors of a CSS style.
+func (s *state) styleMarks(style string, marks []converter.Mark) []converter.Mark {
+	for _, declaration := range strings.Split(style, ";") {
+		property, value, ok := strings.Cut(declaration, ":")
+		if !ok {
+			continue
+		}
+		markType := ""
+		switch strings.TrimSpace(property) {
+		case "color":
+			markType = "textColor"
+		case "background-color":
+			markType = "backgroundColor"
+		default:
+			continue
+		}
+		color, ok := converter.SanitizeCSSColor(strings.TrimSpace(value))
+		if !ok {
+			s.addWarning(converter.WarningDroppedFeature, markType, fmt.Sprintf("invalid color value dropped: %q", strings.TrimSpace(value)))
+			continue
+		}
+		marks = withMark(marks, converter.Mark{Type: markType, Attrs: map[string]interface{}{"color": color}})
+	}
+	return marks
+}
+
+// markFor returns the ADF mark of a Pandoc formatting element.
+func markFor(tag string) converter.Mark {
+	switch tag {
+	case "Emph":
+		return converter.Mark{Type: "em"}
+	case "Strong":
+		return converter.Mark{Type: "strong"}
+	case "Underline":
+		return converter.Mark{Type: "underline"}
+	case "Strikeout":
+		return converter.Mark{Type: "strike"}
+	case "Superscript":
+		return converter.Mark{Type: "subsup", Attrs: map[string]interface{}{"type": "sup"}}
+	default:
+		return converter.Mark{Type: "subsup", Attrs: map[string]interface{}{"type": "sub"}}
+	}
+}
+
+// codeMarks returns the marks of inline code: ADF only combines code with links.
+func codeMarks(marks []converter.Mark) []converter.Mark {
+	var combined []converter.Mark
+	for _, mark := range marks {
+		if mark.Type == "link" {
+			combined = append(combined, mark)
+		}
+	}
+	return append(combined, converter.Mark{Type: "code"})
+}
+
+// mediaNode builds a media node from an image. Images with a file type keep their id and
+// collection; other images are external media with the image source as URL.
+func mediaNode(imageAttr attr, src, alt string) converter.Node {
+	node := converter.Node{Type: "media"}
+	if imageAttr.hasClass("mediaInline") {
+		node.Type = "mediaInline"
+	}
+	mediaType := firstNonEmpty(imageAttr.value("type"), "external")
+	attrs := map[string]interface{}{"type": mediaType}
+	if mediaType == "external" {
+		attrs["url"] = src
+	} else {
+		attrs["id"] = firstNonEmpty(imageAttr.value("id"), src)
+		if collection := imageAttr.value("collection"); collection != "" {
+			attrs["collection"] = collection
+		}
+	}
+	if alt = strings.TrimSpace(alt); alt != "" {
+		attrs["alt"] = alt
+	}
+	for _, dimension := range []string{"width", "height"} {
+		if size, err := strconv.Atoi(strings.TrimSuffix(imageAttr.value(dimension), "px")); err == nil && size > 0 {
+			attrs[dimension] = size
+		}
+	}
+	node.Attrs = attrs
+	return node
+}
+
+// plainText returns the text of inline nodes.
+func plainText(content []converter.Node) string {
+	var sb strings.Builder
+	for _, node := range content {
+		switch node.Type {
+		case "text":
+			sb.WriteString(node.Text)
+		case "hardBreak":
+			sb.WriteString(" ")
+		default:
+			sb.WriteString(node.GetStringAttr("text", ""))
+		}
+	}
+	return sb.String()
+}
+
+// addMark returns inline content with a mark added to its text nodes.
+func addMark(content []converter.Node, mark converter.Mark) []converter.Node {
+	marked := make([]converter.Node, len(content))
+	for i, node := range content {
+		if node.Type == "text" {
+			node.Marks = withMark(node.Marks, mark)
+		}
+		marked[i] = node
+	}
+	return marked
+}
+
+func withMark(marks []converter.Mark, mark converter.Mark) []converter.Mark {
+	for _, existing := range marks {
+		if existing.Type == mark.Type {
+			return marks
+		}
+	}
+	combined := make([]converter.Mark, 0, len(marks)+1)
+	combined = append(combined, marks...)
+	return append(combined, mark)
+}
+
+func newTextNode(text string, marks []converter.Mark) converter.Node {
+	node := converter.Node{
+		Type: "text",
+		Text: text,
+	}
+	if len(marks) > 0 {
+		node.Marks = marks
+	}
+	return node
+}
+
+func appendInlineNodes(content []converter.Node, nodes []converter.Node) []converter.Node {
+	for _, node := range nodes {
+		content = appendInlineNode(content, node)
+	}
+	return content
+}
+
+// appendInlineNode appends a node, merging adjacent text with equal marks and
+// collapsing whitespace across text boundaries.
+func appendInlineNode(content []converter.Node, next converter.Node) []converter.Node {
+	if next.Type == "text" && len(content) > 0 {
+		last := &content[len(content)-1]
+		if last.Type == "text" && strings.HasSuffix(last.Text, " ") && !hasCodeMark(next) {
+			next.Text = strings.TrimLeft(next.Text, " ")
+		}
+		if last.Type == "text" && marksEqual(last.Marks, next.Marks) {
+			last.Text += next.Text
+			return content
+		}
+	}
+	if next.Type == "text" && next.Text == "" {
+		return content
+	}
+	return append(content, next)
+}
+
+func hasCodeMark(node converter.Node) bool {
+	for _, mark := range node.Marks {
+		if mark.Type == "code" {
+			return true
+		}
+	}
+	return false
+}
+
+func marksEqual(a, b []converter.Mark) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Type != b[i].Type || fmt.Sprint(a[i].Attrs) != fmt.Sprint(b[i].Attrs) {
+			return false
+		}
+	}
+	return true
+}
+
+// trimInline removes leading and trailing line breaks and whitespace from inline
+// content, and whitespace around line breaks.
+func trimInline(content []converter.Node) []converter.Node {
+	for len(content) > 0 && content[0].Type == "hardBreak" {
+		content = content[1:]
+	}
+	for len(content) > 0 && content[len(content)-1].Type == "hardBreak" {
+		content = content[:len(content)-1]
+	}
+	if len(content) == 0 {
+		return nil
+	}
+
+	trimmed := append([]converter.Node(nil), content...)
+	for i := range trimmed {
+		if trimmed[i].Type != "text" || hasCodeMark(trimmed[i]) {
+			continue
+		}
+		if i == 0 || trimmed[i-1].Type == "hardBreak" {
+			trimmed[i].Text = strings.TrimLeft(trimmed[i].Text, " ")
+		}
+		if i == len(trimmed)-1 || trimmed[i+1].Type == "hardBreak" {
+			trimmed[i].Text = strings.TrimRight(trimmed[i].Text, " ")
+		}
+	}
+
+	result := trimmed[:0]
+	for _, node := range trimmed {
+		if node.Type == "text" && node.Text == "" {
+			continue
+		}
+		result = append(result, node)
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
@@ -0,0 +1,432 @@
+package pandocjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// Task list items start with the checkbox characters Pandoc's task_lists extension uses.
+const (
+	uncheckedBox = "☐"
+	checkedBox   = "☒"
+)
+
+var (
+	alignDefault    = newElement("AlignDefault")
+	colWidthDefault = newElement("ColWidthDefault")
+)
+
+func (s *state) renderBlocks(content []converter.Node) ([]element, error) {
+	blocks := []element{}
+	for _, node := range content {
+		rendered, err := s.renderBlock(node)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, rendered...)
+	}
+	return blocks, nil
+}
+
+func (s *state) renderBlock(node converter.Node) ([]element, error) {
+	if err := s.checkContext(); err != nil {
+		return nil, err
+	}
+
+	switch node.Type {
+	case "paragraph":
+		inlines, err := s.renderInlines(node.Content)
+		if err != nil || len(inlines) == 0 {
+			return nil, err
+		}
+		return aligned(node, newElement("Para", inlines)), nil
+
+	case "heading":
+		inlines, err := s.renderInlines(node.Content)
+		if err != nil || len(inlines) == 0 {
+			return nil, err
+		}
+		level := node.GetIntAttr("level", 0)
+		if level <= 0 {
+			level = node.Level
+		}
+		level = min(max(level, 1), 6)
+		return aligned(node, newElement("Header", level, attr{}, inlines)), nil
+
+	case "blockquote":
+		blocks, err := s.renderBlocks(node.Content)
+		if err != nil {
+			return nil, err
+		}
+		return []element{newElement("BlockQuote", blocks)}, nil
+
+	case "rule":
+		return []element{newElement("HorizontalRule")}, nil
+
+	case "codeBlock":
+		var text strings.Builder
+		for _, child := range node.Content {
+			text.WriteString(child.Text)
+		}
+		codeAttr := attr{}
+		if language := strings.TrimSpace(node.GetStringAttr("language", "")); language != "" {
+			codeAttr.Classes = []string{language}
+		}
+		return []element{newElement("CodeBlock", codeAttr, text.String())}, nil
+
+	case "bulletList", "orderedList":
+		return s.renderList(node)
+
+	case "taskList":
+		items, err := s.renderTaskItems(node)
+		if err != nil {
+			return nil, err
+		}
+		return []element{newElement("BulletList", items)}, nil
+
+	case "decisionList":
+		return s.renderDecisionList(node)
+
+	case "table":
+		return s.renderTable(node)
+
+	case "panel":
+		return s.renderDiv(node, attr{Classes: []string{"panel"}, Pairs: attrPairs(node.Attrs, "panelType", "panelColor", "panelIcon", "title")})
+
+	case "expand", "nestedExpand":
+		return s.renderDiv(node, classAttr("details").with("summary", node.GetStringAttr("title", "")))
+
+	case "layoutSection":
+		return s.renderDiv(node, classAttr("layoutSection"))
+
+	case "layoutColumn":
+		columnAttr := classAttr("layoutColumn")
+		if width := node.GetFloat64Attr("width", 0); width > 0 {
+			columnAttr = columnAttr.with("width", formatNumber(width)+"%")
+		}
+		return s.renderDiv(node, columnAttr)
+
+	case "mediaSingle":
+		return s.renderMediaSingle(node)
+
+	case "mediaGroup":
+		var images []element
+		for _, child := range node.Content {
+			rendered, err := s.renderInlineNode(child)
+			if err != nil {
+				return nil, err
+			}
+			if len(images) > 0 && len(rendered) > 0 {
+				images = append(images, newElement("Space"))
+			}
+			images = append(images, rendered...)
+		}
+		return []element{newElement("Div", classAttr("mediaGroup"), []element{newElement("Plain", images)})}, nil
+
+	case "extension":
+		var blocks []element
+		if text := node.GetStringAttr("text", ""); text != "" {
+			blocks = append(blocks, newElement("CodeBlock", attr{}, text))
+		}
+		return []element{newElement("Div", extensionAttr("adf-extension", node), nonNil(blocks))}, nil
+
+	case "bodiedExtension":
+		return s.renderDiv(node, extensionAttr("adf-bodied-extension", node))
+
+	default:
+		placeholder, err := s.unknownNode(node)
+		if err != nil || placeholder == "" {
+			return nil, err
+		}
+		return []element{newElement("Para", textInlines(placeholder))}, nil
+	}
+}
+
+// renderDiv renders a node as a Div of its rendered children.
+func (s *state) renderDiv(node converter.Node, divAttr attr) ([]element, error) {
+	blocks, err := s.renderBlocks(node.Content)
+	if err != nil {
+		return nil, err
+	}
+	return []element{newElement("Div", divAttr, blocks)}, nil
+}
+
+// aligned wraps a paragraph or heading with a center or right alignment in a Div with
+// a text-align style, as the Pandoc Markdown strategy does.
+func aligned(node converter.Node, block element) []element {
+	alignment := node.GetStringAttr("align", "")
+	if alignment == "" {
+		alignment = node.GetStringAttr("layout", "")
+	}
+	for _, mark := range node.Marks {
+		if mark.Type == "alignment" && alignment == "" {
+			alignment = mark.GetStringAttr("align", "")
+		}
+	}
+	if alignment == "end" {
+		alignment = "right"
+	}
+	if alignment != "center" && alignment != "right" {
+		return []element{block}
+	}
+	return []element{newElement("Div", attr{}.with("style", "text-align: "+alignment+";"), []element{block})}
+}
+
+func (s *state) renderList(node converter.Node) ([]element, error) {
+	items := []any{}
+	for _, item := range node.Content {
+		if item.Type != "listItem" {
+			if s.config.UnknownNodes == converter.UnknownError {
+				return nil, fmt.Errorf("expected listItem child, got %s", item.Type)
+			}
+			s.addWarning(converter.WarningUnknownNode, item.Type, fmt.Sprintf("unexpected list child %s, expected listItem", item.Type))
+			continue
+		}
+		blocks, err := s.renderBlocks(item.Content)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, blocks)
+	}
+
+	if node.Type == "bulletList" {
+		return []element{newElement("BulletList", items)}, nil
+	}
+	listAttributes := []any{node.GetIntAttr("order", 1), newElement("Decimal"), newElement("Period")}
+	return []element{newElement("OrderedList", listAttributes, items)}, nil
+}
+
+// renderTaskItems renders the items of a taskList as bullet list items starting with a
+// checkbox. A nested task list goes into the item before it.
+func (s *state) renderTaskItems(node converter.Node) ([]any, error) {
+	var items [][]element
+	for _, item := range node.Content {
+		switch item.Type {
+		case "taskItem":
+			inlines, err := s.renderInlines(item.Content)
+			if err != nil {
+				return nil, err
+			}
+			box := uncheckedBox
+			if item.GetStringAttr("state", "TODO") == "DONE" {
+				box = checkedBox
+			}
+			plain := append([]element{newElement("Str", box), newElement("Space")}, inlines...)
+			items = append(items, []element{newElement("Plain", plain)})
+		case "taskList":
+			nested, err := s.renderTaskItems(item)
+			if err != nil {
+				return nil, err
+			}
+			list := newElement("BulletList", nested)
+			if len(items) == 0 {
+				items = append(items, []element{list})
+				continue
+			}
+			items[len(items)-1] = append(items[len(items)-1], list)
+		default:
+			if s.config.UnknownNodes == converter.UnknownError {
+				return nil, fmt.Errorf("taskList expects taskItem child, got %s", item.Type)
+			}
+			s.addWarning(converter.WarningUnknownNode, item.Type, fmt.Sprintf("unexpected task list child %s", item.Type))
+		}
+	}
+
+	rendered := make([]any, len(items))
+	for i, item := range items {
+		rendered[i] = item
+	}
+	return rendered, nil
+}
+
+func (s *state) renderDecisionList(node converter.Node) ([]element, error) {
+	items := []element{}
+	for _, item := range node.Content {
+		if item.Type != "decisionItem" {
+			s.addWarning(converter.WarningUnknownNode, item.Type, fmt.Sprintf("unexpected decision list child %s", item.Type))
+			continue
+		}
+		inlines, err := s.renderInlines(item.Content)
+		if err != nil {
+			return nil, err
+		}
+		itemAttr := classAttr("decisionItem").with("state", item.GetStringAttr("state", "DECIDED"))
+		items = append(items, newElement("Div", itemAttr, []element{newElement("Plain", inlines)}))
+	}
+	return []element{newElement("Div", classAttr("decisionList"), items)}, nil
+}
+
+// renderTable renders a table. Leading rows of header cells become the table head;
+// header cells elsewhere carry the "header" class.
+func (s *state) renderTable(node converter.Node) ([]element, error) {
+	var head, body []any
+	columns := 0
+	for _, row := range node.Content {
+		if row.Type != "tableRow" {
+			s.addWarning(converter.WarningUnknownNode, row.Type, fmt.Sprintf("unexpected table child %s", row.Type))
+			continue
+		}
+
+		inHead := len(body) == 0 && isHeaderRow(row)
+		cells := []any{}
+		width := 0
+		for _, cell := range row.Content {
+			if cell.Type != "tableHeader" && cell.Type != "tableCell" {
+				s.addWarning(converter.WarningUnknownNode, cell.Type, fmt.Sprintf("unexpected table row child %s", cell.Type))
+				continue
+			}
+			blocks, err := s.renderBlocks(cell.Content)
+			if err != nil {
+				return nil, err
+			}
+			cellAttr := attr{}
+			if cell.Type == "tableHeader" && !inHead {
+				cellAttr.Classes = []string{"header"}
+			}
+			cellAttr = cellAttr.with("background", cell.GetStringAttr("background", ""))
+			colspan := max(cell.GetIntAttr("colspan", 1), 1)
+			rowspan := max(cell.GetIntAttr("rowspan", 1), 1)
+			width += colspan
+			cells = append(cells, []any{cellAttr, alignDefault, rowspan, colspan, blocks})
+		}
+		columns = max(columns, width)
+
+		if inHead {
+			head = append(head, []any{attr{}, cells})
+		} else {
+			body = append(body, []any{attr{}, cells})
+		}
+	}
+	if columns == 0 {
+		return nil, nil
+	}
+
+	colSpecs := make([]any, columns)
+	for i := range colSpecs {
+		colSpecs[i] = []any{alignDefault, colWidthDefault}
+	}
+	bodies := []any{}
+	if len(body) > 0 {
+		bodies = append(bodies, []any{attr{}, 0, []any{}, body})
+	}
+	return []element{newElement("Table",
+		attr{},
+		caption{},
+		colSpecs,
+		[]any{attr{}, nonNil(head)},
+		bodies,
+		[]any{attr{}, []any{}},
+	)}, nil
+}
+
+// isHeaderRow reports whether every cell of a row is a tableHeader.
+func isHeaderRow(row converter.Node) bool {
+	for _, cell := range row.Content {
+		if cell.Type != "tableHeader" {
+			return false
+		}
+	}
+	return len(row.Content) > 0
+}
+
+// renderMediaSingle renders a mediaSingle as a Figure with its layout and width, and
+// its caption child as the figure caption.
+func (s *state) renderMediaSingle(node converter.Node) ([]element, error) {
+	var images, captionInlines []element
+	for _, child := range node.Content {
+		if child.Type == "caption" {
+			rendered, err := s.renderInlines(child.Content)
+			if err != nil {
+				return nil, err
+			}
+			captionInlines = rendered
+			continue
+		}
+		rendered, err := s.renderInlineNode(child)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, rendered...)
+	}
+	if len(images) == 0 {
+		return nil, nil
+	}
+
+	figureCaption := caption{}
+	if len(captionInlines) > 0 {
+		figureCaption.Blocks = []element{newElement("Plain", captionInlines)}
+	}
+	figureAttr := attr{Pairs: attrPairs(node.Attrs, "layout", "width", "widthType")}
+	return []element{newElement("Figure", figureAttr, figureCaption, []element{newElement("Plain", images)})}, nil
+}
+
+// extensionAttr returns the attr of an extension Div or Span. The parameters are kept
+// as JSON, as the Pandoc Markdown strategy does.
+func extensionAttr(class string, node converter.Node) attr {
+	extensionAttr := classAttr(class).
+		with("key", node.GetStringAttr("extensionKey", "")).
+		with("extensionType", node.GetStringAttr("extensionType", ""))
+	if parameters, ok := node.Attrs["parameters"]; ok && parameters != nil {
+		if data, err := json.Marshal(parameters); err == nil {
+			extensionAttr = extensionAttr.with("parameters", string(data))
+		}
+	}
+	return extensionAttr
+}
+
+// unknownNode applies the UnknownNodes policy to an ADF node without a mapping and
+// returns the placeholder text, if any.
+func (s *state) unknownNode(node converter.Node) (string, error) {
+	switch s.config.UnknownNodes {
+	case converter.UnknownError:
+		return "", fmt.Errorf("unknown node type: %s", node.Type)
+	case converter.UnknownSkip:
+		s.addWarning(converter.WarningUnknownNode, node.Type, fmt.Sprintf("unknown node skipped: %s", node.Type))
+		return "", nil
+	default:
+		s.addWarning(converter.WarningUnknownNode, node.Type, fmt.Sprintf("unknown node rendered as placeholder: %s", node.Type))
+		return fmt.Sprintf("[Unknown node: %s]", node.Type), nil
+	}
+}
+
+// attrPairs returns the scalar ADF attrs with the given keys as key-value pairs.
+func attrPairs(attrs map[string]interface{}, keys ...string) [][2]string {
+	var pairs [][2]string
+	for _, key := range keys {
+		if value := scalarString(attrs[key]); value != "" {
+			pairs = append(pairs, [2]string{key, value})
+		}
+	}
+	return pairs
+}
+
+func scalarString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return formatNumber(v)
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return ""
+	}
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// nonNil returns an empty slice for nil, so that it is encoded as [] instead of null.
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
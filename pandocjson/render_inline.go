@@ -0,0 +1,281 @@
+package pandocjson
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// markedNode is an inline node with the marks that still wrap it.
+type markedNode struct {
+	node  converter.Node
+	marks []converter.Mark
+}
+
+// renderInlines renders inline content. Adjacent nodes sharing a mark are rendered
+// inside a single wrapping element, so a link over bold and plain text stays one Link.
+func (s *state) renderInlines(content []converter.Node) ([]element, error) {
+	items := make([]markedNode, 0, len(content))
+	for _, node := range content {
+		marks, err := s.wrappingMarks(node)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, markedNode{node: node, marks: marks})
+	}
+	return s.renderMarked(items)
+}
+
+func (s *state) renderMarked(items []markedNode) ([]element, error) {
+	inlines := []element{}
+	for i := 0; i < len(items); {
+		if len(items[i].marks) == 0 {
+			rendered, err := s.renderInlineNode(items[i].node)
+			if err != nil {
+				return nil, err
+			}
+			inlines = append(inlines, rendered...)
+			i++
+			continue
+		}
+
+		mark := items[i].marks[0]
+		end := i + 1
+		for end < len(items) && indexOfMark(items[end].marks, mark) >= 0 {
+			end++
+		}
+
+		inner := make([]markedNode, 0, end-i)
+		for _, item := range items[i:end] {
+			index := indexOfMark(item.marks, mark)
+			marks := append(append([]converter.Mark{}, item.marks[:index]...), item.marks[index+1:]...)
+			inner = append(inner, markedNode{node: item.node, marks: marks})
+		}
+		children, err := s.renderMarked(inner)
+		if err != nil {
+			return nil, err
+		}
+		inlines = append(inlines, wrapMark(mark, children)...)
+		i = end
+	}
+	return inlines, nil
+}
+
+func indexOfMark(marks []converter.Mark, mark converter.Mark) int {
+	for i, candidate := range marks {
+		if candidate.Type == mark.Type && reflect.DeepEqual(candidate.Attrs, mark.Attrs) {
+			return i
+		}
+	}
+	return -1
+}
+
+// wrappingMarks returns the marks of a node that wrap it in a Pandoc element. Code marks
+// are rendered by the text itself; annotations and invalid colors are dropped and unknown
+// marks follow Config.UnknownMarks.
+func (s *state) wrappingMarks(node converter.Node) ([]converter.Mark, error) {
+	var marks []converter.Mark
+	for _, mark := range node.Marks {
+		switch mark.Type {
+		case "strong", "em", "strike", "underline", "link":
+			marks = append(marks, mark)
+		case "subsup":
+			if kind := mark.GetStringAttr("type", ""); kind == "sub" || kind == "sup" {
+				marks = append(marks, mark)
+			}
+		case "textColor", "backgroundColor":
+			raw := mark.GetStringAttr("color", "")
+			color, ok := converter.SanitizeCSSColor(raw)
+			if !ok {
+				if raw != "" {
+					s.addWarning(converter.WarningDroppedFeature, mark.Type, fmt.Sprintf("invalid color value dropped: %q", raw))
+				}
+				continue
+			}
+			marks = append(marks, converter.Mark{Type: mark.Type, Attrs: map[string]interface{}{"color": color}})
+		case "code", "annotation":
+		default:
+			switch s.config.UnknownMarks {
+			case converter.UnknownError:
+				return nil, fmt.Errorf("unknown mark type: %s", mark.Type)
+			case converter.UnknownPlaceholder:
+				s.addWarning(converter.WarningUnknownMark, mark.Type, fmt.Sprintf("unknown mark rendered as placeholder: %s", mark.Type))
+				marks = append(marks, mark)
+			default:
+				s.addWarning(converter.WarningUnknownMark, mark.Type, fmt.Sprintf("unknown mark skipped: %s", mark.Type))
+			}
+		}
+	}
+	return marks, nil
+}
+
+// wrapMark wraps rendered inlines in the element of a mark returned by wrappingMarks.
+func wrapMark(mark converter.Mark, inlines []element) []element {
+	switch mark.Type {
+	case "strong":
+		return []element{newElement("Strong", inlines)}
+	case "em":
+		return []element{newElement("Emph", inlines)}
+	case "strike":
+		return []element{newElement("Strikeout", inlines)}
+	case "underline":
+		return []element{newElement("Underline", inlines)}
+	case "subsup":
+		if mark.GetStringAttr("type", "") == "sub" {
+			return []element{newElement("Subscript", inlines)}
+		}
+		return []element{newElement("Superscript", inlines)}
+	case "textColor":
+		return []element{newElement("Span", attr{}.with("style", "color: "+mark.GetStringAttr("color", "")+";"), inlines)}
+	case "backgroundColor":
+		return []element{newElement("Span", attr{}.with("style", "background-color: "+mark.GetStringAttr("color", "")+";"), inlines)}
+	case "link":
+		href := mark.GetStringAttr("href", "")
+		if href == "" {
+			return inlines
+		}
+		return []element{newElement("Link", attr{}, inlines, target{href, mark.GetStringAttr("title", "")})}
+	default:
+		return append(textInlines(fmt.Sprintf("[Unknown mark: %s]", mark.Type)), inlines...)
+	}
+}
+
+// renderInlineNode renders an inline node without its wrapping marks.
+func (s *state) renderInlineNode(node converter.Node) ([]element, error) {
+	if err := s.checkContext(); err != nil {
+		return nil, err
+	}
+
+	switch node.Type {
+	case "text":
+		for _, mark := range node.Marks {
+			if mark.Type == "code" {
+				return []element{newElement("Code", attr{}, node.Text)}, nil
+			}
+		}
+		return textInlines(node.Text), nil
+
+	case "hardBreak":
+		return []element{newElement("LineBreak")}, nil
+
+	case "mention":
+		mentionAttr := classAttr("mention").with("mention-id", node.GetStringAttr("id", ""))
+		return []element{newElement("Span", mentionAttr, textInlines(node.GetStringAttr("text", "")))}, nil
+
+	case "emoji":
+		shortName := node.GetStringAttr("shortName", "")
+		text := firstNonEmpty(node.GetStringAttr("text", ""), node.GetStringAttr("fallback", ""), shortName)
+		emojiAttr := classAttr("emoji").with("shortName", shortName).with("id", node.GetStringAttr("id", ""))
+		return []element{newElement("Span", emojiAttr, textInlines(text))}, nil
+
+	case "status":
+		statusAttr := classAttr("status").with("color", node.GetStringAttr("color", ""))
+		return []element{newElement("Span", statusAttr, textInlines(node.GetStringAttr("text", "")))}, nil
+
+	case "date":
+		timestamp := node.GetStringAttr("timestamp", "")
+		return []element{newElement("Span", classAttr("date").with("timestamp", timestamp), textInlines(formatDate(timestamp)))}, nil
+
+	case "inlineCard":
+		input := converter.InlineCardRenderInput(s.options.SourcePath, node)
+		if input.Href == "" {
+			s.addWarning(converter.WarningMissingAttribute, node.Type, "inlineCard missing url and valid data")
+			return textInlines("[Smart Link]"), nil
+		}
+		return []element{newElement("Link", classAttr("inline-card"), textInlines(firstNonEmpty(input.Title, input.Href)), target{input.Href, ""})}, nil
+
+	case "placeholder":
+		return []element{newElement("Span", classAttr("placeholder"), textInlines(node.GetStringAttr("text", "")))}, nil
+
+	case "media", "mediaInline":
+		return []element{s.renderImage(node)}, nil
+
+	case "inlineExtension":
+		return []element{newElement("Span", extensionAttr("adf-inline-extension", node), textInlines(node.GetStringAttr("text", "")))}, nil
+
+	default:
+		placeholder, err := s.unknownNode(node)
+		if err != nil || placeholder == "" {
+			return nil, err
+		}
+		return textInlines(placeholder), nil
+	}
+}
+
+// renderImage renders a media node as an Image. The source is the media URL or, for
+// attachments, the media id; the media attrs are kept as key-value pairs.
+func (s *state) renderImage(node converter.Node) element {
+	imageAttr := attr{Pairs: attrPairs(node.Attrs, "type", "id", "collection", "width", "height")}
+	if node.Type == "mediaInline" {
+		imageAttr.Classes = []string{"mediaInline"}
+	}
+	src := firstNonEmpty(node.GetStringAttr("url", ""), node.GetStringAttr("id", ""))
+	if src == "" {
+		s.addWarning(converter.WarningMissingAttribute, node.Type, "media node missing id")
+	}
+	return newElement("Image", imageAttr, textInlines(node.GetStringAttr("alt", "")), target{src, ""})
+}
+
+// textInlines splits text into Str elements separated by Space and SoftBreak, as Pandoc
+// readers do. Runs of spaces become a single Space.
+func textInlines(text string) []element {
+	inlines := []element{}
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			inlines = append(inlines, newElement("Str", word.String()))
+			word.Reset()
+		}
+	}
+	for _, r := range text {
+		switch {
+		case r == '\n':
+			flush()
+			inlines = trimTrailingSpace(inlines)
+			inlines = append(inlines, newElement("SoftBreak"))
+		case r == ' ' || !unicode.IsSpace(r):
+			word.WriteRune(r)
+		default:
+			flush()
+			if len(inlines) > 0 && (inlines[len(inlines)-1].T == "Space" || inlines[len(inlines)-1].T == "SoftBreak") {
+				continue
+			}
+			inlines = append(inlines, newElement("Space"))
+		}
+	}
+	flush()
+	return inlines
+}
+
+func trimTrailingSpace(inlines []element) []element {
+	if len(inlines) > 0 && inlines[len(inlines)-1].T == "Space" {
+		return inlines[:len(inlines)-1]
+	}
+	return inlines
+}
+
+// formatDate formats a date timestamp as YYYY-MM-DD, detecting millisecond timestamps
+// with the same cutoff as the Markdown converter.
+func formatDate(timestamp string) string {
+	var ts int64
+	if _, err := fmt.Sscanf(timestamp, "%d", &ts); err != nil {
+		return timestamp
+	}
+	if ts > 10000000000 {
+		ts = ts / 1000
+	}
+	return time.Unix(ts, 0).UTC().Format("2006-01-02")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
@@ -0,0 +1,23 @@
+package pandocjson
+
+import "github.com/rgonek/jira-adf-converter/converter"
+
+// RenderResult holds the output of an ADF -> Pandoc JSON render.
+type RenderResult struct {
+	// JSON is the Pandoc JSON AST, readable with `pandoc -f json`.
+	JSON     []byte              `json:"json"`
+	Warnings []converter.Warning `json:"warnings,omitempty"`
+	// References lists links, media, mentions, inlineCards, emoji and extensions of the input.
+	References converter.References `json:"references,omitzero"`
+}
+
+// ParseResult holds the output of a Pandoc JSON -> ADF conversion.
+type ParseResult struct {
+	// Doc is the parsed ADF document; ADF is its JSON encoding.
+	Doc      converter.Doc       `json:"-"`
+	ADF      []byte              `json:"adf"`
+	Warnings []converter.Warning `json:"warnings,omitempty"`
+	// References lists links, media, mentions, inlineCards, emoji and extensions of the
+	// generated ADF; paths point into the ADF document.
+	References converter.References `json:"references,omitzero"`
+}
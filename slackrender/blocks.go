@@ -0,0 +1,375 @@
+package slackrender
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// ruleText stands in for a rule in mrkdwn, which has no horizontal rule.
+const ruleText = "———"
+
+// bulletMarkers are the list markers for each nesting level, repeating after the last.
+var bulletMarkers = []string{"•", "◦", "▪"}
+
+// panelEmoji maps panel types to the emoji that leads their context block.
+var panelEmoji = map[string]string{
+	"info":    ":information_source:",
+	"note":    ":memo:",
+	"success": ":white_check_mark:",
+	"warning": ":warning:",
+	"error":   ":x:",
+}
+
+// blockKind is the Block Kit block type a top-level node renders as.
+type blockKind string
+
+const (
+	blockSection blockKind = "section"
+	blockHeader  blockKind = "header"
+	blockContext blockKind = "context"
+	blockDivider blockKind = "divider"
+	blockImage   blockKind = "image"
+)
+
+// block is a rendered top-level node. text is its mrkdwn; plain is the text of a header
+// or the caption of an image.
+type block struct {
+	kind     blockKind
+	text     string
+	plain    string
+	imageURL string
+	altText  string
+}
+
+// mrkdwn returns the block as message text. Context blocks are quoted so that panels
+// stay set apart in plain mrkdwn.
+func (b block) mrkdwn() string {
+	switch b.kind {
+	case blockContext:
+		return quote(b.text)
+	case blockDivider:
+		return ruleText
+	default:
+		return b.text
+	}
+}
+
+func joinMrkdwn(blocks []block) string {
+	parts := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		parts = append(parts, b.mrkdwn())
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// renderDoc renders the top-level content as blocks. Headings, panels, rules and images
+// get their own block types; everything else becomes a section of mrkdwn.
+func (s *state) renderDoc(content []converter.Node) ([]block, error) {
+	var blocks []block
+	for _, node := range content {
+		if err := s.checkContext(); err != nil {
+			return nil, err
+		}
+
+		switch node.Type {
+		case "heading":
+			plain := plainText(node.Content)
+			if plain == "" {
+				continue
+			}
+			blocks = append(blocks, block{kind: blockHeader, text: s.renderHeading(node), plain: plain})
+		case "panel":
+			body, err := s.renderPanelBody(node)
+			if err != nil {
+				return nil, err
+			}
+			if body != "" {
+				blocks = append(blocks, block{kind: blockContext, text: body})
+			}
+		case "rule":
+			blocks = append(blocks, block{kind: blockDivider})
+		case "mediaSingle":
+			b, err := s.renderMediaSingleBlock(node)
+			if err != nil {
+				return nil, err
+			}
+			if b.text != "" {
+				blocks = append(blocks, b)
+			}
+		case "layoutSection":
+			s.addWarning(converter.WarningDroppedFeature, node.Type, "layout columns rendered one after another")
+			for _, column := range node.Content {
+				nested, err := s.renderDoc(column.Content)
+				if err != nil {
+					return nil, err
+				}
+				blocks = append(blocks, nested...)
+			}
+		default:
+			rendered, err := s.renderNode(node)
+			if err != nil {
+				return nil, err
+			}
+			if strings.TrimSpace(rendered) != "" {
+				blocks = append(blocks, block{kind: blockSection, text: rendered})
+			}
+		}
+	}
+	return blocks, nil
+}
+
+// renderHeading renders a heading as a bold line of plain text, since mrkdwn has no
+// headings and formatting cannot nest inside bold.
+func (s *state) renderHeading(node converter.Node) string {
+	plain := plainText(node.Content)
+	if plain == "" {
+		return ""
+	}
+	return "*" + escape(plain) + "*"
+}
+
+func (s *state) renderBlockquote(node converter.Node) (string, error) {
+	content, err := s.renderChildren(node.Content)
+	if err != nil || content == "" {
+		return "", err
+	}
+	return quote(content), nil
+}
+
+// renderCodeBlock renders a code block as a ``` block. Slack ignores languages, and a
+// zero-width space keeps a ``` inside the code from closing the block.
+func renderCodeBlock(node converter.Node) string {
+	var code strings.Builder
+	for _, child := range node.Content {
+		code.WriteString(child.Text)
+	}
+	return codeFence(code.String())
+}
+
+func codeFence(code string) string {
+	code = strings.ReplaceAll(strings.TrimRight(code, "\n"), "```", "``​`")
+	return "```\n" + escape(code) + "\n```"
+}
+
+// renderList renders bulletList and orderedList nodes as indented marker lines.
+func (s *state) renderList(node converter.Node) (string, error) {
+	depth := s.listDepth
+	s.listDepth++
+	defer func() { s.listDepth-- }()
+
+	number := node.GetIntAttr("order", 1)
+	var items []string
+	for _, item := range node.Content {
+		if item.Type != "listItem" {
+			rendered, err := s.renderNode(item)
+			if err != nil {
+				return "", err
+			}
+			if rendered != "" {
+				items = append(items, rendered)
+			}
+			continue
+		}
+
+		marker := bulletMarkers[depth%len(bulletMarkers)]
+		if node.Type == "orderedList" {
+			marker = strconv.Itoa(number) + "."
+			number++
+		}
+		rendered, err := s.renderListItem(item.Content, depth, marker)
+		if err != nil {
+			return "", err
+		}
+		items = append(items, rendered)
+	}
+	return strings.Join(items, "\n"), nil
+}
+
+// renderListItem renders the content of a list item after its marker. Nested lists
+// indent themselves; other blocks are indented to line up with the item text.
+func (s *state) renderListItem(content []converter.Node, depth int, marker string) (string, error) {
+	indent := listIndent(depth)
+	continuation := indent + strings.Repeat(" ", utf8.RuneCountInString(marker)+1)
+
+	var lines []string
+	for i, child := range content {
+		rendered, err := s.renderNode(child)
+		if err != nil {
+			return "", err
+		}
+		if isListNode(child) {
+			if i == 0 {
+				lines = append(lines, indent+marker)
+			}
+			if rendered != "" {
+				lines = append(lines, rendered)
+			}
+			continue
+		}
+		if i == 0 {
+			lines = append(lines, indent+marker+" "+indentLines(rendered, continuation, false))
+			continue
+		}
+		if rendered != "" {
+			lines = append(lines, indentLines(rendered, continuation, true))
+		}
+	}
+	if len(lines) == 0 {
+		return indent + marker, nil
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// renderTaskList renders task items as ☑ and ☐ lines; a taskList directly inside
+// another is one level deeper.
+func (s *state) renderTaskList(node converter.Node) (string, error) {
+	depth := s.listDepth
+	s.listDepth++
+	defer func() { s.listDepth-- }()
+
+	var items []string
+	for _, item := range node.Content {
+		if item.Type != "taskItem" {
+			rendered, err := s.renderNode(item)
+			if err != nil {
+				return "", err
+			}
+			if rendered != "" {
+				items = append(items, rendered)
+			}
+			continue
+		}
+
+		marker := "☐"
+		if item.GetStringAttr("state", "") == "DONE" {
+			marker = "☑"
+		}
+		rendered, err := s.renderListItem([]converter.Node{{Type: "paragraph", Content: item.Content}}, depth, marker)
+		if err != nil {
+			return "", err
+		}
+		items = append(items, rendered)
+	}
+	return strings.Join(items, "\n"), nil
+}
+
+// renderDecisionList renders decision items as bullets labelled like the Markdown
+// converter's decisions.
+func (s *state) renderDecisionList(node converter.Node) (string, error) {
+	depth := s.listDepth
+	s.listDepth++
+	defer func() { s.listDepth-- }()
+
+	var items []string
+	for _, item := range node.Content {
+		if item.Type != "decisionItem" {
+			continue
+		}
+		content, err := s.renderInline(item.Content)
+		if err != nil {
+			return "", err
+		}
+
+		label := "Decision"
+		switch item.GetStringAttr("state", "") {
+		case "DECIDED":
+			label = "✓ Decision"
+		case "UNDECIDED":
+			label = "? Decision"
+		}
+		marker := bulletMarkers[depth%len(bulletMarkers)]
+		continuation := listIndent(depth) + "  "
+		items = append(items, listIndent(depth)+marker+" *"+label+"*: "+indentLines(content, continuation, false))
+	}
+	return strings.Join(items, "\n"), nil
+}
+
+func isListNode(node converter.Node) bool {
+	switch node.Type {
+	case "bulletList", "orderedList", "taskList", "decisionList":
+		return true
+	}
+	return false
+}
+
+func listIndent(depth int) string {
+	return strings.Repeat("    ", depth)
+}
+
+// indentLines prefixes the lines of content with indent, skipping the first line
+// unless first is set and leaving blank lines empty.
+func indentLines(content, indent string, first bool) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if (i == 0 && !first) || line == "" {
+			continue
+		}
+		lines[i] = indent + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderPanelBody renders a panel as its emoji, bold title and content, the text of a
+// context block. Panel colors cannot be shown in Slack.
+func (s *state) renderPanelBody(node converter.Node) (string, error) {
+	content, err := s.renderChildren(node.Content)
+	if err != nil {
+		return "", err
+	}
+	if color := node.GetStringAttr("panelColor", ""); color != "" {
+		s.addWarning(converter.WarningDroppedFeature, node.Type, fmt.Sprintf("panel color %q dropped", color))
+	}
+
+	emoji := panelEmoji[node.GetStringAttr("panelType", "info")]
+	if icon := node.GetStringAttr("panelIcon", ""); isSlackShortcode(icon) {
+		emoji = icon
+	}
+	title := strings.TrimSpace(node.GetStringAttr("title", ""))
+
+	var head []string
+	if emoji != "" {
+		head = append(head, emoji)
+	}
+	if title != "" {
+		head = append(head, "*"+escape(title)+"*")
+	}
+	switch {
+	case len(head) == 0:
+		return content, nil
+	case title != "" && content != "":
+		return strings.Join(head, " ") + "\n" + content, nil
+	case content == "":
+		return strings.Join(head, " "), nil
+	default:
+		return strings.Join(head, " ") + " " + content, nil
+	}
+}
+
+// renderExpand renders an expand as its bold title followed by its content, since Slack
+// has no collapsible sections.
+func (s *state) renderExpand(node converter.Node) (string, error) {
+	content, err := s.renderChildren(node.Content)
+	if err != nil {
+		return "", err
+	}
+	s.addWarning(converter.WarningDroppedFeature, node.Type, "expand rendered expanded; Slack has no collapsible sections")
+
+	title := strings.TrimSpace(node.GetStringAttr("title", ""))
+	if title == "" {
+		return content, nil
+	}
+	if content == "" {
+		return "*" + escape(title) + "*", nil
+	}
+	return "*" + escape(title) + "*\n" + content, nil
+}
+
+// renderLayoutSection renders layout columns one after another.
+func (s *state) renderLayoutSection(node converter.Node) (string, error) {
+	s.addWarning(converter.WarningDroppedFeature, node.Type, "layout columns rendered one after another")
+	return s.renderChildren(node.Content)
+}
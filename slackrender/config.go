@@ -0,0 +1,139 @@
+package slackrender
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// Format selects the rendered message form.
+type Format string
+
+const (
+	// FormatMrkdwn renders the message as mrkdwn text only.
+	FormatMrkdwn Format = "mrkdwn"
+	// FormatBlockKit also renders the message as Block Kit blocks. The mrkdwn text is kept
+	// as the notification fallback Slack expects alongside blocks.
+	FormatBlockKit Format = "blocks"
+)
+
+// TableStyle controls how tables are rendered, since Slack has no table markup.
+type TableStyle string
+
+const (
+	// TableCode renders tables as aligned plain-text columns in a code block.
+	TableCode TableStyle = "code"
+	// TableSummary replaces tables with a one-line summary of their size and headers.
+	TableSummary TableStyle = "summary"
+)
+
+// Slack limits enforced by default.
+const (
+	// DefaultMaxTextLength is the length after which Slack truncates message text.
+	DefaultMaxTextLength = 40000
+	// DefaultMaxBlocks is the maximum number of blocks in one message.
+	DefaultMaxBlocks = 50
+)
+
+// Config holds all renderer configuration options.
+type Config struct {
+	Format     Format     `json:"format,omitempty"`
+	TableStyle TableStyle `json:"tableStyle,omitempty"`
+	// MaxTextLength truncates the mrkdwn text to this many characters.
+	MaxTextLength int `json:"maxTextLength,omitempty"`
+	// MaxBlocks drops the blocks after this many when rendering Block Kit.
+	MaxBlocks      int                      `json:"maxBlocks,omitempty"`
+	Extensions     converter.ExtensionRules `json:"extensions,omitempty"`
+	UnknownNodes   converter.UnknownPolicy  `json:"unknownNodes,omitempty"`
+	UnknownMarks   converter.UnknownPolicy  `json:"unknownMarks,omitempty"`
+	ResolutionMode converter.ResolutionMode `json:"resolutionMode,omitempty"`
+	LinkHook       converter.LinkRenderHook `json:"-"`
+	MediaHook      MediaRenderHook          `json:"-"`
+	MentionHook    MentionRenderHook        `json:"-"`
+}
+
+func (c Config) applyDefaults() Config {
+	if c.Format == "" {
+		c.Format = FormatMrkdwn
+	}
+	if c.TableStyle == "" {
+		c.TableStyle = TableCode
+	}
+	if c.MaxTextLength == 0 {
+		c.MaxTextLength = DefaultMaxTextLength
+	}
+	if c.MaxBlocks == 0 {
+		c.MaxBlocks = DefaultMaxBlocks
+	}
+	if c.Extensions.Default == "" {
+		c.Extensions.Default = converter.ExtensionText
+	}
+	if c.UnknownNodes == "" {
+		c.UnknownNodes = converter.UnknownPlaceholder
+	}
+	if c.UnknownMarks == "" {
+		c.UnknownMarks = converter.UnknownSkip
+	}
+	if c.ResolutionMode == "" {
+		c.ResolutionMode = converter.ResolutionBestEffort
+	}
+	return c
+}
+
+// clone returns a deep copy of Config for map-backed fields.
+func (c Config) clone() Config {
+	cloned := c
+	if c.Extensions.ByType != nil {
+		cloned.Extensions.ByType = make(map[string]converter.ExtensionMode, len(c.Extensions.ByType))
+		for extensionType, mode := range c.Extensions.ByType {
+			cloned.Extensions.ByType[extensionType] = mode
+		}
+	}
+	return cloned
+}
+
+// Validate checks that config values are valid.
+func (c Config) Validate() error {
+	if c.Format != FormatMrkdwn && c.Format != FormatBlockKit {
+		return fmt.Errorf("invalid format %q", c.Format)
+	}
+	if c.TableStyle != TableCode && c.TableStyle != TableSummary {
+		return fmt.Errorf("invalid tableStyle %q", c.TableStyle)
+	}
+	if c.MaxTextLength < minTextLength {
+		return fmt.Errorf("maxTextLength must be at least %d", minTextLength)
+	}
+	if c.MaxBlocks < 1 {
+		return fmt.Errorf("maxBlocks must be positive")
+	}
+	if !isValidExtensionMode(c.Extensions.Default) {
+		return fmt.Errorf("invalid extensions.default %q", c.Extensions.Default)
+	}
+	for extensionType, mode := range c.Extensions.ByType {
+		if strings.TrimSpace(extensionType) == "" {
+			return fmt.Errorf("extensions.byType contains empty key")
+		}
+		if !isValidExtensionMode(mode) {
+			return fmt.Errorf("invalid extensions.byType mode %q for type %q", mode, extensionType)
+		}
+	}
+	if !isValidUnknownPolicy(c.UnknownNodes) {
+		return fmt.Errorf("invalid unknownNodes policy %q", c.UnknownNodes)
+	}
+	if !isValidUnknownPolicy(c.UnknownMarks) {
+		return fmt.Errorf("invalid unknownMarks policy %q", c.UnknownMarks)
+	}
+	if c.ResolutionMode != converter.ResolutionBestEffort && c.ResolutionMode != converter.ResolutionStrict {
+		return fmt.Errorf("invalid resolutionMode %q", c.ResolutionMode)
+	}
+	return nil
+}
+
+func isValidExtensionMode(mode converter.ExtensionMode) bool {
+	return mode == converter.ExtensionJSON || mode == converter.ExtensionText || mode == converter.ExtensionStrip
+}
+
+func isValidUnknownPolicy(policy converter.UnknownPolicy) bool {
+	return policy == converter.UnknownError || policy == converter.UnknownSkip || policy == converter.UnknownPlaceholder
+}
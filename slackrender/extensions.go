@@ -0,0 +1,62 @@
+package slackrender
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+type extensionJSONNode struct {
+	Type    string                 `json:"type"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Content []converter.Node       `json:"content,omitempty"`
+}
+
+// renderExtension renders extension, inlineExtension and bodiedExtension nodes.
+// Bodied extensions keep their body; other extensions follow Config.Extensions.
+func (s *state) renderExtension(node converter.Node) (string, error) {
+	if node.Type == "bodiedExtension" {
+		return s.renderChildren(node.Content)
+	}
+
+	inline := node.Type == "inlineExtension"
+	extensionType := firstNonEmpty(node.GetStringAttr("extensionType", ""), node.GetStringAttr("extensionKey", ""), node.Type)
+	switch mode := s.config.Extensions.ModeFor(extensionType); mode {
+	case converter.ExtensionStrip:
+		s.addWarning(converter.WarningDroppedFeature, node.Type, fmt.Sprintf("extension %q stripped", extensionType))
+		return "", nil
+	case converter.ExtensionText:
+		text := escape(node.GetStringAttr("text", ""))
+		if len(node.Content) > 0 {
+			content, err := s.renderInline(node.Content)
+			if err != nil {
+				return "", err
+			}
+			if strings.TrimSpace(content) != "" {
+				text = content
+			}
+		}
+		if text == "" {
+			s.addWarning(converter.WarningExtensionFallback, node.Type, fmt.Sprintf("extension %q has no fallback text", extensionType))
+		}
+		return text, nil
+	case converter.ExtensionJSON:
+		payload := extensionJSONNode{Type: node.Type, Attrs: node.Attrs, Content: node.Content}
+		if inline {
+			data, err := json.Marshal(payload)
+			if err != nil {
+				return "", fmt.Errorf("failed to marshal extension node: %w", err)
+			}
+			return "`" + escape(string(data)) + "`", nil
+		}
+		data, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal extension node: %w", err)
+		}
+		return codeFence(string(data)), nil
+	default:
+		return "", fmt.Errorf("unknown extension strategy: %s", mode)
+	}
+}
@@ -0,0 +1,112 @@
+package slackrender
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// MediaRenderHook resolves a media node to a URL Slack can load during rendering. It
+// receives the same input as the converter media hook.
+type MediaRenderHook func(ctx context.Context, in converter.MediaRenderInput) (MediaRenderOutput, error)
+
+// MentionRenderHook maps an Atlassian mention to a Slack user during rendering.
+type MentionRenderHook func(ctx context.Context, in MentionRenderInput) (MentionRenderOutput, error)
+
+// MediaRenderOutput contains a hook-provided public URL for a media node.
+type MediaRenderOutput struct {
+	URL     string
+	Handled bool
+}
+
+// MentionRenderInput describes a mention node being rendered.
+type MentionRenderInput struct {
+	SourcePath string
+	// ID is the Atlassian account ID of the mentioned user.
+	ID string
+	// Text is the display text of the mention, usually "@Name".
+	Text  string
+	Attrs map[string]any
+}
+
+// MentionRenderOutput contains hook-provided mention data. UserID renders a Slack user
+// mention such as <@U123>; Text alone replaces the mention text.
+type MentionRenderOutput struct {
+	UserID  string
+	Text    string
+	Handled bool
+}
+
+// applyLinkHook calls Config.LinkHook and applies the same unresolved-reference policy
+// and output validation as the Markdown converter.
+func (s *state) applyLinkHook(nodeType string, input converter.LinkRenderInput) (converter.LinkRenderOutput, bool, error) {
+	if s.config.LinkHook == nil {
+		return converter.LinkRenderOutput{}, false, nil
+	}
+	if err := s.checkContext(); err != nil {
+		return converter.LinkRenderOutput{}, false, err
+	}
+
+	output, err := s.config.LinkHook(s.ctx, input)
+	if err != nil {
+		return converter.LinkRenderOutput{}, false, s.hooks.LinkError(nodeType, input, err)
+	}
+	return converter.CheckLinkRenderOutput(output)
+}
+
+// applyMediaHook calls Config.MediaHook and applies the same unresolved-reference policy
+// as the Markdown converter.
+func (s *state) applyMediaHook(nodeType string, input converter.MediaRenderInput) (MediaRenderOutput, bool, error) {
+	if s.config.MediaHook == nil {
+		return MediaRenderOutput{}, false, nil
+	}
+	if err := s.checkContext(); err != nil {
+		return MediaRenderOutput{}, false, err
+	}
+
+	output, err := s.config.MediaHook(s.ctx, input)
+	if err != nil {
+		return MediaRenderOutput{}, false, s.hooks.MediaError(nodeType, input, err)
+	}
+
+	if !output.Handled {
+		return MediaRenderOutput{}, false, nil
+	}
+	output.URL = strings.TrimSpace(output.URL)
+	if output.URL == "" {
+		return MediaRenderOutput{}, false, errors.New("invalid media hook output: handled media render output requires non-empty url")
+	}
+	return output, true, nil
+}
+
+// applyMentionHook calls Config.MentionHook and applies the same unresolved-reference
+// policy as the link and media hooks.
+func (s *state) applyMentionHook(nodeType string, input MentionRenderInput) (MentionRenderOutput, bool, error) {
+	if s.config.MentionHook == nil {
+		return MentionRenderOutput{}, false, nil
+	}
+	if err := s.checkContext(); err != nil {
+		return MentionRenderOutput{}, false, err
+	}
+
+	output, err := s.config.MentionHook(s.ctx, input)
+	if err != nil {
+		return MentionRenderOutput{}, false, s.hooks.Error(nodeType, "mention", fmt.Sprintf("mention %q", input.ID), err)
+	}
+
+	if !output.Handled {
+		return MentionRenderOutput{}, false, nil
+	}
+	output.UserID = strings.TrimSpace(output.UserID)
+	output.Text = strings.TrimSpace(output.Text)
+	if output.UserID == "" && output.Text == "" {
+		return MentionRenderOutput{}, false, errors.New("invalid mention hook output: handled mention render output requires userID or text")
+	}
+	if strings.ContainsAny(output.UserID, "<>|@ ") {
+		return MentionRenderOutput{}, false, fmt.Errorf("invalid mention hook output: userID %q is not a Slack user ID", output.UserID)
+	}
+	return output, true, nil
+}
@@ -0,0 +1,324 @@
+package slackrender
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// slackShortcodeRe matches emoji short names Slack understands, such as ":tada:".
+var slackShortcodeRe = regexp.MustCompile(`^:[a-z0-9_+'-]+:$`)
+
+// renderInline renders inline content. Adjacent text nodes that share a link mark are
+// rendered as a single link, so the link hook runs once per link.
+func (s *state) renderInline(content []converter.Node) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(content); {
+		link, ok := findLinkMark(content[i])
+		if !ok {
+			rendered, err := s.renderNode(content[i])
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(rendered)
+			i++
+			continue
+		}
+
+		end := i + 1
+		for end < len(content) {
+			next, ok := findLinkMark(content[end])
+			if !ok || !reflect.DeepEqual(next.Attrs, link.Attrs) {
+				break
+			}
+			end++
+		}
+
+		rendered, err := s.renderLink(link, content[i:end])
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(rendered)
+		i = end
+	}
+	return sb.String(), nil
+}
+
+func findLinkMark(node converter.Node) (converter.Mark, bool) {
+	if node.Type != "text" {
+		return converter.Mark{}, false
+	}
+	for _, mark := range node.Marks {
+		if mark.Type == "link" {
+			return mark, true
+		}
+	}
+	return converter.Mark{}, false
+}
+
+// renderLink renders text nodes sharing a link mark as <url|text>.
+func (s *state) renderLink(link converter.Mark, nodes []converter.Node) (string, error) {
+	var inner, plain strings.Builder
+	for _, node := range nodes {
+		rendered, err := s.renderText(node)
+		if err != nil {
+			return "", err
+		}
+		inner.WriteString(rendered)
+		plain.WriteString(node.Text)
+	}
+
+	input, ok := converter.LinkMarkRenderInput(s.options.SourcePath, link)
+	if !ok {
+		return inner.String(), nil
+	}
+
+	href := input.Href
+	output, handled, err := s.applyLinkHook(link.Type, input)
+	if err != nil {
+		return "", err
+	}
+	if handled {
+		if output.TextOnly {
+			return inner.String(), nil
+		}
+		href = output.Href
+	}
+	return slackLink(href, inner.String(), plain.String()), nil
+}
+
+// slackLink renders <url|text>, or <url> when the text is the URL itself.
+func slackLink(href, text, plain string) string {
+	target := strings.ReplaceAll(escape(href), "|", "%7C")
+	if text == "" || plain == href {
+		return "<" + target + ">"
+	}
+	return "<" + target + "|" + text + ">"
+}
+
+// renderText renders a text node with its formatting marks. Link marks are applied by
+// renderInline. Slack only recognizes markers next to non-space characters, so leading
+// and trailing spaces are kept outside them.
+func (s *state) renderText(node converter.Node) (string, error) {
+	var opening, closing strings.Builder
+	for _, mark := range node.Marks {
+		delimiter, err := s.markDelimiter(mark)
+		if err != nil {
+			return "", err
+		}
+		opening.WriteString(delimiter)
+		closing.WriteString(reverse(delimiter))
+	}
+
+	text := escape(node.Text)
+	core := strings.TrimSpace(text)
+	if core == "" || opening.Len() == 0 {
+		return text, nil
+	}
+	start := strings.Index(text, core)
+	return text[:start] + opening.String() + core + reverse(closing.String()) + text[start+len(core):], nil
+}
+
+// reverse reverses an ASCII delimiter sequence so that closing markers nest.
+func reverse(delimiters string) string {
+	out := []byte(delimiters)
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// markDelimiter returns the mrkdwn marker for a mark. Marks Slack cannot show render
+// their text unformatted.
+func (s *state) markDelimiter(mark converter.Mark) (string, error) {
+	switch mark.Type {
+	case "strong":
+		return "*", nil
+	case "em":
+		return "_", nil
+	case "strike":
+		return "~", nil
+	case "code":
+		return "`", nil
+	case "textColor", "backgroundColor":
+		if color := mark.GetStringAttr("color", ""); color != "" {
+			s.addWarning(converter.WarningDroppedFeature, mark.Type, fmt.Sprintf("color %q dropped; Slack has no colored text", color))
+		}
+		return "", nil
+	case "underline", "subsup", "link", "annotation", "alignment", "indentation", "breakout":
+		return "", nil
+	default:
+		if s.config.UnknownMarks == converter.UnknownError {
+			return "", fmt.Errorf("unknown mark type: %s", mark.Type)
+		}
+		// Markers cannot carry placeholder text, so the placeholder policy skips as well.
+		s.addWarning(converter.WarningUnknownMark, mark.Type, fmt.Sprintf("unknown mark skipped: %s", mark.Type))
+		return "", nil
+	}
+}
+
+// renderMention renders a mention as a Slack user mention when Config.MentionHook maps
+// it, and as its @name text otherwise, which does not notify anyone.
+func (s *state) renderMention(node converter.Node) (string, error) {
+	id := node.GetStringAttr("id", "")
+	text := node.GetStringAttr("text", "")
+	if text != "" && !strings.HasPrefix(text, "@") {
+		text = "@" + text
+	}
+
+	if id != "" {
+		output, handled, err := s.applyMentionHook(node.Type, MentionRenderInput{
+			SourcePath: s.options.SourcePath,
+			ID:         id,
+			Text:       text,
+			Attrs:      maps.Clone(node.Attrs),
+		})
+		if err != nil {
+			return "", err
+		}
+		if handled {
+			if output.UserID != "" {
+				return "<@" + output.UserID + ">", nil
+			}
+			return escape(output.Text), nil
+		}
+	} else {
+		s.addWarning(converter.WarningMissingAttribute, node.Type, "mention node missing id")
+	}
+
+	if text == "" {
+		text = "@Unknown User"
+	}
+	return escape(text), nil
+}
+
+// renderStatus renders a status lozenge as inline code.
+func (s *state) renderStatus(node converter.Node) string {
+	text := strings.TrimSpace(node.GetStringAttr("text", ""))
+	if text == "" {
+		return ""
+	}
+	return "`" + escape(text) + "`"
+}
+
+// renderEmoji renders an emoji by its short name when Slack knows the form, and by its
+// text otherwise.
+func (s *state) renderEmoji(node converter.Node) (string, error) {
+	shortName := node.GetStringAttr("shortName", "")
+	if isSlackShortcode(shortName) {
+		return shortName, nil
+	}
+	if text := firstNonEmpty(node.GetStringAttr("text", ""), node.GetStringAttr("fallback", ""), shortName); text != "" {
+		return escape(text), nil
+	}
+	if s.config.UnknownNodes == converter.UnknownError {
+		return "", fmt.Errorf("emoji node missing shortName and fallback")
+	}
+	s.addWarning(converter.WarningMissingAttribute, node.Type, "emoji node missing shortName and fallback")
+	return "", nil
+}
+
+func isSlackShortcode(shortName string) bool {
+	return slackShortcodeRe.MatchString(shortName)
+}
+
+// renderDate renders a date as a Slack date token, which each reader sees in their own
+// locale, with the ISO date as fallback text.
+func (s *state) renderDate(node converter.Node) (string, error) {
+	timestamp := node.GetStringAttr("timestamp", "")
+	date, ok := parseTimestamp(timestamp)
+	if !ok {
+		if s.config.UnknownNodes == converter.UnknownError {
+			return "", fmt.Errorf("date node has invalid timestamp format: %s", timestamp)
+		}
+		s.addWarning(converter.WarningMissingAttribute, node.Type, fmt.Sprintf("date node has invalid timestamp format: %s", timestamp))
+		return escape("[Date: invalid]"), nil
+	}
+	return fmt.Sprintf("<!date^%d^{date_short}|%s>", date.Unix(), date.Format("2006-01-02")), nil
+}
+
+// parseTimestamp parses a date node timestamp. Millisecond timestamps are detected with
+// the same cutoff as the Markdown converter.
+func parseTimestamp(timestamp string) (time.Time, bool) {
+	var ts int64
+	if _, err := fmt.Sscanf(timestamp, "%d", &ts); err != nil {
+		return time.Time{}, false
+	}
+	if ts > 10000000000 {
+		ts = ts / 1000
+	}
+	return time.Unix(ts, 0).UTC(), true
+}
+
+// renderInlineCard renders an inlineCard as a link titled by the link hook, if any.
+func (s *state) renderInlineCard(node converter.Node) (string, error) {
+	input := converter.InlineCardRenderInput(s.options.SourcePath, node)
+	if input.Href == "" {
+		if s.config.UnknownNodes == converter.UnknownError {
+			return "", fmt.Errorf("inlineCard missing url and valid data")
+		}
+		s.addWarning(converter.WarningMissingAttribute, node.Type, "inlineCard missing url and valid data")
+		return escape("[Smart Link]"), nil
+	}
+
+	href, title := input.Href, ""
+	output, handled, err := s.applyLinkHook(node.Type, input)
+	if err != nil {
+		return "", err
+	}
+	if handled {
+		if output.TextOnly {
+			return escape(firstNonEmpty(output.Title, input.Title, href)), nil
+		}
+		href, title = output.Href, output.Title
+	}
+	return slackLink(href, escape(title), title), nil
+}
+
+// plainText returns the text of content without formatting, with blocks separated by
+// single spaces, for header blocks, table cells and image captions.
+func plainText(content []converter.Node) string {
+	var sb strings.Builder
+	var walk func(nodes []converter.Node)
+	walk = func(nodes []converter.Node) {
+		for _, node := range nodes {
+			switch node.Type {
+			case "text":
+				sb.WriteString(node.Text)
+			case "hardBreak":
+				sb.WriteString(" ")
+			case "mention":
+				text := node.GetStringAttr("text", "")
+				if text != "" && !strings.HasPrefix(text, "@") {
+					text = "@" + text
+				}
+				sb.WriteString(text)
+			case "emoji":
+				if shortName := node.GetStringAttr("shortName", ""); isSlackShortcode(shortName) {
+					sb.WriteString(shortName)
+				} else {
+					sb.WriteString(firstNonEmpty(node.GetStringAttr("text", ""), node.GetStringAttr("fallback", ""), shortName))
+				}
+			case "status":
+				sb.WriteString(node.GetStringAttr("text", ""))
+			case "date":
+				if date, ok := parseTimestamp(node.GetStringAttr("timestamp", "")); ok {
+					sb.WriteString(date.Format("2006-01-02"))
+				}
+			case "inlineCard":
+				sb.WriteString(converter.InlineCardRenderInput("", node).Href)
+			default:
+				sb.WriteString(" ")
+				walk(node.Content)
+				sb.WriteString(" ")
+			}
+		}
+	}
+	walk(content)
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
@@ -0,0 +1,207 @@
+package slackrender
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// Block Kit field limits.
+const (
+	maxBlockText   = 3000
+	maxHeaderText  = 150
+	maxAltText     = 2000
+	maxImageURL    = 3000
+	maxImageTitle  = 2000
+	minTextLength  = 100
+	truncationMark = "…"
+	codeFenceMark  = "```"
+)
+
+// kitText is a Block Kit text object.
+type kitText struct {
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+// kitBlock is a Block Kit block; only the fields of its type are set.
+type kitBlock struct {
+	Type     string    `json:"type"`
+	Text     *kitText  `json:"text,omitempty"`
+	Elements []kitText `json:"elements,omitempty"`
+	ImageURL string    `json:"image_url,omitempty"`
+	AltText  string    `json:"alt_text,omitempty"`
+	Title    *kitText  `json:"title,omitempty"`
+}
+
+// blockKit renders blocks as a Block Kit blocks array. Section and context text longer
+// than Slack allows is split across blocks, header text is truncated, and blocks past
+// Config.MaxBlocks are dropped.
+func (s *state) blockKit(blocks []block) (json.RawMessage, error) {
+	kit := make([]kitBlock, 0, len(blocks))
+	for _, b := range blocks {
+		switch b.kind {
+		case blockHeader:
+			text, truncated := truncateRunes(b.plain, maxHeaderText)
+			if truncated {
+				s.addWarning(converter.WarningTruncated, "heading", fmt.Sprintf("header text truncated to %d characters", maxHeaderText))
+			}
+			kit = append(kit, kitBlock{Type: string(blockHeader), Text: &kitText{Type: "plain_text", Text: text, Emoji: true}})
+		case blockContext:
+			for _, chunk := range splitMrkdwn(b.text, maxBlockText) {
+				kit = append(kit, kitBlock{Type: string(blockContext), Elements: []kitText{{Type: "mrkdwn", Text: chunk}}})
+			}
+		case blockDivider:
+			kit = append(kit, kitBlock{Type: string(blockDivider)})
+		case blockImage:
+			if utf8.RuneCountInString(b.imageURL) > maxImageURL {
+				s.addWarning(converter.WarningDroppedFeature, "mediaSingle", "image URL too long for an image block; rendered as a link")
+				kit = append(kit, sectionBlocks(b.text)...)
+				continue
+			}
+			altText, _ := truncateRunes(b.altText, maxAltText)
+			image := kitBlock{Type: string(blockImage), ImageURL: b.imageURL, AltText: altText}
+			if b.plain != "" {
+				title, _ := truncateRunes(b.plain, maxImageTitle)
+				image.Title = &kitText{Type: "plain_text", Text: title, Emoji: true}
+			}
+			kit = append(kit, image)
+		default:
+			kit = append(kit, sectionBlocks(b.text)...)
+		}
+	}
+
+	if len(kit) > s.config.MaxBlocks {
+		s.addWarning(converter.WarningTruncated, "doc", fmt.Sprintf("%d blocks dropped; a message holds at most %d blocks", len(kit)-s.config.MaxBlocks, s.config.MaxBlocks))
+		kit = kit[:s.config.MaxBlocks]
+	}
+
+	// Slack reads <, > and & as mrkdwn escapes, so they are not escaped again as JSON.
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(kit); err != nil {
+		return nil, fmt.Errorf("failed to marshal blocks: %w", err)
+	}
+	return json.RawMessage(bytes.TrimRight(buf.Bytes(), "\n")), nil
+}
+
+func sectionBlocks(text string) []kitBlock {
+	var kit []kitBlock
+	for _, chunk := range splitMrkdwn(text, maxBlockText) {
+		kit = append(kit, kitBlock{Type: string(blockSection), Text: &kitText{Type: "mrkdwn", Text: chunk}})
+	}
+	return kit
+}
+
+// truncateText truncates the message text to Config.MaxTextLength, closing an open
+// code block and never cutting through a <...> token or &...; entity.
+func (s *state) truncateText(text string) string {
+	limit := s.config.MaxTextLength
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	s.addWarning(converter.WarningTruncated, "doc", fmt.Sprintf("message text truncated to %d characters", limit))
+
+	suffix := "\n" + codeFenceMark
+	head := string([]rune(text)[:limit-utf8.RuneCountInString(truncationMark)-len(suffix)])
+	if i := strings.LastIndex(head, "<"); i > strings.LastIndex(head, ">") {
+		head = head[:i]
+	}
+	if i := strings.LastIndex(head, "&"); i > strings.LastIndex(head, ";") {
+		head = head[:i]
+	}
+	head += truncationMark
+	if fenceOpen(head) {
+		head += suffix
+	}
+	return head
+}
+
+// splitMrkdwn splits text into chunks of at most limit characters at line boundaries,
+// closing and reopening code blocks that span chunks. Lines longer than a chunk are cut.
+func splitMrkdwn(text string, limit int) []string {
+	if utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+
+	// Each chunk keeps room for a closing fence line, and each line for a reopened one.
+	budget := limit - len(codeFenceMark) - 1
+	var chunks, current []string
+	length := 0
+	inFence := false
+	flush := func() {
+		if chunk := strings.Join(current, "\n"); strings.TrimSpace(chunk) != "" {
+			if inFence {
+				chunk += "\n" + codeFenceMark
+			}
+			chunks = append(chunks, chunk)
+		}
+		current, length = nil, 0
+		if inFence {
+			current, length = []string{codeFenceMark}, len(codeFenceMark)+1
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		for _, piece := range splitRunes(line, budget-len(codeFenceMark)-1) {
+			size := utf8.RuneCountInString(piece) + 1
+			if length+size > budget && len(current) > 0 {
+				flush()
+			}
+			current = append(current, piece)
+			length += size
+		}
+		if isFenceLine(line) {
+			inFence = !inFence
+		}
+	}
+	if chunk := strings.Join(current, "\n"); strings.TrimSpace(chunk) != "" {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// splitRunes cuts text into pieces of at most size characters.
+func splitRunes(text string, size int) []string {
+	runes := []rune(text)
+	if len(runes) <= size {
+		return []string{text}
+	}
+	var pieces []string
+	for len(runes) > size {
+		pieces = append(pieces, string(runes[:size]))
+		runes = runes[size:]
+	}
+	return append(pieces, string(runes))
+}
+
+// truncateRunes truncates text to limit characters, ending it with an ellipsis.
+func truncateRunes(text string, limit int) (string, bool) {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text, false
+	}
+	return string(runes[:limit-1]) + truncationMark, true
+}
+
+// isFenceLine reports whether a line opens or closes a code block, including in quotes
+// and list items.
+func isFenceLine(line string) bool {
+	return strings.HasPrefix(strings.TrimLeft(line, " >"), codeFenceMark)
+}
+
+func fenceOpen(text string) bool {
+	open := false
+	for _, line := range strings.Split(text, "\n") {
+		if isFenceLine(line) {
+			open = !open
+		}
+	}
+	return open
+}
@@ -0,0 +1,126 @@
+package slackrender
+
+import (
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// resolveMedia returns the URL of a media node, from Config.MediaHook or the url attr,
+// and its display name.
+func (s *state) resolveMedia(node converter.Node) (string, string, error) {
+	input := converter.MediaNodeRenderInput(s.options.SourcePath, node)
+	name := firstNonEmpty(input.Alt, input.Meta.Filename, node.GetStringAttr("name", ""))
+
+	output, handled, err := s.applyMediaHook(node.Type, input)
+	if err != nil {
+		return "", "", err
+	}
+	if handled {
+		return output.URL, name, nil
+	}
+	return strings.TrimSpace(input.URL), name, nil
+}
+
+// renderMedia renders a media node as a link. Media Slack cannot load, such as
+// attachments without a hook-provided URL, render as their name.
+func (s *state) renderMedia(node converter.Node) (string, error) {
+	url, name, err := s.resolveMedia(node)
+	if err != nil {
+		return "", err
+	}
+	return s.mediaLink(node.Type, url, name), nil
+}
+
+func (s *state) mediaLink(nodeType, url, name string) string {
+	if url != "" {
+		return slackLink(url, escape(name), name)
+	}
+	if name != "" {
+		s.addWarning(converter.WarningDroppedFeature, nodeType, "media without URL rendered as its name")
+		return ":paperclip: " + escape(name)
+	}
+	s.addWarning(converter.WarningMissingAttribute, nodeType, "media node missing url and name")
+	return escape("[Media]")
+}
+
+// renderMediaContainer renders mediaSingle and mediaGroup nodes as their media links,
+// separated by spaces, with a mediaSingle caption on the next line.
+func (s *state) renderMediaContainer(node converter.Node) (string, error) {
+	var items []string
+	caption := ""
+	for _, child := range node.Content {
+		if child.Type == "caption" {
+			rendered, err := s.renderInline(child.Content)
+			if err != nil {
+				return "", err
+			}
+			caption = rendered
+			continue
+		}
+		rendered, err := s.renderNode(child)
+		if err != nil {
+			return "", err
+		}
+		if rendered != "" {
+			items = append(items, rendered)
+		}
+	}
+
+	text := strings.Join(items, " ")
+	if caption != "" {
+		if text != "" {
+			text += "\n"
+		}
+		text += caption
+	}
+	return text, nil
+}
+
+// renderMediaSingleBlock renders a top-level mediaSingle as an image block when its
+// media resolves to a URL, and as a section otherwise.
+func (s *state) renderMediaSingleBlock(node converter.Node) (block, error) {
+	var media *converter.Node
+	for i, child := range node.Content {
+		if child.Type == "media" || child.Type == "mediaInline" {
+			if media != nil {
+				media = nil
+				break
+			}
+			media = &node.Content[i]
+		}
+	}
+	if media == nil {
+		text, err := s.renderMediaContainer(node)
+		return block{kind: blockSection, text: text}, err
+	}
+
+	url, name, err := s.resolveMedia(*media)
+	if err != nil {
+		return block{}, err
+	}
+	caption := ""
+	for _, child := range node.Content {
+		if child.Type == "caption" {
+			caption, err = s.renderInline(child.Content)
+			if err != nil {
+				return block{}, err
+			}
+		}
+	}
+
+	text := s.mediaLink(media.Type, url, name)
+	if caption != "" {
+		text += "\n" + caption
+	}
+	if url == "" {
+		return block{kind: blockSection, text: text}, nil
+	}
+	return block{
+		kind:     blockImage,
+		text:     text,
+		plain:    plainText(node.Content),
+		imageURL: url,
+		altText:  firstNonEmpty(name, "image"),
+	}, nil
+}
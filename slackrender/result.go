@@ -0,0 +1,19 @@
+package slackrender
+
+import (
+	"encoding/json"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// Result holds the output of a Slack render.
+type Result struct {
+	// Text is the mrkdwn message text; with FormatBlockKit it is the notification
+	// fallback to send alongside Blocks.
+	Text string `json:"text"`
+	// Blocks is the Block Kit blocks array; it is set only with FormatBlockKit.
+	Blocks   json.RawMessage     `json:"blocks,omitempty"`
+	Warnings []converter.Warning `json:"warnings,omitempty"`
+	// References lists links, media, mentions, inlineCards, emoji and extensions of the input.
+	References converter.References `json:"references,omitzero"`
+}
@@ -0,0 +1,250 @@
+// Package slackrender renders Jira ADF documents as Slack messages, either as mrkdwn
+// text or as Block Kit blocks, for posting issue descriptions and comments to Slack.
+//
+// Slack has no headings, tables or nested formatting, so headings become header blocks
+// (bold lines in mrkdwn), panels become context blocks (quotes in mrkdwn) and tables
+// become code blocks or a summary. Mentions map to Slack users through a mention hook,
+// and Slack's message and block limits are enforced with "truncated" warnings.
+package slackrender
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// Renderer renders ADF to Slack mrkdwn and Block Kit.
+type Renderer struct {
+	config Config
+}
+
+// state holds the per-render state, making the renderer thread-safe.
+type state struct {
+	config   Config
+	ctx      context.Context
+	options  converter.ConvertOptions
+	warnings []converter.Warning
+	hooks    converter.HookPolicy
+
+	listDepth int
+}
+
+// New creates a new Renderer with the given config.
+func New(config Config) (*Renderer, error) {
+	cfg := config.applyDefaults().clone()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Renderer{
+		config: cfg,
+	}, nil
+}
+
+// Render takes an ADF JSON document and returns a Slack message.
+func (r *Renderer) Render(input []byte) (Result, error) {
+	return r.RenderWithContext(context.Background(), input, converter.ConvertOptions{})
+}
+
+// RenderWithContext takes an ADF JSON document and returns a Slack message.
+func (r *Renderer) RenderWithContext(ctx context.Context, input []byte, opts converter.ConvertOptions) (Result, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+
+	var doc converter.Doc
+	if err := json.Unmarshal(input, &doc); err != nil {
+		return Result{}, fmt.Errorf("failed to parse ADF JSON: %w", err)
+	}
+
+	s := &state{
+		config:  r.config,
+		ctx:     ctx,
+		options: opts,
+	}
+	s.hooks = converter.HookPolicy{ResolutionMode: r.config.ResolutionMode, Warn: s.addWarning}
+
+	blocks, err := s.renderDoc(doc.Content)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := s.checkContext(); err != nil {
+		return Result{}, err
+	}
+
+	text := s.truncateText(joinMrkdwn(blocks))
+	var blockKit json.RawMessage
+	if s.config.Format == FormatBlockKit {
+		blockKit, err = s.blockKit(blocks)
+		if err != nil {
+			return Result{}, err
+		}
+	}
+
+	return Result{
+		Text:       text,
+		Blocks:     blockKit,
+		Warnings:   s.warnings,
+		References: converter.CollectReferences(doc),
+	}, nil
+}
+
+// renderNode renders a node as mrkdwn. Block nodes are rendered without a trailing
+// newline and joined by their parent.
+func (s *state) renderNode(node converter.Node) (string, error) {
+	if err := s.checkContext(); err != nil {
+		return "", err
+	}
+
+	switch node.Type {
+	case "doc", "layoutColumn":
+		return s.renderChildren(node.Content)
+
+	case "paragraph":
+		return s.renderInline(node.Content)
+
+	case "heading":
+		return s.renderHeading(node), nil
+
+	case "blockquote":
+		return s.renderBlockquote(node)
+
+	case "rule":
+		return ruleText, nil
+
+	case "hardBreak":
+		return "\n", nil
+
+	case "codeBlock":
+		return renderCodeBlock(node), nil
+
+	case "bulletList", "orderedList":
+		return s.renderList(node)
+
+	case "taskList":
+		return s.renderTaskList(node)
+
+	case "decisionList":
+		return s.renderDecisionList(node)
+
+	case "text":
+		return s.renderText(node)
+
+	case "emoji":
+		return s.renderEmoji(node)
+
+	case "mention":
+		return s.renderMention(node)
+
+	case "status":
+		return s.renderStatus(node), nil
+
+	case "date":
+		return s.renderDate(node)
+
+	case "inlineCard":
+		return s.renderInlineCard(node)
+
+	case "table":
+		return s.renderTable(node)
+
+	case "panel":
+		body, err := s.renderPanelBody(node)
+		if err != nil {
+			return "", err
+		}
+		return quote(body), nil
+
+	case "expand", "nestedExpand":
+		return s.renderExpand(node)
+
+	case "layoutSection":
+		return s.renderLayoutSection(node)
+
+	case "mediaSingle", "mediaGroup":
+		return s.renderMediaContainer(node)
+
+	case "media", "mediaInline":
+		return s.renderMedia(node)
+
+	case "placeholder":
+		return "", nil
+
+	case "extension", "inlineExtension", "bodiedExtension":
+		return s.renderExtension(node)
+
+	default:
+		switch s.config.UnknownNodes {
+		case converter.UnknownError:
+			return "", fmt.Errorf("unknown node type: %s", node.Type)
+		case converter.UnknownSkip:
+			s.addWarning(converter.WarningUnknownNode, node.Type, fmt.Sprintf("unknown node skipped: %s", node.Type))
+			return "", nil
+		default:
+			s.addWarning(converter.WarningUnknownNode, node.Type, fmt.Sprintf("unknown node rendered as placeholder: %s", node.Type))
+			return escape(fmt.Sprintf("[Unknown node: %s]", node.Type)), nil
+		}
+	}
+}
+
+// renderChildren renders block content, separating blocks with a blank line.
+func (s *state) renderChildren(content []converter.Node) (string, error) {
+	var parts []string
+	for _, child := range content {
+		rendered, err := s.renderNode(child)
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(rendered) != "" {
+			parts = append(parts, rendered)
+		}
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
+
+func (s *state) checkContext() error {
+	if s.ctx == nil {
+		return nil
+	}
+	return s.ctx.Err()
+}
+
+func (s *state) addWarning(warnType converter.WarningType, nodeType, message string) {
+	s.warnings = append(s.warnings, converter.Warning{
+		Type:     warnType,
+		NodeType: nodeType,
+		Message:  message,
+	})
+}
+
+// escape escapes the three characters Slack reserves for control sequences.
+func escape(text string) string {
+	return mrkdwnEscaper.Replace(text)
+}
+
+var mrkdwnEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// quote prefixes every line of content with "> ".
+func quote(content string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("> "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
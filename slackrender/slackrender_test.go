@@ -0,0 +1,221 @@
+package slackrender
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRenderer(t *testing.T, cfg Config) *Renderer {
+	t.Helper()
+	r, err := New(cfg)
+	require.NoError(t, err)
+	return r
+}
+
+func TestRenderMrkdwn(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"heading","attrs":{"level":1},"content":[{"type":"text","text":"Release "},{"type":"text","text":"notes","marks":[{"type":"em"}]}]},
+		{"type":"paragraph","content":[
+			{"type":"text","text":"Bold ","marks":[{"type":"strong"}]},
+			{"type":"text","text":"a < b & c, "},
+			{"type":"text","text":"docs","marks":[{"type":"link","attrs":{"href":"https://example.com/a|b"}}]},
+			{"type":"text","text":", "},
+			{"type":"text","text":"x","marks":[{"type":"code"}]},
+			{"type":"text","text":" "},
+			{"type":"status","attrs":{"text":"DONE","color":"green"}},
+			{"type":"text","text":" "},
+			{"type":"emoji","attrs":{"shortName":":tada:","text":"🎉"}},
+			{"type":"text","text":" "},
+			{"type":"date","attrs":{"timestamp":"1700000000000"}}
+		]},
+		{"type":"bulletList","content":[
+			{"type":"listItem","content":[
+				{"type":"paragraph","content":[{"type":"text","text":"one"}]},
+				{"type":"orderedList","attrs":{"order":3},"content":[
+					{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"nested"}]}]}
+				]}
+			]},
+			{"type":"listItem","content":[
+				{"type":"paragraph","content":[{"type":"text","text":"two"}]},
+				{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"x := 1"}]}
+			]}
+		]},
+		{"type":"taskList","content":[
+			{"type":"taskItem","attrs":{"state":"DONE"},"content":[{"type":"text","text":"shipped"}]},
+			{"type":"taskItem","attrs":{"state":"TODO"},"content":[{"type":"text","text":"follow up"}]}
+		]},
+		{"type":"blockquote","content":[{"type":"paragraph","content":[{"type":"text","text":"quoted"}]}]},
+		{"type":"rule"}
+	]}`)
+
+	result, err := newTestRenderer(t, Config{}).Render(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "*Release notes*\n\n"+
+		"*Bold* a &lt; b &amp; c, <https://example.com/a%7Cb|docs>, `x` `DONE` :tada: <!date^1700000000^{date_short}|2023-11-14>\n\n"+
+		"• one\n    3. nested\n• two\n  ```\n  x := 1\n  ```\n\n"+
+		"☑ shipped\n☐ follow up\n\n"+
+		"> quoted\n\n"+
+		"———", result.Text)
+	assert.Empty(t, result.Blocks)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestRenderPanelsAndTables(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"panel","attrs":{"panelType":"warning","title":"Careful"},"content":[
+			{"type":"paragraph","content":[{"type":"text","text":"Heads up"}]}
+		]},
+		{"type":"panel","attrs":{"panelType":"info"},"content":[
+			{"type":"paragraph","content":[{"type":"text","text":"FYI"}]}
+		]},
+		{"type":"expand","attrs":{"title":"Details"},"content":[{"type":"paragraph","content":[{"type":"text","text":"hidden"}]}]},
+		{"type":"table","content":[
+			{"type":"tableRow","content":[
+				{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"Key"}]}]},
+				{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"Value"}]}]}
+			]},
+			{"type":"tableRow","content":[
+				{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"env"}]}]},
+				{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"prod & <staging>","marks":[{"type":"strong"}]}]}]}
+			]}
+		]}
+	]}`)
+
+	result, err := newTestRenderer(t, Config{}).Render(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "> :warning: *Careful*\n> Heads up\n\n"+
+		"> :information_source: FYI\n\n"+
+		"*Details*\nhidden\n\n"+
+		"```\nKey | Value\n----+-----------------\nenv | prod &amp; &lt;staging&gt;\n```", result.Text)
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, converter.WarningDroppedFeature, result.Warnings[0].Type)
+	assert.Equal(t, "expand", result.Warnings[0].NodeType)
+
+	summary, err := newTestRenderer(t, Config{TableStyle: TableSummary}).Render(input)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(summary.Text, "_Table: 2 rows, 2 columns (Key, Value)_"), summary.Text)
+	require.Len(t, summary.Warnings, 2)
+	assert.Equal(t, "table", summary.Warnings[1].NodeType)
+}
+
+func TestRenderBlockKit(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Deploy "},{"type":"emoji","attrs":{"shortName":":rocket:"}}]},
+		{"type":"paragraph","content":[{"type":"text","text":"Shipped by "},{"type":"mention","attrs":{"id":"acc-1","text":"Ada"}}]},
+		{"type":"panel","attrs":{"panelType":"success"},"content":[{"type":"paragraph","content":[{"type":"text","text":"All green"}]}]},
+		{"type":"rule"},
+		{"type":"mediaSingle","content":[
+			{"type":"media","attrs":{"type":"file","id":"m1","alt":"graph.png"}},
+			{"type":"caption","content":[{"type":"text","text":"Latency"}]}
+		]}
+	]}`)
+
+	renderer := newTestRenderer(t, Config{
+		Format: FormatBlockKit,
+		MentionHook: func(_ context.Context, in MentionRenderInput) (MentionRenderOutput, error) {
+			assert.Equal(t, "acc-1", in.ID)
+			assert.Equal(t, "@Ada", in.Text)
+			return MentionRenderOutput{UserID: "U123", Handled: true}, nil
+		},
+		MediaHook: func(_ context.Context, in converter.MediaRenderInput) (MediaRenderOutput, error) {
+			return MediaRenderOutput{URL: "https://cdn.example.com/" + in.ID + ".png", Handled: true}, nil
+		},
+	})
+
+	result, err := renderer.Render(input)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `[
+		{"type":"header","text":{"type":"plain_text","text":"Deploy :rocket:","emoji":true}},
+		{"type":"section","text":{"type":"mrkdwn","text":"Shipped by <@U123>"}},
+		{"type":"context","elements":[{"type":"mrkdwn","text":":white_check_mark: All green"}]},
+		{"type":"divider"},
+		{"type":"image","image_url":"https://cdn.example.com/m1.png","alt_text":"graph.png","title":{"type":"plain_text","text":"Latency","emoji":true}}
+	]`, string(result.Blocks))
+	assert.Equal(t, "*Deploy :rocket:*\n\nShipped by <@U123>\n\n> :white_check_mark: All green\n\n———\n\n<https://cdn.example.com/m1.png|graph.png>\nLatency", result.Text)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestRenderMentionFallbacks(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[{"type":"paragraph","content":[
+		{"type":"mention","attrs":{"id":"acc-1","text":"@Ada"}},
+		{"type":"text","text":" "},
+		{"type":"mention","attrs":{"id":"acc-2","text":"Grace"}}
+	]}]}`)
+
+	renderer := newTestRenderer(t, Config{
+		MentionHook: func(_ context.Context, in MentionRenderInput) (MentionRenderOutput, error) {
+			if in.ID == "acc-2" {
+				return MentionRenderOutput{}, converter.ErrUnresolved
+			}
+			return MentionRenderOutput{}, nil
+		},
+	})
+	result, err := renderer.Render(input)
+	require.NoError(t, err)
+	assert.Equal(t, "@Ada @Grace", result.Text)
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, converter.WarningUnresolvedReference, result.Warnings[0].Type)
+
+	strict := newTestRenderer(t, Config{
+		ResolutionMode: converter.ResolutionStrict,
+		MentionHook: func(_ context.Context, in MentionRenderInput) (MentionRenderOutput, error) {
+			return MentionRenderOutput{}, converter.ErrUnresolved
+		},
+	})
+	_, err = strict.Render(input)
+	require.ErrorIs(t, err, converter.ErrUnresolved)
+}
+
+func TestRenderEnforcesSlackLimits(t *testing.T) {
+	long := strings.Repeat("word ", 700)
+	input := []byte(`{"type":"doc","content":[
+		{"type":"heading","attrs":{"level":1},"content":[{"type":"text","text":"` + strings.Repeat("h", 200) + `"}]},
+		{"type":"codeBlock","content":[{"type":"text","text":"` + strings.Repeat(long+`\n`, 2) + `"}]},
+		{"type":"paragraph","content":[{"type":"text","text":"tail"}]}
+	]}`)
+
+	result, err := newTestRenderer(t, Config{Format: FormatBlockKit, MaxTextLength: 1000, MaxBlocks: 3}).Render(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1000, len([]rune(result.Text)))
+	assert.True(t, strings.HasSuffix(result.Text, "…\n```"), result.Text[len(result.Text)-20:])
+
+	var types []converter.WarningType
+	for _, warning := range result.Warnings {
+		types = append(types, warning.Type)
+	}
+	assert.Equal(t, []converter.WarningType{converter.WarningTruncated, converter.WarningTruncated, converter.WarningTruncated}, types)
+
+	blocks := string(result.Blocks)
+	assert.Contains(t, blocks, `"text":"`+strings.Repeat("h", 149)+`…"`)
+	assert.Equal(t, 2, strings.Count(blocks, `"type":"section"`), "the split code block fills the remaining two blocks")
+	assert.NotContains(t, blocks, "tail")
+	for _, chunk := range splitMrkdwn(renderCodeBlock(converter.Node{Type: "codeBlock", Content: []converter.Node{{Type: "text", Text: strings.Repeat(long+"\n", 2)}}}), maxBlockText) {
+		assert.LessOrEqual(t, len([]rune(chunk)), maxBlockText)
+		assert.True(t, strings.HasPrefix(chunk, "```\n") && strings.HasSuffix(chunk, "\n```"), chunk)
+	}
+}
+
+func TestRenderUnknownNodesAndConfigValidation(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[{"type":"mystery"}]}`)
+
+	result, err := newTestRenderer(t, Config{}).Render(input)
+	require.NoError(t, err)
+	assert.Equal(t, "[Unknown node: mystery]", result.Text)
+
+	_, err = newTestRenderer(t, Config{UnknownNodes: converter.UnknownError}).Render(input)
+	require.Error(t, err)
+
+	_, err = New(Config{Format: "html"})
+	require.Error(t, err)
+	_, err = New(Config{MaxTextLength: 10})
+	require.Error(t, err)
+}
@@ -0,0 +1,89 @@
+package slackrender
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// renderTable renders a table following Config.TableStyle. Cell formatting is dropped,
+// as neither a code block nor a summary can show it.
+func (s *state) renderTable(node converter.Node) (string, error) {
+	var rows [][]string
+	headerRow := false
+	spanned := false
+	columns := 0
+	for i, row := range node.Content {
+		if row.Type != "tableRow" {
+			continue
+		}
+		allHeaders := len(row.Content) > 0
+		cells := make([]string, 0, len(row.Content))
+		for _, cell := range row.Content {
+			if cell.Type != "tableHeader" {
+				allHeaders = false
+			}
+			if cell.GetIntAttr("colspan", 1) > 1 || cell.GetIntAttr("rowspan", 1) > 1 {
+				spanned = true
+			}
+			cells = append(cells, plainText(cell.Content))
+		}
+		if i == 0 {
+			headerRow = allHeaders
+		}
+		columns = max(columns, len(cells))
+		rows = append(rows, cells)
+	}
+	if len(rows) == 0 {
+		return "", nil
+	}
+
+	if s.config.TableStyle == TableSummary {
+		s.addWarning(converter.WarningDroppedFeature, node.Type, "table replaced by a summary")
+		summary := fmt.Sprintf("Table: %d rows, %d columns", len(rows), columns)
+		if headerRow {
+			summary += " (" + strings.Join(rows[0], ", ") + ")"
+		}
+		return "_" + escape(summary) + "_", nil
+	}
+
+	if spanned {
+		s.addWarning(converter.WarningDroppedFeature, node.Type, "merged table cells rendered as single cells")
+	}
+	return codeFence(formatColumns(rows, columns, headerRow)), nil
+}
+
+// formatColumns lays out rows as "|"-separated columns padded to a common width, with a
+// dashed line under a header row.
+func formatColumns(rows [][]string, columns int, headerRow bool) string {
+	widths := make([]int, columns)
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	var lines []string
+	for i, row := range rows {
+		cells := make([]string, columns)
+		for j := range cells {
+			cell := ""
+			if j < len(row) {
+				cell = row[j]
+			}
+			cells[j] = cell + strings.Repeat(" ", widths[j]-utf8.RuneCountInString(cell))
+		}
+		lines = append(lines, strings.TrimRight(strings.Join(cells, " | "), " "))
+
+		if i == 0 && headerRow && len(rows) > 1 {
+			rules := make([]string, columns)
+			for j, width := range widths {
+				rules[j] = strings.Repeat("-", width)
+			}
+			lines = append(lines, strings.Join(rules, "-+-"))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
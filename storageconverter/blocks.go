@@ -0,0 +1,446 @@
+package storageconverter
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// layoutWidths maps Confluence layout section types to ADF column widths.
+var layoutWidths = map[string][]float64{
+	"two_equal":           {50, 50},
+	"two_left_sidebar":    {33.33, 66.66},
+	"two_right_sidebar":   {66.66, 33.33},
+	"three_equal":         {33.33, 33.33, 33.33},
+	"three_with_sidebars": {25, 50, 25},
+}
+
+// isBlockElement reports whether a storage element starts a block. Block elements found
+// inside inline content split the surrounding paragraph.
+func isBlockElement(e *element) bool {
+	switch e.name {
+	case "p", "h1", "h2", "h3", "h4", "h5", "h6", "blockquote", "pre", "hr", "ul", "ol", "table",
+		"div", "section", "ac:task-list", "ac:layout", "ac:layout-section", "ac:layout-cell":
+		return true
+	case "ac:structured-macro", "ac:macro":
+		return !isInlineMacro(e.attrs["ac:name"])
+	default:
+		return false
+	}
+}
+
+// parseBlocks parses a sequence of storage elements into ADF blocks. Runs of inline
+// content between blocks become paragraphs.
+func (s *state) parseBlocks(elements []*element) ([]converter.Node, error) {
+	var blocks []converter.Node
+	var run []*element
+
+	flush := func() error {
+		if len(run) == 0 {
+			return nil
+		}
+		paragraphs, err := s.parseParagraph(run)
+		run = nil
+		if err != nil {
+			return err
+		}
+		blocks = append(blocks, paragraphs...)
+		return nil
+	}
+
+	for _, e := range elements {
+		if err := s.checkContext(); err != nil {
+			return nil, err
+		}
+		if e.name == "" || !isBlockElement(e) {
+			run = append(run, e)
+			continue
+		}
+		if err := flush(); err != nil {
+			return nil, err
+		}
+		nodes, err := s.parseBlock(e)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, nodes...)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+func (s *state) parseBlock(e *element) ([]converter.Node, error) {
+	switch e.name {
+	case "p":
+		return s.parseParagraph(e.children)
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level := int(e.name[1] - '0')
+		return s.splitInline(e.children, func(content []converter.Node) converter.Node {
+			return converter.Node{
+				Type:    "heading",
+				Attrs:   map[string]interface{}{"level": level},
+				Content: content,
+			}
+		}, true)
+	case "blockquote":
+		content, err := s.parseBlocks(e.children)
+		if err != nil {
+			return nil, err
+		}
+		return []converter.Node{{Type: "blockquote", Content: ensureBlocks(content)}}, nil
+	case "pre":
+		return []converter.Node{codeBlock("", strings.TrimSuffix(e.textContent(), "\n"))}, nil
+	case "hr":
+		return []converter.Node{{Type: "rule"}}, nil
+	case "ul", "ol":
+		list, err := s.parseList(e)
+		if err != nil {
+			return nil, err
+		}
+		return []converter.Node{list}, nil
+	case "table":
+		table, err := s.parseTable(e)
+		if err != nil {
+			return nil, err
+		}
+		return []converter.Node{table}, nil
+	case "div", "section", "ac:layout":
+		return s.parseBlocks(e.children)
+	case "ac:layout-section":
+		return s.parseLayoutSection(e)
+	case "ac:layout-cell":
+		return s.parseBlocks(e.children)
+	case "ac:task-list":
+		taskList, err := s.parseTaskList(e)
+		if err != nil || taskList.Type == "" {
+			return nil, err
+		}
+		return []converter.Node{taskList}, nil
+	case "ac:structured-macro", "ac:macro":
+		return s.parseMacro(e, false)
+	default:
+		return s.parseParagraph([]*element{e})
+	}
+}
+
+// parseParagraph parses inline content into paragraphs, split around block nodes such
+// as images and macros. A paragraph holding nothing but a macro becomes a block
+// extension, which is how the Confluence editor stores block macros.
+func (s *state) parseParagraph(elements []*element) ([]converter.Node, error) {
+	nodes, err := s.splitInline(elements, func(content []converter.Node) converter.Node {
+		return converter.Node{Type: "paragraph", Content: content}
+	}, false)
+	if err != nil {
+		return nil, err
+	}
+	for i, node := range nodes {
+		if node.Type == "paragraph" && len(node.Content) == 1 && node.Content[0].Type == "inlineExtension" {
+			nodes[i] = node.Content[0]
+			nodes[i].Type = "extension"
+		}
+	}
+	return nodes, nil
+}
+
+// splitInline parses inline content and wraps each run of inline nodes with wrap. Block
+// nodes produced by the inline content are returned between the wrapped runs. When
+// keepEmpty is set an element without inline content still produces one wrapped node.
+func (s *state) splitInline(elements []*element, wrap func([]converter.Node) converter.Node, keepEmpty bool) ([]converter.Node, error) {
+	content, err := s.parseInline(elements, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []converter.Node
+	var segment []converter.Node
+	wrapped := false
+	flush := func() {
+		if trimmed := trimInline(segment); len(trimmed) > 0 {
+			nodes = append(nodes, wrap(trimmed))
+			wrapped = true
+		}
+		segment = nil
+	}
+	for _, node := range content {
+		if isInlineNode(node) {
+			segment = append(segment, node)
+			continue
+		}
+		flush()
+		nodes = append(nodes, node)
+	}
+	flush()
+
+	if keepEmpty && !wrapped {
+		nodes = append([]converter.Node{wrap(nil)}, nodes...)
+	}
+	return nodes, nil
+}
+
+func isInlineNode(node converter.Node) bool {
+	switch node.Type {
+	case "text", "hardBreak", "mention", "emoji", "date", "status", "inlineCard", "inlineExtension", "placeholder":
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *state) parseList(e *element) (converter.Node, error) {
+	list := converter.Node{Type: "bulletList"}
+	if e.name == "ol" {
+		list.Type = "orderedList"
+		if start, err := strconv.Atoi(strings.TrimSpace(e.attrs["start"])); err == nil && start != 1 {
+			list.Attrs = map[string]interface{}{"order": start}
+		}
+	}
+
+	for _, child := range e.children {
+		if child.name != "li" {
+			continue
+		}
+		content, err := s.parseBlocks(child.children)
+		if err != nil {
+			return converter.Node{}, err
+		}
+		list.Content = append(list.Content, converter.Node{
+			Type:    "listItem",
+			Content: ensureBlocks(content),
+		})
+	}
+	if len(list.Content) == 0 {
+		list.Content = []converter.Node{{Type: "listItem", Content: ensureBlocks(nil)}}
+	}
+	return list, nil
+}
+
+func (s *state) parseTable(e *element) (converter.Node, error) {
+	table := converter.Node{Type: "table"}
+
+	var rows []*element
+	for _, child := range e.children {
+		switch child.name {
+		case "tr":
+			rows = append(rows, child)
+		case "thead", "tbody", "tfoot":
+			for _, row := range child.children {
+				if row.name == "tr" {
+					rows = append(rows, row)
+				}
+			}
+		}
+	}
+
+	for _, row := range rows {
+		tableRow := converter.Node{Type: "tableRow"}
+		for _, cell := range row.children {
+			if cell.name != "th" && cell.name != "td" {
+				continue
+			}
+			content, err := s.parseBlocks(cell.children)
+			if err != nil {
+				return converter.Node{}, err
+			}
+			tableCell := converter.Node{
+				Type:    "tableCell",
+				Content: ensureBlocks(content),
+			}
+			if cell.name == "th" {
+				tableCell.Type = "tableHeader"
+			}
+			for _, span := range []string{"colspan", "rowspan"} {
+				if value, err := strconv.Atoi(strings.TrimSpace(cell.attrs[span])); err == nil && value > 1 {
+					if tableCell.Attrs == nil {
+						tableCell.Attrs = map[string]interface{}{}
+					}
+					tableCell.Attrs[span] = value
+				}
+			}
+			tableRow.Content = append(tableRow.Content, tableCell)
+		}
+		if len(tableRow.Content) > 0 {
+			table.Content = append(table.Content, tableRow)
+		}
+	}
+	return table, nil
+}
+
+// parseLayoutSection builds a layoutSection from an ac:layout-section. Single-column
+// sections have no ADF equivalent and are flattened into their content.
+func (s *state) parseLayoutSection(e *element) ([]converter.Node, error) {
+	var cells []*element
+	for _, child := range e.children {
+		if child.name == "ac:layout-cell" {
+			cells = append(cells, child)
+		}
+	}
+
+	if len(cells) < 2 {
+		return s.parseBlocks(e.children)
+	}
+
+	widths := layoutWidths[strings.ToLower(e.attrs["ac:type"])]
+	if len(widths) != len(cells) {
+		width := math.Floor(10000/float64(len(cells))) / 100
+		widths = make([]float64, len(cells))
+		for i := range widths {
+			widths[i] = width
+		}
+	}
+
+	section := converter.Node{Type: "layoutSection"}
+	for i, cell := range cells {
+		content, err := s.parseBlocks(cell.children)
+		if err != nil {
+			return nil, err
+		}
+		section.Content = append(section.Content, converter.Node{
+			Type:    "layoutColumn",
+			Attrs:   map[string]interface{}{"width": widths[i]},
+			Content: ensureBlocks(content),
+		})
+	}
+	return []converter.Node{section}, nil
+}
+
+// parseTaskList builds a taskList from an ac:task-list. Task lists nested in a task body
+// follow the task they belong to.
+func (s *state) parseTaskList(e *element) (converter.Node, error) {
+	taskList := converter.Node{Type: "taskList"}
+
+	for _, task := range e.children {
+		switch task.name {
+		case "ac:task-list":
+			nested, err := s.parseTaskList(task)
+			if err != nil {
+				return converter.Node{}, err
+			}
+			if nested.Type != "" {
+				taskList.Content = append(taskList.Content, nested)
+			}
+			continue
+		case "ac:task":
+		default:
+			continue
+		}
+
+		taskItem := converter.Node{
+			Type: "taskItem",
+			Attrs: map[string]interface{}{
+				"state": "TODO",
+			},
+		}
+		if id := strings.TrimSpace(task.child("ac:task-id").textContentOrEmpty()); id != "" {
+			taskItem.Attrs["localId"] = id
+		}
+		if strings.EqualFold(strings.TrimSpace(task.child("ac:task-status").textContentOrEmpty()), "complete") {
+			taskItem.Attrs["state"] = "DONE"
+		}
+
+		var nested []converter.Node
+		if body := task.child("ac:task-body"); body != nil {
+			content, err := s.parseInline(body.children, nil)
+			if err != nil {
+				return converter.Node{}, err
+			}
+			var inline []converter.Node
+			for _, node := range content {
+				switch {
+				case isInlineNode(node):
+					inline = appendInlineNode(inline, node)
+				case node.Type == "taskList":
+					nested = append(nested, node)
+				default:
+					s.addWarning(converter.WarningDroppedFeature, node.Type, fmt.Sprintf("%s inside a task dropped", node.Type))
+				}
+			}
+			taskItem.Content = trimInline(inline)
+		}
+		taskList.Content = append(taskList.Content, taskItem)
+		taskList.Content = append(taskList.Content, nested...)
+	}
+
+	if len(taskList.Content) == 0 {
+		return converter.Node{}, nil
+	}
+	return taskList, nil
+}
+
+// textContentOrEmpty returns the text content of e, or "" for a missing element.
+func (e *element) textContentOrEmpty() string {
+	if e == nil {
+		return ""
+	}
+	return e.textContent()
+}
+
+func codeBlock(language, text string) converter.Node {
+	node := converter.Node{Type: "codeBlock"}
+	if language != "" && language != "none" {
+		node.Attrs = map[string]interface{}{
+			"language": language,
+		}
+	}
+	if text != "" {
+		node.Content = []converter.Node{
+			{
+				Type: "text",
+				Text: text,
+			},
+		}
+	}
+	return node
+}
+
+// ensureBlocks returns content, or an empty paragraph for containers that require at
+// least one block.
+func ensureBlocks(content []converter.Node) []converter.Node {
+	if len(content) == 0 {
+		return []converter.Node{{Type: "paragraph"}}
+	}
+	return content
+}
+
+// trimInline removes leading and trailing line breaks and whitespace from inline
+// content, and whitespace around line breaks.
+func trimInline(content []converter.Node) []converter.Node {
+	for len(content) > 0 && content[0].Type == "hardBreak" {
+		content = content[1:]
+	}
+	for len(content) > 0 && content[len(content)-1].Type == "hardBreak" {
+		content = content[:len(content)-1]
+	}
+	if len(content) == 0 {
+		return nil
+	}
+
+	trimmed := append([]converter.Node(nil), content...)
+	for i := range trimmed {
+		if trimmed[i].Type != "text" {
+			continue
+		}
+		if i == 0 || trimmed[i-1].Type == "hardBreak" {
+			trimmed[i].Text = strings.TrimLeft(trimmed[i].Text, " ")
+		}
+		if i == len(trimmed)-1 || trimmed[i+1].Type == "hardBreak" {
+			trimmed[i].Text = strings.TrimRight(trimmed[i].Text, " ")
+		}
+	}
+
+	result := trimmed[:0]
+	for _, node := range trimmed {
+		if node.Type == "text" && node.Text == "" {
+			continue
+		}
+		result = append(result, node)
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package storageconverter
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/rgonek/jira-adf-converter/mdconverter"
+)
+
+// Config configures Confluence storage format to ADF conversion behavior.
+type Config struct {
+	// BaseURL is the Confluence base URL, e.g. "https://wiki.example.com". When set,
+	// page links that no hook resolved link to /display/SPACE/Title below it; otherwise
+	// they keep a relative link with the page in its attrs.
+	BaseURL     string            `json:"baseURL,omitempty"`
+	LanguageMap map[string]string `json:"languageMap,omitempty"`
+	// MentionRegistry maps Confluence Server user keys and user names to account IDs.
+	MentionRegistry map[string]string          `json:"mentionRegistry,omitempty"`
+	ResolutionMode  converter.ResolutionMode   `json:"resolutionMode,omitempty"`
+	LinkHook        mdconverter.LinkParseHook  `json:"-"`
+	MediaHook       mdconverter.MediaParseHook `json:"-"`
+}
+
+func (c Config) applyDefaults() Config {
+	c.BaseURL = strings.TrimRight(strings.TrimSpace(c.BaseURL), "/")
+	if c.ResolutionMode == "" {
+		c.ResolutionMode = converter.ResolutionBestEffort
+	}
+	return c
+}
+
+// clone returns a deep copy of Config for map-backed fields.
+func (c Config) clone() Config {
+	cloned := c
+	cloned.LanguageMap = cloneStringMap(c.LanguageMap)
+	cloned.MentionRegistry = cloneStringMap(c.MentionRegistry)
+	return cloned
+}
+
+// Validate checks that config values are valid.
+func (c Config) Validate() error {
+	if c.BaseURL != "" {
+		parsed, err := url.Parse(c.BaseURL)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return fmt.Errorf("invalid baseURL %q", c.BaseURL)
+		}
+	}
+	if c.ResolutionMode != converter.ResolutionBestEffort && c.ResolutionMode != converter.ResolutionStrict {
+		return fmt.Errorf("invalid resolutionMode %q", c.ResolutionMode)
+	}
+	for name, id := range c.MentionRegistry {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("mentionRegistry contains empty key")
+		}
+		if strings.TrimSpace(id) == "" {
+			return fmt.Errorf("mentionRegistry[%q] is empty", name)
+		}
+	}
+	return nil
+}
+
+func cloneStringMap(src map[string]string) map[string]string {
+	if src == nil {
+		return nil
+	}
+	cloned := make(map[string]string, len(src))
+	for key, value := range src {
+		cloned[key] = value
+	}
+	return cloned
+}
@@ -0,0 +1,91 @@
+package storageconverter
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func goldenConfigForPath(path string) Config {
+	cfg := Config{
+		MentionRegistry: map[string]string{
+			"jsmith": "12345",
+		},
+	}
+
+	base := filepath.Base(path)
+	if strings.Contains(base, "language_map") {
+		cfg.LanguageMap = map[string]string{
+			"c#": "csharp",
+		}
+	}
+	if strings.Contains(base, "baseurl") {
+		cfg.BaseURL = "https://wiki.example.com"
+	}
+
+	return cfg
+}
+
+func TestGoldenFiles(t *testing.T) {
+	fixtures := []string{
+		"blocks/headings",
+		"blocks/paragraphs",
+		"blocks/layout",
+		"codeblocks/code_macro",
+		"codeblocks/language_map",
+		"lists/lists",
+		"lists/tasks",
+		"tables/table",
+		"marks/formatting",
+		"marks/colors",
+		"links/links",
+		"links/pages_baseurl",
+		"links/users",
+		"macros/panels",
+		"macros/expand",
+		"macros/status",
+		"macros/extensions",
+		"media/images",
+		"inline/emoticons",
+		"inline/dates",
+	}
+
+	for _, fixture := range fixtures {
+		fixture := fixture
+		t.Run(fixture, func(t *testing.T) {
+			storagePath := filepath.Join("testdata", filepath.FromSlash(fixture+".xhtml"))
+			jsonPath := filepath.Join("testdata", filepath.FromSlash(fixture+".json"))
+
+			storage, err := os.ReadFile(storagePath)
+			require.NoError(t, err)
+
+			conv, err := New(goldenConfigForPath(storagePath))
+			require.NoError(t, err)
+			result, err := conv.Convert(string(storage))
+			require.NoError(t, err)
+
+			if *update {
+				formatted, err := json.MarshalIndent(result.Doc, "", "  ")
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(jsonPath, append(formatted, '\n'), 0644))
+			}
+
+			expectedJSON, err := os.ReadFile(jsonPath)
+			require.NoError(t, err)
+
+			var actualDoc, expectedDoc converter.Doc
+			require.NoError(t, json.Unmarshal(result.ADF, &actualDoc))
+			require.NoError(t, json.Unmarshal(expectedJSON, &expectedDoc))
+			assert.Equal(t, expectedDoc, actualDoc)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package storageconverter
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/rgonek/jira-adf-converter/mdconverter"
+)
+
+// applyLinkHook calls Config.LinkHook and applies the same unresolved-reference policy
+// and output validation as Markdown import.
+func (s *state) applyLinkHook(input mdconverter.LinkParseInput) (mdconverter.LinkParseOutput, bool, error) {
+	if s.config.LinkHook == nil {
+		return mdconverter.LinkParseOutput{}, false, nil
+	}
+	if err := s.checkContext(); err != nil {
+		return mdconverter.LinkParseOutput{}, false, err
+	}
+
+	output, err := s.config.LinkHook(s.ctx, input)
+	if err != nil {
+		if errors.Is(err, converter.ErrUnresolved) {
+			if s.config.ResolutionMode == converter.ResolutionStrict {
+				return mdconverter.LinkParseOutput{}, false, fmt.Errorf("unresolved link destination %q: %w", input.Destination, err)
+			}
+			s.addWarning(
+				converter.WarningUnresolvedReference,
+				"link",
+				fmt.Sprintf("unresolved link destination %q; using fallback parsing", input.Destination),
+			)
+			return mdconverter.LinkParseOutput{}, false, nil
+		}
+		return mdconverter.LinkParseOutput{}, false, fmt.Errorf("link hook failed: %w", err)
+	}
+
+	if !output.Handled {
+		return mdconverter.LinkParseOutput{}, false, nil
+	}
+
+	output.Destination = strings.TrimSpace(output.Destination)
+	output.Title = strings.TrimSpace(output.Title)
+	if output.ForceLink && output.ForceCard {
+		return mdconverter.LinkParseOutput{}, false, errors.New("invalid link hook output: link parse hook output cannot set both forceLink and forceCard")
+	}
+	if output.Destination == "" {
+		return mdconverter.LinkParseOutput{}, false, errors.New("invalid link hook output: handled link parse output requires non-empty destination")
+	}
+	return output, true, nil
+}
+
+// applyMediaHook calls Config.MediaHook and applies the same unresolved-reference policy
+// and output validation as Markdown import.
+func (s *state) applyMediaHook(input mdconverter.MediaParseInput) (mdconverter.MediaParseOutput, bool, error) {
+	if s.config.MediaHook == nil {
+		return mdconverter.MediaParseOutput{}, false, nil
+	}
+	if err := s.checkContext(); err != nil {
+		return mdconverter.MediaParseOutput{}, false, err
+	}
+
+	output, err := s.config.MediaHook(s.ctx, input)
+	if err != nil {
+		if errors.Is(err, converter.ErrUnresolved) {
+			if s.config.ResolutionMode == converter.ResolutionStrict {
+				return mdconverter.MediaParseOutput{}, false, fmt.Errorf("unresolved media destination %q: %w", input.Destination, err)
+			}
+			s.addWarning(
+				converter.WarningUnresolvedReference,
+				"media",
+				fmt.Sprintf("unresolved media destination %q; using fallback parsing", input.Destination),
+			)
+			return mdconverter.MediaParseOutput{}, false, nil
+		}
+		return mdconverter.MediaParseOutput{}, false, fmt.Errorf("media hook failed: %w", err)
+	}
+
+	if !output.Handled {
+		return mdconverter.MediaParseOutput{}, false, nil
+	}
+
+	output.MediaType = strings.ToLower(strings.TrimSpace(output.MediaType))
+	output.ID = strings.TrimSpace(output.ID)
+	output.URL = strings.TrimSpace(output.URL)
+	output.Alt = strings.TrimSpace(output.Alt)
+	if output.MediaType != "image" && output.MediaType != "file" {
+		return mdconverter.MediaParseOutput{}, false, fmt.Errorf("invalid media hook output: handled media parse output requires supported mediaType, got %q", output.MediaType)
+	}
+	if (output.ID == "") == (output.URL == "") {
+		return mdconverter.MediaParseOutput{}, false, errors.New("invalid media hook output: handled media parse output requires exactly one of id or url")
+	}
+	return output, true, nil
+}
+
+// resolveMention returns the account ID for a Confluence Server user key or user name.
+// Unregistered users keep the name as ID with a warning, or fail in strict mode.
+func (s *state) resolveMention(name string) (string, error) {
+	if id, ok := s.config.MentionRegistry[name]; ok {
+		return id, nil
+	}
+	if s.config.ResolutionMode == converter.ResolutionStrict {
+		return "", fmt.Errorf("unresolved mention %q: %w", name, converter.ErrUnresolved)
+	}
+	s.addWarning(
+		converter.WarningUnresolvedReference,
+		"mention",
+		fmt.Sprintf("mention %q not found in mentionRegistry; using it as id", name),
+	)
+	return name, nil
+}
+
+// referenceMetadata returns the file name and anchor of a link or media destination.
+func referenceMetadata(destination string) (string, string) {
+	destination = strings.TrimSpace(destination)
+	if destination == "" {
+		return "", ""
+	}
+
+	referencePath, anchor := destination, ""
+	if parsed, err := url.Parse(destination); err == nil {
+		anchor = parsed.Fragment
+		if parsed.Path != "" {
+			referencePath = parsed.Path
+		}
+	} else if hashIndex := strings.LastIndex(destination, "#"); hashIndex >= 0 {
+		referencePath, anchor = destination[:hashIndex], destination[hashIndex+1:]
+	}
+
+	referencePath = strings.TrimRight(strings.ReplaceAll(referencePath, "\\", "/"), "/")
+	if referencePath == "" || strings.HasPrefix(referencePath, "#") {
+		return "", strings.TrimSpace(anchor)
+	}
+	filename := path.Base(referencePath)
+	if filename == "." || filename == "/" {
+		filename = ""
+	}
+	return filename, strings.TrimSpace(anchor)
+}
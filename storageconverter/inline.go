@@ -0,0 +1,507 @@
+package storageconverter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/rgonek/jira-adf-converter/mdconverter"
+)
+
+var (
+	hexColorRe = regexp.MustCompile(`^#(?:[0-9a-f]{3}|[0-9a-f]{6})$`)
+	rgbColorRe = regexp.MustCompile(`^rgb\(\s*(\d{1,3})\s*,\s*(\d{1,3})\s*,\s*(\d{1,3})\s*\)$`)
+)
+
+// emoticonShortNames maps Confluence emoticon names to emoji short names.
+var emoticonShortNames = map[string]string{
+	"smile":        ":slight_smile:",
+	"sad":          ":disappointed:",
+	"cheeky":       ":stuck_out_tongue:",
+	"laugh":        ":smiley:",
+	"wink":         ":wink:",
+	"thumbs-up":    ":thumbsup:",
+	"thumbs-down":  ":thumbsdown:",
+	"information":  ":information_source:",
+	"tick":         ":white_check_mark:",
+	"cross":        ":x:",
+	"warning":      ":warning:",
+	"plus":         ":heavy_plus_sign:",
+	"minus":        ":heavy_minus_sign:",
+	"question":     ":question:",
+	"light-on":     ":bulb:",
+	"light-off":    ":bulb:",
+	"yellow-star":  ":star:",
+	"red-star":     ":star:",
+	"green-star":   ":star:",
+	"blue-star":    ":star:",
+	"heart":        ":heart:",
+	"broken-heart": ":broken_heart:",
+}
+
+// elementMarks maps formatting elements to ADF marks.
+var elementMarks = map[string]converter.Mark{
+	"strong": {Type: "strong"},
+	"b":      {Type: "strong"},
+	"em":     {Type: "em"},
+	"i":      {Type: "em"},
+	"u":      {Type: "underline"},
+	"s":      {Type: "strike"},
+	"del":    {Type: "strike"},
+	"strike": {Type: "strike"},
+	"code":   {Type: "code"},
+	"tt":     {Type: "code"},
+	"sub":    {Type: "subsup", Attrs: map[string]interface{}{"type": "sub"}},
+	"sup":    {Type: "subsup", Attrs: map[string]interface{}{"type": "sup"}},
+}
+
+// parseInline parses inline storage content with the given active marks. Whitespace is
+// collapsed as in HTML. Block elements and images are returned as block nodes for the
+// caller to split paragraphs around.
+func (s *state) parseInline(elements []*element, marks []converter.Mark) ([]converter.Node, error) {
+	var content []converter.Node
+
+	for _, e := range elements {
+		if e.name == "" {
+			content = appendInlineNode(content, newTextNode(collapseWhitespace(e.text), marks))
+			continue
+		}
+		if mark, ok := elementMarks[e.name]; ok {
+			nodes, err := s.parseInline(e.children, withMark(marks, mark))
+			if err != nil {
+				return nil, err
+			}
+			content = appendInlineNodes(content, nodes)
+			continue
+		}
+		if isBlockElement(e) && e.name != "ac:structured-macro" && e.name != "ac:macro" {
+			nodes, err := s.parseBlock(e)
+			if err != nil {
+				return nil, err
+			}
+			content = append(content, nodes...)
+			continue
+		}
+
+		var nodes []converter.Node
+		var err error
+		switch e.name {
+		case "br":
+			nodes = []converter.Node{{Type: "hardBreak"}}
+		case "span":
+			nodes, err = s.parseInline(e.children, s.styleMarks(e.attrs["style"], marks))
+		case "a":
+			nodes, err = s.parseAnchor(e, marks)
+		case "img":
+			var node converter.Node
+			node, err = s.parseImage(e, mdconverter.MediaParseInput{
+				SourcePath:  s.options.SourcePath,
+				Destination: strings.TrimSpace(e.attrs["src"]),
+				Alt:         strings.TrimSpace(e.attrs["alt"]),
+				Raw: map[string]any{
+					"kind": "url",
+				},
+			})
+			nodes = []converter.Node{node}
+		case "time":
+			nodes = []converter.Node{dateNode(e.attrs["datetime"], marks)}
+		case "ac:emoticon":
+			nodes = []converter.Node{emojiNode(e)}
+		case "ac:placeholder":
+			if text := strings.TrimSpace(collapseWhitespace(e.textContent())); text != "" {
+				nodes = []converter.Node{{Type: "placeholder", Attrs: map[string]interface{}{"text": text}}}
+			}
+		case "ac:link":
+			nodes, err = s.parseLink(e, marks)
+		case "ac:image":
+			var node converter.Node
+			node, err = s.parseImage(e, s.imageInput(e))
+			nodes = []converter.Node{node}
+		case "ac:structured-macro", "ac:macro":
+			nodes, err = s.parseMacro(e, true)
+		case "ac:inline-comment-marker":
+			nodes, err = s.parseInline(e.children, marks)
+		default:
+			if strings.HasPrefix(e.name, "ac:") || strings.HasPrefix(e.name, "ri:") {
+				s.addWarning(converter.WarningUnknownNode, e.name, fmt.Sprintf("unsupported storage element %q rendered as its content", e.name))
+			}
+			nodes, err = s.parseInline(e.children, marks)
+		}
+		if err != nil {
+			return nil, err
+		}
+		content = appendInlineNodes(content, nodes)
+	}
+	return content, nil
+}
+
+// styleMarks adds textColor and backgroundColor marks for a span style attribute.
+func (s *state) styleMarks(style string, marks []converter.Mark) []converter.Mark {
+	for _, declaration := range strings.Split(style, ";") {
+		property, value, ok := strings.Cut(declaration, ":")
+		if !ok {
+			continue
+		}
+		markType := ""
+		switch strings.ToLower(strings.TrimSpace(property)) {
+		case "color":
+			markType = "textColor"
+		case "background-color":
+			markType = "backgroundColor"
+		default:
+			continue
+		}
+		color, valid := normalizeColor(value)
+		if !valid {
+			s.addWarning(converter.WarningDroppedFeature, markType, fmt.Sprintf("invalid color value dropped: %q", strings.TrimSpace(value)))
+			continue
+		}
+		marks = withMark(marks, converter.Mark{
+			Type: markType,
+			Attrs: map[string]interface{}{
+				"color": color,
+			},
+		})
+	}
+	return marks
+}
+
+// parseAnchor parses an HTML link. Links marked with data-card-appearance become
+// inlineCards, as do links whose text is the destination.
+func (s *state) parseAnchor(e *element, marks []converter.Mark) ([]converter.Node, error) {
+	destination := strings.TrimSpace(e.attrs["href"])
+	title := strings.TrimSpace(e.attrs["title"])
+	if destination == "" {
+		return s.parseInline(e.children, marks)
+	}
+
+	filename, anchor := referenceMetadata(destination)
+	return s.linkNodes(linkReference{
+		input: mdconverter.LinkParseInput{
+			SourcePath:  s.options.SourcePath,
+			Destination: destination,
+			Title:       title,
+			Text:        strings.TrimSpace(collapseWhitespace(e.textContent())),
+			Meta:        mdconverter.LinkMetadata{Filename: filename, Anchor: anchor},
+			Raw: map[string]any{
+				"kind":  "link",
+				"title": title,
+			},
+		},
+		fallback:  destination,
+		body:      e.children,
+		forceCard: e.attrs["data-card-appearance"] != "",
+	}, marks)
+}
+
+// linkReference describes a link before hook resolution.
+type linkReference struct {
+	input mdconverter.LinkParseInput
+	// fallback is the destination used when no hook handles the link; an empty fallback
+	// makes an unhandled link unresolved.
+	fallback string
+	// pageAttrs, set for page links without an address, identify the page on the link
+	// mark of an unhandled link, whose fallback is then a relative reference.
+	pageAttrs map[string]interface{}
+	body      []*element
+	forceLink bool
+	forceCard bool
+}
+
+// linkNodes resolves a link through the link hook and builds a link mark over its body,
+// or an inlineCard following the same rules as Markdown import.
+func (s *state) linkNodes(ref linkReference, marks []converter.Mark) ([]converter.Node, error) {
+	destination, title := ref.fallback, ref.input.Title
+	forceLink, forceCard := ref.forceLink, ref.forceCard
+
+	hookOutput, handled, err := s.applyLinkHook(ref.input)
+	if err != nil {
+		return nil, err
+	}
+	if handled {
+		destination = hookOutput.Destination
+		title = hookOutput.Title
+		forceLink = hookOutput.ForceLink || (forceLink && !hookOutput.ForceCard)
+		forceCard = hookOutput.ForceCard
+	}
+
+	text := ref.input.Text
+	pageAttrs := ref.pageAttrs
+	if handled {
+		pageAttrs = nil
+	}
+	if destination == "" || pageAttrs != nil {
+		if s.config.ResolutionMode == converter.ResolutionStrict {
+			return nil, fmt.Errorf("unresolved link destination %q: %w", ref.input.Destination, converter.ErrUnresolved)
+		}
+		kept := "the link text"
+		if pageAttrs != nil {
+			kept = "a relative link with the page in its attrs"
+		}
+		s.addWarning(
+			converter.WarningUnresolvedReference,
+			"link",
+			fmt.Sprintf("unresolved link destination %q; keeping %s", ref.input.Destination, kept),
+		)
+	}
+	if destination == "" {
+		if len(ref.body) == 0 {
+			return []converter.Node{newTextNode(text, marks)}, nil
+		}
+		return s.parseInline(ref.body, marks)
+	}
+
+	if !forceLink && (forceCard || (title == "" && (text == "" || text == destination))) {
+		return []converter.Node{
+			{
+				Type: "inlineCard",
+				Attrs: map[string]interface{}{
+					"url": destination,
+				},
+			},
+		}, nil
+	}
+
+	mark := converter.Mark{
+		Type: "link",
+		Attrs: map[string]interface{}{
+			"href": destination,
+		},
+	}
+	if title != "" {
+		mark.Attrs["title"] = title
+	}
+	for key, value := range pageAttrs {
+		mark.Attrs[key] = value
+	}
+	if len(ref.body) == 0 {
+		return []converter.Node{newTextNode(firstNonEmpty(text, destination), withMark(marks, mark))}, nil
+	}
+	return s.parseInline(ref.body, withMark(marks, mark))
+}
+
+// imageInput describes the ri:attachment or ri:url resource of an ac:image.
+func (s *state) imageInput(e *element) mdconverter.MediaParseInput {
+	input := mdconverter.MediaParseInput{
+		SourcePath: s.options.SourcePath,
+		Alt:        strings.TrimSpace(e.attrs["ac:alt"]),
+		Raw: map[string]any{
+			"kind": "url",
+		},
+	}
+	if title := strings.TrimSpace(e.attrs["ac:title"]); title != "" {
+		input.Raw["title"] = title
+	}
+
+	if attachment := e.child("ri:attachment"); attachment != nil {
+		input.Destination = strings.TrimSpace(attachment.attrs["ri:filename"])
+		input.Meta.Filename = input.Destination
+		input.Raw["kind"] = "attachment"
+		if page := attachment.child("ri:page"); page != nil {
+			input.Meta.PageID = strings.TrimSpace(page.attrs["ri:content-id"])
+			input.Meta.SpaceKey = strings.TrimSpace(page.attrs["ri:space-key"])
+			input.Raw["pageTitle"] = strings.TrimSpace(page.attrs["ri:content-title"])
+		}
+	} else if resource := e.child("ri:url"); resource != nil {
+		input.Destination = strings.TrimSpace(resource.attrs["ri:value"])
+		input.Meta.Filename, input.Meta.Anchor = referenceMetadata(input.Destination)
+	}
+	return input
+}
+
+// parseImage resolves an image through the media hook and wraps it in a mediaSingle.
+// Unhandled URLs become url media and attachments become id media named by their file
+// name, which also defaults their alt text.
+func (s *state) parseImage(e *element, input mdconverter.MediaParseInput) (converter.Node, error) {
+	attrs := map[string]interface{}{
+		"type": "image",
+	}
+	alt := input.Alt
+
+	hookOutput, handled, err := s.applyMediaHook(input)
+	if err != nil {
+		return converter.Node{}, err
+	}
+	switch {
+	case handled:
+		attrs["type"] = hookOutput.MediaType
+		if hookOutput.ID != "" {
+			attrs["id"] = hookOutput.ID
+		}
+		if hookOutput.URL != "" {
+			attrs["url"] = hookOutput.URL
+		}
+		alt = firstNonEmpty(hookOutput.Alt, alt)
+	case input.Raw["kind"] == "attachment":
+		attrs["id"] = input.Destination
+	default:
+		attrs["url"] = input.Destination
+	}
+	if _, ok := attrs["id"]; ok {
+		alt = firstNonEmpty(alt, input.Meta.Filename)
+	}
+	if alt != "" {
+		attrs["alt"] = alt
+	}
+	for _, dimension := range []string{"width", "height"} {
+		value := firstNonEmpty(e.attrs["ac:"+dimension], e.attrs[dimension])
+		if size, err := strconv.Atoi(strings.TrimSuffix(value, "px")); err == nil && size > 0 {
+			attrs[dimension] = size
+		}
+	}
+
+	return converter.Node{
+		Type: "mediaSingle",
+		Content: []converter.Node{
+			{
+				Type:  "media",
+				Attrs: attrs,
+			},
+		},
+	}, nil
+}
+
+// dateNode builds a date node from a time element's datetime, or keeps the value as
+// text when it is not a date.
+func dateNode(value string, marks []converter.Mark) converter.Node {
+	value = strings.TrimSpace(value)
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return newTextNode(value, marks)
+	}
+	return converter.Node{
+		Type: "date",
+		Attrs: map[string]interface{}{
+			"timestamp": strconv.FormatInt(parsed.Unix(), 10),
+		},
+	}
+}
+
+func emojiNode(e *element) converter.Node {
+	shortName := strings.TrimSpace(e.attrs["ac:emoji-shortname"])
+	if shortName == "" {
+		name := strings.ToLower(strings.TrimSpace(e.attrs["ac:name"]))
+		shortName = emoticonShortNames[name]
+		if shortName == "" {
+			shortName = ":" + name + ":"
+		}
+	}
+	return converter.Node{
+		Type: "emoji",
+		Attrs: map[string]interface{}{
+			"shortName": shortName,
+		},
+	}
+}
+
+// normalizeColor returns a lower-case hex color for a CSS hex or rgb() value.
+func normalizeColor(value string) (string, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if hexColorRe.MatchString(value) {
+		return value, true
+	}
+	match := rgbColorRe.FindStringSubmatch(value)
+	if match == nil {
+		return "", false
+	}
+	hex := "#"
+	for _, component := range match[1:] {
+		channel, err := strconv.Atoi(component)
+		if err != nil || channel > 255 {
+			return "", false
+		}
+		hex += fmt.Sprintf("%02x", channel)
+	}
+	return hex, true
+}
+
+// collapseWhitespace replaces runs of HTML whitespace with a single space. Non-breaking
+// spaces are kept.
+func collapseWhitespace(text string) string {
+	var sb strings.Builder
+	space := false
+	for _, r := range text {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' {
+			if !space {
+				sb.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func withMark(marks []converter.Mark, mark converter.Mark) []converter.Mark {
+	for _, existing := range marks {
+		if existing.Type == mark.Type {
+			return marks
+		}
+	}
+	combined := make([]converter.Mark, 0, len(marks)+1)
+	combined = append(combined, marks...)
+	return append(combined, mark)
+}
+
+func newTextNode(text string, marks []converter.Mark) converter.Node {
+	node := converter.Node{
+		Type: "text",
+		Text: text,
+	}
+	if len(marks) > 0 {
+		node.Marks = marks
+	}
+	return node
+}
+
+func appendInlineNodes(content []converter.Node, nodes []converter.Node) []converter.Node {
+	for _, node := range nodes {
+		content = appendInlineNode(content, node)
+	}
+	return content
+}
+
+// appendInlineNode appends a node, merging adjacent text with equal marks and
+// collapsing whitespace across text boundaries.
+func appendInlineNode(content []converter.Node, next converter.Node) []converter.Node {
+	if next.Type == "text" && len(content) > 0 {
+		last := &content[len(content)-1]
+		if last.Type == "text" && strings.HasSuffix(last.Text, " ") {
+			next.Text = strings.TrimLeft(next.Text, " ")
+		}
+		if last.Type == "text" && marksEqual(last.Marks, next.Marks) {
+			last.Text += next.Text
+			return content
+		}
+	}
+	if next.Type == "text" && next.Text == "" {
+		return content
+	}
+	return append(content, next)
+}
+
+func marksEqual(a, b []converter.Mark) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Type != b[i].Type || fmt.Sprint(a[i].Attrs) != fmt.Sprint(b[i].Attrs) {
+			return false
+		}
+	}
+	return true
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
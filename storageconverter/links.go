@@ -0,0 +1,193 @@
+package storageconverter
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/rgonek/jira-adf-converter/mdconverter"
+)
+
+// parseLink parses an ac:link. User links become mentions; page, blog post, space,
+// attachment, anchor and URL links are resolved through the link hook, with the
+// Confluence identifiers in LinkParseInput.Meta and the resource kind in Raw["kind"].
+func (s *state) parseLink(e *element, marks []converter.Mark) ([]converter.Node, error) {
+	var body []*element
+	if linkBody := e.child("ac:link-body"); linkBody != nil {
+		body = linkBody.children
+	} else if plainBody := e.child("ac:plain-text-link-body"); plainBody != nil {
+		body = []*element{{text: plainBody.textContent()}}
+	}
+	bodyText := strings.TrimSpace(collapseWhitespace((&element{name: "body", children: body}).textContent()))
+
+	if user := e.child("ri:user"); user != nil {
+		return s.parseUserLink(user, bodyText)
+	}
+
+	anchor := strings.TrimSpace(e.attrs["ac:anchor"])
+	ref := linkReference{
+		input: mdconverter.LinkParseInput{
+			SourcePath: s.options.SourcePath,
+			Meta:       mdconverter.LinkMetadata{Anchor: anchor},
+			Raw:        map[string]any{},
+		},
+		body: body,
+	}
+
+	var text string
+	switch {
+	case e.child("ri:page") != nil || e.child("ri:blog-post") != nil:
+		resource := e.child("ri:page")
+		kind := "page"
+		if resource == nil {
+			resource = e.child("ri:blog-post")
+			kind = "blogpost"
+		}
+		title := strings.TrimSpace(resource.attrs["ri:content-title"])
+		ref.input.Destination = title
+		ref.input.Meta.PageID = strings.TrimSpace(resource.attrs["ri:content-id"])
+		ref.input.Meta.SpaceKey = strings.TrimSpace(resource.attrs["ri:space-key"])
+		ref.input.Raw["kind"] = kind
+		if day := strings.TrimSpace(resource.attrs["ri:posting-day"]); day != "" {
+			ref.input.Raw["postingDay"] = day
+		}
+		ref.fallback = s.pageURL(ref.input.Meta.PageID, ref.input.Meta.SpaceKey, title, resource.attrs["ri:posting-day"], anchor)
+		if ref.fallback == "" {
+			ref.fallback, ref.pageAttrs = pageReference(ref.input.Meta.PageID, ref.input.Meta.SpaceKey, title, resource.attrs["ri:posting-day"], anchor)
+		}
+		text = title
+	case e.child("ri:space") != nil:
+		spaceKey := strings.TrimSpace(e.child("ri:space").attrs["ri:space-key"])
+		ref.input.Destination = spaceKey
+		ref.input.Meta.SpaceKey = spaceKey
+		ref.input.Raw["kind"] = "space"
+		if s.config.BaseURL != "" && spaceKey != "" {
+			ref.fallback = s.config.BaseURL + "/display/" + url.PathEscape(spaceKey)
+		}
+		text = spaceKey
+	case e.child("ri:attachment") != nil:
+		attachment := e.child("ri:attachment")
+		filename := strings.TrimSpace(attachment.attrs["ri:filename"])
+		ref.input.Destination = filename
+		ref.input.Meta.Filename = filename
+		if page := attachment.child("ri:page"); page != nil {
+			ref.input.Meta.PageID = strings.TrimSpace(page.attrs["ri:content-id"])
+			ref.input.Meta.SpaceKey = strings.TrimSpace(page.attrs["ri:space-key"])
+			ref.input.Raw["pageTitle"] = strings.TrimSpace(page.attrs["ri:content-title"])
+		}
+		ref.input.Raw["kind"] = "attachment"
+		ref.fallback = filename
+		text = filename
+	case e.child("ri:url") != nil:
+		destination := strings.TrimSpace(e.child("ri:url").attrs["ri:value"])
+		ref.input.Destination = destination
+		ref.input.Meta.Filename, ref.input.Meta.Anchor = referenceMetadata(destination)
+		ref.input.Raw["kind"] = "link"
+		ref.fallback = destination
+		text = destination
+	case anchor != "":
+		ref.input.Destination = "#" + anchor
+		ref.input.Raw["kind"] = "anchor"
+		ref.fallback = "#" + anchor
+		text = anchor
+	default:
+		s.addWarning(converter.WarningMissingAttribute, "link", "ac:link without a resource rendered as its text")
+		if len(body) == 0 {
+			return nil, nil
+		}
+		return s.parseInline(body, marks)
+	}
+
+	ref.input.Text = firstNonEmpty(bodyText, text)
+	// Confluence shows resource links by name; keep them as links rather than cards.
+	ref.forceLink = ref.input.Raw["kind"] != "link"
+	return s.linkNodes(ref, marks)
+}
+
+// pageURL builds the Confluence URL of a page or blog post below Config.BaseURL, or ""
+// when the page cannot be addressed.
+func (s *state) pageURL(pageID, spaceKey, title, postingDay, anchor string) string {
+	if s.config.BaseURL == "" {
+		return ""
+	}
+	pagePath := pagePath(pageID, spaceKey, title, postingDay)
+	if pagePath == "" {
+		return ""
+	}
+	return s.config.BaseURL + pagePath + fragment(anchor)
+}
+
+// pagePath returns the path of a page or blog post below the Confluence base URL, or ""
+// when the page cannot be addressed.
+func pagePath(pageID, spaceKey, title, postingDay string) string {
+	switch {
+	case pageID != "":
+		return "/pages/viewpage.action?pageId=" + url.QueryEscape(pageID)
+	case spaceKey != "" && title != "" && postingDay != "":
+		return "/display/" + url.PathEscape(spaceKey) + "/" + strings.Trim(postingDay, "/") + "/" + url.QueryEscape(title)
+	case spaceKey != "" && title != "":
+		return "/display/" + url.PathEscape(spaceKey) + "/" + url.QueryEscape(title)
+	default:
+		return ""
+	}
+}
+
+// pageReference keeps a page or blog post that has no URL: the destination is its path
+// below the Confluence base URL, or its title relative to the current page's space, and
+// the attrs carry its ID, space key, title and anchor for hooks and later imports. It
+// returns "" and nil when there is nothing to refer to.
+func pageReference(pageID, spaceKey, title, postingDay, anchor string) (string, map[string]interface{}) {
+	destination := pagePath(pageID, spaceKey, title, postingDay)
+	if destination == "" && title != "" {
+		destination = url.QueryEscape(title)
+	}
+	if destination == "" {
+		return "", nil
+	}
+
+	attrs := map[string]interface{}{}
+	for key, value := range map[string]string{
+		"pageId":       pageID,
+		"spaceKey":     spaceKey,
+		"contentTitle": title,
+		"anchor":       anchor,
+	} {
+		if value != "" {
+			attrs[key] = value
+		}
+	}
+	return destination + fragment(anchor), attrs
+}
+
+func fragment(anchor string) string {
+	if anchor == "" {
+		return ""
+	}
+	return "#" + url.PathEscape(anchor)
+}
+
+// parseUserLink builds a mention for an ac:link to a ri:user.
+func (s *state) parseUserLink(user *element, bodyText string) ([]converter.Node, error) {
+	attrs := map[string]interface{}{}
+	if accountID := strings.TrimSpace(user.attrs["ri:account-id"]); accountID != "" {
+		attrs["id"] = accountID
+	} else {
+		name := firstNonEmpty(user.attrs["ri:username"], user.attrs["ri:userkey"])
+		if name == "" {
+			s.addWarning(converter.WarningMissingAttribute, "mention", "ri:user without account-id, userkey or username dropped")
+			return nil, nil
+		}
+		id, err := s.resolveMention(name)
+		if err != nil {
+			return nil, err
+		}
+		attrs["id"] = id
+		if username := strings.TrimSpace(user.attrs["ri:username"]); username != "" {
+			attrs["text"] = username
+		}
+	}
+	if bodyText != "" {
+		attrs["text"] = bodyText
+	}
+	return []converter.Node{{Type: "mention", Attrs: attrs}}, nil
+}
@@ -0,0 +1,233 @@
+package storageconverter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// confluenceMacroExtensionType is the ADF extensionType of Confluence macros.
+const confluenceMacroExtensionType = "com.atlassian.confluence.macro.core"
+
+// panelTypesByMacro maps the Confluence admonition macros to ADF panel types.
+var panelTypesByMacro = map[string]string{
+	"info":    "info",
+	"tip":     "success",
+	"note":    "warning",
+	"warning": "error",
+}
+
+// statusColors maps status macro colours to ADF status colors.
+var statusColors = map[string]string{
+	"grey":   "neutral",
+	"red":    "red",
+	"yellow": "yellow",
+	"green":  "green",
+	"blue":   "blue",
+	"purple": "purple",
+}
+
+// isInlineMacro reports whether a macro renders inline in Confluence and so never
+// splits the surrounding paragraph.
+func isInlineMacro(name string) bool {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "status", "anchor":
+		return true
+	default:
+		return false
+	}
+}
+
+// parseMacro converts an ac:structured-macro. Macros with an ADF equivalent become that
+// node; other macros become extensions carrying their parameters, as inlineExtension in
+// inline context, bodiedExtension when they have a rich text body and extension
+// otherwise.
+func (s *state) parseMacro(e *element, inline bool) ([]converter.Node, error) {
+	name := strings.ToLower(strings.TrimSpace(e.attrs["ac:name"]))
+	params := macroParameters(e)
+
+	switch name {
+	case "code", "noformat":
+		language := ""
+		if name == "code" {
+			language = strings.ToLower(params["language"])
+			if mapped, ok := s.config.LanguageMap[language]; ok {
+				language = mapped
+			}
+			if title := params["title"]; title != "" {
+				s.addWarning(converter.WarningDroppedFeature, "codeBlock", fmt.Sprintf("code block title %q dropped", title))
+			}
+		}
+		return []converter.Node{codeBlock(language, plainTextBody(e))}, nil
+
+	case "info", "tip", "note", "warning", "panel":
+		content, err := s.richTextBody(e)
+		if err != nil {
+			return nil, err
+		}
+		return []converter.Node{s.panel(name, params, content)}, nil
+
+	case "expand":
+		s.expandDepth++
+		content, err := s.richTextBody(e)
+		s.expandDepth--
+		if err != nil {
+			return nil, err
+		}
+		expand := converter.Node{
+			Type:    "expand",
+			Content: ensureBlocks(content),
+		}
+		if s.expandDepth > 0 {
+			expand.Type = "nestedExpand"
+		}
+		if title := params["title"]; title != "" {
+			expand.Attrs = map[string]interface{}{
+				"title": title,
+			}
+		}
+		return []converter.Node{expand}, nil
+
+	case "status":
+		color, ok := statusColors[strings.ToLower(params["colour"])]
+		if !ok {
+			color = "neutral"
+		}
+		return []converter.Node{
+			{
+				Type: "status",
+				Attrs: map[string]interface{}{
+					"text":  params["title"],
+					"color": color,
+				},
+			},
+		}, nil
+
+	case "anchor":
+		// ADF has no anchors; headings are linked by their text instead.
+		return nil, nil
+	}
+
+	return s.extension(e, name, params, inline)
+}
+
+// extension builds an ADF extension for a macro without an ADF equivalent. Parameters
+// are kept in the Confluence ADF layout, parameters.macroParams.<name>.value; a plain
+// text body is kept as the text attribute.
+func (s *state) extension(e *element, name string, params map[string]string, inline bool) ([]converter.Node, error) {
+	macroParams := make(map[string]interface{}, len(params))
+	for key, value := range params {
+		macroParams[key] = map[string]interface{}{"value": value}
+	}
+	parameters := map[string]interface{}{
+		"macroParams": macroParams,
+	}
+	macroMetadata := map[string]interface{}{}
+	if id := strings.TrimSpace(e.attrs["ac:macro-id"]); id != "" {
+		macroMetadata["macroId"] = map[string]interface{}{"value": id}
+	}
+	if version := strings.TrimSpace(e.attrs["ac:schema-version"]); version != "" {
+		macroMetadata["schemaVersion"] = map[string]interface{}{"value": version}
+	}
+	if len(macroMetadata) > 0 {
+		parameters["macroMetadata"] = macroMetadata
+	}
+
+	node := converter.Node{
+		Type: "extension",
+		Attrs: map[string]interface{}{
+			"extensionType": confluenceMacroExtensionType,
+			"extensionKey":  name,
+			"parameters":    parameters,
+		},
+	}
+	if e.child("ac:plain-text-body") != nil {
+		node.Attrs["text"] = plainTextBody(e)
+	}
+
+	switch {
+	case e.child("ac:rich-text-body") != nil:
+		content, err := s.richTextBody(e)
+		if err != nil {
+			return nil, err
+		}
+		node.Type = "bodiedExtension"
+		node.Content = ensureBlocks(content)
+	case inline:
+		node.Type = "inlineExtension"
+	}
+	return []converter.Node{node}, nil
+}
+
+// panel builds an ADF panel for an admonition or panel macro. Panel macros with a
+// bgColor become custom panels.
+func (s *state) panel(name string, params map[string]string, content []converter.Node) converter.Node {
+	attrs := map[string]interface{}{}
+	panelType, ok := panelTypesByMacro[name]
+	if !ok {
+		panelType = "info"
+		if value := params["bgColor"]; value != "" {
+			if color, valid := normalizeColor(value); valid {
+				panelType = "custom"
+				attrs["panelColor"] = color
+			} else {
+				s.addWarning(converter.WarningDroppedFeature, "panel", fmt.Sprintf("panel color %q dropped", value))
+			}
+		}
+	}
+	attrs["panelType"] = panelType
+	if title := params["title"]; title != "" {
+		attrs["title"] = title
+	}
+
+	if content == nil {
+		content = []converter.Node{}
+	}
+	return converter.Node{
+		Type:    "panel",
+		Attrs:   attrs,
+		Content: content,
+	}
+}
+
+func (s *state) richTextBody(e *element) ([]converter.Node, error) {
+	body := e.child("ac:rich-text-body")
+	if body == nil {
+		return nil, nil
+	}
+	return s.parseBlocks(body.children)
+}
+
+// plainTextBody returns the CDATA body of a macro without its surrounding newlines.
+func plainTextBody(e *element) string {
+	body := strings.ReplaceAll(e.child("ac:plain-text-body").textContentOrEmpty(), "\r\n", "\n")
+	return strings.TrimSuffix(strings.TrimPrefix(body, "\n"), "\n")
+}
+
+// macroParameters returns the ac:parameter values of a macro keyed by their name.
+// Resource parameters use the resource identifier as value.
+func macroParameters(e *element) map[string]string {
+	params := map[string]string{}
+	for _, child := range e.children {
+		if child.name != "ac:parameter" {
+			continue
+		}
+		params[strings.TrimSpace(child.attrs["ac:name"])] = parameterValue(child)
+	}
+	return params
+}
+
+func parameterValue(parameter *element) string {
+	for _, child := range parameter.children {
+		if !strings.HasPrefix(child.name, "ri:") {
+			continue
+		}
+		for _, attr := range []string{"ri:account-id", "ri:userkey", "ri:username", "ri:content-title", "ri:filename", "ri:space-key", "ri:value"} {
+			if value := strings.TrimSpace(child.attrs[attr]); value != "" {
+				return value
+			}
+		}
+	}
+	return strings.TrimSpace(parameter.textContent())
+}
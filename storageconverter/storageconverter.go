@@ -0,0 +1,208 @@
+// Package storageconverter parses Confluence storage format, the XHTML dialect of
+// Confluence Server space exports and the v1 REST API, into ADF.
+//
+// Structured macros become panels, expands, code blocks and status lozenges where ADF
+// has an equivalent and extension nodes with their parameters otherwise. Page and
+// attachment references are resolved with the mdconverter hook types, with the Confluence
+// identifiers in LinkMetadata and MediaMetadata; user links are resolved through
+// Config.MentionRegistry.
+package storageconverter
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/rgonek/jira-adf-converter/mdconverter"
+)
+
+// Converter converts Confluence storage format to ADF.
+type Converter struct {
+	config Config
+}
+
+// state holds the per-conversion state, making the converter thread-safe.
+type state struct {
+	config   Config
+	ctx      context.Context
+	options  mdconverter.ConvertOptions
+	warnings []converter.Warning
+
+	expandDepth int
+}
+
+// element is a parsed storage format element. Names and attribute keys keep their
+// namespace prefix ("ac:structured-macro", "ri:filename") and are lower-cased. Text
+// nodes have an empty name.
+type element struct {
+	name     string
+	attrs    map[string]string
+	text     string
+	children []*element
+}
+
+// New creates a new Converter with the given config.
+func New(config Config) (*Converter, error) {
+	cfg := config.applyDefaults().clone()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Converter{
+		config: cfg,
+	}, nil
+}
+
+// Convert takes Confluence storage format and returns an ADF document.
+func (c *Converter) Convert(storage string) (Result, error) {
+	return c.ConvertWithContext(context.Background(), storage, mdconverter.ConvertOptions{})
+}
+
+// ConvertWithContext takes Confluence storage format and returns an ADF document.
+func (c *Converter) ConvertWithContext(ctx context.Context, storage string, opts mdconverter.ConvertOptions) (Result, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+
+	s := &state{
+		config:  c.config,
+		ctx:     ctx,
+		options: opts,
+	}
+
+	root, err := parseStorage(storage)
+	if err != nil {
+		return Result{}, err
+	}
+	content, err := s.parseBlocks(root.children)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := s.checkContext(); err != nil {
+		return Result{}, err
+	}
+
+	doc := converter.Doc{
+		Version: 1,
+		Type:    "doc",
+		Content: content,
+	}
+	adf, err := json.Marshal(doc)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to marshal ADF JSON: %w", err)
+	}
+
+	return Result{
+		Doc:        doc,
+		ADF:        adf,
+		Warnings:   s.warnings,
+		References: converter.CollectReferences(doc),
+	}, nil
+}
+
+// autoCloseElements lists the HTML void elements. The decoder matches them by local
+// name, so "link" is left out to keep ac:link open.
+var autoCloseElements = slices.DeleteFunc(slices.Clone(xml.HTMLAutoClose), func(name string) bool {
+	return name == "link"
+})
+
+// parseStorage parses storage format into an element tree. The ac: and ri: prefixes are
+// never declared in storage format, so the decoder runs in non-strict HTML mode, which
+// also accepts HTML entities and unclosed void elements.
+func parseStorage(storage string) (*element, error) {
+	decoder := xml.NewDecoder(strings.NewReader("<root>" + storage + "</root>"))
+	decoder.Strict = false
+	decoder.AutoClose = autoCloseElements
+	decoder.Entity = xml.HTMLEntity
+
+	root := &element{name: "root"}
+	stack := []*element{root}
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse storage format: %w", err)
+		}
+
+		parent := stack[len(stack)-1]
+		switch typed := token.(type) {
+		case xml.StartElement:
+			child := &element{
+				name:  qualifiedName(typed.Name),
+				attrs: make(map[string]string, len(typed.Attr)),
+			}
+			for _, attr := range typed.Attr {
+				child.attrs[qualifiedName(attr.Name)] = attr.Value
+			}
+			parent.children = append(parent.children, child)
+			stack = append(stack, child)
+		case xml.EndElement:
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			if len(parent.children) > 0 && parent.children[len(parent.children)-1].name == "" {
+				parent.children[len(parent.children)-1].text += string(typed)
+				continue
+			}
+			parent.children = append(parent.children, &element{text: string(typed)})
+		}
+	}
+	return root, nil
+}
+
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return strings.ToLower(name.Local)
+	}
+	return strings.ToLower(name.Space + ":" + name.Local)
+}
+
+// child returns the first child element with the given name.
+func (e *element) child(name string) *element {
+	for _, child := range e.children {
+		if child.name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+// textContent returns the concatenated text of the element and its descendants.
+func (e *element) textContent() string {
+	if e.name == "" {
+		return e.text
+	}
+	var sb strings.Builder
+	for _, child := range e.children {
+		sb.WriteString(child.textContent())
+	}
+	return sb.String()
+}
+
+func (s *state) checkContext() error {
+	if s.ctx == nil {
+		return nil
+	}
+	return s.ctx.Err()
+}
+
+func (s *state) addWarning(warnType converter.WarningType, nodeType, message string) {
+	s.warnings = append(s.warnings, converter.Warning{
+		Type:     warnType,
+		NodeType: nodeType,
+		Message:  message,
+	})
+}
@@ -0,0 +1,161 @@
+package storageconverter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/rgonek/jira-adf-converter/mdconverter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConverter(t *testing.T, cfg Config) *Converter {
+	t.Helper()
+	conv, err := New(cfg)
+	require.NoError(t, err)
+	return conv
+}
+
+func TestConfigValidate(t *testing.T) {
+	_, err := New(Config{BaseURL: "wiki.example.com"})
+	assert.EqualError(t, err, `invalid baseURL "wiki.example.com"`)
+
+	_, err = New(Config{ResolutionMode: "sometimes"})
+	assert.EqualError(t, err, `invalid resolutionMode "sometimes"`)
+
+	_, err = New(Config{MentionRegistry: map[string]string{"jsmith": " "}})
+	assert.EqualError(t, err, `mentionRegistry["jsmith"] is empty`)
+}
+
+func TestConvertPassesConfluenceReferencesToHooks(t *testing.T) {
+	var linkInputs []mdconverter.LinkParseInput
+	var mediaInputs []mdconverter.MediaParseInput
+	conv := newTestConverter(t, Config{
+		LinkHook: func(_ context.Context, in mdconverter.LinkParseInput) (mdconverter.LinkParseOutput, error) {
+			linkInputs = append(linkInputs, in)
+			return mdconverter.LinkParseOutput{Destination: "https://cloud.example.com/wiki/" + in.Meta.PageID + in.Meta.Filename, Handled: true}, nil
+		},
+		MediaHook: func(_ context.Context, in mdconverter.MediaParseInput) (mdconverter.MediaParseOutput, error) {
+			mediaInputs = append(mediaInputs, in)
+			return mdconverter.MediaParseOutput{MediaType: "image", ID: "uploaded-" + in.Meta.Filename, Handled: true}, nil
+		},
+	})
+
+	storage := `<p><ac:link ac:anchor="Intro"><ri:page ri:content-title="Guide" ri:space-key="DOC" ri:content-id="42" /></ac:link> ` +
+		`<ac:link><ri:attachment ri:filename="spec.pdf" /><ac:plain-text-link-body><![CDATA[spec]]></ac:plain-text-link-body></ac:link></p>` +
+		`<ac:image ac:alt="Screen"><ri:attachment ri:filename="screen.png"><ri:page ri:content-title="Other" ri:space-key="OPS" /></ri:attachment></ac:image>`
+	result, err := conv.ConvertWithContext(context.Background(), storage, mdconverter.ConvertOptions{SourcePath: "DOC/Home"})
+	require.NoError(t, err)
+
+	require.Len(t, linkInputs, 2)
+	assert.Equal(t, "DOC/Home", linkInputs[0].SourcePath)
+	assert.Equal(t, "Guide", linkInputs[0].Destination)
+	assert.Equal(t, mdconverter.LinkMetadata{PageID: "42", SpaceKey: "DOC", Anchor: "Intro"}, linkInputs[0].Meta)
+	assert.Equal(t, "page", linkInputs[0].Raw["kind"])
+	assert.Equal(t, "spec", linkInputs[1].Text)
+	assert.Equal(t, mdconverter.LinkMetadata{Filename: "spec.pdf"}, linkInputs[1].Meta)
+	assert.Equal(t, "attachment", linkInputs[1].Raw["kind"])
+
+	require.Len(t, mediaInputs, 1)
+	assert.Equal(t, "screen.png", mediaInputs[0].Destination)
+	assert.Equal(t, "Screen", mediaInputs[0].Alt)
+	assert.Equal(t, mdconverter.MediaMetadata{SpaceKey: "OPS", Filename: "screen.png"}, mediaInputs[0].Meta)
+
+	require.Len(t, result.Doc.Content, 2)
+	paragraph := result.Doc.Content[0]
+	assert.Equal(t, "Guide", paragraph.Content[0].Text)
+	assert.Equal(t, "https://cloud.example.com/wiki/42", paragraph.Content[0].Marks[0].Attrs["href"])
+	assert.Equal(t, "https://cloud.example.com/wiki/spec.pdf", paragraph.Content[2].Marks[0].Attrs["href"])
+	media := result.Doc.Content[1].Content[0]
+	assert.Equal(t, "uploaded-screen.png", media.Attrs["id"])
+	assert.Equal(t, "Screen", media.Attrs["alt"])
+	assert.Empty(t, result.Warnings)
+}
+
+func TestConvertUnresolvedReferences(t *testing.T) {
+	storage := `<p>See <ac:link><ri:page ri:content-title="Missing" /></ac:link> by <ac:link><ri:user ri:username="ghost" /></ac:link></p>`
+
+	result, err := newTestConverter(t, Config{}).Convert(storage)
+	require.NoError(t, err)
+	require.Len(t, result.Warnings, 2)
+	assert.Equal(t, converter.WarningUnresolvedReference, result.Warnings[0].Type)
+	assert.Equal(t, "link", result.Warnings[0].NodeType)
+	assert.Equal(t, converter.WarningUnresolvedReference, result.Warnings[1].Type)
+	assert.Equal(t, "mention", result.Warnings[1].NodeType)
+	paragraph := result.Doc.Content[0]
+	assert.Equal(t, "See ", paragraph.Content[0].Text)
+	assert.Equal(t, "Missing", paragraph.Content[1].Text)
+	require.Len(t, paragraph.Content[1].Marks, 1)
+	assert.Equal(t, map[string]interface{}{"href": "Missing", "contentTitle": "Missing"}, paragraph.Content[1].Marks[0].Attrs)
+
+	_, err = newTestConverter(t, Config{ResolutionMode: converter.ResolutionStrict}).Convert(storage)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, converter.ErrUnresolved))
+}
+
+func TestConvertKeepsPageReferencesWithoutBaseURL(t *testing.T) {
+	storage := `<p><ac:link ac:anchor="Setup"><ri:page ri:content-title="Install Guide" ri:space-key="DOC" ri:content-id="42" />` +
+		`<ac:plain-text-link-body><![CDATA[install]]></ac:plain-text-link-body></ac:link></p>`
+
+	result, err := newTestConverter(t, Config{}).Convert(storage)
+	require.NoError(t, err)
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, converter.WarningUnresolvedReference, result.Warnings[0].Type)
+
+	text := result.Doc.Content[0].Content[0]
+	assert.Equal(t, "install", text.Text)
+	require.Len(t, text.Marks, 1)
+	assert.Equal(t, map[string]interface{}{
+		"href":         "/pages/viewpage.action?pageId=42#Setup",
+		"pageId":       "42",
+		"spaceKey":     "DOC",
+		"contentTitle": "Install Guide",
+		"anchor":       "Setup",
+	}, text.Marks[0].Attrs)
+}
+
+func TestConvertWarnsOnDroppedFeatures(t *testing.T) {
+	storage := `<ac:structured-macro ac:name="code"><ac:parameter ac:name="title">Main.java</ac:parameter>` +
+		`<ac:plain-text-body><![CDATA[class Main {}]]></ac:plain-text-body></ac:structured-macro>` +
+		`<p><span style="color: chartreuse">text</span> <ac:unknown-thing>kept</ac:unknown-thing></p>` +
+		`<ac:structured-macro ac:name="panel"><ac:parameter ac:name="bgColor">nope</ac:parameter></ac:structured-macro>`
+
+	result, err := newTestConverter(t, Config{}).Convert(storage)
+	require.NoError(t, err)
+
+	require.Len(t, result.Warnings, 4)
+	assert.Equal(t, converter.WarningDroppedFeature, result.Warnings[0].Type)
+	assert.Equal(t, "codeBlock", result.Warnings[0].NodeType)
+	assert.Equal(t, "textColor", result.Warnings[1].NodeType)
+	assert.Equal(t, converter.WarningUnknownNode, result.Warnings[2].Type)
+	assert.Equal(t, "ac:unknown-thing", result.Warnings[2].NodeType)
+	assert.Equal(t, "panel", result.Warnings[3].NodeType)
+	assert.Equal(t, "text kept", result.Doc.Content[1].Content[0].Text)
+}
+
+func TestConvertHonorsCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := newTestConverter(t, Config{}).ConvertWithContext(ctx, "<p>text</p>", mdconverter.ConvertOptions{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestConvertExtensionsRenderThroughForwardConverter(t *testing.T) {
+	storage := `<h2>Plan</h2><p><ac:structured-macro ac:name="toc" /></p>` +
+		`<ac:structured-macro ac:name="info"><ac:rich-text-body><p>Careful</p></ac:rich-text-body></ac:structured-macro>`
+
+	result, err := newTestConverter(t, Config{}).Convert(storage)
+	require.NoError(t, err)
+	require.Len(t, result.References.Extensions, 1)
+	assert.Equal(t, "toc", result.References.Extensions[0].ExtensionKey)
+
+	forward, err := converter.New(converter.Config{})
+	require.NoError(t, err)
+	rendered, err := forward.Convert(result.ADF)
+	require.NoError(t, err)
+	assert.Contains(t, rendered.Markdown, "## Plan")
+	assert.Contains(t, rendered.Markdown, "Careful")
+}
@@ -0,0 +1,383 @@
+package storagerender
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// renderDoc renders the top-level content. Confluence requires every block of a page
+// with column layouts to be inside the layout, so when the document has a layoutSection
+// the blocks between sections are wrapped in single-column sections.
+func (s *state) renderDoc(content []converter.Node) (string, error) {
+	hasLayout := false
+	for _, node := range content {
+		if node.Type == "layoutSection" {
+			hasLayout = true
+			break
+		}
+	}
+	if !hasLayout {
+		return s.renderChildren(content)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<ac:layout>\n")
+	var run []converter.Node
+	flush := func() error {
+		if len(run) == 0 {
+			return nil
+		}
+		rendered, err := s.renderChildren(run)
+		run = nil
+		if err != nil {
+			return err
+		}
+		sb.WriteString(`<ac:layout-section ac:type="single">` + "\n<ac:layout-cell>\n" + rendered + "</ac:layout-cell>\n</ac:layout-section>\n")
+		return nil
+	}
+	for _, node := range content {
+		if node.Type != "layoutSection" {
+			run = append(run, node)
+			continue
+		}
+		if err := flush(); err != nil {
+			return "", err
+		}
+		rendered, err := s.renderLayoutSection(node)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(rendered)
+	}
+	if err := flush(); err != nil {
+		return "", err
+	}
+	sb.WriteString("</ac:layout>\n")
+	return sb.String(), nil
+}
+
+// renderParagraph renders a paragraph; empty paragraphs are dropped.
+func (s *state) renderParagraph(node converter.Node) (string, error) {
+	content, err := s.renderInline(node.Content)
+	if err != nil {
+		return "", err
+	}
+	if content == "" {
+		return "", nil
+	}
+	return "<p" + alignmentStyle(node) + ">" + content + "</p>\n", nil
+}
+
+// renderHeading renders a heading, clamping its level to h1-h6.
+func (s *state) renderHeading(node converter.Node) (string, error) {
+	level := node.GetIntAttr("level", 0)
+	if level <= 0 {
+		level = node.Level
+	}
+	level = min(max(level, 1), 6)
+
+	content, err := s.renderInline(node.Content)
+	if err != nil {
+		return "", err
+	}
+	if content == "" {
+		return "", nil
+	}
+	return fmt.Sprintf("<h%d%s>%s</h%d>\n", level, alignmentStyle(node), content, level), nil
+}
+
+// alignmentStyle returns a text-align style attribute from the align/layout attrs or an
+// alignment mark, or an empty string.
+func alignmentStyle(node converter.Node) string {
+	alignment := node.GetStringAttr("align", "")
+	if alignment == "" {
+		alignment = node.GetStringAttr("layout", "")
+	}
+	for _, mark := range node.Marks {
+		if mark.Type == "alignment" && alignment == "" {
+			alignment = mark.GetStringAttr("align", "")
+		}
+	}
+
+	switch alignment {
+	case "center", "right":
+		return attr("style", "text-align: "+alignment+";")
+	case "end":
+		return attr("style", "text-align: right;")
+	default:
+		return ""
+	}
+}
+
+func (s *state) renderBlockquote(node converter.Node) (string, error) {
+	content, err := s.renderChildren(node.Content)
+	if err != nil {
+		return "", err
+	}
+	if content == "" {
+		return "", nil
+	}
+	return "<blockquote>\n" + content + "</blockquote>\n", nil
+}
+
+// renderList renders bulletList and orderedList nodes.
+func (s *state) renderList(node converter.Node) (string, error) {
+	tag := "ul"
+	listAttrs := ""
+	if node.Type == "orderedList" {
+		tag = "ol"
+		if order := node.GetIntAttr("order", 1); order != 1 {
+			listAttrs = fmt.Sprintf(` start="%d"`, order)
+		}
+	}
+
+	var sb strings.Builder
+	for _, item := range node.Content {
+		if item.Type != "listItem" {
+			if s.config.UnknownNodes == converter.UnknownError {
+				return "", fmt.Errorf("expected listItem child, got %s", item.Type)
+			}
+			s.addWarning(converter.WarningUnknownNode, item.Type, fmt.Sprintf("unexpected list child %s, expected listItem", item.Type))
+			continue
+		}
+		rendered, err := s.renderListItem(item)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(rendered)
+	}
+
+	return "<" + tag + listAttrs + ">\n" + sb.String() + "</" + tag + ">\n", nil
+}
+
+func (s *state) renderListItem(node converter.Node) (string, error) {
+	content, err := s.renderItemContent(node.Content)
+	if err != nil {
+		return "", err
+	}
+	return "<li>" + content + "</li>\n", nil
+}
+
+// renderItemContent renders list item content. A leading paragraph is rendered without
+// <p> so that simple items stay tight, as the Confluence editor stores them.
+func (s *state) renderItemContent(content []converter.Node) (string, error) {
+	if len(content) == 0 {
+		return "", nil
+	}
+
+	var sb strings.Builder
+	rest := content
+	if content[0].Type == "paragraph" {
+		inline, err := s.renderInline(content[0].Content)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(inline)
+		rest = content[1:]
+	}
+	if len(rest) == 0 {
+		return sb.String(), nil
+	}
+
+	blocks, err := s.renderChildren(rest)
+	if err != nil {
+		return "", err
+	}
+	if sb.Len() > 0 {
+		sb.WriteString("\n")
+	}
+	sb.WriteString(blocks)
+	return sb.String(), nil
+}
+
+// renderTaskList renders a taskList as ac:task-list. Task IDs are numbered through the
+// document, as Confluence requires; a nested task list goes into the body of the task
+// before it.
+func (s *state) renderTaskList(node converter.Node) (string, error) {
+	var tasks []string
+	for _, item := range node.Content {
+		switch item.Type {
+		case "taskItem":
+			content, err := s.renderInline(item.Content)
+			if err != nil {
+				return "", err
+			}
+			s.taskID++
+			status := "incomplete"
+			if item.GetStringAttr("state", "TODO") == "DONE" {
+				status = "complete"
+			}
+			tasks = append(tasks, fmt.Sprintf("<ac:task>\n<ac:task-id>%d</ac:task-id>\n<ac:task-status>%s</ac:task-status>\n<ac:task-body>%s", s.taskID, status, content))
+		case "taskList":
+			nested, err := s.renderTaskList(item)
+			if err != nil {
+				return "", err
+			}
+			if len(tasks) == 0 {
+				tasks = append(tasks, "")
+			}
+			tasks[len(tasks)-1] += "\n" + nested
+		default:
+			if s.config.UnknownNodes == converter.UnknownError {
+				return "", fmt.Errorf("taskList expects taskItem child, got %s", item.Type)
+			}
+			s.addWarning(converter.WarningUnknownNode, item.Type, fmt.Sprintf("unexpected task list child %s", item.Type))
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<ac:task-list>\n")
+	for _, task := range tasks {
+		if !strings.HasPrefix(task, "<ac:task>") {
+			// A nested list without a task before it stays a direct child.
+			sb.WriteString(strings.TrimPrefix(task, "\n"))
+			continue
+		}
+		sb.WriteString(task + "</ac:task-body>\n</ac:task>\n")
+	}
+	sb.WriteString("</ac:task-list>\n")
+	return sb.String(), nil
+}
+
+// renderDecisionList renders a decisionList as a bullet list with decision markers,
+// since Confluence Server has no decisions.
+func (s *state) renderDecisionList(node converter.Node) (string, error) {
+	s.addWarning(converter.WarningDroppedFeature, node.Type, "decision list rendered as a bullet list")
+
+	var sb strings.Builder
+	for _, item := range node.Content {
+		if item.Type != "decisionItem" {
+			s.addWarning(converter.WarningUnknownNode, item.Type, fmt.Sprintf("unexpected decision list child %s", item.Type))
+			continue
+		}
+		content, err := s.renderInline(item.Content)
+		if err != nil {
+			return "", err
+		}
+		marker := "✓"
+		if item.GetStringAttr("state", "DECIDED") != "DECIDED" {
+			marker = "?"
+		}
+		sb.WriteString("<li>" + marker + " " + content + "</li>\n")
+	}
+	return "<ul>\n" + sb.String() + "</ul>\n", nil
+}
+
+// renderTable renders a table. Cell colspan and rowspan are kept, and a valid background
+// becomes the Confluence data-highlight-colour.
+func (s *state) renderTable(node converter.Node) (string, error) {
+	var sb strings.Builder
+	for _, row := range node.Content {
+		if row.Type != "tableRow" {
+			s.addWarning(converter.WarningUnknownNode, row.Type, fmt.Sprintf("unexpected table child %s", row.Type))
+			continue
+		}
+		sb.WriteString("<tr>\n")
+		for _, cell := range row.Content {
+			var tag string
+			switch cell.Type {
+			case "tableHeader":
+				tag = "th"
+			case "tableCell":
+				tag = "td"
+			default:
+				s.addWarning(converter.WarningUnknownNode, cell.Type, fmt.Sprintf("unexpected table row child %s", cell.Type))
+				continue
+			}
+
+			cellAttrs := ""
+			for _, span := range []string{"colspan", "rowspan"} {
+				if value := cell.GetIntAttr(span, 1); value > 1 {
+					cellAttrs += fmt.Sprintf(` %s="%d"`, span, value)
+				}
+			}
+			if raw := cell.GetStringAttr("background", ""); raw != "" {
+				if color, ok := converter.SanitizeCSSColor(raw); ok {
+					cellAttrs += attr("data-highlight-colour", color)
+				} else {
+					s.addWarning(converter.WarningDroppedFeature, cell.Type, fmt.Sprintf("invalid cell background %q dropped", raw))
+				}
+			}
+
+			content, err := s.renderChildren(cell.Content)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString("<" + tag + cellAttrs + ">" + content + "</" + tag + ">\n")
+		}
+		sb.WriteString("</tr>\n")
+	}
+	if sb.Len() == 0 {
+		return "", nil
+	}
+	return "<table>\n<tbody>\n" + sb.String() + "</tbody>\n</table>\n", nil
+}
+
+// renderLayoutSection renders a layoutSection as ac:layout-section, choosing the
+// section type from the column widths. Confluence Server supports at most three
+// columns; further columns are merged into the third.
+func (s *state) renderLayoutSection(node converter.Node) (string, error) {
+	var columns []converter.Node
+	for _, child := range node.Content {
+		if child.Type == "layoutColumn" {
+			columns = append(columns, child)
+			continue
+		}
+		s.addWarning(converter.WarningUnknownNode, child.Type, fmt.Sprintf("unexpected layoutSection child %s", child.Type))
+	}
+	if len(columns) == 0 {
+		return "", nil
+	}
+
+	cells := make([]string, 0, len(columns))
+	for _, column := range columns {
+		content, err := s.renderChildren(column.Content)
+		if err != nil {
+			return "", err
+		}
+		cells = append(cells, content)
+	}
+	if len(cells) > 3 {
+		s.addWarning(converter.WarningDroppedFeature, node.Type, fmt.Sprintf("%d layout columns merged into three", len(cells)))
+		cells = append(cells[:2], strings.Join(cells[2:], ""))
+		columns = columns[:3]
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<ac:layout-section" + attr("ac:type", layoutType(columns)) + ">\n")
+	for _, cell := range cells {
+		sb.WriteString("<ac:layout-cell>\n" + cell + "</ac:layout-cell>\n")
+	}
+	sb.WriteString("</ac:layout-section>\n")
+	return sb.String(), nil
+}
+
+// layoutType returns the Confluence layout section type matching the column widths.
+func layoutType(columns []converter.Node) string {
+	widths := make([]float64, len(columns))
+	for i, column := range columns {
+		widths[i] = column.GetFloat64Attr("width", 0)
+	}
+
+	switch len(columns) {
+	case 1:
+		return "single"
+	case 2:
+		switch {
+		case math.Abs(widths[0]-widths[1]) < 1:
+			return "two_equal"
+		case widths[0] < widths[1]:
+			return "two_left_sidebar"
+		default:
+			return "two_right_sidebar"
+		}
+	default:
+		if widths[1] > widths[0]+1 && widths[1] > widths[2]+1 {
+			return "three_with_sidebars"
+		}
+		return "three_equal"
+	}
+}
@@ -0,0 +1,87 @@
+package storagerender
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// UserIdentifier selects the ri:user attribute that carries mention IDs.
+type UserIdentifier string
+
+const (
+	// UserAccountID writes mention IDs as ri:account-id, as Confluence Cloud and Data
+	// Center with account IDs expect.
+	UserAccountID UserIdentifier = "account-id"
+	// UserKey writes mention IDs as ri:userkey, for Confluence Server.
+	UserKey UserIdentifier = "userkey"
+	// UserName writes mention IDs as ri:username, for older Confluence Server versions.
+	UserName UserIdentifier = "username"
+)
+
+// Config holds all renderer configuration options.
+type Config struct {
+	// LanguageMap maps ADF code block languages to code macro languages.
+	LanguageMap    map[string]string        `json:"languageMap,omitempty"`
+	UserIdentifier UserIdentifier           `json:"userIdentifier,omitempty"`
+	UnknownNodes   converter.UnknownPolicy  `json:"unknownNodes,omitempty"`
+	UnknownMarks   converter.UnknownPolicy  `json:"unknownMarks,omitempty"`
+	ResolutionMode converter.ResolutionMode `json:"resolutionMode,omitempty"`
+	LinkHook       LinkRenderHook           `json:"-"`
+	MediaHook      MediaRenderHook          `json:"-"`
+}
+
+func (c Config) applyDefaults() Config {
+	if c.UserIdentifier == "" {
+		c.UserIdentifier = UserAccountID
+	}
+	if c.UnknownNodes == "" {
+		c.UnknownNodes = converter.UnknownPlaceholder
+	}
+	if c.UnknownMarks == "" {
+		c.UnknownMarks = converter.UnknownSkip
+	}
+	if c.ResolutionMode == "" {
+		c.ResolutionMode = converter.ResolutionBestEffort
+	}
+	return c
+}
+
+// clone returns a deep copy of Config for map-backed fields.
+func (c Config) clone() Config {
+	cloned := c
+	if c.LanguageMap != nil {
+		cloned.LanguageMap = make(map[string]string, len(c.LanguageMap))
+		for language, mapped := range c.LanguageMap {
+			cloned.LanguageMap[language] = mapped
+		}
+	}
+	return cloned
+}
+
+// Validate checks that config values are valid.
+func (c Config) Validate() error {
+	if c.UserIdentifier != UserAccountID && c.UserIdentifier != UserKey && c.UserIdentifier != UserName {
+		return fmt.Errorf("invalid userIdentifier %q", c.UserIdentifier)
+	}
+	for language := range c.LanguageMap {
+		if strings.TrimSpace(language) == "" {
+			return fmt.Errorf("languageMap contains empty key")
+		}
+	}
+	if !isValidUnknownPolicy(c.UnknownNodes) {
+		return fmt.Errorf("invalid unknownNodes policy %q", c.UnknownNodes)
+	}
+	if !isValidUnknownPolicy(c.UnknownMarks) {
+		return fmt.Errorf("invalid unknownMarks policy %q", c.UnknownMarks)
+	}
+	if c.ResolutionMode != converter.ResolutionBestEffort && c.ResolutionMode != converter.ResolutionStrict {
+		return fmt.Errorf("invalid resolutionMode %q", c.ResolutionMode)
+	}
+	return nil
+}
+
+func isValidUnknownPolicy(policy converter.UnknownPolicy) bool {
+	return policy == converter.UnknownError || policy == converter.UnknownSkip || policy == converter.UnknownPlaceholder
+}
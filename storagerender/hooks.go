@@ -0,0 +1,108 @@
+package storagerender
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// LinkRenderHook resolves a link mark or inlineCard to a Confluence resource during
+// ADF -> storage format rendering. It receives the same input as the converter link hook.
+type LinkRenderHook func(ctx context.Context, in converter.LinkRenderInput) (LinkRenderOutput, error)
+
+// MediaRenderHook resolves a media node to a Confluence attachment or URL during
+// ADF -> storage format rendering. It receives the same input as the converter media hook.
+type MediaRenderHook func(ctx context.Context, in converter.MediaRenderInput) (MediaRenderOutput, error)
+
+// LinkRenderOutput contains hook-provided link targets. Filename links to an attachment
+// and PageTitle to a page, both rendered as ac:link; an attachment with PageTitle belongs
+// to that page. Href renders a plain <a> link and Anchor alone links within the page.
+type LinkRenderOutput struct {
+	Href      string
+	Title     string
+	PageTitle string
+	// SpaceKey is the space of PageTitle; empty means the space of the rendered page.
+	SpaceKey string
+	Filename string
+	Anchor   string
+	TextOnly bool
+	Handled  bool
+}
+
+// MediaRenderOutput contains hook-provided media targets. Filename renders a
+// ri:attachment, on the page PageTitle when set, and URL renders a ri:url.
+type MediaRenderOutput struct {
+	Filename  string
+	PageTitle string
+	SpaceKey  string
+	URL       string
+	Handled   bool
+}
+
+// applyLinkHook calls Config.LinkHook and applies the same unresolved-reference policy
+// as the Markdown converter.
+func (s *state) applyLinkHook(nodeType string, input converter.LinkRenderInput) (LinkRenderOutput, bool, error) {
+	if s.config.LinkHook == nil {
+		return LinkRenderOutput{}, false, nil
+	}
+	if err := s.checkContext(); err != nil {
+		return LinkRenderOutput{}, false, err
+	}
+
+	output, err := s.config.LinkHook(s.ctx, input)
+	if err != nil {
+		return LinkRenderOutput{}, false, s.hooks.LinkError(nodeType, input, err)
+	}
+
+	if !output.Handled {
+		return LinkRenderOutput{}, false, nil
+	}
+
+	output.Href = strings.TrimSpace(output.Href)
+	output.Title = strings.TrimSpace(output.Title)
+	output.PageTitle = strings.TrimSpace(output.PageTitle)
+	output.SpaceKey = strings.TrimSpace(output.SpaceKey)
+	output.Filename = strings.TrimSpace(output.Filename)
+	output.Anchor = strings.TrimPrefix(strings.TrimSpace(output.Anchor), "#")
+	if output.TextOnly {
+		return output, true, nil
+	}
+	if output.Href != "" && (output.PageTitle != "" || output.Filename != "") {
+		return LinkRenderOutput{}, false, errors.New("invalid link hook output: link render output cannot set href together with pageTitle or filename")
+	}
+	if output.Href == "" && output.PageTitle == "" && output.Filename == "" && output.Anchor == "" {
+		return LinkRenderOutput{}, false, errors.New("invalid link hook output: handled link render output requires href, pageTitle, filename or anchor unless textOnly is true")
+	}
+	return output, true, nil
+}
+
+// applyMediaHook calls Config.MediaHook and applies the same unresolved-reference policy
+// as the Markdown converter.
+func (s *state) applyMediaHook(nodeType string, input converter.MediaRenderInput) (MediaRenderOutput, bool, error) {
+	if s.config.MediaHook == nil {
+		return MediaRenderOutput{}, false, nil
+	}
+	if err := s.checkContext(); err != nil {
+		return MediaRenderOutput{}, false, err
+	}
+
+	output, err := s.config.MediaHook(s.ctx, input)
+	if err != nil {
+		return MediaRenderOutput{}, false, s.hooks.MediaError(nodeType, input, err)
+	}
+
+	if !output.Handled {
+		return MediaRenderOutput{}, false, nil
+	}
+
+	output.Filename = strings.TrimSpace(output.Filename)
+	output.PageTitle = strings.TrimSpace(output.PageTitle)
+	output.SpaceKey = strings.TrimSpace(output.SpaceKey)
+	output.URL = strings.TrimSpace(output.URL)
+	if (output.Filename == "") == (output.URL == "") {
+		return MediaRenderOutput{}, false, errors.New("invalid media hook output: handled media render output requires exactly one of filename or url")
+	}
+	return output, true, nil
+}
@@ -0,0 +1,373 @@
+package storagerender
+
+import (
+	"fmt"
+	"html"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// statusColours maps ADF status colors to status macro colours.
+var statusColours = map[string]string{
+	"neutral": "Grey",
+	"purple":  "Purple",
+	"blue":    "Blue",
+	"red":     "Red",
+	"yellow":  "Yellow",
+	"green":   "Green",
+}
+
+// emoticonNames maps emoji short names to Confluence emoticon names.
+var emoticonNames = map[string]string{
+	":slight_smile:":       "smile",
+	":smile:":              "smile",
+	":disappointed:":       "sad",
+	":stuck_out_tongue:":   "cheeky",
+	":smiley:":             "laugh",
+	":laughing:":           "laugh",
+	":wink:":               "wink",
+	":thumbsup:":           "thumbs-up",
+	":+1:":                 "thumbs-up",
+	":thumbsdown:":         "thumbs-down",
+	":-1:":                 "thumbs-down",
+	":information_source:": "information",
+	":white_check_mark:":   "tick",
+	":heavy_check_mark:":   "tick",
+	":x:":                  "cross",
+	":warning:":            "warning",
+	":heavy_plus_sign:":    "plus",
+	":heavy_minus_sign:":   "minus",
+	":question:":           "question",
+	":bulb:":               "light-on",
+	":star:":               "yellow-star",
+	":heart:":              "heart",
+	":broken_heart:":       "broken-heart",
+}
+
+// renderInline renders inline content. Adjacent text nodes that share a link mark are
+// rendered as a single link, so the link hook runs once per link.
+func (s *state) renderInline(content []converter.Node) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(content); {
+		link, ok := findLinkMark(content[i])
+		if !ok {
+			rendered, err := s.renderNode(content[i])
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(rendered)
+			i++
+			continue
+		}
+
+		end := i + 1
+		for end < len(content) {
+			next, ok := findLinkMark(content[end])
+			if !ok || !reflect.DeepEqual(next.Attrs, link.Attrs) {
+				break
+			}
+			end++
+		}
+
+		rendered, err := s.renderLink(link, content[i:end])
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(rendered)
+		i = end
+	}
+	return sb.String(), nil
+}
+
+func findLinkMark(node converter.Node) (converter.Mark, bool) {
+	if node.Type != "text" {
+		return converter.Mark{}, false
+	}
+	for _, mark := range node.Marks {
+		if mark.Type == "link" {
+			return mark, true
+		}
+	}
+	return converter.Mark{}, false
+}
+
+// renderLink renders text nodes sharing a link mark. Links resolved by the hook to a page,
+// attachment or anchor become ac:link, as do fragment-only hrefs; other links stay <a>.
+func (s *state) renderLink(link converter.Mark, nodes []converter.Node) (string, error) {
+	var inner, plain strings.Builder
+	formatted := false
+	for _, node := range nodes {
+		rendered, err := s.renderText(node)
+		if err != nil {
+			return "", err
+		}
+		inner.WriteString(rendered)
+		plain.WriteString(node.Text)
+		if len(node.Marks) > 1 {
+			formatted = true
+		}
+	}
+	body := linkBody{content: inner.String(), plain: plain.String(), formatted: formatted}
+
+	input, ok := converter.LinkMarkRenderInput(s.options.SourcePath, link)
+	if !ok {
+		return body.content, nil
+	}
+
+	output, handled, err := s.applyLinkHook(link.Type, input)
+	if err != nil {
+		return "", err
+	}
+	if handled {
+		if output.TextOnly {
+			return body.content, nil
+		}
+		if output.Href == "" {
+			return resourceLink(output, body), nil
+		}
+		return anchorLink(output.Href, output.Title, body.content), nil
+	}
+
+	if anchor, ok := strings.CutPrefix(input.Href, "#"); ok && anchor != "" {
+		return resourceLink(LinkRenderOutput{Anchor: anchor}, body), nil
+	}
+	return anchorLink(input.Href, input.Title, body.content), nil
+}
+
+// linkBody is the rendered body of a link. Links whose text carries no other marks use
+// ac:plain-text-link-body, as the Confluence editor stores them.
+type linkBody struct {
+	content   string
+	plain     string
+	formatted bool
+}
+
+// resourceLink renders an ac:link to the page, attachment or anchor of a hook output.
+func resourceLink(target LinkRenderOutput, body linkBody) string {
+	var sb strings.Builder
+	sb.WriteString("<ac:link")
+	if target.Anchor != "" {
+		sb.WriteString(attr("ac:anchor", target.Anchor))
+	}
+	sb.WriteString(">")
+
+	switch {
+	case target.Filename != "":
+		sb.WriteString("<ri:attachment" + attr("ri:filename", target.Filename))
+		if target.PageTitle != "" {
+			sb.WriteString(">" + pageResource(target.PageTitle, target.SpaceKey) + "</ri:attachment>")
+		} else {
+			sb.WriteString(" />")
+		}
+	case target.PageTitle != "":
+		sb.WriteString(pageResource(target.PageTitle, target.SpaceKey))
+	}
+
+	switch {
+	case body.formatted:
+		sb.WriteString("<ac:link-body>" + body.content + "</ac:link-body>")
+	case body.plain != "":
+		sb.WriteString("<ac:plain-text-link-body>" + cdata(body.plain) + "</ac:plain-text-link-body>")
+	}
+	sb.WriteString("</ac:link>")
+	return sb.String()
+}
+
+// pageResource renders a ri:page reference; an empty space key means the current space.
+func pageResource(title, spaceKey string) string {
+	spaceAttr := ""
+	if spaceKey != "" {
+		spaceAttr = attr("ri:space-key", spaceKey)
+	}
+	return "<ri:page" + spaceAttr + attr("ri:content-title", title) + " />"
+}
+
+func anchorLink(href, title, content string) string {
+	anchorAttrs := attr("href", href)
+	if title != "" {
+		anchorAttrs += attr("title", title)
+	}
+	return "<a" + anchorAttrs + ">" + content + "</a>"
+}
+
+// renderText renders a text node with its formatting marks. Link marks are applied by
+// renderInline; the first mark in the list becomes the outermost element.
+func (s *state) renderText(node converter.Node) (string, error) {
+	result := html.EscapeString(node.Text)
+	for i := len(node.Marks) - 1; i >= 0; i-- {
+		opening, closing, err := s.markTags(node.Marks[i])
+		if err != nil {
+			return "", err
+		}
+		result = opening + result + closing
+	}
+	return result, nil
+}
+
+func (s *state) markTags(mark converter.Mark) (string, string, error) {
+	switch mark.Type {
+	case "strong":
+		return "<strong>", "</strong>", nil
+	case "em":
+		return "<em>", "</em>", nil
+	case "strike":
+		return "<s>", "</s>", nil
+	case "underline":
+		return "<u>", "</u>", nil
+	case "code":
+		return "<code>", "</code>", nil
+	case "subsup":
+		switch mark.GetStringAttr("type", "") {
+		case "sub":
+			return "<sub>", "</sub>", nil
+		case "sup":
+			return "<sup>", "</sup>", nil
+		}
+		return "", "", nil
+	case "textColor", "backgroundColor":
+		raw := mark.GetStringAttr("color", "")
+		color, ok := converter.SanitizeCSSColor(raw)
+		if !ok {
+			if raw != "" {
+				s.addWarning(converter.WarningDroppedFeature, mark.Type, fmt.Sprintf("invalid color value dropped: %q", raw))
+			}
+			return "", "", nil
+		}
+		property := "color: "
+		if mark.Type == "backgroundColor" {
+			property = "background-color: "
+		}
+		return "<span" + attr("style", property+color+";") + ">", "</span>", nil
+	case "link", "annotation":
+		return "", "", nil
+	default:
+		if s.config.UnknownMarks == converter.UnknownError {
+			return "", "", fmt.Errorf("unknown mark type: %s", mark.Type)
+		}
+		if s.config.UnknownMarks == converter.UnknownPlaceholder {
+			s.addWarning(converter.WarningUnknownMark, mark.Type, fmt.Sprintf("unknown mark rendered as placeholder: %s", mark.Type))
+			return html.EscapeString(fmt.Sprintf("[Unknown mark: %s]", mark.Type)), "", nil
+		}
+		s.addWarning(converter.WarningUnknownMark, mark.Type, fmt.Sprintf("unknown mark skipped: %s", mark.Type))
+		return "", "", nil
+	}
+}
+
+// renderMention renders a mention as a ri:user link identified by Config.UserIdentifier.
+// Mentions without an id render their text.
+func (s *state) renderMention(node converter.Node) (string, error) {
+	id := node.GetStringAttr("id", "")
+	if id == "" {
+		s.addWarning(converter.WarningMissingAttribute, node.Type, "mention node missing id")
+		text := node.GetStringAttr("text", "")
+		if text == "" {
+			text = "Unknown User"
+		} else if !strings.HasPrefix(text, "@") {
+			text = "@" + text
+		}
+		return html.EscapeString(text), nil
+	}
+	return "<ac:link><ri:user" + attr("ri:"+string(s.config.UserIdentifier), id) + " /></ac:link>", nil
+}
+
+// renderStatus renders a status lozenge as the status macro.
+func (s *state) renderStatus(node converter.Node) (string, error) {
+	text := node.GetStringAttr("text", "Unknown")
+	colour, ok := statusColours[strings.ToLower(node.GetStringAttr("color", "neutral"))]
+	if !ok {
+		colour = statusColours["neutral"]
+	}
+	return `<ac:structured-macro ac:name="status" ac:schema-version="1">` +
+		macroParameter("colour", colour) + macroParameter("title", text) +
+		"</ac:structured-macro>", nil
+}
+
+// renderEmoji renders an emoji as ac:emoticon. Emoji without a matching Confluence
+// emoticon carry their short name and fallback text, as Confluence 7 stores them.
+func (s *state) renderEmoji(node converter.Node) (string, error) {
+	shortName := node.GetStringAttr("shortName", "")
+	fallback := firstNonEmpty(node.GetStringAttr("text", ""), node.GetStringAttr("fallback", ""))
+	if shortName == "" && fallback == "" {
+		if s.config.UnknownNodes == converter.UnknownError {
+			return "", fmt.Errorf("emoji node missing shortName and fallback")
+		}
+		s.addWarning(converter.WarningMissingAttribute, node.Type, "emoji node missing shortName and fallback")
+		return "", nil
+	}
+
+	if name, ok := emoticonNames[shortName]; ok {
+		return "<ac:emoticon" + attr("ac:name", name) + " />", nil
+	}
+	if shortName == "" {
+		return html.EscapeString(fallback), nil
+	}
+	emoticonAttrs := attr("ac:name", "blue-star") + attr("ac:emoji-shortname", shortName)
+	if fallback != "" {
+		emoticonAttrs += attr("ac:emoji-fallback", fallback)
+	}
+	return "<ac:emoticon" + emoticonAttrs + " />", nil
+}
+
+// renderDate renders a date node as <time> with an ISO 8601 datetime attribute, which
+// Confluence displays as a date lozenge.
+func (s *state) renderDate(node converter.Node) (string, error) {
+	timestamp := node.GetStringAttr("timestamp", "")
+
+	var ts int64
+	if _, err := fmt.Sscanf(timestamp, "%d", &ts); err != nil {
+		if s.config.UnknownNodes == converter.UnknownError {
+			return "", fmt.Errorf("date node has invalid timestamp format: %s", timestamp)
+		}
+		s.addWarning(converter.WarningMissingAttribute, node.Type, fmt.Sprintf("date node has invalid timestamp format: %s", timestamp))
+		return html.EscapeString("[Date: invalid]"), nil
+	}
+
+	// Millisecond timestamps are detected with the same cutoff as the Markdown converter.
+	if ts > 10000000000 {
+		ts = ts / 1000
+	}
+
+	return "<time" + attr("datetime", time.Unix(ts, 0).UTC().Format("2006-01-02")) + " />", nil
+}
+
+// renderInlineCard renders an inlineCard as an inline card link, or as an ac:link when
+// the link hook resolves it to a page or attachment.
+func (s *state) renderInlineCard(node converter.Node) (string, error) {
+	input := converter.InlineCardRenderInput(s.options.SourcePath, node)
+	if input.Href == "" {
+		if s.config.UnknownNodes == converter.UnknownError {
+			return "", fmt.Errorf("inlineCard missing url and valid data")
+		}
+		s.addWarning(converter.WarningMissingAttribute, node.Type, "inlineCard missing url and valid data")
+		return html.EscapeString("[Smart Link]"), nil
+	}
+
+	href := input.Href
+	output, handled, err := s.applyLinkHook(node.Type, input)
+	if err != nil {
+		return "", err
+	}
+	if handled {
+		if output.TextOnly {
+			return html.EscapeString(firstNonEmpty(output.Title, input.Title, href)), nil
+		}
+		if output.Href == "" {
+			return resourceLink(output, linkBody{plain: output.Title}), nil
+		}
+		href = output.Href
+	}
+
+	return "<a" + attr("href", href) + attr("data-card-appearance", "inline") + ">" + html.EscapeString(href) + "</a>", nil
+}
+
+// renderPlaceholder renders a placeholder as ac:placeholder instruction text.
+func (s *state) renderPlaceholder(node converter.Node) (string, error) {
+	text := node.GetStringAttr("text", "")
+	if text == "" {
+		return "", nil
+	}
+	return "<ac:placeholder>" + html.EscapeString(text) + "</ac:placeholder>", nil
+}
@@ -0,0 +1,185 @@
+package storagerender
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// panelMacros maps ADF panel types to the Confluence admonition macros. Other panel
+// types use the panel macro with a background color.
+var panelMacros = map[string]string{
+	"info":    "info",
+	"success": "tip",
+	"warning": "note",
+	"error":   "warning",
+}
+
+// notePanelColor is the background of note panels, which have no admonition macro.
+const notePanelColor = "#eae6ff"
+
+// renderCodeBlock renders a codeBlock as the code macro, mapping its language through
+// Config.LanguageMap.
+func (s *state) renderCodeBlock(node converter.Node) (string, error) {
+	var sb strings.Builder
+	for _, child := range node.Content {
+		sb.WriteString(child.Text)
+	}
+
+	params := ""
+	if language := strings.TrimSpace(node.GetStringAttr("language", "")); language != "" {
+		if mapped, ok := s.config.LanguageMap[language]; ok {
+			language = mapped
+		}
+		params = macroParameter("language", language)
+	}
+	return `<ac:structured-macro ac:name="code" ac:schema-version="1">` + params +
+		"<ac:plain-text-body>" + cdata(sb.String()) + "</ac:plain-text-body></ac:structured-macro>\n", nil
+}
+
+// renderPanel renders a panel as an admonition macro, or as the panel macro for note
+// and custom panels.
+func (s *state) renderPanel(node converter.Node) (string, error) {
+	content, err := s.renderChildren(node.Content)
+	if err != nil {
+		return "", err
+	}
+
+	panelType := node.GetStringAttr("panelType", "info")
+	name, ok := panelMacros[panelType]
+	var params strings.Builder
+	if !ok {
+		name = "panel"
+		color := notePanelColor
+		if panelType != "note" {
+			color = ""
+			if raw := node.GetStringAttr("panelColor", ""); raw != "" {
+				if sanitized, valid := converter.SanitizeCSSColor(raw); valid {
+					color = sanitized
+				} else {
+					s.addWarning(converter.WarningDroppedFeature, node.Type, fmt.Sprintf("invalid panel color %q dropped", raw))
+				}
+			}
+		}
+		if color != "" {
+			params.WriteString(macroParameter("bgColor", color))
+		}
+	}
+	if title := node.GetStringAttr("title", ""); title != "" {
+		params.WriteString(macroParameter("title", title))
+	}
+
+	return "<ac:structured-macro" + attr("ac:name", name) + ` ac:schema-version="1">` + params.String() +
+		"<ac:rich-text-body>\n" + content + "</ac:rich-text-body></ac:structured-macro>\n", nil
+}
+
+// renderExpand renders expand and nestedExpand nodes as the expand macro.
+func (s *state) renderExpand(node converter.Node) (string, error) {
+	content, err := s.renderChildren(node.Content)
+	if err != nil {
+		return "", err
+	}
+
+	params := ""
+	if title := node.GetStringAttr("title", ""); title != "" {
+		params = macroParameter("title", title)
+	}
+	return `<ac:structured-macro ac:name="expand" ac:schema-version="1">` + params +
+		"<ac:rich-text-body>\n" + content + "</ac:rich-text-body></ac:structured-macro>\n", nil
+}
+
+// renderExtension renders an extension node as the macro named by its extensionKey.
+// Parameters are read from the Confluence ADF layout, parameters.macroParams.<name>.value,
+// falling back to flat scalar parameters; the text attr becomes the plain-text body and
+// bodiedExtension content the rich-text body.
+func (s *state) renderExtension(node converter.Node) (string, error) {
+	key := node.GetStringAttr("extensionKey", "")
+	if key == "" {
+		if s.config.UnknownNodes == converter.UnknownError {
+			return "", fmt.Errorf("%s missing extensionKey", node.Type)
+		}
+		s.addWarning(converter.WarningMissingAttribute, node.Type, fmt.Sprintf("%s missing extensionKey", node.Type))
+		if node.Type == "bodiedExtension" {
+			return s.renderChildren(node.Content)
+		}
+		return "", nil
+	}
+
+	parameters, _ := node.Attrs["parameters"].(map[string]interface{})
+	macroAttrs := attr("ac:name", key)
+	metadata, _ := parameters["macroMetadata"].(map[string]interface{})
+	if id := parameterString(metadata["macroId"]); id != "" {
+		macroAttrs += attr("ac:macro-id", id)
+	}
+	version := parameterString(metadata["schemaVersion"])
+	macroAttrs += attr("ac:schema-version", firstNonEmpty(version, "1"))
+
+	var sb strings.Builder
+	sb.WriteString("<ac:structured-macro" + macroAttrs + ">")
+	params := extensionParameters(parameters)
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		sb.WriteString(macroParameter(name, params[name]))
+	}
+
+	if text, ok := node.Attrs["text"].(string); ok && text != "" {
+		sb.WriteString("<ac:plain-text-body>" + cdata(text) + "</ac:plain-text-body>")
+	}
+	if node.Type == "bodiedExtension" {
+		content, err := s.renderChildren(node.Content)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString("<ac:rich-text-body>\n" + content + "</ac:rich-text-body>")
+	}
+	sb.WriteString("</ac:structured-macro>")
+	if node.Type != "inlineExtension" {
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+// extensionParameters returns the macro parameters of an extension by name.
+func extensionParameters(parameters map[string]interface{}) map[string]string {
+	params := map[string]string{}
+	if macroParams, ok := parameters["macroParams"].(map[string]interface{}); ok {
+		for name, value := range macroParams {
+			if text := parameterString(value); text != "" {
+				params[name] = text
+			}
+		}
+		return params
+	}
+	for name, value := range parameters {
+		if name == "macroMetadata" {
+			continue
+		}
+		switch value.(type) {
+		case string, float64, bool:
+			params[name] = fmt.Sprint(value)
+		}
+	}
+	return params
+}
+
+// parameterString returns a scalar parameter value, unwrapping the {"value": ...} layout
+// Confluence uses for macro parameters and metadata.
+func parameterString(value interface{}) string {
+	if wrapped, ok := value.(map[string]interface{}); ok {
+		value = wrapped["value"]
+	}
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64, bool:
+		return fmt.Sprint(v)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,157 @@
+package storagerender
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// mediaAlignments maps mediaSingle layouts to ac:image alignments.
+var mediaAlignments = map[string]string{
+	"center":      "center",
+	"align-start": "left",
+	"wrap-left":   "left",
+	"align-end":   "right",
+	"wrap-right":  "right",
+}
+
+// renderMediaSingle renders a mediaSingle as a paragraph holding its media. Confluence
+// images have no captions, so a caption child becomes the paragraph after the image.
+func (s *state) renderMediaSingle(node converter.Node) (string, error) {
+	align := mediaAlignments[node.GetStringAttr("layout", "")]
+	width := 0
+	if node.GetStringAttr("widthType", "") == "pixel" {
+		width = node.GetIntAttr("width", 0)
+	}
+
+	var body, caption strings.Builder
+	for _, child := range node.Content {
+		switch child.Type {
+		case "media", "mediaInline":
+			rendered, err := s.renderMedia(child, align, width)
+			if err != nil {
+				return "", err
+			}
+			body.WriteString(rendered)
+		case "caption":
+			rendered, err := s.renderInline(child.Content)
+			if err != nil {
+				return "", err
+			}
+			caption.WriteString(rendered)
+		default:
+			rendered, err := s.renderNode(child)
+			if err != nil {
+				return "", err
+			}
+			body.WriteString(rendered)
+		}
+	}
+
+	var sb strings.Builder
+	if body.Len() > 0 {
+		sb.WriteString("<p>" + body.String() + "</p>\n")
+	}
+	if caption.Len() > 0 {
+		sb.WriteString("<p>" + caption.String() + "</p>\n")
+	}
+	return sb.String(), nil
+}
+
+// renderMediaGroup renders a mediaGroup as a paragraph of its media, separated by spaces.
+func (s *state) renderMediaGroup(node converter.Node) (string, error) {
+	var items []string
+	for _, child := range node.Content {
+		rendered, err := s.renderNode(child)
+		if err != nil {
+			return "", err
+		}
+		if rendered != "" {
+			items = append(items, rendered)
+		}
+	}
+	if len(items) == 0 {
+		return "", nil
+	}
+	return "<p>" + strings.Join(items, " ") + "</p>\n", nil
+}
+
+// renderMedia renders media and mediaInline nodes. Images become ac:image and files the
+// view-file macro. The media hook resolves the node to an attachment or URL; otherwise
+// absolute URLs become ri:url, and relative URLs and media ids become attachments.
+// A non-empty align and a positive width come from the enclosing mediaSingle.
+func (s *state) renderMedia(node converter.Node, align string, width int) (string, error) {
+	input := converter.MediaNodeRenderInput(s.options.SourcePath, node)
+	target, handled, err := s.applyMediaHook(node.Type, input)
+	if err != nil {
+		return "", err
+	}
+	if !handled {
+		target = defaultMediaTarget(input)
+	}
+	if target.Filename == "" && target.URL == "" {
+		if s.config.UnknownNodes == converter.UnknownError {
+			return "", fmt.Errorf("media node missing id")
+		}
+		s.addWarning(converter.WarningMissingAttribute, node.Type, "media node missing id")
+		return "", nil
+	}
+
+	if input.MediaType == "file" {
+		if target.URL != "" {
+			label := firstNonEmpty(node.GetStringAttr("filename", ""), input.Alt, path.Base(target.URL))
+			return "<a" + attr("href", target.URL) + ">" + html.EscapeString(label) + "</a>", nil
+		}
+		return `<ac:structured-macro ac:name="view-file" ac:schema-version="1"><ac:parameter ac:name="name">` +
+			mediaResource(target) + "</ac:parameter></ac:structured-macro>", nil
+	}
+
+	imageAttrs := ""
+	if align != "" {
+		imageAttrs += attr("ac:align", align)
+	}
+	if width <= 0 {
+		width = node.GetIntAttr("width", 0)
+	}
+	if width > 0 {
+		imageAttrs += fmt.Sprintf(` ac:width="%d"`, width)
+	}
+	if height := node.GetIntAttr("height", 0); height > 0 {
+		imageAttrs += fmt.Sprintf(` ac:height="%d"`, height)
+	}
+	if input.Alt != "" {
+		imageAttrs += attr("ac:alt", input.Alt)
+	}
+	return "<ac:image" + imageAttrs + ">" + mediaResource(target) + "</ac:image>", nil
+}
+
+// defaultMediaTarget resolves media without a hook. Absolute URLs stay URLs; relative
+// URLs and media ids name attachments of the rendered page.
+func defaultMediaTarget(input converter.MediaRenderInput) MediaRenderOutput {
+	if input.URL != "" {
+		if parsed, err := url.Parse(input.URL); err == nil && parsed.Scheme != "" {
+			return MediaRenderOutput{URL: input.URL}
+		}
+		filename := input.URL
+		if unescaped, err := url.PathUnescape(filename); err == nil {
+			filename = unescaped
+		}
+		return MediaRenderOutput{Filename: path.Base(filename)}
+	}
+	return MediaRenderOutput{Filename: firstNonEmpty(input.Meta.Filename, input.ID)}
+}
+
+// mediaResource renders the ri:attachment or ri:url of a media target.
+func mediaResource(target MediaRenderOutput) string {
+	if target.URL != "" {
+		return "<ri:url" + attr("ri:value", target.URL) + " />"
+	}
+	if target.PageTitle == "" {
+		return "<ri:attachment" + attr("ri:filename", target.Filename) + " />"
+	}
+	return "<ri:attachment" + attr("ri:filename", target.Filename) + ">" + pageResource(target.PageTitle, target.SpaceKey) + "</ri:attachment>"
+}
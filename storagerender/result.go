@@ -0,0 +1,11 @@
+package storagerender
+
+import "github.com/rgonek/jira-adf-converter/converter"
+
+// Result holds the output of a Confluence storage format render.
+type Result struct {
+	Storage  string              `json:"storage"`
+	Warnings []converter.Warning `json:"warnings,omitempty"`
+	// References lists links, media, mentions, inlineCards, emoji and extensions of the input.
+	References converter.References `json:"references,omitzero"`
+}
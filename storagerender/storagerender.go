@@ -0,0 +1,240 @@
+// Package storagerender renders Jira ADF documents as Confluence storage format, the
+// XHTML dialect accepted by Confluence Server and Data Center, so that content written
+// in Markdown and parsed with mdconverter can be published there.
+//
+// Panels, expands, code blocks, status lozenges and extension nodes become ac:
+// structured macros, tasks become ac:task-list and mentions become ri:user links. Link
+// and media hooks resolve references to ri:page and ri:attachment resources.
+package storagerender
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+// Renderer renders ADF to Confluence storage format.
+type Renderer struct {
+	config Config
+}
+
+// state holds the per-render state, making the renderer thread-safe.
+type state struct {
+	config   Config
+	ctx      context.Context
+	options  converter.ConvertOptions
+	warnings []converter.Warning
+	hooks    converter.HookPolicy
+
+	taskID int
+}
+
+// New creates a new Renderer with the given config.
+func New(config Config) (*Renderer, error) {
+	cfg := config.applyDefaults().clone()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Renderer{
+		config: cfg,
+	}, nil
+}
+
+// Render takes an ADF JSON document and returns Confluence storage format.
+func (r *Renderer) Render(input []byte) (Result, error) {
+	return r.RenderWithContext(context.Background(), input, converter.ConvertOptions{})
+}
+
+// RenderWithContext takes an ADF JSON document and returns Confluence storage format.
+func (r *Renderer) RenderWithContext(ctx context.Context, input []byte, opts converter.ConvertOptions) (Result, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+
+	var doc converter.Doc
+	if err := json.Unmarshal(input, &doc); err != nil {
+		return Result{}, fmt.Errorf("failed to parse ADF JSON: %w", err)
+	}
+
+	s := &state{
+		config:  r.config,
+		ctx:     ctx,
+		options: opts,
+	}
+	s.hooks = converter.HookPolicy{ResolutionMode: r.config.ResolutionMode, Warn: s.addWarning}
+
+	output, err := s.renderDoc(doc.Content)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := s.checkContext(); err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Storage:    output,
+		Warnings:   s.warnings,
+		References: converter.CollectReferences(doc),
+	}, nil
+}
+
+func (s *state) renderNode(node converter.Node) (string, error) {
+	if err := s.checkContext(); err != nil {
+		return "", err
+	}
+
+	switch node.Type {
+	case "doc":
+		return s.renderDoc(node.Content)
+
+	case "paragraph":
+		return s.renderParagraph(node)
+
+	case "heading":
+		return s.renderHeading(node)
+
+	case "blockquote":
+		return s.renderBlockquote(node)
+
+	case "rule":
+		return "<hr />\n", nil
+
+	case "hardBreak":
+		return "<br />", nil
+
+	case "codeBlock":
+		return s.renderCodeBlock(node)
+
+	case "bulletList", "orderedList":
+		return s.renderList(node)
+
+	case "listItem":
+		return s.renderListItem(node)
+
+	case "taskList":
+		return s.renderTaskList(node)
+
+	case "decisionList":
+		return s.renderDecisionList(node)
+
+	case "text":
+		return s.renderText(node)
+
+	case "emoji":
+		return s.renderEmoji(node)
+
+	case "mention":
+		return s.renderMention(node)
+
+	case "status":
+		return s.renderStatus(node)
+
+	case "date":
+		return s.renderDate(node)
+
+	case "inlineCard":
+		return s.renderInlineCard(node)
+
+	case "table":
+		return s.renderTable(node)
+
+	case "panel":
+		return s.renderPanel(node)
+
+	case "expand", "nestedExpand":
+		return s.renderExpand(node)
+
+	case "layoutSection":
+		return s.renderLayoutSection(node)
+
+	case "layoutColumn":
+		return s.renderChildren(node.Content)
+
+	case "mediaSingle":
+		return s.renderMediaSingle(node)
+
+	case "mediaGroup":
+		return s.renderMediaGroup(node)
+
+	case "media", "mediaInline":
+		return s.renderMedia(node, "", 0)
+
+	case "placeholder":
+		return s.renderPlaceholder(node)
+
+	case "extension", "inlineExtension", "bodiedExtension":
+		return s.renderExtension(node)
+
+	default:
+		switch s.config.UnknownNodes {
+		case converter.UnknownError:
+			return "", fmt.Errorf("unknown node type: %s", node.Type)
+		case converter.UnknownSkip:
+			s.addWarning(converter.WarningUnknownNode, node.Type, fmt.Sprintf("unknown node skipped: %s", node.Type))
+			return "", nil
+		default:
+			s.addWarning(converter.WarningUnknownNode, node.Type, fmt.Sprintf("unknown node rendered as placeholder: %s", node.Type))
+			return html.EscapeString(fmt.Sprintf("[Unknown node: %s]", node.Type)), nil
+		}
+	}
+}
+
+func (s *state) renderChildren(content []converter.Node) (string, error) {
+	var sb strings.Builder
+	for _, child := range content {
+		rendered, err := s.renderNode(child)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(rendered)
+	}
+	return sb.String(), nil
+}
+
+func (s *state) checkContext() error {
+	if s.ctx == nil {
+		return nil
+	}
+	return s.ctx.Err()
+}
+
+func (s *state) addWarning(warnType converter.WarningType, nodeType, message string) {
+	s.warnings = append(s.warnings, converter.Warning{
+		Type:     warnType,
+		NodeType: nodeType,
+		Message:  message,
+	})
+}
+
+// attr renders an escaped attribute with a leading space.
+func attr(name, value string) string {
+	return " " + name + `="` + html.EscapeString(value) + `"`
+}
+
+// cdata wraps text in a CDATA section, splitting any "]]>" in the text.
+func cdata(text string) string {
+	return "<![CDATA[" + strings.ReplaceAll(text, "]]>", "]]]]><![CDATA[>") + "]]>"
+}
+
+// macroParameter renders an ac:parameter with an escaped text value.
+func macroParameter(name, value string) string {
+	return "<ac:parameter" + attr("ac:name", name) + ">" + html.EscapeString(value) + "</ac:parameter>"
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
@@ -0,0 +1,358 @@
+package storagerender
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/rgonek/jira-adf-converter/mdconverter"
+	"github.com/rgonek/jira-adf-converter/storageconverter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRenderer(t *testing.T, cfg Config) *Renderer {
+	t.Helper()
+	r, err := New(cfg)
+	require.NoError(t, err)
+	return r
+}
+
+func TestRenderBasicBlocksAndMarks(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"heading","attrs":{"level":1},"content":[{"type":"text","text":"Title"}]},
+		{"type":"paragraph","marks":[{"type":"alignment","attrs":{"align":"center"}}],"content":[
+			{"type":"text","text":"Bold","marks":[{"type":"strong"}]},
+			{"type":"text","text":" <tag> & "},
+			{"type":"text","text":"red","marks":[{"type":"textColor","attrs":{"color":"#ff0000"}}]}
+		]},
+		{"type":"orderedList","attrs":{"order":3},"content":[
+			{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"three"}]}]}
+		]},
+		{"type":"codeBlock","attrs":{"language":"golang"},"content":[{"type":"text","text":"a := \"]]>\""}]}
+	]}`)
+
+	result, err := newTestRenderer(t, Config{LanguageMap: map[string]string{"golang": "go"}}).Render(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "<h1>Title</h1>\n"+
+		`<p style="text-align: center;"><strong>Bold</strong> &lt;tag&gt; &amp; <span style="color: #ff0000;">red</span></p>`+"\n"+
+		"<ol start=\"3\">\n<li>three</li>\n</ol>\n"+
+		`<ac:structured-macro ac:name="code" ac:schema-version="1"><ac:parameter ac:name="language">go</ac:parameter>`+
+		`<ac:plain-text-body><![CDATA[a := "]]]]><![CDATA[>"]]></ac:plain-text-body></ac:structured-macro>`+"\n", result.Storage)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestRenderPanelsExpandStatusAndMention(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"panel","attrs":{"panelType":"success","title":"Done"},"content":[
+			{"type":"paragraph","content":[
+				{"type":"status","attrs":{"text":"In Progress","color":"neutral"}},
+				{"type":"text","text":" "},
+				{"type":"mention","attrs":{"id":"u1","text":"Ada"}}
+			]}
+		]},
+		{"type":"panel","attrs":{"panelType":"custom","panelColor":"#FFEEDD"},"content":[
+			{"type":"paragraph","content":[{"type":"text","text":"custom"}]}
+		]},
+		{"type":"expand","attrs":{"title":"More <info>"},"content":[
+			{"type":"paragraph","content":[{"type":"text","text":"hidden"}]}
+		]}
+	]}`)
+
+	result, err := newTestRenderer(t, Config{UserIdentifier: UserKey}).Render(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, `<ac:structured-macro ac:name="tip" ac:schema-version="1"><ac:parameter ac:name="title">Done</ac:parameter><ac:rich-text-body>`+"\n"+
+		`<p><ac:structured-macro ac:name="status" ac:schema-version="1"><ac:parameter ac:name="colour">Grey</ac:parameter><ac:parameter ac:name="title">In Progress</ac:parameter></ac:structured-macro> `+
+		`<ac:link><ri:user ri:userkey="u1" /></ac:link></p>`+"\n"+
+		"</ac:rich-text-body></ac:structured-macro>\n"+
+		`<ac:structured-macro ac:name="panel" ac:schema-version="1"><ac:parameter ac:name="bgColor">#FFEEDD</ac:parameter><ac:rich-text-body>`+"\n"+
+		"<p>custom</p>\n</ac:rich-text-body></ac:structured-macro>\n"+
+		`<ac:structured-macro ac:name="expand" ac:schema-version="1"><ac:parameter ac:name="title">More &lt;info&gt;</ac:parameter><ac:rich-text-body>`+"\n"+
+		"<p>hidden</p>\n</ac:rich-text-body></ac:structured-macro>\n", result.Storage)
+}
+
+func TestRenderTaskListsNumberTasksAcrossDocument(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"taskList","content":[
+			{"type":"taskItem","attrs":{"state":"DONE"},"content":[{"type":"text","text":"Write"}]},
+			{"type":"taskList","content":[
+				{"type":"taskItem","attrs":{"state":"TODO"},"content":[{"type":"text","text":"Review"}]}
+			]}
+		]},
+		{"type":"taskList","content":[
+			{"type":"taskItem","attrs":{"state":"TODO"},"content":[{"type":"text","text":"Ship"}]}
+		]}
+	]}`)
+
+	result, err := newTestRenderer(t, Config{}).Render(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "<ac:task-list>\n"+
+		"<ac:task>\n<ac:task-id>1</ac:task-id>\n<ac:task-status>complete</ac:task-status>\n<ac:task-body>Write\n"+
+		"<ac:task-list>\n<ac:task>\n<ac:task-id>2</ac:task-id>\n<ac:task-status>incomplete</ac:task-status>\n<ac:task-body>Review</ac:task-body>\n</ac:task>\n</ac:task-list>\n"+
+		"</ac:task-body>\n</ac:task>\n</ac:task-list>\n"+
+		"<ac:task-list>\n<ac:task>\n<ac:task-id>3</ac:task-id>\n<ac:task-status>incomplete</ac:task-status>\n<ac:task-body>Ship</ac:task-body>\n</ac:task>\n</ac:task-list>\n", result.Storage)
+}
+
+func TestRenderLayoutWrapsTopLevelBlocks(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"paragraph","content":[{"type":"text","text":"intro"}]},
+		{"type":"layoutSection","content":[
+			{"type":"layoutColumn","attrs":{"width":33.33},"content":[{"type":"paragraph","content":[{"type":"text","text":"side"}]}]},
+			{"type":"layoutColumn","attrs":{"width":66.66},"content":[{"type":"paragraph","content":[{"type":"text","text":"main"}]}]}
+		]}
+	]}`)
+
+	result, err := newTestRenderer(t, Config{}).Render(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "<ac:layout>\n"+
+		"<ac:layout-section ac:type=\"single\">\n<ac:layout-cell>\n<p>intro</p>\n</ac:layout-cell>\n</ac:layout-section>\n"+
+		"<ac:layout-section ac:type=\"two_left_sidebar\">\n<ac:layout-cell>\n<p>side</p>\n</ac:layout-cell>\n<ac:layout-cell>\n<p>main</p>\n</ac:layout-cell>\n</ac:layout-section>\n"+
+		"</ac:layout>\n", result.Storage)
+}
+
+func TestRenderTableWithSpansAndHighlight(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[{"type":"table","content":[
+		{"type":"tableRow","content":[
+			{"type":"tableHeader","attrs":{"colspan":2},"content":[{"type":"paragraph","content":[{"type":"text","text":"Head"}]}]}
+		]},
+		{"type":"tableRow","content":[
+			{"type":"tableCell","attrs":{"background":"#deebff"},"content":[{"type":"paragraph","content":[{"type":"text","text":"a"}]}]},
+			{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"b"}]}]}
+		]}
+	]}]}`)
+
+	result, err := newTestRenderer(t, Config{}).Render(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "<table>\n<tbody>\n"+
+		"<tr>\n<th colspan=\"2\"><p>Head</p>\n</th>\n</tr>\n"+
+		"<tr>\n<td data-highlight-colour=\"#deebff\"><p>a</p>\n</td>\n<td><p>b</p>\n</td>\n</tr>\n"+
+		"</tbody>\n</table>\n", result.Storage)
+}
+
+func TestRenderLinkHookResolvesPagesAndAttachments(t *testing.T) {
+	var inputs []converter.LinkRenderInput
+	cfg := Config{
+		LinkHook: func(ctx context.Context, in converter.LinkRenderInput) (LinkRenderOutput, error) {
+			inputs = append(inputs, in)
+			switch in.Href {
+			case "guide.md#setup":
+				return LinkRenderOutput{PageTitle: "Setup Guide", SpaceKey: "DOC", Anchor: "#setup", Handled: true}, nil
+			case "files/spec.pdf":
+				return LinkRenderOutput{Filename: "spec.pdf", PageTitle: "Specs", Handled: true}, nil
+			}
+			return LinkRenderOutput{}, nil
+		},
+	}
+	input := []byte(`{"type":"doc","content":[{"type":"paragraph","content":[
+		{"type":"text","text":"read ","marks":[{"type":"link","attrs":{"href":"guide.md#setup"}}]},
+		{"type":"text","text":"this","marks":[{"type":"link","attrs":{"href":"guide.md#setup"}},{"type":"strong"}]},
+		{"type":"text","text":", "},
+		{"type":"text","text":"the spec","marks":[{"type":"link","attrs":{"href":"files/spec.pdf"}}]},
+		{"type":"text","text":", "},
+		{"type":"text","text":"below","marks":[{"type":"link","attrs":{"href":"#notes"}}]},
+		{"type":"text","text":" or "},
+		{"type":"text","text":"web","marks":[{"type":"link","attrs":{"href":"https://example.com/?a=1&b=2"}}]}
+	]}]}`)
+
+	result, err := newTestRenderer(t, cfg).RenderWithContext(context.Background(), input, converter.ConvertOptions{SourcePath: "docs/index.md"})
+	require.NoError(t, err)
+
+	assert.Equal(t, `<p><ac:link ac:anchor="setup"><ri:page ri:space-key="DOC" ri:content-title="Setup Guide" /><ac:link-body>read <strong>this</strong></ac:link-body></ac:link>, `+
+		`<ac:link><ri:attachment ri:filename="spec.pdf"><ri:page ri:content-title="Specs" /></ri:attachment><ac:plain-text-link-body><![CDATA[the spec]]></ac:plain-text-link-body></ac:link>, `+
+		`<ac:link ac:anchor="notes"><ac:plain-text-link-body><![CDATA[below]]></ac:plain-text-link-body></ac:link> or `+
+		`<a href="https://example.com/?a=1&amp;b=2">web</a></p>`+"\n", result.Storage)
+	require.Len(t, inputs, 4)
+	assert.Equal(t, "docs/index.md", inputs[0].SourcePath)
+}
+
+func TestRenderLinkHookUnresolvedFollowsResolutionMode(t *testing.T) {
+	hook := func(ctx context.Context, in converter.LinkRenderInput) (LinkRenderOutput, error) {
+		return LinkRenderOutput{}, converter.ErrUnresolved
+	}
+	input := []byte(`{"type":"doc","content":[{"type":"paragraph","content":[
+		{"type":"text","text":"x","marks":[{"type":"link","attrs":{"href":"missing.md"}}]}
+	]}]}`)
+
+	result, err := newTestRenderer(t, Config{LinkHook: hook}).Render(input)
+	require.NoError(t, err)
+	assert.Equal(t, "<p><a href=\"missing.md\">x</a></p>\n", result.Storage)
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, converter.WarningUnresolvedReference, result.Warnings[0].Type)
+
+	_, err = newTestRenderer(t, Config{LinkHook: hook, ResolutionMode: converter.ResolutionStrict}).Render(input)
+	require.ErrorIs(t, err, converter.ErrUnresolved)
+}
+
+func TestRenderLinkHookRejectsInvalidOutput(t *testing.T) {
+	cfg := Config{
+		LinkHook: func(ctx context.Context, in converter.LinkRenderInput) (LinkRenderOutput, error) {
+			return LinkRenderOutput{Href: "https://example.com", PageTitle: "Page", Handled: true}, nil
+		},
+	}
+	input := []byte(`{"type":"doc","content":[{"type":"paragraph","content":[
+		{"type":"text","text":"x","marks":[{"type":"link","attrs":{"href":"page.md"}}]}
+	]}]}`)
+
+	_, err := newTestRenderer(t, cfg).Render(input)
+	require.EqualError(t, err, "invalid link hook output: link render output cannot set href together with pageTitle or filename")
+}
+
+func TestRenderMedia(t *testing.T) {
+	cfg := Config{
+		MediaHook: func(ctx context.Context, in converter.MediaRenderInput) (MediaRenderOutput, error) {
+			if in.ID != "att-1" {
+				return MediaRenderOutput{}, nil
+			}
+			return MediaRenderOutput{Filename: "diagram.png", PageTitle: "Assets", SpaceKey: "OPS", Handled: true}, nil
+		},
+	}
+	input := []byte(`{"type":"doc","content":[
+		{"type":"mediaSingle","attrs":{"layout":"center","width":400,"widthType":"pixel"},"content":[
+			{"type":"media","attrs":{"type":"file","id":"att-1","alt":"Diagram"}}
+		]},
+		{"type":"mediaSingle","content":[{"type":"media","attrs":{"type":"image","url":"images/my%20chart.png"}}]},
+		{"type":"mediaSingle","content":[{"type":"media","attrs":{"type":"image","url":"https://example.com/logo.png","height":50}}]},
+		{"type":"mediaGroup","content":[{"type":"media","attrs":{"type":"file","id":"report.pdf"}}]}
+	]}`)
+
+	result, err := newTestRenderer(t, cfg).Render(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, `<p><ac:structured-macro ac:name="view-file" ac:schema-version="1"><ac:parameter ac:name="name">`+
+		`<ri:attachment ri:filename="diagram.png"><ri:page ri:space-key="OPS" ri:content-title="Assets" /></ri:attachment></ac:parameter></ac:structured-macro></p>`+"\n"+
+		`<p><ac:image><ri:attachment ri:filename="my chart.png" /></ac:image></p>`+"\n"+
+		`<p><ac:image ac:height="50"><ri:url ri:value="https://example.com/logo.png" /></ac:image></p>`+"\n"+
+		`<p><ac:structured-macro ac:name="view-file" ac:schema-version="1"><ac:parameter ac:name="name"><ri:attachment ri:filename="report.pdf" /></ac:parameter></ac:structured-macro></p>`+"\n", result.Storage)
+}
+
+func TestRenderImageAlignmentAndWidth(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"mediaSingle","attrs":{"layout":"wrap-right","width":250,"widthType":"pixel"},"content":[
+			{"type":"media","attrs":{"type":"image","id":"chart.png","alt":"Chart","width":1000}}
+		]}
+	]}`)
+
+	result, err := newTestRenderer(t, Config{}).Render(input)
+	require.NoError(t, err)
+	assert.Equal(t, `<p><ac:image ac:align="right" ac:width="250" ac:alt="Chart"><ri:attachment ri:filename="chart.png" /></ac:image></p>`+"\n", result.Storage)
+}
+
+func TestRenderExtensionsAsMacros(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[
+		{"type":"extension","attrs":{"extensionType":"com.atlassian.confluence.macro.core","extensionKey":"toc","parameters":{
+			"macroParams":{"maxLevel":{"value":"3"},"minLevel":{"value":"1"}},
+			"macroMetadata":{"macroId":{"value":"abc-123"},"schemaVersion":{"value":"2"}}
+		}}},
+		{"type":"bodiedExtension","attrs":{"extensionType":"com.atlassian.confluence.macro.core","extensionKey":"section"},"content":[
+			{"type":"paragraph","content":[{"type":"text","text":"inside"}]}
+		]},
+		{"type":"paragraph","content":[
+			{"type":"inlineExtension","attrs":{"extensionType":"com.example","extensionKey":"jira","parameters":{"key":"PROJ-1"}}}
+		]},
+		{"type":"extension","attrs":{"extensionType":"com.atlassian.confluence.macro.core","extensionKey":"html","text":"<b>raw</b>"}}
+	]}`)
+
+	result, err := newTestRenderer(t, Config{}).Render(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, `<ac:structured-macro ac:name="toc" ac:macro-id="abc-123" ac:schema-version="2">`+
+		`<ac:parameter ac:name="maxLevel">3</ac:parameter><ac:parameter ac:name="minLevel">1</ac:parameter></ac:structured-macro>`+"\n"+
+		`<ac:structured-macro ac:name="section" ac:schema-version="1"><ac:rich-text-body>`+"\n<p>inside</p>\n</ac:rich-text-body></ac:structured-macro>\n"+
+		`<p><ac:structured-macro ac:name="jira" ac:schema-version="1"><ac:parameter ac:name="key">PROJ-1</ac:parameter></ac:structured-macro></p>`+"\n"+
+		`<ac:structured-macro ac:name="html" ac:schema-version="1"><ac:plain-text-body><![CDATA[<b>raw</b>]]></ac:plain-text-body></ac:structured-macro>`+"\n", result.Storage)
+}
+
+func TestRenderInlineNodes(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[{"type":"paragraph","content":[
+		{"type":"emoji","attrs":{"shortName":":thumbsup:"}},
+		{"type":"emoji","attrs":{"shortName":":rocket:","text":"🚀"}},
+		{"type":"text","text":" due "},
+		{"type":"date","attrs":{"timestamp":"1705276800000"}},
+		{"type":"text","text":" see "},
+		{"type":"inlineCard","attrs":{"url":"https://example.com/card"}},
+		{"type":"hardBreak"},
+		{"type":"placeholder","attrs":{"text":"Type here"}}
+	]}]}`)
+
+	result, err := newTestRenderer(t, Config{}).Render(input)
+	require.NoError(t, err)
+
+	assert.Equal(t, `<p><ac:emoticon ac:name="thumbs-up" /><ac:emoticon ac:name="blue-star" ac:emoji-shortname=":rocket:" ac:emoji-fallback="🚀" />`+
+		` due <time datetime="2024-01-15" /> see <a href="https://example.com/card" data-card-appearance="inline">https://example.com/card</a>`+
+		`<br /><ac:placeholder>Type here</ac:placeholder></p>`+"\n", result.Storage)
+}
+
+func TestRenderDecisionListWarns(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[{"type":"decisionList","content":[
+		{"type":"decisionItem","attrs":{"state":"DECIDED"},"content":[{"type":"text","text":"Use Go"}]}
+	]}]}`)
+
+	result, err := newTestRenderer(t, Config{}).Render(input)
+	require.NoError(t, err)
+	assert.Equal(t, "<ul>\n<li>✓ Use Go</li>\n</ul>\n", result.Storage)
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, converter.WarningDroppedFeature, result.Warnings[0].Type)
+}
+
+func TestRenderUnknownNodePolicy(t *testing.T) {
+	input := []byte(`{"type":"doc","content":[{"type":"mystery"}]}`)
+
+	result, err := newTestRenderer(t, Config{}).Render(input)
+	require.NoError(t, err)
+	assert.Equal(t, "[Unknown node: mystery]", result.Storage)
+	require.Len(t, result.Warnings, 1)
+
+	_, err = newTestRenderer(t, Config{UnknownNodes: converter.UnknownError}).Render(input)
+	require.Error(t, err)
+}
+
+func TestRenderHonorsCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := newTestRenderer(t, Config{}).RenderWithContext(ctx, []byte(`{"type":"doc","content":[]}`), converter.ConvertOptions{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRenderMarkdownRoundTripsThroughStorageImport(t *testing.T) {
+	markdown := "# Release\n\n" +
+		"Ship **v2** with `flags` and [docs](https://example.com/docs).\n\n" +
+		"- [x] Write notes\n- [ ] Publish\n\n" +
+		"```go\nfmt.Println(\"hi\")\n```\n\n" +
+		"| A | B |\n| --- | --- |\n| 1 | 2 |\n"
+
+	md, err := mdconverter.New(mdconverter.ReverseConfig{})
+	require.NoError(t, err)
+	parsed, err := md.Convert(markdown)
+	require.NoError(t, err)
+
+	rendered, err := newTestRenderer(t, Config{}).Render(parsed.ADF)
+	require.NoError(t, err)
+
+	importer, err := storageconverter.New(storageconverter.Config{})
+	require.NoError(t, err)
+	imported, err := importer.Convert(rendered.Storage)
+	require.NoError(t, err)
+	assert.Empty(t, imported.Warnings)
+
+	forward, err := converter.New(converter.Config{})
+	require.NoError(t, err)
+	original, err := forward.Convert(parsed.ADF)
+	require.NoError(t, err)
+	roundTripped, err := forward.Convert(imported.ADF)
+	require.NoError(t, err)
+	assert.Equal(t, original.Markdown, roundTripped.Markdown)
+}
+
+func TestConfigValidate(t *testing.T) {
+	_, err := New(Config{UserIdentifier: "email"})
+	require.EqualError(t, err, `invalid userIdentifier "email"`)
+
+	_, err = New(Config{ResolutionMode: "sometimes"})
+	require.EqualError(t, err, `invalid resolutionMode "sometimes"`)
+}
@@ -0,0 +1,591 @@
+package wikiconverter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+)
+
+var (
+	headingRe    = regexp.MustCompile(`^h([1-6])\.(?:\s+(.*))?$`)
+	listItemRe   = regexp.MustCompile(`^([*#]+|-)\s+(.*)$`)
+	blockMacroRe = regexp.MustCompile(`^\{(code|noformat|quote|panel)(?::([^}]*))?\}`)
+)
+
+// panelTypesByColor maps Jira panel background colors to ADF panel types; it is the
+// inverse of the colors used for wiki markup output.
+var panelTypesByColor = map[string]string{
+	"#deebff": "info",
+	"#eae6ff": "note",
+	"#e3fcef": "success",
+	"#fffae6": "warning",
+	"#ffebe6": "error",
+}
+
+// wikiListLine is one line of a wiki list with its marker prefix, e.g. "*#".
+type wikiListLine struct {
+	markers string
+	text    string
+}
+
+// wikiCell is one cell of a wiki table row.
+type wikiCell struct {
+	header bool
+	text   string
+}
+
+// parseBlocks parses lines of wiki markup into ADF block nodes.
+func (s *state) parseBlocks(lines []string) ([]converter.Node, error) {
+	var blocks []converter.Node
+	for i := 0; i < len(lines); {
+		if err := s.checkContext(); err != nil {
+			return nil, err
+		}
+
+		line := strings.TrimSpace(lines[i])
+		switch {
+		case line == "":
+			i++
+
+		case blockMacroRe.MatchString(line):
+			nodes, next, err := s.parseBlockMacro(lines, i)
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, nodes...)
+			i = next
+
+		case headingRe.MatchString(line):
+			node, err := s.parseHeading(line)
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, node)
+			i++
+
+		case isBlockQuoteLine(line):
+			content, err := s.parseParagraph(strings.TrimSpace(strings.TrimPrefix(line, "bq.")))
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, converter.Node{Type: "blockquote", Content: content})
+			i++
+
+		case line == "----":
+			blocks = append(blocks, converter.Node{Type: "rule"})
+			i++
+
+		case listItemRe.MatchString(line):
+			nodes, next, err := s.parseList(lines, i)
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, nodes...)
+			i = next
+
+		case strings.HasPrefix(line, "|"):
+			node, next, err := s.parseTable(lines, i)
+			if err != nil {
+				return nil, err
+			}
+			if node.Type != "" {
+				blocks = append(blocks, node)
+			}
+			i = next
+
+		default:
+			paragraph := []string{line}
+			i++
+			for i < len(lines) && strings.TrimSpace(lines[i]) != "" && !isBlockStart(strings.TrimSpace(lines[i])) {
+				paragraph = append(paragraph, strings.TrimSpace(lines[i]))
+				i++
+			}
+			content, err := s.parseParagraph(strings.Join(paragraph, "\n"))
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, content...)
+		}
+	}
+	return blocks, nil
+}
+
+func isBlockQuoteLine(line string) bool {
+	return line == "bq." || strings.HasPrefix(line, "bq. ")
+}
+
+// isBlockStart reports whether a trimmed line starts a block other than a paragraph.
+func isBlockStart(line string) bool {
+	return blockMacroRe.MatchString(line) ||
+		headingRe.MatchString(line) ||
+		isBlockQuoteLine(line) ||
+		line == "----" ||
+		listItemRe.MatchString(line) ||
+		strings.HasPrefix(line, "|")
+}
+
+// parseParagraph parses inline wiki markup into paragraphs. Images and attachments
+// become mediaSingle blocks between the surrounding paragraphs.
+func (s *state) parseParagraph(text string) ([]converter.Node, error) {
+	content, err := s.parseInline(text, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var blocks []converter.Node
+	var pending []converter.Node
+	flush := func() {
+		if trimmed := trimInline(pending); len(trimmed) > 0 {
+			blocks = append(blocks, converter.Node{Type: "paragraph", Content: trimmed})
+		}
+		pending = nil
+	}
+	for _, node := range content {
+		if node.Type == "mediaSingle" {
+			flush()
+			blocks = append(blocks, node)
+			continue
+		}
+		pending = append(pending, node)
+	}
+	flush()
+	return blocks, nil
+}
+
+func (s *state) parseHeading(line string) (converter.Node, error) {
+	match := headingRe.FindStringSubmatch(line)
+	level := min(max(int(match[1][0]-'0')+s.config.HeadingOffset, 1), 6)
+
+	content, err := s.parseInline(match[2], nil)
+	if err != nil {
+		return converter.Node{}, err
+	}
+	for idx, node := range content {
+		if node.Type == "mediaSingle" {
+			s.addWarning(
+				converter.WarningDroppedFeature,
+				node.Type,
+				"image inside heading; converted to placeholder text",
+			)
+			content[idx] = newTextNode("[Embedded content]", nil)
+		}
+	}
+
+	return converter.Node{
+		Type: "heading",
+		Attrs: map[string]interface{}{
+			"level": level,
+		},
+		Content: trimInline(content),
+	}, nil
+}
+
+// parseBlockMacro parses a {code}, {noformat}, {quote} or {panel} macro starting at
+// lines[start]. Text after the closing tag is left in place as the next line to parse.
+func (s *state) parseBlockMacro(lines []string, start int) ([]converter.Node, int, error) {
+	line := strings.TrimSpace(lines[start])
+	match := blockMacroRe.FindStringSubmatch(line)
+	name, params := match[1], match[2]
+	closing := "{" + name + "}"
+
+	var body []string
+	next := len(lines)
+	closed := false
+	rest := line[len(match[0]):]
+	for i := start; i < len(lines); i++ {
+		current := lines[i]
+		if i == start {
+			current = rest
+		}
+		if idx := strings.Index(current, closing); idx >= 0 {
+			if prefix := current[:idx]; strings.TrimSpace(prefix) != "" {
+				body = append(body, prefix)
+			}
+			if remainder := current[idx+len(closing):]; strings.TrimSpace(remainder) != "" {
+				lines[i] = remainder
+				next = i
+			} else {
+				next = i + 1
+			}
+			closed = true
+			break
+		}
+		if i > start || strings.TrimSpace(current) != "" {
+			body = append(body, current)
+		}
+	}
+	if !closed {
+		s.addWarning(converter.WarningDroppedFeature, name, fmt.Sprintf("unclosed {%s} macro; closed at end of input", name))
+	}
+
+	switch name {
+	case "code", "noformat":
+		return []converter.Node{s.codeBlock(name, params, body)}, next, nil
+
+	case "quote":
+		content, err := s.parseBlocks(body)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(content) == 0 {
+			return nil, next, nil
+		}
+		return []converter.Node{{Type: "blockquote", Content: content}}, next, nil
+
+	default:
+		content, err := s.parseBlocks(body)
+		if err != nil {
+			return nil, 0, err
+		}
+		return []converter.Node{s.panel(params, content)}, next, nil
+	}
+}
+
+func (s *state) codeBlock(name, params string, body []string) converter.Node {
+	codeBlock := converter.Node{Type: "codeBlock"}
+
+	if name == "code" {
+		positional, named := parseMacroParams(params)
+		language := strings.ToLower(firstNonEmpty(named["language"], positional))
+		if mapped, ok := s.config.LanguageMap[language]; ok {
+			language = mapped
+		}
+		if language != "" && language != "none" {
+			codeBlock.Attrs = map[string]interface{}{
+				"language": language,
+			}
+		}
+		if title := named["title"]; title != "" {
+			s.addWarning(converter.WarningDroppedFeature, "codeBlock", fmt.Sprintf("code block title %q dropped", title))
+		}
+	}
+
+	if text := strings.Join(body, "\n"); text != "" {
+		codeBlock.Content = []converter.Node{
+			{
+				Type: "text",
+				Text: text,
+			},
+		}
+	}
+	return codeBlock
+}
+
+// panel builds an ADF panel. The panel type follows bgColor, or a title naming a panel
+// type; other colors become a custom panel.
+func (s *state) panel(params string, content []converter.Node) converter.Node {
+	_, named := parseMacroParams(params)
+	title := named["title"]
+	color := strings.ToLower(named["bgcolor"])
+
+	attrs := map[string]interface{}{}
+	panelType, ok := panelTypesByColor[color]
+	switch {
+	case ok:
+	case isPanelTypeName(title) && color == "":
+		panelType = strings.ToLower(title)
+	case color != "":
+		if normalized, valid := normalizeColor(color); valid {
+			panelType = "custom"
+			attrs["panelColor"] = normalized
+		} else {
+			s.addWarning(converter.WarningDroppedFeature, "panel", fmt.Sprintf("panel color %q dropped", named["bgcolor"]))
+			panelType = "info"
+		}
+	default:
+		panelType = "info"
+	}
+	attrs["panelType"] = panelType
+	if title != "" && !strings.EqualFold(title, panelType) {
+		attrs["title"] = title
+	}
+
+	if content == nil {
+		content = []converter.Node{}
+	}
+	return converter.Node{
+		Type:    "panel",
+		Attrs:   attrs,
+		Content: content,
+	}
+}
+
+func isPanelTypeName(value string) bool {
+	switch strings.ToLower(value) {
+	case "info", "note", "success", "warning", "error":
+		return true
+	default:
+		return false
+	}
+}
+
+// parseMacroParams splits macro parameters such as "java|title=Example" into the first
+// positional value and named values keyed by lower-case name.
+func parseMacroParams(params string) (string, map[string]string) {
+	positional := ""
+	named := map[string]string{}
+	for _, part := range strings.Split(params, "|") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			if positional == "" {
+				positional = part
+			}
+			continue
+		}
+		named[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+	return positional, named
+}
+
+// parseList parses consecutive list lines starting at lines[start]. Non-list lines
+// directly after an item continue it after a line break.
+func (s *state) parseList(lines []string, start int) ([]converter.Node, int, error) {
+	var items []wikiListLine
+	i := start
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			break
+		}
+		if match := listItemRe.FindStringSubmatch(line); match != nil {
+			items = append(items, wikiListLine{markers: match[1], text: match[2]})
+			continue
+		}
+		if isBlockStart(line) {
+			break
+		}
+		items[len(items)-1].text += "\n" + line
+	}
+
+	nodes, err := s.buildLists(items, 0)
+	return nodes, i, err
+}
+
+// buildLists builds the lists at one nesting depth. Lines with longer marker prefixes
+// nest under the preceding item; a change of marker starts a new list.
+func (s *state) buildLists(items []wikiListLine, depth int) ([]converter.Node, error) {
+	var lists []converter.Node
+	var current *converter.Node
+	for k := 0; k < len(items); {
+		item := items[k]
+		if len(item.markers) > depth+1 {
+			end := k + 1
+			for end < len(items) && len(items[end].markers) > depth+1 {
+				end++
+			}
+			nested, err := s.buildLists(items[k:end], depth+1)
+			if err != nil {
+				return nil, err
+			}
+			if current == nil {
+				lists = append(lists, newList(item.markers[depth]))
+				current = &lists[len(lists)-1]
+			}
+			if len(current.Content) == 0 {
+				current.Content = append(current.Content, converter.Node{
+					Type:    "listItem",
+					Content: []converter.Node{{Type: "paragraph"}},
+				})
+			}
+			last := &current.Content[len(current.Content)-1]
+			last.Content = append(last.Content, nested...)
+			k = end
+			continue
+		}
+
+		if current == nil || listType(item.markers[depth]) != current.Type {
+			lists = append(lists, newList(item.markers[depth]))
+			current = &lists[len(lists)-1]
+		}
+		content, err := s.parseParagraph(item.text)
+		if err != nil {
+			return nil, err
+		}
+		if len(content) == 0 {
+			content = []converter.Node{{Type: "paragraph"}}
+		}
+		current.Content = append(current.Content, converter.Node{
+			Type:    "listItem",
+			Content: content,
+		})
+		k++
+	}
+	return lists, nil
+}
+
+func listType(marker byte) string {
+	if marker == '#' {
+		return "orderedList"
+	}
+	return "bulletList"
+}
+
+func newList(marker byte) converter.Node {
+	list := converter.Node{Type: listType(marker)}
+	if list.Type == "orderedList" {
+		list.Attrs = map[string]interface{}{
+			"order": 1,
+		}
+	}
+	return list
+}
+
+// parseTable parses consecutive table rows starting at lines[start]. A row that does
+// not end with a cell delimiter continues on the following lines.
+func (s *state) parseTable(lines []string, start int) (converter.Node, int, error) {
+	table := converter.Node{Type: "table"}
+	i := start
+	for i < len(lines) {
+		row := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(row, "|") {
+			break
+		}
+		i++
+		for !strings.HasSuffix(row, "|") && i < len(lines) {
+			next := strings.TrimSpace(lines[i])
+			if next == "" || strings.HasPrefix(next, "|") {
+				break
+			}
+			row += "\n" + next
+			i++
+		}
+
+		converted, err := s.parseTableRow(row)
+		if err != nil {
+			return converter.Node{}, 0, err
+		}
+		if len(converted.Content) > 0 {
+			table.Content = append(table.Content, converted)
+		}
+	}
+
+	if len(table.Content) == 0 {
+		return converter.Node{}, i, nil
+	}
+	return table, i, nil
+}
+
+func (s *state) parseTableRow(row string) (converter.Node, error) {
+	node := converter.Node{Type: "tableRow"}
+	for _, cell := range splitTableCells(row) {
+		content, err := s.parseBlocks(strings.Split(strings.TrimSpace(cell.text), "\n"))
+		if err != nil {
+			return converter.Node{}, err
+		}
+		if len(content) == 0 {
+			content = []converter.Node{{Type: "paragraph"}}
+		}
+
+		cellType := "tableCell"
+		if cell.header {
+			cellType = "tableHeader"
+		}
+		node.Content = append(node.Content, converter.Node{
+			Type:    cellType,
+			Content: content,
+		})
+	}
+	return node, nil
+}
+
+// splitTableCells splits a row on | and || delimiters outside links, images and
+// macros. Escaped characters are kept for inline parsing.
+func splitTableCells(row string) []wikiCell {
+	var cells []wikiCell
+	var current *wikiCell
+	var text strings.Builder
+	bracketDepth, braceDepth := 0, 0
+
+	for i := 0; i < len(row); i++ {
+		c := row[i]
+		switch {
+		case c == '\\' && i+1 < len(row):
+			text.WriteByte(c)
+			text.WriteByte(row[i+1])
+			i++
+			continue
+		case c == '[':
+			bracketDepth++
+		case c == ']' && bracketDepth > 0:
+			bracketDepth--
+		case c == '{':
+			braceDepth++
+		case c == '}' && braceDepth > 0:
+			braceDepth--
+		case c == '!' && bracketDepth == 0 && braceDepth == 0:
+			// Image parameters such as !a.png|thumbnail! do not end the cell.
+			if end := imageEnd(row, i); end > 0 {
+				text.WriteString(row[i : end+1])
+				i = end
+				continue
+			}
+		case c == '|' && bracketDepth == 0 && braceDepth == 0:
+			if current != nil {
+				current.text = text.String()
+				cells = append(cells, *current)
+			}
+			text.Reset()
+			current = &wikiCell{}
+			if i+1 < len(row) && row[i+1] == '|' {
+				current.header = true
+				i++
+			}
+			continue
+		}
+		text.WriteByte(c)
+	}
+
+	if current != nil && text.Len() > 0 {
+		current.text = text.String()
+		cells = append(cells, *current)
+	}
+	return cells
+}
+
+// trimInline removes leading and trailing line breaks and whitespace from inline content.
+func trimInline(content []converter.Node) []converter.Node {
+	for len(content) > 0 && content[0].Type == "hardBreak" {
+		content = content[1:]
+	}
+	for len(content) > 0 && content[len(content)-1].Type == "hardBreak" {
+		content = content[:len(content)-1]
+	}
+	if len(content) == 0 {
+		return nil
+	}
+
+	trimmed := append([]converter.Node(nil), content...)
+	if first := &trimmed[0]; first.Type == "text" {
+		first.Text = strings.TrimLeft(first.Text, " \t")
+	}
+	if last := &trimmed[len(trimmed)-1]; last.Type == "text" {
+		last.Text = strings.TrimRight(last.Text, " \t")
+	}
+
+	result := trimmed[:0]
+	for _, node := range trimmed {
+		if node.Type == "text" && node.Text == "" {
+			continue
+		}
+		result = append(result, node)
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
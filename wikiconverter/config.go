@@ -0,0 +1,66 @@
+package wikiconverter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/rgonek/jira-adf-converter/mdconverter"
+)
+
+// Config configures Jira wiki markup to ADF conversion behavior.
+type Config struct {
+	HeadingOffset int               `json:"headingOffset,omitempty"`
+	LanguageMap   map[string]string `json:"languageMap,omitempty"`
+	MediaBaseURL  string            `json:"mediaBaseURL,omitempty"`
+	// MentionRegistry maps Jira Server user names from [~name] mentions to account IDs.
+	MentionRegistry map[string]string          `json:"mentionRegistry,omitempty"`
+	ResolutionMode  converter.ResolutionMode   `json:"resolutionMode,omitempty"`
+	LinkHook        mdconverter.LinkParseHook  `json:"-"`
+	MediaHook       mdconverter.MediaParseHook `json:"-"`
+}
+
+func (c Config) applyDefaults() Config {
+	if c.ResolutionMode == "" {
+		c.ResolutionMode = converter.ResolutionBestEffort
+	}
+	return c
+}
+
+// clone returns a deep copy of Config for map-backed fields.
+func (c Config) clone() Config {
+	cloned := c
+	cloned.LanguageMap = cloneStringMap(c.LanguageMap)
+	cloned.MentionRegistry = cloneStringMap(c.MentionRegistry)
+	return cloned
+}
+
+// Validate checks that config values are valid.
+func (c Config) Validate() error {
+	if c.HeadingOffset < -5 || c.HeadingOffset > 5 {
+		return fmt.Errorf("headingOffset must be between -5 and 5, got %d", c.HeadingOffset)
+	}
+	if c.ResolutionMode != converter.ResolutionBestEffort && c.ResolutionMode != converter.ResolutionStrict {
+		return fmt.Errorf("invalid resolutionMode %q", c.ResolutionMode)
+	}
+	for name, id := range c.MentionRegistry {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("mentionRegistry contains empty key")
+		}
+		if strings.TrimSpace(id) == "" {
+			return fmt.Errorf("mentionRegistry[%q] is empty", name)
+		}
+	}
+	return nil
+}
+
+func cloneStringMap(src map[string]string) map[string]string {
+	if src == nil {
+		return nil
+	}
+	cloned := make(map[string]string, len(src))
+	for key, value := range src {
+		cloned[key] = value
+	}
+	return cloned
+}
@@ -0,0 +1,97 @@
+package wikiconverter
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func goldenConfigForPath(path string) Config {
+	cfg := Config{
+		MentionRegistry: map[string]string{
+			"jsmith": "12345",
+		},
+	}
+
+	base := filepath.Base(path)
+	if strings.Contains(base, "language_map") {
+		cfg.LanguageMap = map[string]string{
+			"c#": "csharp",
+		}
+	}
+	if strings.Contains(base, "media_baseurl") {
+		cfg.MediaBaseURL = "https://jira.example.com/secure/attachment/"
+	}
+
+	return cfg
+}
+
+func TestGoldenFiles(t *testing.T) {
+	fixtures := []string{
+		"blocks/headings",
+		"blocks/paragraphs",
+		"blocks/quote",
+		"blocks/rule",
+		"blocks/panel",
+		"blocks/panel_custom_color",
+		"codeblocks/code",
+		"codeblocks/code_params",
+		"codeblocks/noformat",
+		"codeblocks/language_map",
+		"lists/bullet",
+		"lists/ordered",
+		"lists/mixed_nested",
+		"lists/continuation",
+		"tables/header_row",
+		"tables/header_column",
+		"tables/multiline_cell",
+		"marks/formatting",
+		"marks/color",
+		"marks/escapes",
+		"inline/links",
+		"inline/mentions",
+		"inline/emoticons",
+		"media/images",
+		"media/attachments",
+		"media/media_baseurl",
+	}
+
+	for _, fixture := range fixtures {
+		fixture := fixture
+		t.Run(fixture, func(t *testing.T) {
+			wikiPath := filepath.Join("testdata", filepath.FromSlash(fixture+".wiki"))
+			jsonPath := filepath.Join("testdata", filepath.FromSlash(fixture+".json"))
+
+			markup, err := os.ReadFile(wikiPath)
+			require.NoError(t, err)
+
+			conv, err := New(goldenConfigForPath(wikiPath))
+			require.NoError(t, err)
+			result, err := conv.Convert(string(markup))
+			require.NoError(t, err)
+
+			if *update {
+				formatted, err := json.MarshalIndent(result.Doc, "", "  ")
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(jsonPath, append(formatted, '\n'), 0644))
+			}
+
+			expectedJSON, err := os.ReadFile(jsonPath)
+			require.NoError(t, err)
+
+			var actualDoc, expectedDoc converter.Doc
+			require.NoError(t, json.Unmarshal(result.ADF, &actualDoc))
+			require.NoError(t, json.Unmarshal(expectedJSON, &expectedDoc))
+			assert.Equal(t, expectedDoc, actualDoc)
+		})
+	}
+}
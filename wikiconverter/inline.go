@@ -0,0 +1,716 @@
+package wikiconverter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/rgonek/jira-adf-converter/mdconverter"
+)
+
+var (
+	colorMacroRe  = regexp.MustCompile(`^\{color:([^}]*)\}`)
+	inlineMacroRe = regexp.MustCompile(`^\{([a-zA-Z]+)(?::[^}]*)?\}`)
+	hexColorRe    = regexp.MustCompile(`^#(?:[0-9a-f]{3}|[0-9a-f]{6})$`)
+	entityRe      = regexp.MustCompile(`^&#(?:([0-9]{1,7})|[xX]([0-9a-fA-F]{1,6}));`)
+)
+
+// wikiEmoticons maps Jira emoticons to emoji short names, longest match first.
+var wikiEmoticons = []struct {
+	emoticon  string
+	shortName string
+}{
+	{"(*y)", ":star:"},
+	{"(off)", ":bulb:"},
+	{"(on)", ":bulb:"},
+	{"(y)", ":thumbsup:"},
+	{"(n)", ":thumbsdown:"},
+	{"(i)", ":information_source:"},
+	{"(/)", ":white_check_mark:"},
+	{"(x)", ":x:"},
+	{"(!)", ":warning:"},
+	{"(+)", ":heavy_plus_sign:"},
+	{"(-)", ":heavy_minus_sign:"},
+	{"(?)", ":question:"},
+	{"(*)", ":star:"},
+	{":)", ":slight_smile:"},
+	{":(", ":disappointed:"},
+	{":P", ":stuck_out_tongue:"},
+	{":D", ":smiley:"},
+	{";)", ":wink:"},
+}
+
+// namedColors maps the color names accepted by the Jira {color} macro to hex values.
+var namedColors = map[string]string{
+	"black":   "#000000",
+	"white":   "#ffffff",
+	"red":     "#ff0000",
+	"green":   "#008000",
+	"blue":    "#0000ff",
+	"yellow":  "#ffff00",
+	"orange":  "#ffa500",
+	"purple":  "#800080",
+	"gray":    "#808080",
+	"grey":    "#808080",
+	"silver":  "#c0c0c0",
+	"maroon":  "#800000",
+	"navy":    "#000080",
+	"teal":    "#008080",
+	"olive":   "#808000",
+	"lime":    "#00ff00",
+	"aqua":    "#00ffff",
+	"fuchsia": "#ff00ff",
+}
+
+// formattingMarks maps wiki formatting delimiters to ADF marks.
+var formattingMarks = map[string]converter.Mark{
+	"*":  {Type: "strong"},
+	"_":  {Type: "em"},
+	"??": {Type: "em"},
+	"-":  {Type: "strike"},
+	"+":  {Type: "underline"},
+	"^":  {Type: "subsup", Attrs: map[string]interface{}{"type": "sup"}},
+	"~":  {Type: "subsup", Attrs: map[string]interface{}{"type": "sub"}},
+}
+
+// parseInline parses inline wiki markup with the given active marks. Newlines become
+// hard breaks; images and attachments are returned as mediaSingle nodes for the caller
+// to place between paragraphs.
+func (s *state) parseInline(text string, marks []converter.Mark) ([]converter.Node, error) {
+	var content []converter.Node
+	var buf strings.Builder
+	emit := func(nodes ...converter.Node) {
+		if buf.Len() > 0 {
+			content = appendInlineNode(content, newTextNode(buf.String(), marks))
+			buf.Reset()
+		}
+		for _, node := range nodes {
+			content = appendInlineNode(content, node)
+		}
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '\\' && i+1 < len(text):
+			if text[i+1] == '\\' {
+				emit(converter.Node{Type: "hardBreak"})
+				i += 2
+				continue
+			}
+			if isEscapable(text[i+1]) {
+				buf.WriteByte(text[i+1])
+				i += 2
+				continue
+			}
+
+		case c == '&':
+			if r, consumed := matchEntity(text[i:]); consumed > 0 {
+				buf.WriteRune(r)
+				i += consumed
+				continue
+			}
+
+		case c == '\n':
+			emit(converter.Node{Type: "hardBreak"})
+			i++
+			continue
+
+		case c == '{':
+			nodes, consumed, err := s.parseInlineMacro(text[i:], marks)
+			if err != nil {
+				return nil, err
+			}
+			if consumed > 0 {
+				emit(nodes...)
+				i += consumed
+				continue
+			}
+
+		case c == '[':
+			nodes, consumed, err := s.parseBracket(text[i:], marks)
+			if err != nil {
+				return nil, err
+			}
+			if consumed > 0 {
+				emit(nodes...)
+				i += consumed
+				continue
+			}
+
+		case c == '!':
+			if end := imageEnd(text, i); end > 0 {
+				node, err := s.parseImage(text[i+1 : end])
+				if err != nil {
+					return nil, err
+				}
+				emit(node)
+				i = end + 1
+				continue
+			}
+
+		case (c == 'h' || c == 'H') && !isWordBefore(text, i) && hasURLPrefix(text[i:]):
+			end := urlEnd(text, i)
+			nodes, err := s.parseLink(text[i:end], text[i:end], "", marks)
+			if err != nil {
+				return nil, err
+			}
+			emit(nodes...)
+			i = end
+			continue
+		}
+
+		if shortName, consumed := matchEmoticon(text, i); consumed > 0 {
+			emit(converter.Node{
+				Type: "emoji",
+				Attrs: map[string]interface{}{
+					"shortName": shortName,
+				},
+			})
+			i += consumed
+			continue
+		}
+
+		if delimiter, end := matchFormatting(text, i); end > 0 {
+			inner, err := s.parseInline(text[i+len(delimiter):end], withMark(marks, formattingMarks[delimiter]))
+			if err != nil {
+				return nil, err
+			}
+			emit(inner...)
+			i = end + len(delimiter)
+			continue
+		}
+
+		buf.WriteByte(c)
+		i++
+	}
+	emit()
+	return content, nil
+}
+
+// parseInlineMacro parses {{monospace}}, {color} and other inline macros at the start
+// of text. It returns the number of bytes consumed, or zero for literal text.
+func (s *state) parseInlineMacro(text string, marks []converter.Mark) ([]converter.Node, int, error) {
+	if strings.HasPrefix(text, "{{") {
+		end := strings.Index(text[2:], "}}")
+		if end <= 0 {
+			return nil, 0, nil
+		}
+		code := unescape(text[2 : 2+end])
+		return []converter.Node{newTextNode(code, withMark(marks, converter.Mark{Type: "code"}))}, end + 4, nil
+	}
+
+	// {*}bold{*} and the other braced forms let formatting start or end inside a word.
+	if len(text) >= 3 && text[2] == '}' {
+		if mark, ok := formattingMarks[text[1:2]]; ok {
+			if end := strings.Index(text[3:], text[:3]); end > 0 {
+				nodes, err := s.parseInline(text[3:3+end], withMark(marks, mark))
+				return nodes, end + 6, err
+			}
+		}
+	}
+
+	if match := colorMacroRe.FindStringSubmatch(text); match != nil {
+		body := text[len(match[0]):]
+		consumed := len(text)
+		if end := strings.Index(body, "{color}"); end >= 0 {
+			body = body[:end]
+			consumed = len(match[0]) + end + len("{color}")
+		}
+
+		innerMarks := marks
+		if color, ok := normalizeColor(match[1]); ok {
+			innerMarks = withMark(marks, converter.Mark{
+				Type: "textColor",
+				Attrs: map[string]interface{}{
+					"color": color,
+				},
+			})
+		} else {
+			s.addWarning(converter.WarningDroppedFeature, "textColor", fmt.Sprintf("invalid color value dropped: %q", match[1]))
+		}
+		nodes, err := s.parseInline(body, innerMarks)
+		return nodes, consumed, err
+	}
+
+	match := inlineMacroRe.FindStringSubmatch(text)
+	if match == nil {
+		return nil, 0, nil
+	}
+	switch strings.ToLower(match[1]) {
+	case "color":
+		// A closing {color} without an opening macro.
+		return nil, len(match[0]), nil
+	case "anchor":
+		// ADF has no anchors; headings are linkable by their text.
+		return nil, len(match[0]), nil
+	default:
+		s.addWarning(converter.WarningUnknownNode, match[1], fmt.Sprintf("unsupported macro kept as text: %s", match[0]))
+		return []converter.Node{newTextNode(match[0], marks)}, len(match[0]), nil
+	}
+}
+
+// parseBracket parses [~user] mentions, [^attachment] files and [text|url|tooltip]
+// links at the start of text. It returns zero consumed bytes for literal brackets.
+func (s *state) parseBracket(text string, marks []converter.Mark) ([]converter.Node, int, error) {
+	end := indexUnescaped(text, 1, ']')
+	if end < 0 {
+		return nil, 0, nil
+	}
+	inner := text[1:end]
+	if strings.TrimSpace(inner) == "" || strings.Contains(inner, "\n") {
+		return nil, 0, nil
+	}
+
+	switch inner[0] {
+	case '~':
+		name := strings.TrimSpace(inner[1:])
+		id, err := s.resolveMention(name)
+		if err != nil {
+			return nil, 0, err
+		}
+		attrs := map[string]interface{}{
+			"id": id,
+		}
+		if !strings.HasPrefix(name, "accountid:") {
+			attrs["text"] = name
+		}
+		return []converter.Node{{Type: "mention", Attrs: attrs}}, end + 1, nil
+
+	case '^':
+		node, err := s.parseAttachment(strings.TrimSpace(inner[1:]))
+		return []converter.Node{node}, end + 1, err
+	}
+
+	parts := splitUnescaped(inner, '|')
+	linkText, destination, title := "", strings.TrimSpace(parts[0]), ""
+	if len(parts) > 1 {
+		linkText, destination = parts[0], strings.TrimSpace(parts[1])
+	}
+	if len(parts) > 2 {
+		title = strings.TrimSpace(parts[2])
+	}
+	if destination == "" || (len(parts) == 1 && !isLinkDestination(destination)) {
+		return nil, 0, nil
+	}
+	if len(parts) == 1 && strings.HasPrefix(destination, "#") {
+		// Anchor links show the anchor name.
+		linkText = destination[1:]
+	}
+
+	nodes, err := s.parseLink(linkText, destination, title, marks)
+	return nodes, end + 1, err
+}
+
+// parseLink builds a link mark over the parsed link text, or an inlineCard for links
+// whose text is the destination, following the same rules as Markdown import.
+func (s *state) parseLink(linkText, destination, title string, marks []converter.Mark) ([]converter.Node, error) {
+	plainText := strings.TrimSpace(unescape(linkText))
+	forceLink, forceCard := false, false
+
+	filename, anchor := referenceMetadata(destination)
+	hookOutput, handled, err := s.applyLinkHook(mdconverter.LinkParseInput{
+		SourcePath:  s.options.SourcePath,
+		Destination: destination,
+		Title:       title,
+		Text:        plainText,
+		Meta:        mdconverter.LinkMetadata{Filename: filename, Anchor: anchor},
+		Raw: map[string]any{
+			"kind":  "link",
+			"title": title,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if handled {
+		destination = hookOutput.Destination
+		title = hookOutput.Title
+		forceLink = hookOutput.ForceLink
+		forceCard = hookOutput.ForceCard
+	}
+
+	if forceCard || (!forceLink && title == "" && (plainText == "" || plainText == destination)) {
+		return []converter.Node{
+			{
+				Type: "inlineCard",
+				Attrs: map[string]interface{}{
+					"url": destination,
+				},
+			},
+		}, nil
+	}
+
+	mark := converter.Mark{
+		Type: "link",
+		Attrs: map[string]interface{}{
+			"href": destination,
+		},
+	}
+	if title != "" {
+		mark.Attrs["title"] = title
+	}
+	if strings.TrimSpace(linkText) == "" {
+		return []converter.Node{newTextNode(destination, withMark(marks, mark))}, nil
+	}
+	return s.parseInline(strings.TrimSpace(linkText), withMark(marks, mark))
+}
+
+// parseImage parses the body of an !image.png|thumbnail,alt=...! embed.
+func (s *state) parseImage(body string) (converter.Node, error) {
+	source, rawParams, _ := strings.Cut(body, "|")
+	source = strings.TrimSpace(source)
+
+	params := map[string]string{}
+	for _, param := range strings.Split(rawParams, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			params[key] = strings.TrimSpace(value)
+		}
+	}
+
+	filename, anchor := referenceMetadata(source)
+	raw := map[string]any{
+		"kind": "image",
+	}
+	if len(params) > 0 {
+		raw["params"] = params
+	}
+	node, err := s.mediaNode("image", source, params["alt"], mdconverter.MediaParseInput{
+		SourcePath:  s.options.SourcePath,
+		Destination: source,
+		Alt:         params["alt"],
+		Meta:        mdconverter.MediaMetadata{Filename: filename, Anchor: anchor},
+		Raw:         raw,
+	})
+	if err != nil {
+		return converter.Node{}, err
+	}
+
+	media := &node.Content[0]
+	for _, dimension := range []string{"width", "height"} {
+		if value, err := strconv.Atoi(strings.TrimSuffix(params[dimension], "px")); err == nil && value > 0 {
+			media.Attrs[dimension] = value
+		}
+	}
+	return node, nil
+}
+
+// parseAttachment parses the file name of a [^attachment] link.
+func (s *state) parseAttachment(name string) (converter.Node, error) {
+	filename, anchor := referenceMetadata(name)
+	return s.mediaNode("file", name, "", mdconverter.MediaParseInput{
+		SourcePath:  s.options.SourcePath,
+		Destination: name,
+		Meta:        mdconverter.MediaMetadata{Filename: filename, Anchor: anchor},
+		Raw: map[string]any{
+			"kind": "attachment",
+		},
+	})
+}
+
+// mediaNode resolves a media reference through the media hook and wraps it in a
+// mediaSingle. Unhandled absolute URLs become url media; other references become id
+// media with MediaBaseURL stripped. Id media default their alt text to the file name.
+func (s *state) mediaNode(mediaType, source, alt string, input mdconverter.MediaParseInput) (converter.Node, error) {
+	attrs := map[string]interface{}{
+		"type": mediaType,
+	}
+
+	hookOutput, handled, err := s.applyMediaHook(input)
+	if err != nil {
+		return converter.Node{}, err
+	}
+	switch {
+	case handled:
+		attrs["type"] = hookOutput.MediaType
+		if hookOutput.ID != "" {
+			attrs["id"] = hookOutput.ID
+		}
+		if hookOutput.URL != "" {
+			attrs["url"] = hookOutput.URL
+		}
+		alt = firstNonEmpty(hookOutput.Alt, alt)
+	case s.config.MediaBaseURL != "" && strings.HasPrefix(source, s.config.MediaBaseURL) && len(source) > len(s.config.MediaBaseURL):
+		attrs["id"] = strings.TrimPrefix(source, s.config.MediaBaseURL)
+	case hasURLPrefix(source):
+		attrs["url"] = source
+	default:
+		attrs["id"] = source
+	}
+	if _, ok := attrs["id"]; ok {
+		// Attachments are known by their file name.
+		alt = firstNonEmpty(alt, input.Meta.Filename, source)
+	}
+	if alt != "" {
+		attrs["alt"] = alt
+	}
+
+	return converter.Node{
+		Type: "mediaSingle",
+		Content: []converter.Node{
+			{
+				Type:  "media",
+				Attrs: attrs,
+			},
+		},
+	}, nil
+}
+
+// matchFormatting reports the formatting delimiter opening at text[i] and the index of
+// its closing delimiter. Delimiters open after a non-word character before a non-space
+// and close after a non-space before a non-word character.
+func matchFormatting(text string, i int) (string, int) {
+	delimiter := string(text[i])
+	if strings.HasPrefix(text[i:], "??") {
+		delimiter = "??"
+	}
+	if _, ok := formattingMarks[delimiter]; !ok || isWordBefore(text, i) {
+		return "", 0
+	}
+
+	start := i + len(delimiter)
+	if start >= len(text) || isSpaceAt(text, start) || strings.HasPrefix(text[start:], delimiter) {
+		return "", 0
+	}
+
+	for j := start + 1; j < len(text); j++ {
+		if text[j] == '\\' {
+			j++
+			continue
+		}
+		if text[j] == '\n' && j+1 < len(text) && text[j+1] == '\n' {
+			return "", 0
+		}
+		if !strings.HasPrefix(text[j:], delimiter) || isSpaceBefore(text, j) {
+			continue
+		}
+		if after := j + len(delimiter); after < len(text) && isWordAt(text, after) {
+			continue
+		}
+		return delimiter, j
+	}
+	return "", 0
+}
+
+// matchEmoticon reports the emoji short name of an emoticon at text[i].
+func matchEmoticon(text string, i int) (string, int) {
+	if isWordBefore(text, i) {
+		return "", 0
+	}
+	for _, candidate := range wikiEmoticons {
+		if !strings.HasPrefix(text[i:], candidate.emoticon) {
+			continue
+		}
+		end := i + len(candidate.emoticon)
+		if candidate.emoticon[0] != '(' && end < len(text) && !isSpaceAt(text, end) && !unicode.IsPunct(rune(text[end])) {
+			continue
+		}
+		return candidate.shortName, len(candidate.emoticon)
+	}
+	return "", 0
+}
+
+// imageEnd returns the index of the "!" closing an image embed opened at text[i], or
+// -1 when text[i] is a literal exclamation mark.
+func imageEnd(text string, i int) int {
+	if i+1 >= len(text) || isSpaceAt(text, i+1) || text[i+1] == '!' {
+		return -1
+	}
+	end := strings.IndexAny(text[i+1:], "!\n")
+	if end < 0 || text[i+1+end] != '!' || isSpaceBefore(text, i+1+end) {
+		return -1
+	}
+	return i + 1 + end
+}
+
+// urlEnd returns the end of a bare URL starting at text[i], excluding trailing
+// punctuation. A backslash ends the URL so that an escaped bracket after it stays text.
+func urlEnd(text string, i int) int {
+	end := i
+	for end < len(text) && !isSpaceAt(text, end) && !strings.ContainsRune("|[]{}<>\"\\", rune(text[end])) {
+		end++
+	}
+	for end > i && strings.ContainsRune(".,;:!?)'", rune(text[end-1])) {
+		end--
+	}
+	return end
+}
+
+func hasURLPrefix(text string) bool {
+	lower := strings.ToLower(text)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// isLinkDestination reports whether a single-part [destination] is a link rather than
+// bracketed text.
+func isLinkDestination(destination string) bool {
+	lower := strings.ToLower(destination)
+	return strings.Contains(lower, "://") ||
+		strings.HasPrefix(lower, "mailto:") ||
+		strings.HasPrefix(lower, "file:") ||
+		strings.HasPrefix(destination, "#")
+}
+
+func normalizeColor(value string) (string, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if hex, ok := namedColors[value]; ok {
+		return hex, true
+	}
+	if !strings.HasPrefix(value, "#") {
+		value = "#" + value
+	}
+	if !hexColorRe.MatchString(value) {
+		return "", false
+	}
+	if len(value) == 4 {
+		value = "#" + strings.Repeat(value[1:2], 2) + strings.Repeat(value[2:3], 2) + strings.Repeat(value[3:4], 2)
+	}
+	return value, true
+}
+
+func isEscapable(c byte) bool {
+	return c < utf8.RuneSelf && unicode.IsPunct(rune(c)) || c == '^' || c == '+' || c == '~' || c == '|'
+}
+
+func unescape(text string) string {
+	if !strings.ContainsAny(text, "\\&") {
+		return text
+	}
+	var sb strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] == '&' {
+			if r, consumed := matchEntity(text[i:]); consumed > 0 {
+				sb.WriteRune(r)
+				i += consumed - 1
+				continue
+			}
+		}
+		if text[i] == '\\' && i+1 < len(text) && text[i+1] != '\\' && isEscapable(text[i+1]) {
+			i++
+		}
+		sb.WriteByte(text[i])
+	}
+	return sb.String()
+}
+
+// matchEntity decodes a numeric character reference such as &#92; at the start of text.
+// It returns the number of bytes consumed, or zero when text does not start with one.
+func matchEntity(text string) (rune, int) {
+	match := entityRe.FindStringSubmatch(text)
+	if match == nil {
+		return 0, 0
+	}
+	var code int64
+	if match[1] != "" {
+		code, _ = strconv.ParseInt(match[1], 10, 32)
+	} else {
+		code, _ = strconv.ParseInt(match[2], 16, 32)
+	}
+	if code == 0 || !utf8.ValidRune(rune(code)) {
+		return 0, 0
+	}
+	return rune(code), len(match[0])
+}
+
+// indexUnescaped returns the index of the first unescaped target byte at or after from.
+func indexUnescaped(text string, from int, target byte) int {
+	for i := from; i < len(text); i++ {
+		if text[i] == '\\' {
+			i++
+			continue
+		}
+		if text[i] == target {
+			return i
+		}
+	}
+	return -1
+}
+
+func splitUnescaped(text string, separator byte) []string {
+	var parts []string
+	for {
+		idx := indexUnescaped(text, 0, separator)
+		if idx < 0 {
+			return append(parts, text)
+		}
+		parts = append(parts, text[:idx])
+		text = text[idx+1:]
+	}
+}
+
+func isWordBefore(text string, i int) bool {
+	if i == 0 {
+		return false
+	}
+	r, _ := utf8.DecodeLastRuneInString(text[:i])
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isWordAt(text string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isSpaceAt(text string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	return unicode.IsSpace(r)
+}
+
+func isSpaceBefore(text string, i int) bool {
+	r, _ := utf8.DecodeLastRuneInString(text[:i])
+	return unicode.IsSpace(r)
+}
+
+func withMark(marks []converter.Mark, mark converter.Mark) []converter.Mark {
+	for _, existing := range marks {
+		if existing.Type == mark.Type {
+			return marks
+		}
+	}
+	combined := make([]converter.Mark, 0, len(marks)+1)
+	combined = append(combined, marks...)
+	return append(combined, mark)
+}
+
+func newTextNode(text string, marks []converter.Mark) converter.Node {
+	node := converter.Node{
+		Type: "text",
+		Text: text,
+	}
+	if len(marks) > 0 {
+		node.Marks = marks
+	}
+	return node
+}
+
+// appendInlineNode appends a node, merging adjacent text with equal marks.
+func appendInlineNode(content []converter.Node, next converter.Node) []converter.Node {
+	if next.Type == "text" && next.Text == "" {
+		return content
+	}
+	if len(content) > 0 {
+		last := &content[len(content)-1]
+		if last.Type == "text" && next.Type == "text" && marksEqual(last.Marks, next.Marks) {
+			last.Text += next.Text
+			return content
+		}
+	}
+	return append(content, next)
+}
+
+func marksEqual(a, b []converter.Mark) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Type != b[i].Type || fmt.Sprint(a[i].Attrs) != fmt.Sprint(b[i].Attrs) {
+			return false
+		}
+	}
+	return true
+}
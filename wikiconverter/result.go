@@ -0,0 +1,14 @@
+package wikiconverter
+
+import "github.com/rgonek/jira-adf-converter/converter"
+
+// Result holds the output of a Jira wiki markup conversion.
+type Result struct {
+	// Doc is the parsed ADF document; ADF is its JSON encoding.
+	Doc      converter.Doc       `json:"-"`
+	ADF      []byte              `json:"adf"`
+	Warnings []converter.Warning `json:"warnings,omitempty"`
+	// References lists links, media, mentions, inlineCards, emoji and extensions of the
+	// generated ADF; paths point into the ADF document.
+	References converter.References `json:"references,omitzero"`
+}
@@ -0,0 +1,105 @@
+// Package wikiconverter parses Jira wiki markup, the text format of Jira Server, Data
+// Center and the v2 REST API, into ADF.
+//
+// Link and media references are resolved with the mdconverter hook types, so a resolver
+// written for Markdown import also serves wiki import. [~name] mentions are resolved
+// through Config.MentionRegistry.
+package wikiconverter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/rgonek/jira-adf-converter/mdconverter"
+)
+
+// Converter converts Jira wiki markup to ADF.
+type Converter struct {
+	config Config
+}
+
+// state holds the per-conversion state, making the converter thread-safe.
+type state struct {
+	config   Config
+	ctx      context.Context
+	options  mdconverter.ConvertOptions
+	warnings []converter.Warning
+}
+
+// New creates a new Converter with the given config.
+func New(config Config) (*Converter, error) {
+	cfg := config.applyDefaults().clone()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Converter{
+		config: cfg,
+	}, nil
+}
+
+// Convert takes Jira wiki markup and returns an ADF document.
+func (c *Converter) Convert(markup string) (Result, error) {
+	return c.ConvertWithContext(context.Background(), markup, mdconverter.ConvertOptions{})
+}
+
+// ConvertWithContext takes Jira wiki markup and returns an ADF document.
+func (c *Converter) ConvertWithContext(ctx context.Context, markup string, opts mdconverter.ConvertOptions) (Result, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+
+	s := &state{
+		config:  c.config,
+		ctx:     ctx,
+		options: opts,
+	}
+
+	markup = strings.ReplaceAll(markup, "\r\n", "\n")
+	content, err := s.parseBlocks(strings.Split(markup, "\n"))
+	if err != nil {
+		return Result{}, err
+	}
+	if err := s.checkContext(); err != nil {
+		return Result{}, err
+	}
+
+	doc := converter.Doc{
+		Version: 1,
+		Type:    "doc",
+		Content: content,
+	}
+	adf, err := json.Marshal(doc)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to marshal ADF JSON: %w", err)
+	}
+
+	return Result{
+		Doc:        doc,
+		ADF:        adf,
+		Warnings:   s.warnings,
+		References: converter.CollectReferences(doc),
+	}, nil
+}
+
+func (s *state) checkContext() error {
+	if s.ctx == nil {
+		return nil
+	}
+	return s.ctx.Err()
+}
+
+func (s *state) addWarning(warnType converter.WarningType, nodeType, message string) {
+	s.warnings = append(s.warnings, converter.Warning{
+		Type:     warnType,
+		NodeType: nodeType,
+		Message:  message,
+	})
+}
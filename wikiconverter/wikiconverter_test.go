@@ -0,0 +1,194 @@
+package wikiconverter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/rgonek/jira-adf-converter/converter"
+	"github.com/rgonek/jira-adf-converter/mdconverter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConverter(t *testing.T, cfg Config) *Converter {
+	t.Helper()
+	conv, err := New(cfg)
+	require.NoError(t, err)
+	return conv
+}
+
+func TestConfigValidate(t *testing.T) {
+	_, err := New(Config{HeadingOffset: 6})
+	assert.EqualError(t, err, "headingOffset must be between -5 and 5, got 6")
+
+	_, err = New(Config{ResolutionMode: "sometimes"})
+	assert.EqualError(t, err, `invalid resolutionMode "sometimes"`)
+
+	_, err = New(Config{MentionRegistry: map[string]string{"jsmith": " "}})
+	assert.EqualError(t, err, `mentionRegistry["jsmith"] is empty`)
+}
+
+func TestConvertUsesLinkAndMediaHooks(t *testing.T) {
+	var linkInputs []mdconverter.LinkParseInput
+	var mediaInputs []mdconverter.MediaParseInput
+	conv := newTestConverter(t, Config{
+		LinkHook: func(_ context.Context, in mdconverter.LinkParseInput) (mdconverter.LinkParseOutput, error) {
+			linkInputs = append(linkInputs, in)
+			return mdconverter.LinkParseOutput{Destination: "https://cloud.example.com/browse/PROJ-1", Handled: true}, nil
+		},
+		MediaHook: func(_ context.Context, in mdconverter.MediaParseInput) (mdconverter.MediaParseOutput, error) {
+			mediaInputs = append(mediaInputs, in)
+			return mdconverter.MediaParseOutput{MediaType: "image", ID: "uploaded-" + in.Meta.Filename, Handled: true}, nil
+		},
+	})
+
+	result, err := conv.ConvertWithContext(context.Background(), "See [PROJ-1|https://jira.example.com/browse/PROJ-1]\n\n!screen.png|thumbnail!", mdconverter.ConvertOptions{SourcePath: "PROJ-1"})
+	require.NoError(t, err)
+
+	require.Len(t, linkInputs, 1)
+	assert.Equal(t, "PROJ-1", linkInputs[0].SourcePath)
+	assert.Equal(t, "https://jira.example.com/browse/PROJ-1", linkInputs[0].Destination)
+	assert.Equal(t, "PROJ-1", linkInputs[0].Text)
+	require.Len(t, mediaInputs, 1)
+	assert.Equal(t, "screen.png", mediaInputs[0].Destination)
+	assert.Equal(t, map[string]string{"thumbnail": ""}, mediaInputs[0].Raw["params"])
+
+	require.Len(t, result.Doc.Content, 2)
+	link := result.Doc.Content[0].Content[1]
+	assert.Equal(t, "https://cloud.example.com/browse/PROJ-1", link.Marks[0].Attrs["href"])
+	media := result.Doc.Content[1].Content[0]
+	assert.Equal(t, "uploaded-screen.png", media.Attrs["id"])
+	assert.Equal(t, "screen.png", media.Attrs["alt"])
+	assert.Empty(t, result.Warnings)
+}
+
+func TestConvertUnresolvedReferences(t *testing.T) {
+	unresolved := func(_ context.Context, _ mdconverter.LinkParseInput) (mdconverter.LinkParseOutput, error) {
+		return mdconverter.LinkParseOutput{}, converter.ErrUnresolved
+	}
+
+	result, err := newTestConverter(t, Config{LinkHook: unresolved}).Convert("[docs|https://example.com] by [~ghost]")
+	require.NoError(t, err)
+	require.Len(t, result.Warnings, 2)
+	assert.Equal(t, converter.WarningUnresolvedReference, result.Warnings[0].Type)
+	assert.Equal(t, "link", result.Warnings[0].NodeType)
+	assert.Equal(t, converter.WarningUnresolvedReference, result.Warnings[1].Type)
+	assert.Equal(t, "mention", result.Warnings[1].NodeType)
+
+	_, err = newTestConverter(t, Config{ResolutionMode: converter.ResolutionStrict}).Convert("by [~ghost]")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, converter.ErrUnresolved))
+}
+
+func TestConvertWarnsOnDroppedFeatures(t *testing.T) {
+	markup := "{code:title=Main.java|language=java}\nclass Main {}\n{code}\n\n{toc} and {color:nope}text{color}\n\n{quote}\nunclosed"
+
+	result, err := newTestConverter(t, Config{}).Convert(markup)
+	require.NoError(t, err)
+
+	require.Len(t, result.Warnings, 4)
+	assert.Equal(t, converter.WarningDroppedFeature, result.Warnings[0].Type)
+	assert.Equal(t, "codeBlock", result.Warnings[0].NodeType)
+	assert.Equal(t, converter.WarningUnknownNode, result.Warnings[1].Type)
+	assert.Equal(t, "toc", result.Warnings[1].NodeType)
+	assert.Equal(t, "textColor", result.Warnings[2].NodeType)
+	assert.Equal(t, "quote", result.Warnings[3].NodeType)
+}
+
+func TestConvertRoundTripsWikiOutput(t *testing.T) {
+	markup := "h2. Plan\n\n" +
+		"*Bold* and [docs|https://example.com/docs] for [~accountid:u1]\n\n" +
+		"* one\n*# nested\n\n" +
+		"{code:go}\nx := 1\n{code}\n\n" +
+		"{panel:title=Warning|bgColor=#fffae6}\nCareful\n{panel}\n\n" +
+		"||Key||Value||\n|a|b|\n"
+
+	result, err := newTestConverter(t, Config{}).Convert(markup)
+	require.NoError(t, err)
+
+	forward, err := converter.New(converter.Config{})
+	require.NoError(t, err)
+	rendered, err := forward.ConvertWiki(result.ADF)
+	require.NoError(t, err)
+
+	assert.Equal(t, markup, rendered.Markup)
+}
+
+func TestConvertRoundTripsEscapedText(t *testing.T) {
+	forward, err := converter.New(converter.Config{})
+	require.NoError(t, err)
+	reverse := newTestConverter(t, Config{})
+
+	texts := []string{
+		`C:\temp\ and a \\ double backslash`,
+		`\[not escaped\] and \*stars\*`,
+		"&#92; is a character reference",
+		"h1. Not a heading",
+		"bq. Not a quote",
+		"* not a list",
+		"# not a list",
+		"- not a list",
+		"**# not a nested list",
+		"??not a citation?? and why??",
+	}
+	for _, text := range texts {
+		t.Run(text, func(t *testing.T) {
+			doc := converter.Doc{Version: 1, Type: "doc", Content: []converter.Node{
+				{Type: "paragraph", Content: []converter.Node{{Type: "text", Text: text}}},
+			}}
+			input, err := json.Marshal(doc)
+			require.NoError(t, err)
+
+			rendered, err := forward.ConvertWiki(input)
+			require.NoError(t, err)
+			result, err := reverse.Convert(rendered.Markup)
+			require.NoError(t, err)
+
+			assert.JSONEq(t, string(input), string(result.ADF), "markup: %s", rendered.Markup)
+		})
+	}
+}
+
+func TestConvertRoundTripsEscapedBracketsAroundURL(t *testing.T) {
+	input := `{"version":1,"type":"doc","content":[{"type":"paragraph","content":[
+		{"type":"text","text":"[link|http://x]"}
+	]}]}`
+
+	forward, err := converter.New(converter.Config{})
+	require.NoError(t, err)
+	rendered, err := forward.ConvertWiki([]byte(input))
+	require.NoError(t, err)
+	assert.Equal(t, `\[link\|http://x\]`+"\n", rendered.Markup)
+
+	// The bare URL is still linked, but the escaped brackets around it stay text.
+	result, err := newTestConverter(t, Config{}).Convert(rendered.Markup)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"version":1,"type":"doc","content":[{"type":"paragraph","content":[
+		{"type":"text","text":"[link|"},
+		{"type":"inlineCard","attrs":{"url":"http://x"}},
+		{"type":"text","text":"]"}
+	]}]}`, string(result.ADF))
+}
+
+func TestConvertRoundTripsIntrawordMarksAndLinks(t *testing.T) {
+	input := `{"version":1,"type":"doc","content":[{"type":"paragraph","content":[
+		{"type":"text","text":"pre"},
+		{"type":"text","text":"bold","marks":[{"type":"strong"}]},
+		{"type":"text","text":"mid"},
+		{"type":"text","text":"gone","marks":[{"type":"strike"}]},
+		{"type":"text","text":" and "},
+		{"type":"text","text":"a|b]","marks":[{"type":"link","attrs":{"href":"https://example.com/?q=a%7Cb"}}]}
+	]}]}`
+
+	forward, err := converter.New(converter.Config{})
+	require.NoError(t, err)
+	rendered, err := forward.ConvertWiki([]byte(input))
+	require.NoError(t, err)
+	assert.Equal(t, `pre{*}bold{*}mid{-}gone{-} and [a\|b\]|https://example.com/?q=a%7Cb]`+"\n", rendered.Markup)
+
+	result, err := newTestConverter(t, Config{}).Convert(rendered.Markup)
+	require.NoError(t, err)
+	assert.JSONEq(t, input, string(result.ADF))
+}